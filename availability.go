@@ -0,0 +1,239 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/wealdtech/go-ens/v3/contracts/baseregistrar"
+	"github.com/wealdtech/go-ens/v3/contracts/ethregistrarcontroller"
+	"github.com/wealdtech/go-ens/v3/contracts/multicall3"
+)
+
+// availabilityBatchSize is the number of names checked in each call to
+// Multicall3.
+const availabilityBatchSize = 250
+
+// minLabelLength is the minimum length in characters of a label that the
+// registrar controller accepts.
+const minLabelLength = 3
+
+// Availability is the availability of a name for registration.
+type Availability struct {
+	// Name is the name as supplied.
+	Name string
+	// Normalized is the normalized name, including the chain's root, or empty
+	// if the name could not be normalized.
+	Normalized string
+	// Valid is true if the name can be registered with the chain's registrar
+	// controller.
+	Valid bool
+	// Available is true if the name is available for registration.
+	Available bool
+	// Expiry is the time at which the name's registration expires, or the zero
+	// time if the name has never been registered.  Names remain unavailable
+	// for a grace period after they expire.
+	Expiry time.Time
+	// Err is the reason that the name is not valid, or the error obtaining its
+	// availability.
+	Err error
+}
+
+// AvailableMany checks the availability of names for registration with the
+// chain's registrar controller, for example the candidates of a name search.
+// Names can be supplied as labels, such as "vitalik", or with the chain's
+// root, such as "vitalik.eth".
+//
+// Names are normalized and validated before any calls are made, and those
+// that could not be registered are returned as not valid with the reason in
+// Err.  The availability and expiry of the remaining names are then read in
+// batches with Multicall3, or with individual calls if the chain does not
+// have Multicall3.  The results are in the same order as the names supplied.
+func AvailableMany(backend bind.ContractBackend, names []string, chainId ChainId, opts ...CallOption) ([]*Availability, error) {
+	config := ChainConfigFor(chainId)
+	if config.ChainId != chainId || config.RegistrarController == UnknownAddress {
+		return nil, fmt.Errorf("no registrar controller for chain %d", chainId)
+	}
+	o, release := newCallOptions(opts)
+	defer release()
+
+	results := make([]*Availability, len(names))
+	// Names that normalize to the same label are only checked once.
+	indices := make(map[string][]int)
+	labels := make([]string, 0, len(names))
+	for i, name := range names {
+		results[i] = &Availability{Name: name}
+		label, err := availabilityLabel(name, config.Root)
+		if label != "" {
+			results[i].Normalized = fmt.Sprintf("%s.%s", label, config.Root)
+		}
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Valid = true
+		if _, exists := indices[label]; !exists {
+			labels = append(labels, label)
+		}
+		indices[label] = append(indices[label], i)
+	}
+	if len(labels) == 0 {
+		return results, nil
+	}
+
+	registrarAddr, err := RegistrarContractAddress(backend, config.Root, chainId)
+	if err != nil {
+		return nil, err
+	}
+	controllerABI, err := ethregistrarcontroller.ContractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	registrarABI, err := abi.JSON(strings.NewReader(baseregistrar.ContractABI))
+	if err != nil {
+		return nil, err
+	}
+
+	for start := 0; start < len(labels); start += availabilityBatchSize {
+		end := start + availabilityBatchSize
+		if end > len(labels) {
+			end = len(labels)
+		}
+		batch := labels[start:end]
+		calls := make([]multicall3.Multicall3Call3, 0, 2*len(batch))
+		for _, label := range batch {
+			availableData, err := controllerABI.Pack("available", label)
+			if err != nil {
+				return nil, err
+			}
+			id := new(big.Int).SetBytes(crypto.Keccak256([]byte(label)))
+			expiresData, err := registrarABI.Pack("nameExpires", id)
+			if err != nil {
+				return nil, err
+			}
+			calls = append(calls,
+				multicall3.Multicall3Call3{Target: config.RegistrarController, AllowFailure: true, CallData: availableData},
+				multicall3.Multicall3Call3{Target: registrarAddr, AllowFailure: true, CallData: expiresData},
+			)
+		}
+
+		returnData, err := aggregate3(backend, calls, o)
+		if err != nil {
+			// Multicall3 is not available, so make the calls individually.
+			returnData = individualCalls(backend, calls, o)
+		}
+
+		for i, label := range batch {
+			available, expiry, err := decodeAvailability(controllerABI, &registrarABI, returnData[2*i], returnData[2*i+1])
+			for _, index := range indices[label] {
+				results[index].Available = available
+				results[index].Expiry = expiry
+				results[index].Err = err
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// availabilityLabel returns the normalized label of a name to be registered
+// under a root, or an error if the name could not be registered.
+func availabilityLabel(name string, root string) (string, error) {
+	normalized, err := NormaliseDomain(name)
+	if err != nil {
+		return "", wrapError(ErrInvalidName, "%s: %v", name, err)
+	}
+	label := normalized
+	if strings.Contains(normalized, ".") {
+		label, err = UnqualifiedName(normalized, root)
+		if err != nil {
+			return "", err
+		}
+	}
+	if label == "" {
+		return "", errors.New("no label supplied")
+	}
+	if err := ValidateName(label); err != nil {
+		return label, err
+	}
+	if utf8.RuneCountInString(label) < minLabelLength {
+		return label, fmt.Errorf("label %s is shorter than %d characters", label, minLabelLength)
+	}
+
+	return label, nil
+}
+
+// individualCalls carries out calls individually, for chains without
+// Multicall3, returning the data returned by each call, or nil if the call
+// failed.
+func individualCalls(backend bind.ContractBackend, calls []multicall3.Multicall3Call3, o *callOptions) [][]byte {
+	opts := o.callOpts()
+	returnData := make([][]byte, len(calls))
+	for i := range calls {
+		msg := ethereum.CallMsg{
+			From: opts.From,
+			To:   &calls[i].Target,
+			Data: calls[i].CallData,
+		}
+		res, err := backend.CallContract(opts.Context, msg, opts.BlockNumber)
+		if err == nil {
+			returnData[i] = res
+		}
+	}
+
+	return returnData
+}
+
+// decodeAvailability decodes the results of the calls to obtain the
+// availability and expiry of a name.
+func decodeAvailability(controllerABI *abi.ABI, registrarABI *abi.ABI, availableData []byte, expiresData []byte) (bool, time.Time, error) {
+	if len(availableData) == 0 {
+		return false, time.Time{}, errors.New("failed to obtain availability")
+	}
+	values, err := controllerABI.Unpack("available", availableData)
+	if err != nil {
+		return false, time.Time{}, fmt.Errorf("failed to decode availability: %w", err)
+	}
+	available := values[0].(bool)
+
+	if len(expiresData) == 0 {
+		return available, time.Time{}, errors.New("failed to obtain expiry")
+	}
+	values, err = registrarABI.Unpack("nameExpires", expiresData)
+	if err != nil {
+		return available, time.Time{}, fmt.Errorf("failed to decode expiry: %w", err)
+	}
+	expires := values[0].(*big.Int)
+	if expires.Sign() == 0 || !expires.IsInt64() {
+		return available, time.Time{}, nil
+	}
+
+	return available, time.Unix(expires.Int64(), 0), nil
+}
+
+// AvailableMany checks the availability of names for registration; see the
+// package-level AvailableMany for details.
+func (c *Client) AvailableMany(names []string, opts ...CallOption) ([]*Availability, error) {
+	return AvailableMany(c.backend, names, c.chainId, opts...)
+}
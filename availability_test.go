@@ -0,0 +1,144 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/baseregistrar"
+	"github.com/wealdtech/go-ens/v3/contracts/ethregistrarcontroller"
+)
+
+func TestAvailableMany(t *testing.T) {
+	registrarAddr := common.HexToAddress("0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85")
+	expiries := map[string]int64{
+		"taken":   time.Now().Add(365 * 24 * time.Hour).Unix(),
+		"expired": time.Now().Add(-365 * 24 * time.Hour).Unix(),
+		"grace":   time.Now().Add(-24 * time.Hour).Unix(),
+	}
+
+	names := []string{
+		"vitalik",
+		"Taken.eth",
+		"taken",
+		"expired.eth",
+		"grace",
+		"ab",
+		"foo.bar.eth",
+		"ab--c",
+		"",
+		"broken",
+	}
+	expected := []*Availability{
+		{Name: "vitalik", Normalized: "vitalik.eth", Valid: true, Available: true},
+		{Name: "Taken.eth", Normalized: "taken.eth", Valid: true, Expiry: time.Unix(expiries["taken"], 0)},
+		{Name: "taken", Normalized: "taken.eth", Valid: true, Expiry: time.Unix(expiries["taken"], 0)},
+		{Name: "expired.eth", Normalized: "expired.eth", Valid: true, Available: true, Expiry: time.Unix(expiries["expired"], 0)},
+		{Name: "grace", Normalized: "grace.eth", Valid: true, Expiry: time.Unix(expiries["grace"], 0)},
+		{Name: "ab", Normalized: "ab.eth"},
+		{Name: "foo.bar.eth"},
+		{Name: "ab--c", Normalized: "ab--c.eth"},
+		{Name: ""},
+		{Name: "broken", Normalized: "broken.eth", Valid: true},
+	}
+	errs := []string{
+		"",
+		"",
+		"",
+		"",
+		"",
+		"label ab is shorter than 3 characters",
+		"foo.bar.eth not a direct child of eth",
+		"hyphens in the third and fourth positions",
+		"no label supplied",
+		"failed to obtain availability",
+	}
+
+	tests := []struct {
+		name       string
+		multicall3 bool
+		calls      int
+	}{
+		{
+			name:       "Multicall3",
+			multicall3: true,
+			// The registrar address and a single multicall.
+			calls: 2,
+		},
+		{
+			name: "Individual",
+			// The registrar address, a failed multicall and two calls for
+			// each of the five unique valid labels.
+			calls: 12,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockENS()
+			m.register("eth", registrarAddr, UnknownAddress)
+			m.backend.deploy(ChainConfigFor(EthereumMainnet).RegistrarController, ethregistrarcontroller.ContractABI).
+				on("available", func(args []interface{}) ([]interface{}, error) {
+					label := args[0].(string)
+					if label == "broken" {
+						return nil, errors.New("execution reverted")
+					}
+					expiry, exists := expiries[label]
+					return []interface{}{!exists || expiry < time.Now().Add(-90*24*time.Hour).Unix()}, nil
+				})
+			labels := make(map[string]string)
+			for label := range expiries {
+				labels[fmt.Sprintf("%x", crypto.Keccak256([]byte(label)))] = label
+			}
+			m.backend.deploy(registrarAddr, baseregistrar.ContractABI).
+				on("nameExpires", func(args []interface{}) ([]interface{}, error) {
+					label := labels[fmt.Sprintf("%x", args[0].(*big.Int).Bytes())]
+					return []interface{}{big.NewInt(expiries[label])}, nil
+				})
+			if test.multicall3 {
+				deployMockMulticall3(m.backend)
+			}
+
+			calls := m.backend.callCount()
+			client, err := NewClient(m.backend)
+			require.NoError(t, err)
+			results, err := client.AvailableMany(names)
+			require.NoError(t, err)
+			require.Equal(t, test.calls, m.backend.callCount()-calls)
+			require.Len(t, results, len(names))
+			for i := range results {
+				if errs[i] == "" {
+					require.NoError(t, results[i].Err, names[i])
+				} else {
+					require.ErrorContains(t, results[i].Err, errs[i], names[i])
+				}
+				results[i].Err = nil
+				require.Equal(t, expected[i], results[i], names[i])
+			}
+		})
+	}
+}
+
+func TestAvailableManyNoController(t *testing.T) {
+	_, err := AvailableMany(newMockBackend(), []string{"vitalik"}, BaseMainnet)
+	require.EqualError(t, err, fmt.Sprintf("no registrar controller for chain %d", BaseMainnet))
+}
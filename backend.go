@@ -0,0 +1,167 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Backend is the minimal set of chain operations required by go-ens.  It
+// allows alternative clients to be used without implementing the whole of
+// go-ethereum's bind.ContractBackend, and is accepted by the main entry points
+// such as Resolve, ReverseResolve, NewResolver, NewRegistry and NewName.  Any
+// bind.ContractBackend is also a Backend.
+type Backend interface {
+	// CallContract executes a contract call with the given data as input.
+	CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	// CodeAt returns the code of the given account.
+	CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error)
+	// SendTransaction sends a signed transaction.
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+	// FilterLogs returns the logs that match the given query.
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	// SubscribeFilterLogs subscribes to logs that match the given query.
+	SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error)
+}
+
+// Optional backend methods, used to prepare transactions.
+type (
+	headerReader interface {
+		HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	}
+	pendingCodeReader interface {
+		PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error)
+	}
+	pendingNonceReader interface {
+		PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	}
+	gasPricer interface {
+		SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	}
+	gasTipCapper interface {
+		SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	}
+	gasEstimator interface {
+		EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	}
+)
+
+// backendAdapter adapts a Backend to a bind.ContractBackend, as required by
+// the contract bindings.  The methods used to prepare transactions, such as
+// PendingNonceAt and EstimateGas, are passed to the backend if it implements
+// them and otherwise return an error, in which case transactions must be sent
+// with options that supply the nonce, gas limit and fees.
+type backendAdapter struct {
+	backend Backend
+}
+
+var _ bind.ContractBackend = (*backendAdapter)(nil)
+
+// asContractBackend returns the backend as a bind.ContractBackend, adapting it
+// if it is not one already so that wrapping backends are passed on unchanged.
+func asContractBackend(backend Backend) bind.ContractBackend {
+	if backend == nil {
+		return nil
+	}
+	if contractBackend, isContractBackend := backend.(bind.ContractBackend); isContractBackend {
+		return contractBackend
+	}
+
+	return &backendAdapter{
+		backend: backend,
+	}
+}
+
+// CodeAt returns the code of the given account.
+func (b *backendAdapter) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return b.backend.CodeAt(ctx, contract, blockNumber)
+}
+
+// CallContract executes an Ethereum contract call with the specified data as the input.
+func (b *backendAdapter) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return b.backend.CallContract(ctx, call, blockNumber)
+}
+
+// HeaderByNumber returns a block header from the current canonical chain.
+func (b *backendAdapter) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if backend, isHeaderReader := b.backend.(headerReader); isHeaderReader {
+		return backend.HeaderByNumber(ctx, number)
+	}
+	return nil, errors.New("backend does not support HeaderByNumber")
+}
+
+// PendingCodeAt returns the code of the given account in the pending state.
+func (b *backendAdapter) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	if backend, isPendingCodeReader := b.backend.(pendingCodeReader); isPendingCodeReader {
+		return backend.PendingCodeAt(ctx, account)
+	}
+	// Pending code is only used to check that a contract exists, for which
+	// the latest code suffices.
+	return b.backend.CodeAt(ctx, account, nil)
+}
+
+// PendingNonceAt retrieves the current pending nonce associated with an account.
+func (b *backendAdapter) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	if backend, isPendingNonceReader := b.backend.(pendingNonceReader); isPendingNonceReader {
+		return backend.PendingNonceAt(ctx, account)
+	}
+	return 0, errors.New("backend does not support PendingNonceAt")
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price.
+func (b *backendAdapter) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	if backend, isGasPricer := b.backend.(gasPricer); isGasPricer {
+		return backend.SuggestGasPrice(ctx)
+	}
+	return nil, errors.New("backend does not support SuggestGasPrice")
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap.
+func (b *backendAdapter) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	if backend, isGasTipCapper := b.backend.(gasTipCapper); isGasTipCapper {
+		return backend.SuggestGasTipCap(ctx)
+	}
+	return nil, errors.New("backend does not support SuggestGasTipCap")
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction.
+func (b *backendAdapter) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	if backend, isGasEstimator := b.backend.(gasEstimator); isGasEstimator {
+		return backend.EstimateGas(ctx, call)
+	}
+	return 0, errors.New("backend does not support EstimateGas")
+}
+
+// SendTransaction injects the transaction into the pending pool for execution.
+func (b *backendAdapter) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return b.backend.SendTransaction(ctx, tx)
+}
+
+// FilterLogs executes a log filter operation.
+func (b *backendAdapter) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return b.backend.FilterLogs(ctx, query)
+}
+
+// SubscribeFilterLogs creates a background log filtering operation.
+func (b *backendAdapter) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return b.backend.SubscribeFilterLogs(ctx, query, ch)
+}
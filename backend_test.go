@@ -0,0 +1,95 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// minimalBackend exposes only the methods of Backend.
+type minimalBackend struct {
+	Backend
+}
+
+func TestBackend(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("adapted.eth", address, address)
+	m.setReverse(address, "adapted.eth")
+	backend := &minimalBackend{m.backend}
+
+	resolved, err := Resolve(backend, "adapted.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+
+	name, err := ReverseResolve(backend, address, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, "adapted.eth", name)
+
+	resolver, err := NewResolver(backend, "adapted.eth", EthereumMainnet)
+	require.NoError(t, err)
+	resolved, err = resolver.Address()
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+
+	registry, err := NewRegistry(backend, EthereumMainnet)
+	require.NoError(t, err)
+	owner, err := registry.Owner("adapted.eth")
+	require.NoError(t, err)
+	require.Equal(t, address, owner)
+}
+
+func TestAsContractBackend(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	require.Nil(t, asContractBackend(nil))
+	// Contract backends are used as they are.
+	require.Equal(t, m.backend, asContractBackend(m.backend))
+
+	backend := asContractBackend(&minimalBackend{m.backend})
+	ctx := context.Background()
+	_, err := backend.HeaderByNumber(ctx, nil)
+	require.EqualError(t, err, "backend does not support HeaderByNumber")
+	_, err = backend.PendingNonceAt(ctx, address)
+	require.EqualError(t, err, "backend does not support PendingNonceAt")
+	_, err = backend.SuggestGasPrice(ctx)
+	require.EqualError(t, err, "backend does not support SuggestGasPrice")
+	_, err = backend.SuggestGasTipCap(ctx)
+	require.EqualError(t, err, "backend does not support SuggestGasTipCap")
+	_, err = backend.EstimateGas(ctx, ethereum.CallMsg{})
+	require.EqualError(t, err, "backend does not support EstimateGas")
+	code, err := backend.PendingCodeAt(ctx, m.registryAddr)
+	require.NoError(t, err)
+	require.NotEmpty(t, code)
+	_, err = backend.FilterLogs(ctx, ethereum.FilterQuery{FromBlock: big.NewInt(0)})
+	require.NoError(t, err)
+
+	// Optional methods are passed to backends that implement them.
+	backend = &backendAdapter{backend: m.backend}
+	gasPrice, err := backend.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	expected, err := m.backend.SuggestGasPrice(ctx)
+	require.NoError(t, err)
+	require.Equal(t, expected, gasPrice)
+	_, err = backend.PendingNonceAt(ctx, address)
+	require.NoError(t, err)
+}
@@ -0,0 +1,363 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Cache is a store of values with per-entry expiry.  Implementations must be
+// safe for concurrent use.
+type Cache interface {
+	// Get obtains a value from the cache.
+	Get(key string) (interface{}, bool)
+	// Set places a value in the cache for the given duration.
+	Set(key string, value interface{}, ttl time.Duration)
+	// Delete removes a value from the cache.
+	Delete(key string)
+}
+
+// DefaultCacheSize is the number of entries held by the default cache.
+const DefaultCacheSize = 10000
+
+// LRUCache is an in-memory least-recently-used cache.
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time
+}
+
+// NewLRUCache creates a new LRU cache holding up to size entries.
+func NewLRUCache(size int) *LRUCache {
+	if size <= 0 {
+		size = DefaultCacheSize
+	}
+	return &LRUCache{
+		size:    size,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+// Get obtains a value from the cache.
+func (c *LRUCache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, exists := c.entries[key]
+	if !exists {
+		return nil, false
+	}
+	entry := element.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, true
+}
+
+// Set places a value in the cache for the given duration.
+func (c *LRUCache) Set(key string, value interface{}, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expires := time.Now().Add(ttl)
+	if element, exists := c.entries[key]; exists {
+		entry := element.Value.(*lruEntry)
+		entry.value = value
+		entry.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Delete removes a value from the cache.
+func (c *LRUCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, exists := c.entries[key]; exists {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+// Len returns the number of entries in the cache, including those that have
+// expired but not yet been evicted.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+var (
+	// invalidationTopics are the registry and resolver events that alter
+	// cached records.
+	invalidationTopics = []common.Hash{
+		crypto.Keccak256Hash([]byte("NewResolver(bytes32,address)")),
+		crypto.Keccak256Hash([]byte("AddrChanged(bytes32,address)")),
+		crypto.Keccak256Hash([]byte("AddressChanged(bytes32,uint256,bytes)")),
+		crypto.Keccak256Hash([]byte("NameChanged(bytes32,string)")),
+	}
+)
+
+const (
+	cacheKindResolver = "resolver"
+	cacheKindAddress  = "addr"
+	cacheKindName     = "name"
+	cacheKindNoName   = "noname"
+)
+
+// CachingResolver resolves names and addresses, caching the results.
+// Failed lookups are not cached, although reverse resolutions that find an
+// address does not have a name are.
+type CachingResolver struct {
+	backend bind.ContractBackend
+	chainId ChainId
+	cache   Cache
+	// opts are the options for lookups that are not cached.
+	opts []CallOption
+	// metrics receives cache lookups.
+	metrics Metrics
+
+	// ResolverTTL is the time for which resolver addresses are cached.
+	ResolverTTL time.Duration
+	// AddressTTL is the time for which forward resolutions are cached.
+	AddressTTL time.Duration
+	// NameTTL is the time for which reverse resolutions are cached.
+	NameTTL time.Duration
+}
+
+// NewCachingResolver creates a caching resolver.  If cache is nil then an
+// LRU cache of DefaultCacheSize entries is used.
+func NewCachingResolver(backend bind.ContractBackend, cache Cache, chainId ChainId) *CachingResolver {
+	if cache == nil {
+		cache = NewLRUCache(DefaultCacheSize)
+	}
+	return &CachingResolver{
+		backend:     backend,
+		chainId:     chainId,
+		cache:       cache,
+		metrics:     noopMetrics{},
+		ResolverTTL: time.Hour,
+		AddressTTL:  5 * time.Minute,
+		NameTTL:     5 * time.Minute,
+	}
+}
+
+// ResolverAddress obtains the address of the resolver for a name.
+func (c *CachingResolver) ResolverAddress(name string) (common.Address, error) {
+	nameHash, err := NameHash(name)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	key := c.cacheKey(cacheKindResolver, nameHash)
+	value, exists := c.cache.Get(key)
+	c.metrics.CacheLookup(cacheKindResolver, exists)
+	if exists {
+		return value.(common.Address), nil
+	}
+
+	registry, err := registryFor(c.backend, name, c.chainId)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	address, err := registry.ResolverAddress(name)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	c.cache.Set(key, address, c.ResolverTTL)
+
+	return address, nil
+}
+
+// Resolve resolves an ENS name in to an Ethereum address.  If the address is
+// not cached it is resolved using the given options.
+func (c *CachingResolver) Resolve(name string, opts ...CallOption) (common.Address, error) {
+	nameHash, err := NameHash(name)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	key := c.cacheKey(cacheKindAddress, nameHash)
+	value, exists := c.cache.Get(key)
+	c.metrics.CacheLookup(cacheKindAddress, exists)
+	if exists {
+		return value.(common.Address), nil
+	}
+
+	address, err := Resolve(c.backend, name, c.chainId, c.lookupOptions(opts)...)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	c.cache.Set(key, address, c.AddressTTL)
+
+	return address, nil
+}
+
+// ReverseResolve resolves an address in to an ENS name.  If the name is not
+// cached it is resolved using the given options.  If the address does not
+// have a name ErrNoResolution is returned.
+func (c *CachingResolver) ReverseResolve(address common.Address, opts ...CallOption) (string, error) {
+	nameHash, err := NameHash(fmt.Sprintf("%x.%s", address.Bytes(), getRegistryAddress(c.chainId)))
+	if err != nil {
+		return "", err
+	}
+	key := c.cacheKey(cacheKindName, nameHash)
+	value, exists := c.cache.Get(key)
+	c.metrics.CacheLookup(cacheKindName, exists)
+	if exists {
+		if value.(string) == "" {
+			return "", ErrNoResolution
+		}
+		return value.(string), nil
+	}
+
+	name, err := ReverseResolve(c.backend, address, c.chainId, c.lookupOptions(opts)...)
+	if err != nil {
+		if isNoPrimaryName(err) {
+			c.cache.Set(key, "", c.NameTTL)
+		}
+		return "", err
+	}
+	c.cache.Set(key, name, c.NameTTL)
+
+	return name, nil
+}
+
+// ReverseResolveMany resolves addresses in to ENS names, returning a map of
+// address to name.  Cached names are used where available, and the remaining
+// addresses are resolved in batches with ReverseResolveMany using the given
+// options.  Addresses found not to have a name are cached as such.
+func (c *CachingResolver) ReverseResolveMany(addresses []common.Address, opts ...CallOption) (map[common.Address]string, error) {
+	names := make(map[common.Address]string, len(addresses))
+	keys := make(map[common.Address]string, len(addresses))
+	uncached := make([]common.Address, 0, len(addresses))
+	for _, address := range addresses {
+		if _, exists := keys[address]; exists {
+			continue
+		}
+		nameHash, err := NameHash(fmt.Sprintf("%x.%s", address.Bytes(), getRegistryAddress(c.chainId)))
+		if err != nil {
+			return nil, err
+		}
+		key := c.cacheKey(cacheKindName, nameHash)
+		keys[address] = key
+		value, exists := c.cache.Get(key)
+		c.metrics.CacheLookup(cacheKindName, exists)
+		if exists {
+			if name := value.(string); name != "" {
+				names[address] = name
+			}
+			continue
+		}
+		uncached = append(uncached, address)
+	}
+	if len(uncached) == 0 {
+		return names, nil
+	}
+
+	resolved, err := reverseResolveMany(c.backend, uncached, c.chainId, c.lookupOptions(opts))
+	for address, name := range resolved {
+		c.cache.Set(keys[address], name, c.NameTTL)
+		if name != "" {
+			names[address] = name
+		}
+	}
+
+	return names, err
+}
+
+// Invalidate removes all cached records for a node, along with any cached
+// resolver for the node.
+func (c *CachingResolver) Invalidate(node [32]byte) {
+	forgetResolver(node)
+	for _, kind := range []string{cacheKindResolver, cacheKindAddress, cacheKindName} {
+		c.cache.Delete(c.cacheKey(kind, node))
+	}
+}
+
+// WatchInvalidations subscribes to registry and resolver events, invalidating
+// cached records for nodes as they change.  It requires a backend that supports
+// subscriptions, and runs until the context is cancelled or the subscription
+// fails; the returned subscription can also be used to stop it.
+func (c *CachingResolver) WatchInvalidations(ctx context.Context) (ethereum.Subscription, error) {
+	query := ethereum.FilterQuery{
+		Topics: [][]common.Hash{invalidationTopics},
+	}
+	logs := make(chan types.Log)
+	sub, err := c.backend.SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		return nil, err
+	}
+
+	go func() {
+		for {
+			select {
+			case log := <-logs:
+				if len(log.Topics) > 1 {
+					c.Invalidate(log.Topics[1])
+				}
+			case <-sub.Err():
+				return
+			case <-ctx.Done():
+				sub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return sub, nil
+}
+
+// lookupOptions returns the options for a lookup that is not cached, being
+// the resolver's options followed by those given.
+func (c *CachingResolver) lookupOptions(opts []CallOption) []CallOption {
+	res := make([]CallOption, 0, len(c.opts)+len(opts))
+	res = append(res, c.opts...)
+
+	return append(res, opts...)
+}
+
+// cacheKey returns the key for a record, scoped by chain so that a cache can
+// be shared between resolvers.
+func (c *CachingResolver) cacheKey(kind string, node [32]byte) string {
+	return fmt.Sprintf("%d:%s:%x", c.chainId, kind, node)
+}
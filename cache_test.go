@@ -0,0 +1,129 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := NewLRUCache(2)
+
+	cache.Set("a", 1, time.Minute)
+	cache.Set("b", 2, time.Minute)
+	_, exists := cache.Get("a")
+	require.True(t, exists)
+
+	// Adding a third entry evicts the least-recently used.
+	cache.Set("c", 3, time.Minute)
+	require.Equal(t, 2, cache.Len())
+	_, exists = cache.Get("b")
+	require.False(t, exists)
+
+	// Expired entries are not returned.
+	cache.Set("d", 4, -time.Second)
+	_, exists = cache.Get("d")
+	require.False(t, exists)
+
+	cache.Delete("a")
+	_, exists = cache.Get("a")
+	require.False(t, exists)
+}
+
+func TestCachingResolver(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("cached.eth", address, address)
+	m.setReverse(address, "cached.eth")
+
+	resolver := NewCachingResolver(m.backend, nil, EthereumMainnet)
+
+	resolved, err := resolver.Resolve("cached.eth")
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+	name, err := resolver.ReverseResolve(address)
+	require.NoError(t, err)
+	require.Equal(t, "cached.eth", name)
+
+	calls := m.backend.callCount()
+	resolved, err = resolver.Resolve("cached.eth")
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+	name, err = resolver.ReverseResolve(address)
+	require.NoError(t, err)
+	require.Equal(t, "cached.eth", name)
+	require.Equal(t, calls, m.backend.callCount())
+
+	// Failures are not cached.
+	_, err = resolver.Resolve("unregistered.eth")
+	require.Error(t, err)
+	calls = m.backend.callCount()
+	_, err = resolver.Resolve("unregistered.eth")
+	require.Error(t, err)
+	require.Greater(t, m.backend.callCount(), calls)
+
+	// Addresses without a name are cached as such.
+	unnamed := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	_, err = resolver.ReverseResolve(unnamed)
+	require.ErrorIs(t, err, ErrNotAResolver)
+	calls = m.backend.callCount()
+	_, err = resolver.ReverseResolve(unnamed)
+	require.ErrorIs(t, err, ErrNoResolution)
+	names, err := resolver.ReverseResolveMany([]common.Address{address, unnamed})
+	require.NoError(t, err)
+	require.Equal(t, map[common.Address]string{address: "cached.eth"}, names)
+	require.Equal(t, calls, m.backend.callCount())
+}
+
+func TestCachingResolverInvalidation(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	updated := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	m.register("cached.eth", address, address)
+
+	resolver := NewCachingResolver(m.backend, nil, EthereumMainnet)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := resolver.WatchInvalidations(ctx)
+	require.NoError(t, err)
+
+	resolved, err := resolver.Resolve("cached.eth")
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+
+	m.register("cached.eth", address, updated)
+	resolved, err = resolver.Resolve("cached.eth")
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+
+	m.backend.emit(types.Log{
+		Address: m.resolverAddr,
+		Topics: []common.Hash{
+			crypto.Keccak256Hash([]byte("AddrChanged(bytes32,address)")),
+			mustNameHash("cached.eth"),
+		},
+	})
+	require.Eventually(t, func() bool {
+		resolved, err := resolver.Resolve("cached.eth")
+		return err == nil && resolved == updated
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CallOption is an option for calls that read from ENS contracts.
+type CallOption func(*callOptions)
+
+type callOptions struct {
+	ctx         context.Context
+	timeout     time.Duration
+	blockNumber *big.Int
+	pending     bool
+	from        common.Address
+	// noUniversalResolver is set if the universal resolver is not used.
+	noUniversalResolver bool
+	// metrics receives instrumentation.
+	metrics Metrics
+	// logRange is the number of blocks searched for logs.
+	logRange uint64
+
+	// Options for Format.
+	shortAddress bool
+	fallback     func(common.Address) string
+	noNameCache  Cache
+	noNameTTL    time.Duration
+}
+
+// WithContext sets the context for calls.
+func WithContext(ctx context.Context) CallOption {
+	return func(o *callOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithTimeout bounds the time taken by a call, including any requests to
+// offchain gateways.  The timeout covers the call as a whole, however many
+// requests it makes; calls that take longer fail with
+// context.DeadlineExceeded.  It applies on top of any deadline of the context
+// supplied with WithContext.
+func WithTimeout(timeout time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithBlockNumber pins calls to the state at the given block.
+func WithBlockNumber(blockNumber *big.Int) CallOption {
+	return func(o *callOptions) {
+		o.blockNumber = blockNumber
+	}
+}
+
+// WithPending runs calls against the pending state.
+func WithPending() CallOption {
+	return func(o *callOptions) {
+		o.pending = true
+	}
+}
+
+// WithFrom sets the address from which calls are made.
+func WithFrom(from common.Address) CallOption {
+	return func(o *callOptions) {
+		o.from = from
+	}
+}
+
+// WithoutUniversalResolver resolves names through the registry and resolvers
+// even if the chain has a universal resolver.
+func WithoutUniversalResolver() CallOption {
+	return func(o *callOptions) {
+		o.noUniversalResolver = true
+	}
+}
+
+// WithLogRange is an option for SecureReverseResolve that sets the number of
+// blocks, up to and including the block of the call, searched for the logs
+// that show when records last changed.  It defaults to 10,000 blocks, which
+// most providers accept in a single query.
+func WithLogRange(blocks uint64) CallOption {
+	return func(o *callOptions) {
+		o.logRange = blocks
+	}
+}
+
+// WithShortAddress is an option for Format that shortens the address shown
+// when there is no name to its checksummed form with the middle removed, for
+// example "0x1234…abcd".
+func WithShortAddress() CallOption {
+	return func(o *callOptions) {
+		o.shortAddress = true
+	}
+}
+
+// WithFallback is an option for Format that supplies the string shown for an
+// address when there is no name.  It takes precedence over WithShortAddress.
+func WithFallback(fallback func(common.Address) string) CallOption {
+	return func(o *callOptions) {
+		o.fallback = fallback
+	}
+}
+
+// WithNoNameCache is an option for Format that records addresses without a
+// name in the given cache for the given duration, so that reverse resolution
+// is not attempted for them again until the entry expires.
+func WithNoNameCache(cache Cache, ttl time.Duration) CallOption {
+	return func(o *callOptions) {
+		o.noNameCache = cache
+		o.noNameTTL = ttl
+	}
+}
+
+// newCallOptions creates call options from the supplied options.  The
+// returned function releases the resources of any timeout, and must be called
+// once the calls are complete.
+func newCallOptions(opts []CallOption) (*callOptions, context.CancelFunc) {
+	o := parseCallOptions(opts)
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		o.ctx, cancel = context.WithTimeout(o.ctx, o.timeout)
+		return o, cancel
+	}
+	return o, func() {}
+}
+
+// parseCallOptions creates call options from the supplied options without
+// applying any timeout, for callers that only inspect the options.
+func parseCallOptions(opts []CallOption) *callOptions {
+	o := &callOptions{
+		ctx:      context.Background(),
+		metrics:  noopMetrics{},
+		logRange: defaultLogRange,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.ctx == nil {
+		o.ctx = context.Background()
+	}
+	return o
+}
+
+// withContext returns a copy of the options with the given context.
+func (o *callOptions) withContext(ctx context.Context) *callOptions {
+	res := *o
+	res.ctx = ctx
+	return &res
+}
+
+// callOpts returns the options as contract call options.
+func (o *callOptions) callOpts() *bind.CallOpts {
+	return &bind.CallOpts{
+		Context:     o.ctx,
+		BlockNumber: o.blockNumber,
+		Pending:     o.pending,
+		From:        o.from,
+	}
+}
+
+// historical returns true if the calls are not against the latest state, in
+// which case cached values must not be used.
+func (o *callOptions) historical() bool {
+	return o.blockNumber != nil || o.pending
+}
@@ -0,0 +1,161 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingBackend is a mock backend that records the parameters of calls.
+type recordingBackend struct {
+	*mockBackend
+	mu           sync.Mutex
+	blockNumbers []*big.Int
+	froms        []common.Address
+	pendingCalls int
+}
+
+func (b *recordingBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	b.blockNumbers = append(b.blockNumbers, blockNumber)
+	b.froms = append(b.froms, call.From)
+	b.mu.Unlock()
+	return b.mockBackend.CallContract(ctx, call, blockNumber)
+}
+
+func (b *recordingBackend) PendingCallContract(ctx context.Context, call ethereum.CallMsg) ([]byte, error) {
+	b.mu.Lock()
+	b.pendingCalls++
+	b.mu.Unlock()
+	return b.mockBackend.CallContract(ctx, call, nil)
+}
+
+func TestCallOptions(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	from := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	m.register("options.eth", address, address)
+	m.setReverse(address, "options.eth")
+
+	backend := &recordingBackend{mockBackend: m.backend}
+	resolved, err := Resolve(backend, "options.eth", EthereumMainnet, WithBlockNumber(big.NewInt(1234)), WithFrom(from))
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+	require.NotEmpty(t, backend.blockNumbers)
+	for i := range backend.blockNumbers {
+		require.Equal(t, big.NewInt(1234), backend.blockNumbers[i])
+		require.Equal(t, from, backend.froms[i])
+	}
+
+	name, err := ReverseResolve(backend, address, EthereumMainnet, WithPending())
+	require.NoError(t, err)
+	require.Equal(t, "options.eth", name)
+	require.NotZero(t, backend.pendingCalls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = Resolve(backend, "options.eth", EthereumMainnet, WithContext(ctx))
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCallOptionsRelease(t *testing.T) {
+	o, release := newCallOptions([]CallOption{WithTimeout(time.Minute)})
+	_, hasDeadline := o.ctx.Deadline()
+	require.True(t, hasDeadline)
+	release()
+	require.ErrorIs(t, o.ctx.Err(), context.Canceled)
+
+	// Options without a timeout have nothing to release.
+	o, release = newCallOptions(nil)
+	release()
+	require.NoError(t, o.ctx.Err())
+}
+
+func TestFormat(t *testing.T) {
+	m := newMockENS()
+	named := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	m.register("alice.eth", named, named)
+	m.setReverse(named, "alice.eth")
+	unnamed := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+
+	tests := []struct {
+		name    string
+		address common.Address
+		opts    []CallOption
+		output  string
+	}{
+		{
+			name:    "Named",
+			address: named,
+			opts:    []CallOption{WithShortAddress()},
+			output:  "alice.eth",
+		},
+		{
+			name:    "Unnamed",
+			address: unnamed,
+			output:  "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		},
+		{
+			name:    "Short",
+			address: unnamed,
+			opts:    []CallOption{WithShortAddress()},
+			output:  "0x5aAe…eAed",
+		},
+		{
+			name:    "Fallback",
+			address: unnamed,
+			opts: []CallOption{
+				WithShortAddress(),
+				WithFallback(func(address common.Address) string { return "unknown " + address.Hex()[:4] }),
+			},
+			output: "unknown 0x5a",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.output, Format(m.backend, test.address, EthereumMainnet, test.opts...))
+		})
+	}
+}
+
+func TestFormatNoNameCache(t *testing.T) {
+	m := newMockENS()
+	unnamed := common.HexToAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed")
+	cache := NewLRUCache(10)
+
+	require.Equal(t, "0x5aAe…eAed", Format(m.backend, unnamed, EthereumMainnet, WithShortAddress(), WithNoNameCache(cache, time.Minute)))
+	require.Equal(t, 1, cache.Len())
+
+	// The cached address is formatted without calling the backend.
+	calls := m.backend.callCount()
+	require.Equal(t, "0x5aAe…eAed", Format(m.backend, unnamed, EthereumMainnet, WithShortAddress(), WithNoNameCache(cache, time.Minute)))
+	require.Equal(t, calls, m.backend.callCount())
+
+	// Historical calls do not use the cache.
+	Format(m.backend, unnamed, EthereumMainnet, WithNoNameCache(cache, time.Minute), WithBlockNumber(big.NewInt(1)))
+	require.Greater(t, m.backend.callCount(), calls)
+}
@@ -0,0 +1,97 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+// Interface IDs of resolver profiles.
+var (
+	addrInterfaceID            = [4]byte{0x3b, 0x3b, 0x57, 0xde}
+	multiAddrInterfaceID       = [4]byte{0xf1, 0xcb, 0x7e, 0x06}
+	textInterfaceID            = [4]byte{0x59, 0xd1, 0xd4, 0x3c}
+	contenthashInterfaceID     = [4]byte{0xbc, 0x1c, 0x58, 0xd1}
+	abiInterfaceID             = [4]byte{0x22, 0x03, 0xab, 0x56}
+	pubkeyInterfaceID          = [4]byte{0xc8, 0x69, 0x02, 0x33}
+	dnsRecordInterfaceID       = [4]byte{0xa8, 0xfa, 0x56, 0x82}
+	extendedInterfaceID        = [4]byte{0x90, 0x61, 0xb9, 0x23}
+	multicallInterfaceID       = [4]byte{0x4f, 0xbf, 0x04, 0x33}
+	legacyMulticallInterfaceID = [4]byte{0xac, 0x96, 0x50, 0xd8}
+)
+
+// ResolverCapabilities are the profiles that a resolver supports, as reported
+// by its EIP-165 supportsInterface function.
+type ResolverCapabilities struct {
+	// Addr is support for Ethereum addresses (ENSIP-1).
+	Addr bool
+	// MultiAddr is support for addresses of other coin types (ENSIP-9).
+	MultiAddr bool
+	// Text is support for text records (ENSIP-5).
+	Text bool
+	// Contenthash is support for contenthash records (ENSIP-7).
+	Contenthash bool
+	// ABI is support for ABI records (ENSIP-4).
+	ABI bool
+	// PubKey is support for public key records.
+	PubKey bool
+	// DNS is support for DNS records.
+	DNS bool
+	// Wildcard is support for wildcard resolution (ENSIP-10), which is also
+	// required for offchain resolution.
+	Wildcard bool
+	// Multicall is support for setting multiple records in a single
+	// transaction.
+	Multicall bool
+}
+
+// Supports returns the capabilities of the resolver.
+func (r *Resolver) Supports(opts ...CallOption) (*ResolverCapabilities, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	callOpts := o.callOpts()
+	supports := func(interfaceIDs ...[4]byte) (bool, error) {
+		for _, interfaceID := range interfaceIDs {
+			supported, err := r.Contract.SupportsInterface(callOpts, interfaceID)
+			if err != nil {
+				return false, err
+			}
+			if supported {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	res := &ResolverCapabilities{}
+	for _, capability := range []struct {
+		supported    *bool
+		interfaceIDs [][4]byte
+	}{
+		{&res.Addr, [][4]byte{addrInterfaceID}},
+		{&res.MultiAddr, [][4]byte{multiAddrInterfaceID}},
+		{&res.Text, [][4]byte{textInterfaceID}},
+		{&res.Contenthash, [][4]byte{contenthashInterfaceID}},
+		{&res.ABI, [][4]byte{abiInterfaceID}},
+		{&res.PubKey, [][4]byte{pubkeyInterfaceID}},
+		{&res.DNS, [][4]byte{dnsRecordInterfaceID}},
+		{&res.Wildcard, [][4]byte{extendedInterfaceID}},
+		{&res.Multicall, [][4]byte{multicallInterfaceID, legacyMulticallInterfaceID}},
+	} {
+		supported, err := supports(capability.interfaceIDs...)
+		if err != nil {
+			return nil, err
+		}
+		*capability.supported = supported
+	}
+
+	return res, nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverSupports(t *testing.T) {
+	tests := []struct {
+		name         string
+		interfaceIDs [][4]byte
+		err          error
+		capabilities *ResolverCapabilities
+	}{
+		{
+			name:         "None",
+			capabilities: &ResolverCapabilities{},
+		},
+		{
+			name:         "Legacy",
+			interfaceIDs: [][4]byte{addrInterfaceID, contenthashInterfaceID, legacyMulticallInterfaceID},
+			capabilities: &ResolverCapabilities{Addr: true, Contenthash: true, Multicall: true},
+		},
+		{
+			name: "Public",
+			interfaceIDs: [][4]byte{
+				addrInterfaceID, multiAddrInterfaceID, textInterfaceID, contenthashInterfaceID,
+				abiInterfaceID, pubkeyInterfaceID, dnsRecordInterfaceID, multicallInterfaceID,
+			},
+			capabilities: &ResolverCapabilities{
+				Addr: true, MultiAddr: true, Text: true, Contenthash: true,
+				ABI: true, PubKey: true, DNS: true, Multicall: true,
+			},
+		},
+		{
+			name:         "Offchain",
+			interfaceIDs: [][4]byte{extendedInterfaceID},
+			capabilities: &ResolverCapabilities{Wildcard: true},
+		},
+		{
+			name: "Error",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockENS()
+			m.register("supports.eth", common.Address{0x01}, UnknownAddress)
+			resolver, err := NewResolver(m.backend, "supports.eth", EthereumMainnet)
+			require.NoError(t, err)
+			m.backend.contracts[m.resolverAddr].on("supportsInterface", func(args []interface{}) ([]interface{}, error) {
+				if test.err != nil {
+					return nil, test.err
+				}
+				for _, interfaceID := range test.interfaceIDs {
+					if args[0].([4]byte) == interfaceID {
+						return []interface{}{true}, nil
+					}
+				}
+				return []interface{}{false}, nil
+			})
+
+			capabilities, err := resolver.Supports()
+			if test.err != nil {
+				require.EqualError(t, err, test.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.capabilities, capabilities)
+		})
+	}
+}
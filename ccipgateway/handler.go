@@ -0,0 +1,288 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ccipgateway provides an HTTP handler for an EIP-3668 CCIP-Read
+// gateway, allowing names held by an ENS offchain resolver to be served from
+// a Go service:
+//
+//	signer := ccipgateway.NewKeySigner(key)
+//	handler, err := ccipgateway.NewHandler(resolver, signer, []common.Address{resolverAddr}, 5*time.Minute)
+//	...
+//	err = http.ListenAndServe(":8080", handler)
+//
+// The handler accepts both forms of gateway request: GET requests to
+// /{sender}/{data}, where data may have a ".json" suffix, and POST requests
+// with a JSON body holding the sender and data.  Requests must be ENSIP-10
+// resolve(bytes,bytes) calls from one of the offchain resolvers that the
+// gateway serves.  Responses are signed in the format expected by the ENS
+// offchain resolver, which checks that the signer is one of its signers.
+package ccipgateway
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// maxRequestBodySize is the maximum size of the body of a POST request, which
+// is far larger than any resolve request.
+const maxRequestBodySize = 64 * 1024
+
+var (
+	// resolveSelector is the selector of the ENSIP-10 resolve function.
+	resolveSelector = crypto.Keccak256([]byte("resolve(bytes,bytes)"))[:4]
+
+	bytesType, _       = abi.NewType("bytes", "", nil)
+	uint64Type, _      = abi.NewType("uint64", "", nil)
+	resolveArgs        = abi.Arguments{{Type: bytesType}, {Type: bytesType}}
+	signedResponseArgs = abi.Arguments{{Type: bytesType}, {Type: uint64Type}, {Type: bytesType}}
+)
+
+// ResolveRequest is an ENSIP-10 resolve request sent to a gateway.
+type ResolveRequest struct {
+	// Sender is the offchain resolver that made the request.
+	Sender common.Address
+	// Name is the name being resolved.
+	Name string
+	// Node is the namehash of the name.
+	Node [32]byte
+	// Data is the resolver call for the name, for example addr(bytes32).
+	Data []byte
+}
+
+// DecodeResolveRequest decodes the data of a gateway request, which must be
+// an ENSIP-10 resolve(bytes,bytes) call.
+func DecodeResolveRequest(data []byte) (*ResolveRequest, error) {
+	if len(data) < 4 || string(data[:4]) != string(resolveSelector) {
+		return nil, errors.New("request is not a resolve call")
+	}
+	values, err := resolveArgs.Unpack(data[4:])
+	if err != nil {
+		return nil, errors.New("invalid resolve call")
+	}
+	name, err := ens.DNSDecodeName(values[0].([]byte))
+	if err != nil {
+		return nil, fmt.Errorf("invalid name: %w", err)
+	}
+	node, err := ens.NameHash(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name: %w", err)
+	}
+
+	return &ResolveRequest{
+		Name: name,
+		Node: node,
+		Data: values[1].([]byte),
+	}, nil
+}
+
+// Resolver answers resolve requests for a gateway.
+type Resolver interface {
+	// Resolve returns the ABI-encoded result of the resolver call in the
+	// request.  It should return an error that wraps ens.ErrUnregisteredName
+	// if the name is not known, and ens.ErrFormatUnsupported if the call is
+	// not supported.
+	Resolve(ctx context.Context, req *ResolveRequest) ([]byte, error)
+}
+
+// Signer signs gateway responses.
+type Signer interface {
+	// Address returns the address of the signer, which must be one of the
+	// signers of the offchain resolver.
+	Address() common.Address
+	// SignHash signs a hash, returning the signature in Ethereum format with
+	// a recovery ID of 27 or 28.
+	SignHash(ctx context.Context, hash []byte) ([]byte, error)
+}
+
+// keySigner signs with a private key.
+type keySigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// NewKeySigner creates a signer using a private key.
+func NewKeySigner(key *ecdsa.PrivateKey) Signer {
+	return &keySigner{key: key}
+}
+
+// Address returns the address of the signer.
+func (s *keySigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+// SignHash signs a hash.
+func (s *keySigner) SignHash(_ context.Context, hash []byte) ([]byte, error) {
+	sig, err := crypto.Sign(hash, s.key)
+	if err != nil {
+		return nil, err
+	}
+	sig[64] += 27
+
+	return sig, nil
+}
+
+// Handler is an HTTP handler for a CCIP-Read gateway.
+type Handler struct {
+	resolver Resolver
+	signer   Signer
+	senders  map[common.Address]bool
+	ttl      time.Duration
+	mux      *http.ServeMux
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler creates a gateway handler that answers requests with the given
+// resolver, signing responses with the given signer.  Only requests from the
+// offchain resolvers at the given addresses are answered, so that the signer
+// does not vouch for responses to resolvers that do not use this gateway.
+// Responses are valid for ttl from the time they are signed.
+func NewHandler(resolver Resolver, signer Signer, senders []common.Address, ttl time.Duration) (*Handler, error) {
+	if resolver == nil {
+		return nil, errors.New("no resolver supplied")
+	}
+	if signer == nil {
+		return nil, errors.New("no signer supplied")
+	}
+	if len(senders) == 0 {
+		return nil, errors.New("no resolver addresses supplied")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("ttl must be positive")
+	}
+
+	allowedSenders := make(map[common.Address]bool, len(senders))
+	for _, sender := range senders {
+		allowedSenders[sender] = true
+	}
+
+	h := &Handler{
+		resolver: resolver,
+		signer:   signer,
+		senders:  allowedSenders,
+		ttl:      ttl,
+		mux:      http.NewServeMux(),
+	}
+	h.mux.HandleFunc("GET /{sender}/{data}", h.get)
+	h.mux.HandleFunc("POST /", h.post)
+
+	return h, nil
+}
+
+// ServeHTTP serves an HTTP request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) get(w http.ResponseWriter, r *http.Request) {
+	h.serve(r.Context(), w, r.PathValue("sender"), strings.TrimSuffix(r.PathValue("data"), ".json"))
+}
+
+func (h *Handler) post(w http.ResponseWriter, r *http.Request) {
+	req := &ens.GatewayRequest{}
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize)).Decode(req); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, errors.New("request body too large"))
+			return
+		}
+		writeError(w, http.StatusBadRequest, errors.New("invalid request body"))
+		return
+	}
+	h.serve(r.Context(), w, req.Sender, req.Data)
+}
+
+func (h *Handler) serve(ctx context.Context, w http.ResponseWriter, senderInput string, dataInput string) {
+	if !common.IsHexAddress(senderInput) {
+		writeError(w, http.StatusBadRequest, errors.New("invalid sender"))
+		return
+	}
+	sender := common.HexToAddress(senderInput)
+	if !h.senders[sender] {
+		writeError(w, http.StatusNotFound, errors.New("sender is not served by this gateway"))
+		return
+	}
+	data, err := hexutil.Decode(dataInput)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, errors.New("invalid data"))
+		return
+	}
+
+	req, err := DecodeResolveRequest(data)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	req.Sender = sender
+
+	result, err := h.resolver.Resolve(ctx, req)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+
+	response, err := h.sign(ctx, sender, data, result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &ens.GatewayResponse{Data: hexutil.Encode(response)})
+}
+
+// sign creates a signed response for the ENS offchain resolver.
+func (h *Handler) sign(ctx context.Context, sender common.Address, request []byte, result []byte) ([]byte, error) {
+	expires := uint64(time.Now().Add(h.ttl).Unix())
+	sig, err := h.signer.SignHash(ctx, ens.SignedResponseHash(sender, expires, request, result))
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign response: %w", err)
+	}
+
+	return signedResponseArgs.Pack(result, expires, sig)
+}
+
+// errorStatus returns the HTTP status for a resolution error.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ens.ErrUnregisteredName),
+		errors.Is(err, ens.ErrNoResolution):
+		return http.StatusNotFound
+	case errors.Is(err, ens.ErrFormatUnsupported):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &ens.GatewayResponse{Message: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// An error here means that the client has gone away, so is ignored.
+	_ = json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,257 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccipgateway
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+func resolveData(t *testing.T, name string, method string, args ...interface{}) []byte {
+	t.Helper()
+	node, err := ens.NameHash(name)
+	require.NoError(t, err)
+
+	return resolveCall(t, name, method, append([]interface{}{node}, args...)...)
+}
+
+// resolveCall creates the data of a request for a resolver call whose
+// arguments do not start with the node.
+func resolveCall(t *testing.T, name string, method string, args ...interface{}) []byte {
+	t.Helper()
+	encodedName, err := ens.DNSEncodeName(name)
+	require.NoError(t, err)
+	call, err := resolverABI.Pack(method, args...)
+	require.NoError(t, err)
+	data, err := resolveArgs.Pack(encodedName, call)
+	require.NoError(t, err)
+
+	return append(append([]byte{}, resolveSelector...), data...)
+}
+
+func TestHandler(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	sender := common.HexToAddress("0x00000000000000000000000000000000000cc1b0")
+	address := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+
+	resolver := NewRecordsResolver(func(_ context.Context, name string) (*ens.RecordSet, error) {
+		if name != "test.offchain.eth" {
+			return nil, nil
+		}
+		return &ens.RecordSet{
+			Addr:        address,
+			Addresses:   map[uint64][]byte{0: {0x00, 0x14}},
+			Texts:       map[string]string{"url": "https://example.com/"},
+			Contenthash: []byte{0xe3, 0x01},
+		}, nil
+	})
+	handler, err := NewHandler(resolver, NewKeySigner(key), []common.Address{sender}, time.Minute)
+	require.NoError(t, err)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		post   bool
+		sender string
+		data   []byte
+		status int
+		result interface{}
+		err    string
+	}{
+		{
+			name:   "Addr",
+			data:   resolveData(t, "test.offchain.eth", "addr"),
+			result: address,
+		},
+		{
+			name:   "AddrPost",
+			post:   true,
+			data:   resolveData(t, "test.offchain.eth", "addr"),
+			result: address,
+		},
+		{
+			name:   "MultiAddr",
+			data:   resolveData(t, "test.offchain.eth", "addr0", big.NewInt(0)),
+			result: []byte{0x00, 0x14},
+		},
+		{
+			name:   "MultiAddrUnset",
+			data:   resolveData(t, "test.offchain.eth", "addr0", big.NewInt(2)),
+			result: []byte{},
+		},
+		{
+			name:   "Text",
+			data:   resolveData(t, "test.offchain.eth", "text", "url"),
+			result: "https://example.com/",
+		},
+		{
+			name:   "Contenthash",
+			post:   true,
+			data:   resolveData(t, "test.offchain.eth", "contenthash"),
+			result: []byte{0xe3, 0x01},
+		},
+		{
+			name:   "UnknownName",
+			data:   resolveData(t, "unknown.offchain.eth", "addr"),
+			status: http.StatusNotFound,
+			err:    "unknown.offchain.eth: unregistered name",
+		},
+		{
+			name:   "Unsupported",
+			data:   resolveData(t, "test.offchain.eth", "name"),
+			status: http.StatusBadRequest,
+			err:    "resolver call name(bytes32) not supported: unsupported format",
+		},
+		{
+			name:   "SupportsInterface",
+			data:   resolveCall(t, "test.offchain.eth", "supportsInterface", [4]byte{0x3b, 0x3b, 0x57, 0xde}),
+			status: http.StatusBadRequest,
+			err:    "resolver call supportsInterface(bytes4) not supported: unsupported format",
+		},
+		{
+			name:   "Multicall",
+			post:   true,
+			data:   resolveCall(t, "test.offchain.eth", "multicall", [][]byte{{0x01}}),
+			status: http.StatusBadRequest,
+			err:    "resolver call multicall(bytes[]) not supported: unsupported format",
+		},
+		{
+			name:   "CoinTypeTooLarge",
+			data:   resolveData(t, "test.offchain.eth", "addr0", new(big.Int).Lsh(big.NewInt(1), 64)),
+			status: http.StatusBadRequest,
+			err:    "coin type 18446744073709551616 not supported: unsupported format",
+		},
+		{
+			name:   "NotResolve",
+			data:   []byte{0x01, 0x02, 0x03, 0x04},
+			status: http.StatusBadRequest,
+			err:    "request is not a resolve call",
+		},
+		{
+			name:   "InvalidSender",
+			sender: "0x01",
+			data:   resolveData(t, "test.offchain.eth", "addr"),
+			status: http.StatusBadRequest,
+			err:    "invalid sender",
+		},
+		{
+			name:   "UnknownSender",
+			sender: "0x0000000000000000000000000000000000000bad",
+			data:   resolveData(t, "test.offchain.eth", "addr"),
+			status: http.StatusNotFound,
+			err:    "sender is not served by this gateway",
+		},
+		{
+			name:   "UnknownSenderPost",
+			post:   true,
+			sender: "0x0000000000000000000000000000000000000bad",
+			data:   resolveData(t, "test.offchain.eth", "addr"),
+			status: http.StatusNotFound,
+			err:    "sender is not served by this gateway",
+		},
+		{
+			name:   "BodyTooLarge",
+			post:   true,
+			data:   make([]byte, maxRequestBodySize),
+			status: http.StatusRequestEntityTooLarge,
+			err:    "request body too large",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			senderInput := test.sender
+			if senderInput == "" {
+				senderInput = strings.ToLower(sender.Hex())
+			}
+			var res *http.Response
+			var err error
+			if test.post {
+				body, err := json.Marshal(&ens.GatewayRequest{Sender: senderInput, Data: hexutil.Encode(test.data)})
+				require.NoError(t, err)
+				res, err = http.Post(server.URL+"/gateway", "application/json", strings.NewReader(string(body)))
+				require.NoError(t, err)
+			} else {
+				res, err = http.Get(server.URL + "/" + senderInput + "/" + hexutil.Encode(test.data) + ".json")
+				require.NoError(t, err)
+			}
+			defer res.Body.Close()
+
+			body := &ens.GatewayResponse{}
+			require.NoError(t, json.NewDecoder(res.Body).Decode(body))
+			if test.err != "" {
+				require.Equal(t, test.status, res.StatusCode)
+				require.Equal(t, test.err, body.Message)
+				return
+			}
+			require.Equal(t, http.StatusOK, res.StatusCode)
+
+			response, err := hexutil.Decode(body.Data)
+			require.NoError(t, err)
+			values, err := signedResponseArgs.Unpack(response)
+			require.NoError(t, err)
+			result := values[0].([]byte)
+			expires := values[1].(uint64)
+			sig := values[2].([]byte)
+			require.Greater(t, int64(expires), time.Now().Unix())
+
+			sig[64] -= 27
+			pubKey, err := crypto.SigToPub(ens.SignedResponseHash(sender, expires, test.data, result), sig)
+			require.NoError(t, err)
+			require.Equal(t, crypto.PubkeyToAddress(key.PublicKey), crypto.PubkeyToAddress(*pubKey))
+
+			req, err := DecodeResolveRequest(test.data)
+			require.NoError(t, err)
+			method, err := resolverABI.MethodById(req.Data[:4])
+			require.NoError(t, err)
+			outputs, err := method.Outputs.Unpack(result)
+			require.NoError(t, err)
+			require.Equal(t, test.result, outputs[0])
+		})
+	}
+}
+
+func TestNewHandler(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	resolver := NewRecordsResolver(func(_ context.Context, _ string) (*ens.RecordSet, error) {
+		return nil, nil
+	})
+
+	senders := []common.Address{common.HexToAddress("0x00000000000000000000000000000000000cc1b0")}
+
+	_, err = NewHandler(nil, NewKeySigner(key), senders, time.Minute)
+	require.EqualError(t, err, "no resolver supplied")
+	_, err = NewHandler(resolver, nil, senders, time.Minute)
+	require.EqualError(t, err, "no signer supplied")
+	_, err = NewHandler(resolver, NewKeySigner(key), nil, time.Minute)
+	require.EqualError(t, err, "no resolver addresses supplied")
+	_, err = NewHandler(resolver, NewKeySigner(key), senders, 0)
+	require.EqualError(t, err, "ttl must be positive")
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ccipgateway
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+var resolverABI, _ = abi.JSON(strings.NewReader(resolver.ContractABI))
+
+// recordCalls are the signatures of the resolver calls answered from records,
+// all of which take the node as their first argument.
+var recordCalls = map[string]bool{
+	"addr(bytes32)":         true,
+	"addr(bytes32,uint256)": true,
+	"text(bytes32,string)":  true,
+	"contenthash(bytes32)":  true,
+}
+
+// RecordsFunc returns the records of a name, or nil if the name is not known.
+type RecordsFunc func(ctx context.Context, name string) (*ens.RecordSet, error)
+
+// recordsResolver answers resolve requests from sets of records.
+type recordsResolver struct {
+	records RecordsFunc
+}
+
+// NewRecordsResolver creates a resolver that answers addr, text and
+// contenthash calls from the records returned by the given function, which
+// is commonly a lookup in a database.  Records that are not in the record
+// set are returned as empty.
+func NewRecordsResolver(records RecordsFunc) Resolver {
+	return &recordsResolver{records: records}
+}
+
+// Resolve returns the result of the resolver call in the request.
+func (r *recordsResolver) Resolve(ctx context.Context, req *ResolveRequest) ([]byte, error) {
+	if len(req.Data) < 4 {
+		return nil, fmt.Errorf("resolver call too short: %w", ens.ErrFormatUnsupported)
+	}
+	method, err := resolverABI.MethodById(req.Data[:4])
+	if err != nil {
+		return nil, fmt.Errorf("unknown resolver call: %w", ens.ErrFormatUnsupported)
+	}
+	if !recordCalls[method.Sig] {
+		return nil, fmt.Errorf("resolver call %s not supported: %w", method.Sig, ens.ErrFormatUnsupported)
+	}
+	args, err := method.Inputs.Unpack(req.Data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid resolver call: %w", ens.ErrFormatUnsupported)
+	}
+	if node := args[0].([32]byte); node != req.Node {
+		return nil, fmt.Errorf("resolver call is for a different name: %w", ens.ErrFormatUnsupported)
+	}
+
+	records, err := r.records(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if records == nil {
+		return nil, fmt.Errorf("%s: %w", req.Name, ens.ErrUnregisteredName)
+	}
+
+	var result interface{}
+	switch method.Sig {
+	case "addr(bytes32)":
+		address := records.Addr
+		if address == ens.UnknownAddress && len(records.Addresses[60]) == common.AddressLength {
+			address = common.BytesToAddress(records.Addresses[60])
+		}
+		result = address
+	case "addr(bytes32,uint256)":
+		coinTypeArg := args[1].(*big.Int)
+		if !coinTypeArg.IsUint64() {
+			return nil, fmt.Errorf("coin type %s not supported: %w", coinTypeArg, ens.ErrFormatUnsupported)
+		}
+		coinType := coinTypeArg.Uint64()
+		address := records.Addresses[coinType]
+		if address == nil && coinType == 60 && records.Addr != ens.UnknownAddress {
+			address = records.Addr.Bytes()
+		}
+		if address == nil {
+			address = []byte{}
+		}
+		result = address
+	case "text(bytes32,string)":
+		result = records.Texts[args[1].(string)]
+	case "contenthash(bytes32)":
+		contenthash := records.Contenthash
+		if contenthash == nil {
+			contenthash = []byte{}
+		}
+		result = contenthash
+	}
+
+	return method.Outputs.Pack(result)
+}
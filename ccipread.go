@@ -0,0 +1,480 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxOffchainLookups is the maximum number of offchain lookups followed for
+// a single call.
+const maxOffchainLookups = 4
+
+var (
+	// offchainLookupSelector is the selector of the EIP-3668 OffchainLookup
+	// error.
+	offchainLookupSelector = crypto.Keccak256([]byte("OffchainLookup(address,string[],bytes,bytes4,bytes)"))[:4]
+	// resolveWithProofSelector is the selector of the callback used by
+	// offchain resolvers that verify signed gateway responses.
+	resolveWithProofSelector = [4]byte(crypto.Keccak256([]byte("resolveWithProof(bytes,bytes)"))[:4])
+	// signersSelector is the selector of the function that returns if an
+	// address is an allowed signer for an offchain resolver.
+	signersSelector = crypto.Keccak256([]byte("signers(address)"))[:4]
+)
+
+var (
+	addressType, _      = abi.NewType("address", "", nil)
+	stringArrayType, _  = abi.NewType("string[]", "", nil)
+	bytesType, _        = abi.NewType("bytes", "", nil)
+	bytes4Type, _       = abi.NewType("bytes4", "", nil)
+	uint64Type, _       = abi.NewType("uint64", "", nil)
+	offchainLookupArgs  = abi.Arguments{{Type: addressType}, {Type: stringArrayType}, {Type: bytesType}, {Type: bytes4Type}, {Type: bytesType}}
+	callbackArgs        = abi.Arguments{{Type: bytesType}, {Type: bytesType}}
+	signedResponseArgs  = abi.Arguments{{Type: bytesType}, {Type: uint64Type}, {Type: bytesType}}
+	errGatewayNoResults = errors.New("no gateway returned a response")
+)
+
+// OffchainLookup is an EIP-3668 request from a contract for data to be
+// obtained from a gateway.
+type OffchainLookup struct {
+	// Sender is the contract making the request.
+	Sender common.Address
+	// URLs are the URLs of the gateways.
+	URLs []string
+	// CallData is the data to send to the gateway.
+	CallData []byte
+	// CallbackFunction is the selector of the function to call with the
+	// gateway's response.
+	CallbackFunction [4]byte
+	// ExtraData is the data to pass to the callback function.
+	ExtraData []byte
+}
+
+// CCIPReadBackend is a contract backend that follows EIP-3668 offchain
+// lookups, so that contract calls to offchain resolvers return the data from
+// their gateways.
+//
+// Responses for offchain resolvers that sign gateway responses, using the
+// resolveWithProof callback of the ENS offchain resolver, are verified before
+// they are used: the response must not have expired, and must be signed by
+// an address that the resolver lists as a signer.
+type CCIPReadBackend struct {
+	backend    bind.ContractBackend
+	httpClient *http.Client
+	// metrics receives failed gateway requests.
+	metrics Metrics
+}
+
+var _ bind.ContractBackend = (*CCIPReadBackend)(nil)
+
+// NewCCIPReadBackend creates a backend that follows offchain lookups using
+// the given HTTP client.  If httpClient is nil http.DefaultClient is used.
+func NewCCIPReadBackend(backend bind.ContractBackend, httpClient *http.Client) (*CCIPReadBackend, error) {
+	if backend == nil {
+		return nil, errors.New("no backend supplied")
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &CCIPReadBackend{
+		backend:    backend,
+		httpClient: httpClient,
+		metrics:    noopMetrics{},
+	}, nil
+}
+
+// Unwrap returns the upstream backend, which does not follow offchain
+// lookups.
+func (b *CCIPReadBackend) Unwrap() bind.ContractBackend {
+	return b.backend
+}
+
+// wrappingBackend is a backend that wraps an upstream backend.
+type wrappingBackend interface {
+	Unwrap() bind.ContractBackend
+}
+
+// followsOffchainLookups returns true if a backend, or any backend that it
+// wraps, follows offchain lookups.
+func followsOffchainLookups(backend bind.ContractBackend) bool {
+	for backend != nil {
+		if _, isCCIPBackend := backend.(*CCIPReadBackend); isCCIPBackend {
+			return true
+		}
+		wrapper, isWrapper := backend.(wrappingBackend)
+		if !isWrapper {
+			return false
+		}
+		backend = wrapper.Unwrap()
+	}
+	return false
+}
+
+// withoutOffchainLookups returns a backend that makes the same requests as a
+// backend but does not follow offchain lookups, keeping any timeout that
+// applies to the backend.
+func withoutOffchainLookups(backend bind.ContractBackend) bind.ContractBackend {
+	switch b := backend.(type) {
+	case *CCIPReadBackend:
+		return b.backend
+	case *TimeoutBackend:
+		return &TimeoutBackend{
+			backend: withoutOffchainLookups(b.backend),
+			timeout: b.timeout,
+		}
+	default:
+		return backend
+	}
+}
+
+// CodeAt returns the code of the given account.
+func (b *CCIPReadBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return b.backend.CodeAt(ctx, contract, blockNumber)
+}
+
+// CallContract executes an Ethereum contract call with the specified data as
+// the input, following any offchain lookups.
+func (b *CCIPReadBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	for lookups := 0; ; lookups++ {
+		res, err := b.backend.CallContract(ctx, call, blockNumber)
+		if err == nil {
+			return res, nil
+		}
+		lookup, isLookup := offchainLookupFromError(err)
+		if !isLookup {
+			return nil, err
+		}
+		if lookups == maxOffchainLookups {
+			return nil, errors.New("too many offchain lookups")
+		}
+		if call.To == nil || lookup.Sender != *call.To {
+			return nil, errors.New("offchain lookup sender does not match contract")
+		}
+
+		response, err := b.fetch(ctx, lookup)
+		if err != nil {
+			return nil, err
+		}
+		if lookup.CallbackFunction == resolveWithProofSelector {
+			if err := b.verifySignedResponse(ctx, lookup, response, blockNumber); err != nil {
+				return nil, err
+			}
+		}
+
+		args, err := callbackArgs.Pack(response, lookup.ExtraData)
+		if err != nil {
+			return nil, err
+		}
+		call.Data = append(lookup.CallbackFunction[:], args...)
+	}
+}
+
+// HeaderByNumber returns a block header from the current canonical chain.
+func (b *CCIPReadBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return b.backend.HeaderByNumber(ctx, number)
+}
+
+// PendingCodeAt returns the code of the given account in the pending state.
+func (b *CCIPReadBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return b.backend.PendingCodeAt(ctx, account)
+}
+
+// PendingNonceAt retrieves the current pending nonce associated with an account.
+func (b *CCIPReadBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return b.backend.PendingNonceAt(ctx, account)
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price.
+func (b *CCIPReadBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return b.backend.SuggestGasPrice(ctx)
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap.
+func (b *CCIPReadBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return b.backend.SuggestGasTipCap(ctx)
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction.
+func (b *CCIPReadBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return b.backend.EstimateGas(ctx, call)
+}
+
+// SendTransaction injects the transaction into the pending pool for execution.
+func (b *CCIPReadBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	return b.backend.SendTransaction(ctx, tx)
+}
+
+// FilterLogs executes a log filter operation.
+func (b *CCIPReadBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return b.backend.FilterLogs(ctx, query)
+}
+
+// SubscribeFilterLogs creates a background log filtering operation.
+func (b *CCIPReadBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return b.backend.SubscribeFilterLogs(ctx, query, ch)
+}
+
+// offchainLookupFromError obtains the offchain lookup from the error returned
+// by a call, if the call reverted with OffchainLookup.
+func offchainLookupFromError(err error) (*OffchainLookup, bool) {
+	var dataErr rpc.DataError
+	if !errors.As(err, &dataErr) {
+		return nil, false
+	}
+	hexData, isString := dataErr.ErrorData().(string)
+	if !isString {
+		return nil, false
+	}
+	data, err := hexutil.Decode(hexData)
+	if err != nil || len(data) < 4 || !bytes.Equal(data[:4], offchainLookupSelector) {
+		return nil, false
+	}
+	values, err := offchainLookupArgs.Unpack(data[4:])
+	if err != nil {
+		return nil, false
+	}
+
+	return &OffchainLookup{
+		Sender:           values[0].(common.Address),
+		URLs:             values[1].([]string),
+		CallData:         values[2].([]byte),
+		CallbackFunction: values[3].([4]byte),
+		ExtraData:        values[4].([]byte),
+	}, true
+}
+
+// GatewayRequest is the body of an EIP-3668 POST request to a gateway.
+type GatewayRequest struct {
+	Data   string `json:"data"`
+	Sender string `json:"sender"`
+}
+
+// GatewayResponse is the body of an EIP-3668 response from a gateway.  Data
+// is set for successful responses, and Message for errors.
+type GatewayResponse struct {
+	Data    string `json:"data,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// fetch obtains the response to an offchain lookup from its gateways.  As per
+// EIP-3668 the gateways are tried in turn until one responds, although a
+// client error from a gateway is returned immediately.
+func (b *CCIPReadBackend) fetch(ctx context.Context, lookup *OffchainLookup) ([]byte, error) {
+	sender := strings.ToLower(lookup.Sender.Hex())
+	data := hexutil.Encode(lookup.CallData)
+
+	for _, url := range lookup.URLs {
+		get := strings.Contains(url, "{data}")
+		url = strings.ReplaceAll(strings.ReplaceAll(url, "{sender}", sender), "{data}", data)
+		req, err := gatewayRequest(ctx, url, sender, data, get)
+		if err != nil {
+			return nil, err
+		}
+		quirks := gatewayQuirksFor(req.URL.Host)
+		if get && quirks.Post {
+			if req, err = gatewayRequest(ctx, url, sender, data, false); err != nil {
+				return nil, err
+			}
+		}
+
+		res, err := b.fetchFrom(req, quirks)
+		var clientErr *gatewayClientError
+		if errors.As(err, &clientErr) && clientErr.status == http.StatusMethodNotAllowed && req.Method == http.MethodGet {
+			// The gateway only accepts POST requests.
+			if req, err = gatewayRequest(ctx, url, sender, data, false); err != nil {
+				return nil, err
+			}
+			res, err = b.fetchFrom(req, quirks)
+		}
+		if err != nil {
+			b.metrics.CCIPGatewayError(req.URL.Host)
+			if errors.As(err, &clientErr) {
+				return nil, err
+			}
+			if ctx.Err() != nil {
+				// Out of time to try other gateways.
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		return res, nil
+	}
+
+	return nil, errGatewayNoResults
+}
+
+// gatewayRequest creates a request to a gateway, with GET if get is true and
+// otherwise with POST and the data in the body.
+func gatewayRequest(ctx context.Context, url string, sender string, data string, get bool) (*http.Request, error) {
+	if get {
+		return http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	}
+
+	body, err := json.Marshal(&GatewayRequest{Data: data, Sender: sender})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+// gatewayClientError is returned when a gateway rejects a request, in which
+// case other gateways are not tried.
+type gatewayClientError struct {
+	status  int
+	message string
+}
+
+func (e *gatewayClientError) Error() string {
+	if e.message == "" {
+		return fmt.Sprintf("gateway returned status %d", e.status)
+	}
+	return fmt.Sprintf("gateway returned status %d: %s", e.status, e.message)
+}
+
+// maxGatewayResponseSize is the maximum size of the body of a gateway
+// response.
+const maxGatewayResponseSize = 4 * 1024 * 1024
+
+// fetchFrom obtains the response to an offchain lookup from a single gateway.
+func (b *CCIPReadBackend) fetchFrom(req *http.Request, quirks *GatewayQuirks) ([]byte, error) {
+	resp, err := b.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxGatewayResponseSize))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 {
+		var errBody GatewayResponse
+		_ = json.Unmarshal(body, &errBody)
+		return nil, &gatewayClientError{status: resp.StatusCode, message: errBody.Message}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gateway returned status %d", resp.StatusCode)
+	}
+
+	if quirks.DecodeResponse != nil {
+		return quirks.DecodeResponse(body)
+	}
+
+	return decodeGatewayResponse(body)
+}
+
+// verifySignedResponse verifies a gateway response for an offchain resolver
+// that checks gateway signatures, in the format used by the ENS offchain
+// resolver: the response is the result, an expiry time and a signature over
+// the resolver's address, the expiry time, the request and the result.
+func (b *CCIPReadBackend) verifySignedResponse(ctx context.Context, lookup *OffchainLookup, response []byte, blockNumber *big.Int) error {
+	values, err := signedResponseArgs.Unpack(response)
+	if err != nil {
+		return errors.New("invalid signed gateway response")
+	}
+	result := values[0].([]byte)
+	expires := values[1].(uint64)
+	sig := values[2].([]byte)
+
+	if time.Now().Unix() > int64(expires) {
+		return fmt.Errorf("gateway response expired at %s", time.Unix(int64(expires), 0).UTC().Format(time.RFC3339))
+	}
+
+	signer, err := recoverResponseSigner(lookup.Sender, expires, lookup.ExtraData, result, sig)
+	if err != nil {
+		return err
+	}
+
+	msg := ethereum.CallMsg{
+		To:   &lookup.Sender,
+		Data: append(append([]byte{}, signersSelector...), common.LeftPadBytes(signer.Bytes(), 32)...),
+	}
+	res, err := b.backend.CallContract(ctx, msg, blockNumber)
+	if err != nil {
+		return fmt.Errorf("failed to obtain signers of offchain resolver: %w", err)
+	}
+	if len(res) != 32 || res[31] != 1 {
+		return fmt.Errorf("gateway response signed by %s, which is not a signer for the resolver", signer.Hex())
+	}
+
+	return nil
+}
+
+// SignedResponseHash returns the hash signed by a gateway for a response to
+// an offchain resolver, in the format used by the ENS offchain resolver.
+// target is the address of the resolver, request is the data of the offchain
+// lookup and result is the result returned to the resolver.
+func SignedResponseHash(target common.Address, expires uint64, request []byte, result []byte) []byte {
+	expiresBytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(expiresBytes, expires)
+
+	return crypto.Keccak256(
+		[]byte{0x19, 0x00},
+		target.Bytes(),
+		expiresBytes,
+		crypto.Keccak256(request),
+		crypto.Keccak256(result),
+	)
+}
+
+// recoverResponseSigner recovers the address that signed a gateway response.
+func recoverResponseSigner(target common.Address, expires uint64, request []byte, result []byte, sig []byte) (common.Address, error) {
+	if len(sig) != 65 {
+		return common.Address{}, errors.New("invalid gateway response signature")
+	}
+	hash := SignedResponseHash(target, expires, request, result)
+
+	// Signatures from Ethereum tools have a recovery ID of 27 or 28.
+	recoverySig := append([]byte{}, sig...)
+	if recoverySig[64] >= 27 {
+		recoverySig[64] -= 27
+	}
+	pubKey, err := crypto.SigToPub(hash, recoverySig)
+	if err != nil {
+		return common.Address{}, errors.New("invalid gateway response signature")
+	}
+
+	return crypto.PubkeyToAddress(*pubKey), nil
+}
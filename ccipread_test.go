@@ -0,0 +1,207 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+const mockOffchainResolverABI = `[
+{"type":"function","name":"addr","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"bytes"}]},
+{"type":"function","name":"resolveWithProof","stateMutability":"view","inputs":[{"name":"response","type":"bytes"},{"name":"extraData","type":"bytes"}],"outputs":[{"name":"","type":"bytes"}]},
+{"type":"function","name":"signers","stateMutability":"view","inputs":[{"name":"signer","type":"address"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+// signGatewayResponse creates a response as returned by an ENS offchain
+// resolver gateway.
+func signGatewayResponse(t *testing.T, key *ecdsa.PrivateKey, target common.Address, expires uint64, request []byte, result []byte) []byte {
+	t.Helper()
+
+	sig, err := crypto.Sign(SignedResponseHash(target, expires, request, result), key)
+	require.NoError(t, err)
+	sig[64] += 27
+
+	response, err := signedResponseArgs.Pack(result, expires, sig)
+	require.NoError(t, err)
+
+	return response
+}
+
+func TestCCIPReadBackend(t *testing.T) {
+	signerKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	resolverAddr := common.HexToAddress("0x00000000000000000000000000000000000cc1b0")
+	result := []byte("offchain result")
+
+	tests := []struct {
+		name    string
+		key     *ecdsa.PrivateKey
+		expires time.Duration
+		status  int
+		get     bool
+		err     string
+	}{
+		{
+			name:    "Post",
+			key:     signerKey,
+			expires: time.Hour,
+		},
+		{
+			name:    "Get",
+			key:     signerKey,
+			expires: time.Hour,
+			get:     true,
+		},
+		{
+			name:    "NotSigner",
+			key:     otherKey,
+			expires: time.Hour,
+			err:     "which is not a signer for the resolver",
+		},
+		{
+			name:    "Expired",
+			key:     signerKey,
+			expires: -time.Hour,
+			err:     "gateway response expired",
+		},
+		{
+			name:   "ClientError",
+			status: http.StatusNotFound,
+			err:    "gateway returned status 404: not found",
+		},
+		{
+			name:   "ServerError",
+			status: http.StatusInternalServerError,
+			err:    "no gateway returned a response",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var request []byte
+			gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.status != 0 {
+					w.WriteHeader(test.status)
+					_ = json.NewEncoder(w).Encode(map[string]string{"message": "not found"})
+					return
+				}
+				var data string
+				if r.Method == http.MethodGet {
+					data = r.URL.Query().Get("data")
+					require.Equal(t, "/"+hexutil.Encode(resolverAddr.Bytes()), r.URL.Path)
+				} else {
+					body := &GatewayRequest{}
+					require.NoError(t, json.NewDecoder(r.Body).Decode(body))
+					data = body.Data
+					require.Equal(t, hexutil.Encode(resolverAddr.Bytes()), body.Sender)
+				}
+				require.Equal(t, hexutil.Encode(request), data)
+				expires := uint64(time.Now().Add(test.expires).Unix())
+				response := signGatewayResponse(t, test.key, resolverAddr, expires, request, result)
+				_ = json.NewEncoder(w).Encode(map[string]string{"data": hexutil.Encode(response)})
+			}))
+			defer gateway.Close()
+
+			url := gateway.URL
+			if test.get {
+				url += "/{sender}?data={data}"
+			}
+
+			backend := newMockBackend()
+			backend.deploy(resolverAddr, mockOffchainResolverABI).
+				on("addr", func(args []interface{}) ([]interface{}, error) {
+					node := args[0].([32]byte)
+					request = crypto.Keccak256(node[:])
+					return nil, &mockRevertError{data: offchainLookupData(&OffchainLookup{
+						Sender:           resolverAddr,
+						URLs:             []string{url},
+						CallData:         request,
+						CallbackFunction: resolveWithProofSelector,
+						ExtraData:        request,
+					})}
+				}).
+				on("resolveWithProof", func(args []interface{}) ([]interface{}, error) {
+					values, err := signedResponseArgs.Unpack(args[0].([]byte))
+					if err != nil {
+						return nil, err
+					}
+					return []interface{}{values[0]}, nil
+				}).
+				on("signers", func(args []interface{}) ([]interface{}, error) {
+					return []interface{}{args[0].(common.Address) == crypto.PubkeyToAddress(signerKey.PublicKey)}, nil
+				})
+
+			ccipBackend, err := NewCCIPReadBackend(backend, nil)
+			require.NoError(t, err)
+
+			contract := backend.contracts[resolverAddr]
+			data, err := contract.abi.Pack("addr", mustNameHash("offchain.eth"))
+			require.NoError(t, err)
+			res, err := ccipBackend.CallContract(context.Background(), ethereum.CallMsg{To: &resolverAddr, Data: data}, nil)
+			if test.err != "" {
+				require.ErrorContains(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			values, err := contract.abi.Unpack("resolveWithProof", res)
+			require.NoError(t, err)
+			require.Equal(t, result, values[0])
+		})
+	}
+}
+
+func TestCCIPReadBackendTooManyLookups(t *testing.T) {
+	resolverAddr := common.HexToAddress("0x00000000000000000000000000000000000cc1b0")
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{"data": "0x"})
+	}))
+	defer gateway.Close()
+
+	backend := newMockBackend()
+	lookup := func(_ []interface{}) ([]interface{}, error) {
+		return nil, &mockRevertError{data: offchainLookupData(&OffchainLookup{
+			Sender:           resolverAddr,
+			URLs:             []string{gateway.URL},
+			CallbackFunction: [4]byte(crypto.Keccak256([]byte("addr(bytes32)"))[:4]),
+		})}
+	}
+	backend.deploy(resolverAddr, mockOffchainResolverABI).on("addr", lookup)
+
+	ccipBackend, err := NewCCIPReadBackend(backend, nil)
+	require.NoError(t, err)
+	data, err := backend.contracts[resolverAddr].abi.Pack("addr", mustNameHash("offchain.eth"))
+	require.NoError(t, err)
+	_, err = ccipBackend.CallContract(context.Background(), ethereum.CallMsg{To: &resolverAddr, Data: data}, nil)
+	require.EqualError(t, err, "too many offchain lookups")
+}
+
+func TestNewCCIPReadBackend(t *testing.T) {
+	_, err := NewCCIPReadBackend(nil, nil)
+	require.EqualError(t, err, "no backend supplied")
+}
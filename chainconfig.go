@@ -0,0 +1,155 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ChainConfig contains the addresses of the ENS contracts deployed on a chain.
+// Only the registry address is required; other contracts are discovered
+// through the registry if their addresses are not supplied.
+type ChainConfig struct {
+	// ChainId is the ID of the chain.
+	ChainId ChainId
+	// Registry is the address of the ENS registry.
+	Registry common.Address
+	// ReverseRegistrar is the address of the reverse registrar.
+	// If not supplied it is obtained from the owner of the reverse namespace.
+	ReverseRegistrar common.Address
+	// ReverseNamespace is the name under which reverse records are held.
+	// If not supplied it defaults to "addr.reverse".
+	ReverseNamespace string
+	// Root is the name under which the chain's registrar controller issues
+	// names, for example "eth" or "base.eth".
+	// If not supplied it defaults to "eth".
+	Root string
+	// ETHController is the address of the registrar controller that issues
+	// names under Root with commit and reveal (see ETHController).
+	// On Ethereum mainnet, if not supplied it is obtained from the resolver
+	// for the TLD.
+	ETHController common.Address
+	// L2Controller is the address of the registrar controller for chains
+	// that issue names without commit and reveal (see L2Controller).
+	L2Controller common.Address
+	// RegistrarController is the address of the registrar controller that
+	// accepts resolver records and reverse records at registration (see
+	// RegistrarController).
+	RegistrarController common.Address
+	// NameWrapper is the address of the name wrapper.
+	NameWrapper common.Address
+	// UniversalResolver is the address of the universal resolver.
+	UniversalResolver common.Address
+}
+
+// Configuration is built in for the chains with an ENS registry of their own.
+// L2s without one, such as Optimism, are not configured: names are resolved
+// on Ethereum mainnet, and primary names for addresses on these chains are
+// obtained with PrimaryName using the chain's ENSIP-19 coin type.
+var (
+	chainConfigsMu sync.RWMutex
+	chainConfigs   = map[ChainId]*ChainConfig{
+		EthereumMainnet: {
+			ChainId:             EthereumMainnet,
+			Registry:            common.HexToAddress("00000000000C2E074eC69A0dFb2997BA6C7d2e1e"),
+			ReverseNamespace:    "addr.reverse",
+			Root:                "eth",
+			RegistrarController: common.HexToAddress("253553366Da8546fC250F225fe3d25d0C782303b"),
+			NameWrapper:         common.HexToAddress("D4416b13d2b3a9aBae7AcD5D6C2BbDBE25686401"),
+			UniversalResolver:   common.HexToAddress("ce01f8eee7E479C928F8919abD53E553a36CeF67"),
+		},
+		BaseMainnet: {
+			ChainId:          BaseMainnet,
+			Registry:         common.HexToAddress("b94704422c2a1e396835a571837aa5ae53285a95"),
+			ReverseRegistrar: common.HexToAddress("79ea96012eea67a83431f1701b3dff7e37f9e282"),
+			ReverseNamespace: "80002105.reverse",
+			Root:             "base.eth",
+			L2Controller:     common.HexToAddress("4cCb0BB02FCABA27e82a56646E81d8c5bC4119a5"),
+		},
+		LineaMainnet: {
+			ChainId:          LineaMainnet,
+			Registry:         common.HexToAddress("50130b669B28C339991d8676FA73CF122a121267"),
+			ReverseNamespace: "addr.reverse",
+			Root:             "linea.eth",
+			ETHController:    common.HexToAddress("Db75Db974B1F2bD3b5916d503036208064D18295"),
+		},
+	}
+)
+
+// RegisterChainConfig registers the configuration for a chain, replacing any
+// existing configuration for the same chain.  This allows ENS deployments on
+// private chains, forks and new testnets to be used.
+func RegisterChainConfig(config *ChainConfig) error {
+	if config == nil {
+		return errors.New("no chain configuration supplied")
+	}
+	if config.ChainId == 0 {
+		return errors.New("no chain ID supplied")
+	}
+	if config.Registry == UnknownAddress {
+		return errors.New("no registry address supplied")
+	}
+
+	// Take a copy so that later changes by the caller have no effect.
+	registered := *config
+	if registered.ReverseNamespace == "" {
+		registered.ReverseNamespace = "addr.reverse"
+	}
+	if registered.Root == "" {
+		registered.Root = "eth"
+	}
+
+	chainConfigsMu.Lock()
+	chainConfigs[config.ChainId] = &registered
+	chainConfigsMu.Unlock()
+
+	return nil
+}
+
+// ChainConfigFor returns the configuration for a chain.
+// If no configuration has been registered for the chain then the
+// configuration for Ethereum mainnet is returned.
+func ChainConfigFor(chainId ChainId) *ChainConfig {
+	chainConfigsMu.RLock()
+	defer chainConfigsMu.RUnlock()
+
+	config, exists := chainConfigs[chainId]
+	if !exists {
+		config = chainConfigs[EthereumMainnet]
+	}
+	// Return a copy so that the caller cannot alter the registered configuration.
+	res := *config
+
+	return &res
+}
+
+// registeredChainIds returns the IDs of the chains with registered
+// configurations, in ascending order.
+func registeredChainIds() []ChainId {
+	chainConfigsMu.RLock()
+	defer chainConfigsMu.RUnlock()
+
+	chainIds := make([]ChainId, 0, len(chainConfigs))
+	for chainId := range chainConfigs {
+		chainIds = append(chainIds, chainId)
+	}
+	sort.Slice(chainIds, func(i, j int) bool { return chainIds[i] < chainIds[j] })
+
+	return chainIds
+}
@@ -0,0 +1,120 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterChainConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		config *ChainConfig
+		err    string
+	}{
+		{
+			name: "Nil",
+			err:  "no chain configuration supplied",
+		},
+		{
+			name: "ChainIdMissing",
+			config: &ChainConfig{
+				Registry: common.HexToAddress("0x01"),
+			},
+			err: "no chain ID supplied",
+		},
+		{
+			name: "RegistryMissing",
+			config: &ChainConfig{
+				ChainId: 1337,
+			},
+			err: "no registry address supplied",
+		},
+		{
+			name: "Good",
+			config: &ChainConfig{
+				ChainId:  1337,
+				Registry: common.HexToAddress("0x01"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := RegisterChainConfig(test.config)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+				config := ChainConfigFor(test.config.ChainId)
+				require.Equal(t, test.config.Registry, config.Registry)
+				require.Equal(t, "addr.reverse", config.ReverseNamespace)
+			}
+		})
+	}
+}
+
+func TestChainConfigForUnknown(t *testing.T) {
+	config := ChainConfigFor(ChainId(999999))
+	require.Equal(t, EthereumMainnet, config.ChainId)
+	require.Equal(t, common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"), config.Registry)
+
+	// Ensure that changes to the returned configuration are not persisted.
+	config.Registry = UnknownAddress
+	require.NotEqual(t, UnknownAddress, ChainConfigFor(EthereumMainnet).Registry)
+}
+
+func TestChainConfigCustomRegistry(t *testing.T) {
+	chainId := ChainId(31337)
+	m := newMockENSAt(common.HexToAddress("0x5FbDB2315678afecb367f032d93F642f64180aa3"))
+	address := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	m.register("foo.test", address, address)
+
+	require.NoError(t, RegisterChainConfig(&ChainConfig{
+		ChainId:  chainId,
+		Registry: m.registryAddr,
+	}))
+
+	resolved, err := Resolve(m.backend, "foo.test", chainId)
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+}
+
+func TestChainConfigETHController(t *testing.T) {
+	chainId := ChainId(31338)
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	require.NoError(t, RegisterChainConfig(&ChainConfig{
+		ChainId:       chainId,
+		Registry:      m.registryAddr,
+		ETHController: common.HexToAddress("0x0000000000000000000000000000000000000e70"),
+	}))
+
+	// A commit and reveal controller is not used as an L2 controller.
+	client, err := NewClient(m.backend, WithChainId(chainId), WithCache(nil))
+	require.NoError(t, err)
+	_, err = client.Register(testTransactOpts(t), "foo.eth", address, 365*24*time.Hour, UnknownAddress, nil, false)
+	require.EqualError(t, err, "chain 31338 registers with commit and reveal; use Name to register")
+	_, err = NewL2Controller(m.backend, chainId)
+	require.EqualError(t, err, "no L2 controller for chain 31338")
+
+	config := ChainConfigFor(LineaMainnet)
+	require.NotEqual(t, UnknownAddress, config.ETHController)
+	require.Equal(t, UnknownAddress, config.L2Controller)
+}
@@ -0,0 +1,494 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ClientOption is an option for a client.
+type ClientOption func(*clientOptions)
+
+type clientOptions struct {
+	chainId           ChainId
+	caching           bool
+	cache             Cache
+	requestsPerSecond float64
+	burst             int
+	universalResolver bool
+	ccipRead          bool
+	httpClient        *http.Client
+	timeout           time.Duration
+	referrer          *[32]byte
+	metrics           Metrics
+}
+
+// WithChainId sets the chain for the client.  If not set the client uses
+// Ethereum mainnet.
+func WithChainId(chainId ChainId) ClientOption {
+	return func(o *clientOptions) {
+		o.chainId = chainId
+	}
+}
+
+// WithCache caches the results of resolution and reverse resolution.  If
+// cache is nil then an LRU cache of DefaultCacheSize entries is used.
+func WithCache(cache Cache) ClientOption {
+	return func(o *clientOptions) {
+		o.caching = true
+		o.cache = cache
+	}
+}
+
+// WithRateLimit limits the rate of requests made to the backend by the
+// client.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(o *clientOptions) {
+		o.requestsPerSecond = requestsPerSecond
+		o.burst = burst
+	}
+}
+
+// WithUniversalResolver sets whether the client uses the chain's universal
+// resolver for lookups that support it.  This is enabled by default, and has
+// no effect on chains without a universal resolver.
+func WithUniversalResolver(enabled bool) ClientOption {
+	return func(o *clientOptions) {
+		o.universalResolver = enabled
+	}
+}
+
+// WithCCIPRead sets whether the client follows offchain lookups (EIP-3668)
+// to gateways.  This is enabled by default.
+func WithCCIPRead(enabled bool) ClientOption {
+	return func(o *clientOptions) {
+		o.ccipRead = enabled
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests to CCIP-Read gateways
+// and ENS web services.  If not set http.DefaultClient is used.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.httpClient = client
+	}
+}
+
+// WithClientTimeout bounds the time taken by each request that the client
+// makes to the backend, including any requests to CCIP-Read gateways made to
+// answer it.  Individual calls can be bounded as a whole with WithTimeout.
+func WithClientTimeout(timeout time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithReferrer sets the referrer credited with registrations and renewals
+// made by the client, for registrars that accept a referrer (see
+// ReferralRegistrar).  Registrations and renewals with registrars that do not
+// accept a referrer fail rather than proceeding without it.
+func WithReferrer(referrer [32]byte) ClientOption {
+	return func(o *clientOptions) {
+		o.referrer = &referrer
+	}
+}
+
+// WithMetrics sets the implementation that receives instrumentation from the
+// client: the duration of resolutions and of requests to the backend, lookups
+// in the client's cache and failed requests to CCIP-Read gateways.  Package
+// ensprom provides an implementation backed by Prometheus collectors.
+func WithMetrics(metrics Metrics) ClientOption {
+	return func(o *clientOptions) {
+		o.metrics = metrics
+	}
+}
+
+// Client provides access to ENS on a single chain, holding the configuration
+// that would otherwise be supplied to each package-level function.  It is
+// safe for concurrent use.
+type Client struct {
+	backend           bind.ContractBackend
+	chainId           ChainId
+	config            *ChainConfig
+	resolver          *CachingResolver
+	universalResolver bool
+	ccipRead          bool
+	httpClient        *http.Client
+	referrer          *[32]byte
+	metrics           Metrics
+}
+
+// NewClient creates a client for the given backend.
+func NewClient(backend bind.ContractBackend, opts ...ClientOption) (*Client, error) {
+	if backend == nil {
+		return nil, errors.New("no backend supplied")
+	}
+	o := &clientOptions{
+		chainId:           EthereumMainnet,
+		universalResolver: true,
+		ccipRead:          true,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	config := ChainConfigFor(o.chainId)
+	if config.ChainId != o.chainId {
+		return nil, fmt.Errorf("no configuration for chain %d", o.chainId)
+	}
+
+	metrics := o.metrics
+	if metrics == nil {
+		metrics = noopMetrics{}
+	} else {
+		// The instrumented backend is innermost so that it reports each
+		// request to the upstream backend.
+		var err error
+		backend, err = NewInstrumentedBackend(backend, metrics)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if o.requestsPerSecond != 0 || o.burst != 0 {
+		var err error
+		backend, err = NewRateLimitedBackend(backend, o.requestsPerSecond, o.burst)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	httpClient := o.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	if o.ccipRead {
+		ccipBackend, err := NewCCIPReadBackend(backend, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		ccipBackend.metrics = metrics
+		backend = ccipBackend
+	}
+
+	if o.timeout != 0 {
+		// The timeout backend is outermost so that it covers offchain
+		// lookups.
+		var err error
+		backend, err = NewTimeoutBackend(backend, o.timeout)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c := &Client{
+		backend:           backend,
+		chainId:           o.chainId,
+		config:            config,
+		universalResolver: o.universalResolver && config.UniversalResolver != UnknownAddress,
+		ccipRead:          o.ccipRead,
+		httpClient:        httpClient,
+		referrer:          o.referrer,
+		metrics:           metrics,
+	}
+	if o.caching {
+		c.resolver = NewCachingResolver(backend, o.cache, o.chainId)
+		c.resolver.opts = c.callOptions(nil)
+		c.resolver.metrics = metrics
+	}
+
+	return c, nil
+}
+
+// Backend returns the backend used by the client, including any rate limiting,
+// following of offchain lookups and timeouts.
+func (c *Client) Backend() bind.ContractBackend {
+	return c.backend
+}
+
+// ChainId returns the chain of the client.
+func (c *Client) ChainId() ChainId {
+	return c.chainId
+}
+
+// ChainConfig returns the configuration of the client's chain, as it was when
+// the client was created.
+func (c *Client) ChainConfig() *ChainConfig {
+	config := *c.config
+	return &config
+}
+
+// CachingResolver returns the caching resolver used by the client, or nil if
+// the client does not cache results.
+func (c *Client) CachingResolver() *CachingResolver {
+	return c.resolver
+}
+
+// UsesUniversalResolver returns true if the client uses the universal
+// resolver for lookups that support it.
+func (c *Client) UsesUniversalResolver() bool {
+	return c.universalResolver
+}
+
+// FollowsOffchainLookups returns true if the client follows offchain lookups
+// to CCIP-Read gateways.
+func (c *Client) FollowsOffchainLookups() bool {
+	return c.ccipRead
+}
+
+// Resolve resolves a name to an address.  Cached results are used if the
+// client caches results and the call is for the latest state; the call
+// options apply to lookups that are not cached.
+func (c *Client) Resolve(name string, opts ...CallOption) (common.Address, error) {
+	if c.resolver != nil && !parseCallOptions(opts).historical() {
+		return c.resolver.Resolve(name, opts...)
+	}
+
+	return Resolve(c.backend, name, c.chainId, c.callOptions(opts)...)
+}
+
+// ReverseResolve resolves an address to a name.  Cached results are used if
+// the client caches results and the call is for the latest state; the call
+// options apply to lookups that are not cached.
+func (c *Client) ReverseResolve(address common.Address, opts ...CallOption) (string, error) {
+	if c.resolver != nil && !parseCallOptions(opts).historical() {
+		return c.resolver.ReverseResolve(address, opts...)
+	}
+
+	return ReverseResolve(c.backend, address, c.chainId, c.callOptions(opts)...)
+}
+
+// ReverseResolveMany resolves addresses to names; see the package-level
+// ReverseResolveMany for details.  Cached results are used if the client
+// caches results and the call is for the latest state.
+func (c *Client) ReverseResolveMany(addresses []common.Address, opts ...CallOption) (map[common.Address]string, error) {
+	if c.resolver != nil && !parseCallOptions(opts).historical() {
+		return c.resolver.ReverseResolveMany(addresses, opts...)
+	}
+
+	return ReverseResolveMany(c.backend, addresses, c.chainId, c.callOptions(opts)...)
+}
+
+// SecureReverseResolve resolves an address to a name, returning a report of
+// the resolution.  Results are not cached.
+func (c *Client) SecureReverseResolve(address common.Address, opts ...CallOption) (*ReverseResolution, error) {
+	return SecureReverseResolve(c.backend, address, c.chainId, c.callOptions(opts)...)
+}
+
+// NameProfile is the set of records most commonly displayed for a name.
+type NameProfile struct {
+	// Name is the name.
+	Name string
+	// Address is the Ethereum address, or UnknownAddress if it is not set.
+	Address common.Address
+	// Addresses are the addresses keyed by coin type, including the Ethereum
+	// address.
+	Addresses map[uint64][]byte
+	// Avatar is the avatar text record.
+	Avatar string
+	// Texts are the well-known text records keyed by key.
+	Texts map[string]string
+	// Contenthash is the contenthash.
+	Contenthash []byte
+}
+
+// Profile obtains the Ethereum address, the addresses for the given coin
+// types, the well-known text records such as avatar, url and com.twitter, and
+// the contenthash of a name.  After finding the resolver the records are read
+// in a single call, as for Resolver.Records.  Records that are not set are
+// omitted.
+func (c *Client) Profile(name string, coinTypes []uint64, opts ...CallOption) (*NameProfile, error) {
+	resolver, err := NewResolver(c.backend, name, c.chainId, c.callOptions(opts)...)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &RecordsSpec{
+		CoinTypes:   []uint64{60},
+		Texts:       wellKnownTextKeys,
+		Contenthash: true,
+	}
+	for _, coinType := range coinTypes {
+		if coinType != 60 {
+			spec.CoinTypes = append(spec.CoinTypes, coinType)
+		}
+	}
+	records, err := resolver.Records(spec, c.callOptions(opts)...)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &NameProfile{
+		Name:        name,
+		Address:     UnknownAddress,
+		Addresses:   records.Addresses,
+		Avatar:      records.Texts["avatar"],
+		Texts:       records.Texts,
+		Contenthash: records.Contenthash,
+	}
+	if address := records.Addresses[60]; len(address) == common.AddressLength {
+		profile.Address = common.BytesToAddress(address)
+	}
+
+	return profile, nil
+}
+
+// callOptions returns the supplied call options along with those required by
+// the client's configuration.
+func (c *Client) callOptions(opts []CallOption) []CallOption {
+	opts = append([]CallOption{withMetrics(c.metrics)}, opts...)
+	if !c.universalResolver {
+		opts = append([]CallOption{WithoutUniversalResolver()}, opts...)
+	}
+
+	return opts
+}
+
+// Lookup returns a lookup for names and addresses on the client's chain.
+func (c *Client) Lookup(coinTypes ...uint64) *Lookup {
+	return NewLookup(c.backend, c.chainId, coinTypes...)
+}
+
+// Registry returns the registry.
+func (c *Client) Registry() (*Registry, error) {
+	return NewRegistry(c.backend, c.chainId)
+}
+
+// Resolver returns the resolver for a name.
+func (c *Client) Resolver(name string, opts ...CallOption) (*Resolver, error) {
+	return NewResolver(c.backend, name, c.chainId, c.callOptions(opts)...)
+}
+
+// Name returns a name for management.  Names are only supported on Ethereum
+// mainnet.
+func (c *Client) Name(name string) (*Name, error) {
+	if c.chainId != EthereumMainnet {
+		return nil, fmt.Errorf("names are not supported on chain %d", c.chainId)
+	}
+
+	return NewName(c.backend, name)
+}
+
+// NameWrapper returns the name wrapper.
+func (c *Client) NameWrapper() (*NameWrapper, error) {
+	return NewNameWrapper(c.backend, c.chainId)
+}
+
+// RegistrarController returns the registrar controller.
+func (c *Client) RegistrarController() (*RegistrarController, error) {
+	return NewRegistrarController(c.backend, c.chainId)
+}
+
+// Metadata returns a client for the ENS metadata service, using the client's
+// HTTP client.
+func (c *Client) Metadata() (*MetadataClient, error) {
+	return NewMetadataClient(DefaultMetadataEndpoint, c.chainId, c.httpClient)
+}
+
+// Register registers a domain on a chain whose controller registers without
+// commit and reveal, such as Basenames, or under a top-level domain whose
+// handler supplies a registrar (see RegisterTLDHandler).  Names on Ethereum
+// mainnet are registered with Name.RegisterStageOne and
+// Name.RegisterStageTwo.
+func (c *Client) Register(opts *bind.TransactOpts,
+	domain string,
+	owner common.Address,
+	duration time.Duration,
+	resolver common.Address,
+	data [][]byte,
+	reverseRecord bool,
+) (
+	*types.Transaction,
+	error,
+) {
+	registrar, err := c.registrar(domain)
+	if err != nil {
+		return nil, err
+	}
+	if c.referrer != nil {
+		referralRegistrar, err := c.referralRegistrar(registrar, domain)
+		if err != nil {
+			return nil, err
+		}
+		return referralRegistrar.RegisterWithReferrer(opts, domain, owner, duration, resolver, data, reverseRecord, *c.referrer)
+	}
+
+	return registrar.Register(opts, domain, owner, duration, resolver, data, reverseRecord)
+}
+
+// Renew renews a domain on a chain whose controller registers without commit
+// and reveal, or under a top-level domain whose handler supplies a
+// registrar.  Names on Ethereum mainnet are renewed with
+// Name.ExtendRegistration.
+func (c *Client) Renew(opts *bind.TransactOpts, domain string, duration time.Duration) (*types.Transaction, error) {
+	registrar, err := c.registrar(domain)
+	if err != nil {
+		return nil, err
+	}
+	if c.referrer != nil {
+		referralRegistrar, err := c.referralRegistrar(registrar, domain)
+		if err != nil {
+			return nil, err
+		}
+		return referralRegistrar.RenewWithReferrer(opts, domain, duration, *c.referrer)
+	}
+
+	return registrar.Renew(opts, domain, duration)
+}
+
+// referralRegistrar returns the registrar as a referral registrar, for
+// clients with a referrer.
+func (c *Client) referralRegistrar(registrar TLDRegistrar, domain string) (ReferralRegistrar, error) {
+	referralRegistrar, isReferralRegistrar := registrar.(ReferralRegistrar)
+	if !isReferralRegistrar {
+		return nil, fmt.Errorf("registrar for %s does not accept a referrer", domain)
+	}
+
+	return referralRegistrar, nil
+}
+
+// registrar returns the registrar for a domain, which is that supplied by
+// the handler for its top-level domain if there is one, otherwise the
+// chain's controller.
+func (c *Client) registrar(domain string) (TLDRegistrar, error) {
+	registrar, handled, err := tldRegistrarFor(c.backend, domain, c.chainId)
+	if handled {
+		return registrar, err
+	}
+	controller, err := c.l2Controller()
+	if err != nil {
+		return nil, err
+	}
+
+	return controller, nil
+}
+
+// l2Controller returns the controller for chains that register without
+// commit and reveal.
+func (c *Client) l2Controller() (*L2Controller, error) {
+	if c.config.L2Controller == UnknownAddress {
+		return nil, fmt.Errorf("chain %d registers with commit and reveal; use Name to register", c.chainId)
+	}
+
+	return NewL2ControllerAt(c.backend, c.config.Root, c.config.L2Controller)
+}
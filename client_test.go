@@ -0,0 +1,225 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/universalresolver"
+)
+
+func TestNewClient(t *testing.T) {
+	backend := newMockBackend()
+
+	tests := []struct {
+		name              string
+		opts              []ClientOption
+		chainId           ChainId
+		universalResolver bool
+		err               string
+	}{
+		{
+			name:              "Default",
+			chainId:           EthereumMainnet,
+			universalResolver: true,
+		},
+		{
+			name:    "NoUniversalResolver",
+			opts:    []ClientOption{WithUniversalResolver(false)},
+			chainId: EthereumMainnet,
+		},
+		{
+			name:    "ChainWithoutUniversalResolver",
+			opts:    []ClientOption{WithChainId(BaseMainnet)},
+			chainId: BaseMainnet,
+		},
+		{
+			name: "UnknownChain",
+			opts: []ClientOption{WithChainId(ChainId(999999))},
+			err:  "no configuration for chain 999999",
+		},
+		{
+			name: "BadRateLimit",
+			opts: []ClientOption{WithRateLimit(0, 1)},
+			err:  "requests per second must be greater than 0",
+		},
+		{
+			name:              "RateLimited",
+			opts:              []ClientOption{WithRateLimit(100, 10)},
+			chainId:           EthereumMainnet,
+			universalResolver: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client, err := NewClient(backend, test.opts...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.chainId, client.ChainId())
+			require.Equal(t, test.chainId, client.ChainConfig().ChainId)
+			require.Equal(t, test.universalResolver, client.UsesUniversalResolver())
+			require.True(t, client.FollowsOffchainLookups())
+		})
+	}
+
+	_, err := NewClient(nil)
+	require.EqualError(t, err, "no backend supplied")
+}
+
+func TestClientResolve(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("client.eth", address, address)
+	m.setReverse(address, "client.eth")
+
+	client, err := NewClient(m.backend, WithCache(nil))
+	require.NoError(t, err)
+	require.NotNil(t, client.CachingResolver())
+
+	for i := 0; i < 2; i++ {
+		resolved, err := client.Resolve("client.eth")
+		require.NoError(t, err)
+		require.Equal(t, address, resolved)
+		name, err := client.ReverseResolve(address)
+		require.NoError(t, err)
+		require.Equal(t, "client.eth", name)
+	}
+
+	// Lookups with call options bypass the cache.
+	calls := m.backend.callCount()
+	resolved, err := client.Resolve("client.eth", WithBlockNumber(big.NewInt(1)))
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+	require.Greater(t, m.backend.callCount(), calls)
+
+	_, err = client.Register(testTransactOpts(t), "client.eth", address, 365*24*time.Hour, UnknownAddress, nil, false)
+	require.EqualError(t, err, "chain 1 registers with commit and reveal; use Name to register")
+}
+
+func TestClientCallOptionsCached(t *testing.T) {
+	m := newMockENS()
+	named := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	m.register("alice.eth", named, named)
+	m.setReverse(named, "alice.eth")
+	reverses := 0
+	m.backend.deploy(ChainConfigFor(EthereumMainnet).UniversalResolver, universalresolver.ContractABI).
+		on("reverse", func(_ []interface{}) ([]interface{}, error) {
+			reverses++
+			return []interface{}{"alice.eth", named, m.resolverAddr, m.resolverAddr}, nil
+		})
+
+	client, err := NewClient(m.backend, WithCache(nil))
+	require.NoError(t, err)
+
+	// Per-call options apply to lookups that are not cached.
+	name, err := client.ReverseResolve(named, WithoutUniversalResolver())
+	require.NoError(t, err)
+	require.Equal(t, "alice.eth", name)
+	require.Zero(t, reverses)
+	names, err := client.ReverseResolveMany([]common.Address{named}, WithoutUniversalResolver())
+	require.NoError(t, err)
+	require.Equal(t, map[common.Address]string{named: "alice.eth"}, names)
+	require.Zero(t, reverses)
+
+	// Cached results are used regardless.
+	name, err = client.ReverseResolve(named)
+	require.NoError(t, err)
+	require.Equal(t, "alice.eth", name)
+	require.Zero(t, reverses)
+}
+
+func TestClientProfile(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	btcAddress := []byte{0x00, 0x14, 0x01, 0x02}
+	contenthash := []byte{0xe3, 0x01, 0x01}
+	m.register("profile.eth", address, address)
+	m.setCoinAddr("profile.eth", 0, btcAddress)
+	m.setText("profile.eth", "avatar", "https://example.com/avatar.png")
+	m.setText("profile.eth", "com.twitter", "profile")
+	m.backend.contracts[m.resolverAddr].on("contenthash", func(args []interface{}) ([]interface{}, error) {
+		if args[0].([32]byte) != mustNameHash("profile.eth") {
+			return []interface{}{[]byte{}}, nil
+		}
+		return []interface{}{contenthash}, nil
+	})
+	m.register("empty.eth", address, UnknownAddress)
+
+	client, err := NewClient(m.backend, WithUniversalResolver(false))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		domain    string
+		coinTypes []uint64
+		err       string
+		profile   *NameProfile
+	}{
+		{
+			name:   "Unregistered",
+			domain: "unregistered.eth",
+			err:    "unregistered name",
+		},
+		{
+			name:   "Empty",
+			domain: "empty.eth",
+			profile: &NameProfile{
+				Name:      "empty.eth",
+				Address:   UnknownAddress,
+				Addresses: map[uint64][]byte{},
+				Texts:     map[string]string{},
+			},
+		},
+		{
+			name:      "Good",
+			domain:    "profile.eth",
+			coinTypes: []uint64{0, 60},
+			profile: &NameProfile{
+				Name:    "profile.eth",
+				Address: address,
+				Addresses: map[uint64][]byte{
+					0:  btcAddress,
+					60: address.Bytes(),
+				},
+				Avatar: "https://example.com/avatar.png",
+				Texts: map[string]string{
+					"avatar":      "https://example.com/avatar.png",
+					"com.twitter": "profile",
+				},
+				Contenthash: contenthash,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			profile, err := client.Profile(test.domain, test.coinTypes)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.profile, profile)
+		})
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var contenthashCmd = &cobra.Command{
+	Use:   "contenthash",
+	Short: "Obtain and set contenthash records",
+}
+
+var contenthashGetCmd = &cobra.Command{
+	Use:   "get NAME",
+	Short: "Obtain the contenthash of a name",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+		_, client, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		resolver, err := client.Resolver(args[0], ens.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		contenthash, err := resolver.Contenthash(ens.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		if len(contenthash) == 0 {
+			return fmt.Errorf("%s has no contenthash", args[0])
+		}
+		value, err := ens.ContenthashToString(contenthash)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), value)
+
+		return nil
+	},
+}
+
+var contenthashSetCmd = &cobra.Command{
+	Use:   "set NAME CONTENTHASH",
+	Short: "Set the contenthash of a name, for example /ipfs/Qm...",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		contenthash, err := ens.StringToContenthash(args[1])
+		if err != nil {
+			return err
+		}
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+		backend, client, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		resolver, err := client.Resolver(args[0], ens.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		opts, err := transactOpts(ctx, backend)
+		if err != nil {
+			return err
+		}
+
+		tx, err := resolver.SetContenthash(opts, contenthash)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), tx.Hash().Hex())
+
+		return nil
+	},
+}
+
+func init() {
+	contenthashCmd.AddCommand(contenthashGetCmd)
+	contenthashCmd.AddCommand(contenthashSetCmd)
+	rootCmd.AddCommand(contenthashCmd)
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var (
+	registerOwner    string
+	registerDuration time.Duration
+	renewDuration    time.Duration
+)
+
+var registerCmd = &cobra.Command{
+	Use:   "register NAME",
+	Short: "Register a name",
+	Long: `Register a name.
+
+On Ethereum mainnet registration is a two-stage process: a commitment is sent,
+and once it has been mined and the minimum commitment interval has passed the
+registration itself is sent.  This takes at least a minute, and is not bound
+by --timeout.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := cmd.Context()
+		backend, client, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		opts, err := transactOpts(ctx, backend)
+		if err != nil {
+			return err
+		}
+		owner := opts.From
+		if registerOwner != "" {
+			if !common.IsHexAddress(registerOwner) {
+				return errInvalidAddress
+			}
+			owner = common.HexToAddress(registerOwner)
+		}
+
+		if client.ChainConfig().L2Controller != ens.UnknownAddress {
+			// The chain registers without commit and reveal.
+			controller, err := ens.NewL2Controller(backend, client.ChainId())
+			if err != nil {
+				return err
+			}
+			opts.Value, err = controller.RegisterPrice(args[0], registerDuration)
+			if err != nil {
+				return err
+			}
+			tx, err := client.Register(opts, args[0], owner, registerDuration, ens.UnknownAddress, nil, false)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), tx.Hash().Hex())
+			return nil
+		}
+
+		name, err := client.Name(args[0])
+		if err != nil {
+			return err
+		}
+		tx, secret, err := name.RegisterStageOne(owner, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Commitment sent in %s\n", tx.Hash().Hex())
+		if err := waitMined(ctx, backend, tx); err != nil {
+			return err
+		}
+
+		interval, err := name.RegistrationInterval()
+		if err != nil {
+			return err
+		}
+		// Allow for the timestamp of the next block.
+		interval += 15 * time.Second
+		fmt.Fprintf(cmd.OutOrStdout(), "Waiting %v before registering\n", interval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+
+		opts, err = transactOpts(ctx, backend)
+		if err != nil {
+			return err
+		}
+		opts.Value, err = rentValue(name, registerDuration)
+		if err != nil {
+			return err
+		}
+		tx, err = name.RegisterStageTwo(owner, secret, opts)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "Registration sent in %s\n", tx.Hash().Hex())
+
+		return waitMined(ctx, backend, tx)
+	},
+}
+
+var renewCmd = &cobra.Command{
+	Use:   "renew NAME",
+	Short: "Renew a name",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+		backend, client, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		opts, err := transactOpts(ctx, backend)
+		if err != nil {
+			return err
+		}
+
+		var tx *types.Transaction
+		if client.ChainConfig().L2Controller != ens.UnknownAddress {
+			controller, err := ens.NewL2Controller(backend, client.ChainId())
+			if err != nil {
+				return err
+			}
+			// Any excess over the renewal price is refunded.
+			opts.Value, err = controller.RegisterPrice(args[0], renewDuration)
+			if err != nil {
+				return err
+			}
+			tx, err = client.Renew(opts, args[0], renewDuration)
+			if err != nil {
+				return err
+			}
+		} else {
+			name, err := client.Name(args[0])
+			if err != nil {
+				return err
+			}
+			opts.Value, err = rentValue(name, renewDuration)
+			if err != nil {
+				return err
+			}
+			tx, err = name.ExtendRegistration(opts)
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), tx.Hash().Hex())
+
+		return nil
+	},
+}
+
+// rentValue returns the value to send to rent a name for the given duration.
+func rentValue(name *ens.Name, duration time.Duration) (*big.Int, error) {
+	rentCost, err := name.RentCost()
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).Mul(rentCost, big.NewInt(int64(duration.Seconds()))), nil
+}
+
+// waitMined waits for a transaction to be mined, returning an error if it
+// failed.
+func waitMined(ctx context.Context, backend *ethclient.Client, tx *types.Transaction) error {
+	receipt, err := bind.WaitMined(ctx, backend, tx)
+	if err != nil {
+		return err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return errors.New("transaction failed")
+	}
+
+	return nil
+}
+
+func init() {
+	registerCmd.Flags().StringVar(&registerOwner, "owner", "", "owner of the name (defaults to the sender)")
+	registerCmd.Flags().DurationVar(&registerDuration, "duration", 365*24*time.Hour, "duration of the registration")
+	renewCmd.Flags().DurationVar(&renewDuration, "duration", 365*24*time.Hour, "duration of the renewal")
+	rootCmd.AddCommand(registerCmd)
+	rootCmd.AddCommand(renewCmd)
+}
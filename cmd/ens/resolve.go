@@ -0,0 +1,75 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var resolveCmd = &cobra.Command{
+	Use:   "resolve NAME",
+	Short: "Resolve a name to an address",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+		_, client, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+
+		address, err := client.Resolve(args[0], ens.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), address.Hex())
+
+		return nil
+	},
+}
+
+var reverseCmd = &cobra.Command{
+	Use:   "reverse ADDRESS",
+	Short: "Resolve an address to a name",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !common.IsHexAddress(args[0]) {
+			return errInvalidAddress
+		}
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+		_, client, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+
+		name, err := client.ReverseResolve(common.HexToAddress(args[0]), ens.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), name)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(resolveCmd)
+	rootCmd.AddCommand(reverseCmd)
+}
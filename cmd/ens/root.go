@@ -0,0 +1,101 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/spf13/cobra"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/util"
+)
+
+var (
+	connection string
+	privateKey string
+	timeout    time.Duration
+)
+
+var errInvalidAddress = errors.New("invalid address")
+
+var rootCmd = &cobra.Command{
+	Use:           "ens",
+	Short:         "Resolve and manage ENS names",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&connection, "connection", os.Getenv("ENS_CONNECTION"), "URL of the Ethereum node (defaults to $ENS_CONNECTION)")
+	rootCmd.PersistentFlags().StringVar(&privateKey, "private-key", "", "private key with which to sign transactions (defaults to $ENS_PRIVATE_KEY)")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", time.Minute, "timeout for calls to the Ethereum node")
+}
+
+// connect connects to the Ethereum node, returning a client for its chain.
+func connect(ctx context.Context) (*ethclient.Client, *ens.Client, error) {
+	if connection == "" {
+		return nil, nil, errors.New("no connection supplied; use --connection or set ENS_CONNECTION")
+	}
+	backend, err := ethclient.DialContext(ctx, connection)
+	if err != nil {
+		return nil, nil, err
+	}
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	client, err := ens.NewClient(backend, ens.WithChainId(ens.ChainId(chainID.Uint64())))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return backend, client, nil
+}
+
+// transactOpts creates transaction options for the account of the private key.
+func transactOpts(ctx context.Context, backend *ethclient.Client) (*bind.TransactOpts, error) {
+	key := privateKey
+	if key == "" {
+		key = os.Getenv("ENS_PRIVATE_KEY")
+	}
+	if key == "" {
+		return nil, errors.New("no private key supplied; use --private-key or set ENS_PRIVATE_KEY")
+	}
+	ecdsaKey, err := crypto.HexToECDSA(strings.TrimPrefix(key, "0x"))
+	if err != nil {
+		return nil, errors.New("invalid private key")
+	}
+	chainID, err := backend.ChainID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	from := crypto.PubkeyToAddress(ecdsaKey.PublicKey)
+
+	return ens.NewTransactOptsBuilder(backend, from, util.KeySigner(chainID, ecdsaKey)).
+		WithContext(ctx).
+		Build()
+}
+
+// commandContext returns the context for a command, bounded by the timeout.
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(cmd.Context(), timeout)
+}
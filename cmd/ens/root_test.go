@@ -0,0 +1,72 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommands(t *testing.T) {
+	t.Setenv("ENS_CONNECTION", "")
+
+	tests := []struct {
+		name string
+		args []string
+		err  string
+	}{
+		{
+			name: "NoArgs",
+			args: []string{"resolve"},
+			err:  "accepts 1 arg(s), received 0",
+		},
+		{
+			name: "NoConnection",
+			args: []string{"resolve", "ethereum.eth", "--connection", ""},
+			err:  "no connection supplied; use --connection or set ENS_CONNECTION",
+		},
+		{
+			name: "InvalidAddress",
+			args: []string{"reverse", "bad"},
+			err:  "invalid address",
+		},
+		{
+			name: "InvalidContenthash",
+			args: []string{"contenthash", "set", "foo.eth", "bad"},
+			err:  "invalid content hash",
+		},
+		{
+			name: "NotSubdomain",
+			args: []string{"subdomain", "create", "foo.eth"},
+			err:  "foo.eth is not a subdomain",
+		},
+		{
+			name: "TextArgs",
+			args: []string{"text", "set", "foo.eth", "url"},
+			err:  "accepts 3 arg(s), received 2",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rootCmd.SetArgs(test.args)
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			require.EqualError(t, rootCmd.Execute(), test.err)
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/spf13/cobra"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var (
+	subdomainOwner    string
+	subdomainResolver string
+	subdomainTTL      time.Duration
+)
+
+var subdomainCmd = &cobra.Command{
+	Use:   "subdomain",
+	Short: "Manage subdomains",
+}
+
+var subdomainCreateCmd = &cobra.Command{
+	Use:   "create NAME",
+	Short: "Create a subdomain, for example foo.mydomain.eth",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, err := ens.NormaliseDomain(args[0])
+		if err != nil {
+			return err
+		}
+		if ens.DomainLevel(name) < 2 {
+			return fmt.Errorf("%s is not a subdomain", name)
+		}
+		label, err := ens.DomainPart(name, 1)
+		if err != nil {
+			return err
+		}
+		parent := ens.Domain(name)
+
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+		backend, client, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		registry, err := client.Registry()
+		if err != nil {
+			return err
+		}
+		opts, err := transactOpts(ctx, backend)
+		if err != nil {
+			return err
+		}
+
+		owner := opts.From
+		if subdomainOwner != "" {
+			if !common.IsHexAddress(subdomainOwner) {
+				return errInvalidAddress
+			}
+			owner = common.HexToAddress(subdomainOwner)
+		}
+		var resolver common.Address
+		if subdomainResolver != "" {
+			if !common.IsHexAddress(subdomainResolver) {
+				return errInvalidAddress
+			}
+			resolver = common.HexToAddress(subdomainResolver)
+		} else {
+			// Use the resolver of the parent.
+			resolver, err = registry.ResolverAddress(parent)
+			if err != nil {
+				return err
+			}
+		}
+
+		tx, err := registry.SetSubdomainRecord(opts, parent, label, owner, resolver, subdomainTTL)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), tx.Hash().Hex())
+
+		return nil
+	},
+}
+
+func init() {
+	subdomainCreateCmd.Flags().StringVar(&subdomainOwner, "owner", "", "owner of the subdomain (defaults to the sender)")
+	subdomainCreateCmd.Flags().StringVar(&subdomainResolver, "resolver", "", "resolver of the subdomain (defaults to the resolver of its parent)")
+	subdomainCreateCmd.Flags().DurationVar(&subdomainTTL, "ttl", 0, "TTL of the subdomain's records")
+	subdomainCmd.AddCommand(subdomainCreateCmd)
+	rootCmd.AddCommand(subdomainCmd)
+}
@@ -0,0 +1,89 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+var textCmd = &cobra.Command{
+	Use:   "text",
+	Short: "Obtain and set text records",
+}
+
+var textGetCmd = &cobra.Command{
+	Use:   "get NAME KEY",
+	Short: "Obtain a text record of a name",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+		_, client, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		resolver, err := client.Resolver(args[0], ens.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		value, err := resolver.Text(args[1], ens.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), value)
+
+		return nil
+	},
+}
+
+var textSetCmd = &cobra.Command{
+	Use:   "set NAME KEY VALUE",
+	Short: "Set a text record of a name",
+	Args:  cobra.ExactArgs(3),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+		backend, client, err := connect(ctx)
+		if err != nil {
+			return err
+		}
+		resolver, err := client.Resolver(args[0], ens.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+		opts, err := transactOpts(ctx, backend)
+		if err != nil {
+			return err
+		}
+
+		tx, err := resolver.SetText(opts, args[1], args[2])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), tx.Hash().Hex())
+
+		return nil
+	},
+}
+
+func init() {
+	textCmd.AddCommand(textGetCmd)
+	textCmd.AddCommand(textSetCmd)
+	rootCmd.AddCommand(textCmd)
+}
@@ -0,0 +1,318 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mr-tron/base58"
+)
+
+// Coin types with native address formats, as per SLIP-44.
+const (
+	bitcoinCoinType  = uint64(0)
+	litecoinCoinType = uint64(2)
+	dogecoinCoinType = uint64(3)
+	solanaCoinType   = uint64(501)
+)
+
+// utxoCoinFormat is the address format of a Bitcoin-derived coin.
+type utxoCoinFormat struct {
+	p2pkhVersions []byte
+	p2shVersions  []byte
+	// hrp is the human-readable part of segwit addresses, or "" if the coin
+	// does not support segwit.
+	hrp string
+}
+
+var utxoCoinFormats = map[uint64]*utxoCoinFormat{
+	bitcoinCoinType:  {p2pkhVersions: []byte{0x00}, p2shVersions: []byte{0x05}, hrp: "bc"},
+	litecoinCoinType: {p2pkhVersions: []byte{0x30}, p2shVersions: []byte{0x32, 0x05}, hrp: "ltc"},
+	dogecoinCoinType: {p2pkhVersions: []byte{0x1e}, p2shVersions: []byte{0x16}},
+}
+
+// CoinAddressToBytes converts an address in the native format of a coin to
+// the binary format stored by resolvers, as per ENSIP-9 and ENSIP-11.
+// Checksums in the address are validated.
+//
+// Bitcoin, Litecoin and Dogecoin addresses are stored as their output
+// scripts, Solana addresses as their public keys, and the addresses of
+// Ethereum and other EVM chains as their 20 bytes.  An error that wraps
+// ErrFormatUnsupported is returned for other coin types.
+func CoinAddressToBytes(coinType uint64, address string) ([]byte, error) {
+	if coinType == EthereumCoinType || coinType&DefaultCoinType != 0 {
+		return evmAddressToBytes(address)
+	}
+	if coinType == solanaCoinType {
+		data, err := base58.Decode(address)
+		if err != nil || len(data) != 32 {
+			return nil, errors.New("invalid Solana address")
+		}
+		return data, nil
+	}
+	if format, exists := utxoCoinFormats[coinType]; exists {
+		return format.toBytes(address)
+	}
+
+	return nil, wrapError(ErrFormatUnsupported, "no address format for coin type %d", coinType)
+}
+
+// CoinAddressToString converts an address in the binary format stored by
+// resolvers to the native format of its coin.  It is the inverse of
+// CoinAddressToBytes.
+func CoinAddressToString(coinType uint64, address []byte) (string, error) {
+	if coinType == EthereumCoinType || coinType&DefaultCoinType != 0 {
+		if len(address) != common.AddressLength {
+			return "", errors.New("invalid EVM address")
+		}
+		return common.BytesToAddress(address).Hex(), nil
+	}
+	if coinType == solanaCoinType {
+		if len(address) != 32 {
+			return "", errors.New("invalid Solana address")
+		}
+		return base58.Encode(address), nil
+	}
+	if format, exists := utxoCoinFormats[coinType]; exists {
+		return format.toString(address)
+	}
+
+	return "", wrapError(ErrFormatUnsupported, "no address format for coin type %d", coinType)
+}
+
+// SetAddrForCoin sets the address of the domain for a given coin type, with
+// the address in the native format of the coin, for example a bech32 address
+// for Bitcoin.
+func (r *Resolver) SetAddrForCoin(opts *bind.TransactOpts, coinType uint64, address string) (*types.Transaction, error) {
+	data, err := CoinAddressToBytes(coinType, address)
+	if err != nil {
+		return nil, err
+	}
+
+	return r.SetMultiAddress(opts, coinType, data)
+}
+
+// evmAddressToBytes converts a hex address to bytes, validating its EIP-55
+// checksum if it is mixed-case.
+func evmAddressToBytes(address string) ([]byte, error) {
+	if !common.IsHexAddress(address) || !strings.HasPrefix(address, "0x") {
+		return nil, errors.New("invalid EVM address")
+	}
+	hex := address[2:]
+	if hex != strings.ToLower(hex) && hex != strings.ToUpper(hex) && common.HexToAddress(address).Hex() != address {
+		return nil, errors.New("invalid checksum for EVM address")
+	}
+
+	return common.HexToAddress(address).Bytes(), nil
+}
+
+func (f *utxoCoinFormat) toBytes(address string) ([]byte, error) {
+	if f.hrp != "" && strings.HasPrefix(strings.ToLower(address), f.hrp+"1") {
+		version, program, err := decodeSegwitAddress(f.hrp, address)
+		if err != nil {
+			return nil, err
+		}
+		script := []byte{0x00}
+		if version > 0 {
+			script[0] = 0x50 + version
+		}
+		script = append(script, byte(len(program)))
+		return append(script, program...), nil
+	}
+
+	data, err := base58.Decode(address)
+	if err != nil || len(data) != 25 {
+		return nil, errors.New("invalid address")
+	}
+	checksum := doubleSHA256(data[:21])
+	if !bytes.Equal(checksum[:4], data[21:]) {
+		return nil, errors.New("invalid checksum for address")
+	}
+	switch {
+	case bytes.IndexByte(f.p2pkhVersions, data[0]) != -1:
+		return append(append([]byte{0x76, 0xa9, 0x14}, data[1:21]...), 0x88, 0xac), nil
+	case bytes.IndexByte(f.p2shVersions, data[0]) != -1:
+		return append(append([]byte{0xa9, 0x14}, data[1:21]...), 0x87), nil
+	default:
+		return nil, fmt.Errorf("unknown address version %d", data[0])
+	}
+}
+
+func (f *utxoCoinFormat) toString(script []byte) (string, error) {
+	switch {
+	case len(script) == 25 && bytes.HasPrefix(script, []byte{0x76, 0xa9, 0x14}) && bytes.HasSuffix(script, []byte{0x88, 0xac}):
+		return base58Check(f.p2pkhVersions[0], script[3:23]), nil
+	case len(script) == 23 && bytes.HasPrefix(script, []byte{0xa9, 0x14}) && script[22] == 0x87:
+		return base58Check(f.p2shVersions[0], script[2:22]), nil
+	case f.hrp != "" && len(script) >= 4 && (script[0] == 0x00 || (script[0] >= 0x51 && script[0] <= 0x60)) && int(script[1]) == len(script)-2:
+		version := script[0]
+		if version != 0 {
+			version -= 0x50
+		}
+		return encodeSegwitAddress(f.hrp, version, script[2:])
+	default:
+		return "", errors.New("unknown output script")
+	}
+}
+
+func doubleSHA256(data []byte) []byte {
+	first := sha256.Sum256(data)
+	second := sha256.Sum256(first[:])
+	return second[:]
+}
+
+func base58Check(version byte, payload []byte) string {
+	data := append([]byte{version}, payload...)
+	return base58.Encode(append(data, doubleSHA256(data)[:4]...))
+}
+
+// Bech32 is defined by BIP-173, and bech32m by BIP-350.
+const (
+	bech32Charset     = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+	bech32Constant    = 1
+	bech32mConstant   = 0x2bc830a3
+	maxBech32Length   = 90
+	bech32ChecksumLen = 6
+)
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+func bech32HRPExpand(hrp string) []byte {
+	res := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		res = append(res, hrp[i]>>5)
+	}
+	res = append(res, 0)
+	for i := 0; i < len(hrp); i++ {
+		res = append(res, hrp[i]&31)
+	}
+	return res
+}
+
+// convertBits regroups data from one bit width to another.
+func convertBits(data []byte, from uint, to uint, pad bool) ([]byte, error) {
+	acc := uint32(0)
+	bits := uint(0)
+	maxv := uint32(1)<<to - 1
+	res := make([]byte, 0, len(data)*int(from)/int(to)+1)
+	for _, value := range data {
+		if uint32(value)>>from != 0 {
+			return nil, errors.New("invalid data")
+		}
+		acc = acc<<from | uint32(value)
+		bits += from
+		for bits >= to {
+			bits -= to
+			res = append(res, byte(acc>>bits&maxv))
+		}
+	}
+	if pad {
+		if bits > 0 {
+			res = append(res, byte(acc<<(to-bits)&maxv))
+		}
+	} else if bits >= from || acc<<(to-bits)&maxv != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	return res, nil
+}
+
+// decodeSegwitAddress decodes a segwit address, returning its witness
+// version and program.
+func decodeSegwitAddress(hrp string, address string) (byte, []byte, error) {
+	if len(address) > maxBech32Length || (address != strings.ToLower(address) && address != strings.ToUpper(address)) {
+		return 0, nil, errors.New("invalid segwit address")
+	}
+	address = strings.ToLower(address)
+	sep := strings.LastIndexByte(address, '1')
+	if sep < 1 || address[:sep] != hrp || len(address)-sep-1 < bech32ChecksumLen+1 {
+		return 0, nil, errors.New("invalid segwit address")
+	}
+	data := make([]byte, 0, len(address)-sep-1)
+	for i := sep + 1; i < len(address); i++ {
+		idx := strings.IndexByte(bech32Charset, address[i])
+		if idx == -1 {
+			return 0, nil, errors.New("invalid character in segwit address")
+		}
+		data = append(data, byte(idx))
+	}
+
+	version := data[0]
+	constant := uint32(bech32Constant)
+	if version > 0 {
+		constant = bech32mConstant
+	}
+	if bech32Polymod(append(bech32HRPExpand(hrp), data...)) != constant {
+		return 0, nil, errors.New("invalid checksum for segwit address")
+	}
+	if version > 16 {
+		return 0, nil, errors.New("invalid witness version")
+	}
+	program, err := convertBits(data[1:len(data)-bech32ChecksumLen], 5, 8, false)
+	if err != nil {
+		return 0, nil, errors.New("invalid segwit address")
+	}
+	if len(program) < 2 || len(program) > 40 || (version == 0 && len(program) != 20 && len(program) != 32) {
+		return 0, nil, errors.New("invalid witness program length")
+	}
+
+	return version, program, nil
+}
+
+// encodeSegwitAddress encodes a witness version and program as a segwit
+// address.
+func encodeSegwitAddress(hrp string, version byte, program []byte) (string, error) {
+	converted, err := convertBits(program, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	data := append([]byte{version}, converted...)
+	constant := uint32(bech32Constant)
+	if version > 0 {
+		constant = bech32mConstant
+	}
+	values := append(bech32HRPExpand(hrp), data...)
+	polymod := bech32Polymod(append(values, make([]byte, bech32ChecksumLen)...)) ^ constant
+	for i := 0; i < bech32ChecksumLen; i++ {
+		data = append(data, byte(polymod>>(5*(5-i))&31))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+	for _, v := range data {
+		sb.WriteByte(bech32Charset[v])
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoinAddressToBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		coinType uint64
+		address  string
+		res      string
+		err      string
+	}{
+		{
+			name:     "BitcoinP2PKH",
+			coinType: 0,
+			address:  "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa",
+			res:      "0x76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac",
+		},
+		{
+			name:     "BitcoinP2SH",
+			coinType: 0,
+			address:  "3Ai1JZ8pdJb2ksieUV8FsxSNVJCpoPi8W6",
+			res:      "0xa91462e907b15cbf27d5425399ebf6f0fb50ebb88f1887",
+		},
+		{
+			name:     "BitcoinSegwit",
+			coinType: 0,
+			address:  "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",
+			res:      "0x0014751e76e8199196d454941c45d1b3a323f1433bd6",
+		},
+		{
+			name:     "BitcoinSegwitUpperCase",
+			coinType: 0,
+			address:  "BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4",
+			res:      "0x0014751e76e8199196d454941c45d1b3a323f1433bd6",
+		},
+		{
+			name:     "BitcoinTaproot",
+			coinType: 0,
+			address:  "bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0",
+			res:      "0x512079be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+		},
+		{
+			name:     "BitcoinBadChecksum",
+			coinType: 0,
+			address:  "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb",
+			err:      "invalid checksum for address",
+		},
+		{
+			name:     "BitcoinSegwitBadChecksum",
+			coinType: 0,
+			address:  "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5",
+			err:      "invalid checksum for segwit address",
+		},
+		{
+			name:     "BitcoinSegwitMixedCase",
+			coinType: 0,
+			address:  "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3T4",
+			err:      "invalid segwit address",
+		},
+		{
+			name:     "BitcoinLitecoinVersion",
+			coinType: 0,
+			address:  "LaMT348PWRnrqeeWArpwQPbuanpXDZGEUz",
+			err:      "unknown address version 48",
+		},
+		{
+			name:     "Litecoin",
+			coinType: 2,
+			address:  "LaMT348PWRnrqeeWArpwQPbuanpXDZGEUz",
+			res:      "0x76a914a5f4d12ce3685781b227c1f39548ddef429e978388ac",
+		},
+		{
+			name:     "Solana",
+			coinType: 501,
+			address:  "11111111111111111111111111111111",
+			res:      "0x0000000000000000000000000000000000000000000000000000000000000000",
+		},
+		{
+			name:     "SolanaInvalid",
+			coinType: 501,
+			address:  "1111",
+			err:      "invalid Solana address",
+		},
+		{
+			name:     "Ethereum",
+			coinType: 60,
+			address:  "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			res:      "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		},
+		{
+			name:     "EthereumLowerCase",
+			coinType: 60,
+			address:  "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+			res:      "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		},
+		{
+			name:     "EthereumBadChecksum",
+			coinType: 60,
+			address:  "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD",
+			err:      "invalid checksum for EVM address",
+		},
+		{
+			name:     "Base",
+			coinType: CoinTypeForChain(BaseMainnet),
+			address:  "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+			res:      "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		},
+		{
+			name:     "Unsupported",
+			coinType: 145,
+			address:  "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a",
+			err:      "no address format for coin type 145",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := CoinAddressToBytes(test.coinType, test.address)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.res, hexutil.Encode(res))
+
+			str, err := CoinAddressToString(test.coinType, res)
+			require.NoError(t, err)
+			require.Equal(t, res, mustCoinAddressToBytes(t, test.coinType, str))
+		})
+	}
+}
+
+func mustCoinAddressToBytes(t *testing.T, coinType uint64, address string) []byte {
+	t.Helper()
+	res, err := CoinAddressToBytes(coinType, address)
+	require.NoError(t, err)
+	return res
+}
+
+func TestCoinAddressToString(t *testing.T) {
+	str, err := CoinAddressToString(0, hexutil.MustDecode("0x0014751e76e8199196d454941c45d1b3a323f1433bd6"))
+	require.NoError(t, err)
+	require.Equal(t, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", str)
+
+	str, err = CoinAddressToString(0, hexutil.MustDecode("0x76a91462e907b15cbf27d5425399ebf6f0fb50ebb88f1888ac"))
+	require.NoError(t, err)
+	require.Equal(t, "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", str)
+
+	_, err = CoinAddressToString(0, []byte{0x01, 0x02})
+	require.EqualError(t, err, "unknown output script")
+
+	_, err = CoinAddressToString(145, []byte{0x01, 0x02})
+	require.ErrorIs(t, err, ErrFormatUnsupported)
+}
+
+func TestSetAddrForCoin(t *testing.T) {
+	m := newMockENS()
+	m.register("coin.eth", common.HexToAddress("0x01"), UnknownAddress)
+	var set []byte
+	m.backend.contracts[m.resolverAddr].on("setAddr0", func(args []interface{}) ([]interface{}, error) {
+		require.Zero(t, args[1].(*big.Int).Sign())
+		set = args[2].([]byte)
+		return []interface{}{}, nil
+	})
+
+	resolver, err := NewResolver(m.backend, "coin.eth", EthereumMainnet)
+	require.NoError(t, err)
+
+	_, err = resolver.SetAddrForCoin(testTransactOpts(t), 0, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5")
+	require.EqualError(t, err, "invalid checksum for segwit address")
+
+	_, err = resolver.SetAddrForCoin(testTransactOpts(t), 0, "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4")
+	require.NoError(t, err)
+	require.Equal(t, hexutil.MustDecode("0x0014751e76e8199196d454941c45d1b3a323f1433bd6"), set)
+}
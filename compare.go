@@ -0,0 +1,60 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import "strings"
+
+// Equal returns true if two names are the same once normalized with
+// NormaliseDomain, which applies UTS46 IDNA mapping, so that names that look
+// identical, for example differing only in case or in the presence of the
+// emoji presentation selector U+FE0F, are treated as the same name.  This is
+// not full ENSIP-15 normalization, so some names that ENSIP-15 would treat as
+// the same are not equal.  Names that cannot be normalized are only equal to
+// themselves.
+func Equal(a string, b string) bool {
+	if a == b {
+		return true
+	}
+	normalizedA, err := NormaliseDomain(a)
+	if err != nil {
+		return false
+	}
+	normalizedB, err := NormaliseDomain(b)
+	if err != nil {
+		return false
+	}
+
+	return normalizedA == normalizedB
+}
+
+// Compare compares two names by their forms normalized with NormaliseDomain,
+// returning -1 if a sorts before b, 0 if they are equal as per Equal and +1
+// if a sorts after b.  Names that cannot be normalized sort after all names
+// that can, in the order of the names as supplied.  Compare is suitable for
+// use with slices.SortFunc and for deduplicating sorted names.
+func Compare(a string, b string) int {
+	normalizedA, errA := NormaliseDomain(a)
+	normalizedB, errB := NormaliseDomain(b)
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return 1
+	case errB != nil:
+		return -1
+	default:
+		return strings.Compare(normalizedA, normalizedB)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEqualCompare(t *testing.T) {
+	tests := []struct {
+		name    string
+		a       string
+		b       string
+		equal   bool
+		compare int
+	}{
+		{
+			name:  "Identical",
+			a:     "vitalik.eth",
+			b:     "vitalik.eth",
+			equal: true,
+		},
+		{
+			name:  "Case",
+			a:     "Vitalik.ETH",
+			b:     "vitalik.eth",
+			equal: true,
+		},
+		{
+			name:  "Fullwidth",
+			a:     "ｖｉｔａｌｉｋ.eth",
+			b:     "vitalik.eth",
+			equal: true,
+		},
+		{
+			name:  "EmojiPresentation",
+			a:     "❤️.eth",
+			b:     "❤.eth",
+			equal: true,
+		},
+		{
+			name:  "ZWJSequence",
+			a:     "\U0001f468‍\U0001f4bb.eth",
+			b:     "\U0001f468‍\U0001f4bb️.eth",
+			equal: true,
+		},
+		{
+			name:  "Composed",
+			a:     "café.eth",
+			b:     "café.eth",
+			equal: true,
+		},
+		{
+			name:    "SharpS",
+			a:       "straße.eth",
+			b:       "strasse.eth",
+			compare: 1,
+		},
+		{
+			name:    "Different",
+			a:       "alice.eth",
+			b:       "Bob.eth",
+			compare: -1,
+		},
+		{
+			name:    "InvalidFirst",
+			a:       "a。b�.eth",
+			b:       "vitalik.eth",
+			compare: 1,
+		},
+		{
+			name:    "InvalidSecond",
+			a:       "vitalik.eth",
+			b:       "a�.eth",
+			compare: -1,
+		},
+		{
+			name:  "InvalidIdentical",
+			a:     "a�.eth",
+			b:     "a�.eth",
+			equal: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.equal, Equal(test.a, test.b))
+			require.Equal(t, test.equal, Equal(test.b, test.a))
+			require.Equal(t, test.compare, Compare(test.a, test.b))
+			require.Equal(t, -test.compare, Compare(test.b, test.a))
+		})
+	}
+}
+
+func TestCompareSort(t *testing.T) {
+	names := []string{"bob.eth", "❤️.eth", "Alice.eth", "a�.eth", "❤.eth", "alice.eth"}
+	sort.SliceStable(names, func(i, j int) bool {
+		return Compare(names[i], names[j]) < 0
+	})
+	require.Equal(t, []string{"Alice.eth", "alice.eth", "bob.eth", "❤️.eth", "❤.eth", "a�.eth"}, names)
+}
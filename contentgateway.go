@@ -0,0 +1,213 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multibase"
+	"github.com/multiformats/go-multihash"
+	"github.com/pkg/errors"
+	"github.com/wealdtech/go-multicodec"
+	"golang.org/x/net/idna"
+)
+
+// arweaveCodec is the multicodec of Arweave contenthashes, which is not known
+// to the multicodec library.
+const arweaveCodec = 0xb29910
+
+// ContentGateway is an HTTP gateway that serves content referenced by
+// contenthashes.
+type ContentGateway struct {
+	// Template is the template for URLs served by the gateway.  "{protocol}"
+	// is replaced by the protocol of the content, "{id}" by its identifier
+	// and "{name}" by the ENS name.  Identifiers are valid DNS labels, so
+	// can be used in the host by subdomain-style gateways.
+	Template string
+	// Protocols are the protocols served by the gateway: "ipfs", "ipns",
+	// "bzz" and "arweave".
+	Protocols []string
+	// TLD, if set, limits the gateway to names in the given top-level
+	// domain.
+	TLD string
+}
+
+var (
+	// EthLimoGateway is the eth.limo gateway, which resolves .eth names
+	// itself.
+	EthLimoGateway = &ContentGateway{
+		Template:  "https://{name}.limo/",
+		Protocols: []string{"ipfs", "ipns", "bzz", "arweave"},
+		TLD:       "eth",
+	}
+	// IPFSGateway is the ipfs.io path-style gateway.
+	IPFSGateway = &ContentGateway{
+		Template:  "https://ipfs.io/{protocol}/{id}/",
+		Protocols: []string{"ipfs", "ipns"},
+	}
+	// DwebLinkGateway is the dweb.link subdomain-style gateway, which gives
+	// each piece of content its own origin.
+	DwebLinkGateway = &ContentGateway{
+		Template:  "https://{id}.{protocol}.dweb.link/",
+		Protocols: []string{"ipfs", "ipns"},
+	}
+	// ArweaveGateway is the arweave.net gateway.
+	ArweaveGateway = &ContentGateway{
+		Template:  "https://arweave.net/{id}",
+		Protocols: []string{"arweave"},
+	}
+	// SwarmGateway is the Swarm public gateway.
+	SwarmGateway = &ContentGateway{
+		Template:  "https://api.gateway.ethswarm.org/bzz/{id}/",
+		Protocols: []string{"bzz"},
+	}
+
+	// DefaultContentGateways are the gateways used by ContenthashURLs if none
+	// are supplied.
+	DefaultContentGateways = []*ContentGateway{
+		EthLimoGateway,
+		IPFSGateway,
+		DwebLinkGateway,
+		ArweaveGateway,
+		SwarmGateway,
+	}
+)
+
+// ContenthashURLs turns the EIP-1577 contenthash of a name in to URLs from
+// which its content can be fetched, one for each of the gateways that serve
+// the content in the order supplied.  If no gateways are supplied then
+// DefaultContentGateways are used.  IPFS and IPNS content is identified by
+// its CIDv1 in base32 and base36 respectively, converting from CIDv0 if
+// required.
+func ContenthashURLs(name string, contenthash []byte, gateways ...*ContentGateway) ([]string, error) {
+	protocol, id, err := contenthashIdentifier(contenthash)
+	if err != nil {
+		return nil, err
+	}
+	if len(gateways) == 0 {
+		gateways = DefaultContentGateways
+	}
+	host, err := idna.ToASCII(name)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid name")
+	}
+
+	urls := make([]string, 0)
+	for _, gateway := range gateways {
+		if !gateway.serves(protocol, name) {
+			continue
+		}
+		urls = append(urls, strings.NewReplacer(
+			"{protocol}", protocol,
+			"{id}", id,
+			"{name}", host,
+		).Replace(gateway.Template))
+	}
+	if len(urls) == 0 {
+		return nil, wrapError(ErrFormatUnsupported, "no gateway serves %s content", protocol)
+	}
+
+	return urls, nil
+}
+
+// ContentURLs obtains the contenthash of the domain and turns it in to URLs
+// from which its content can be fetched, as per ContenthashURLs.
+func (r *Resolver) ContentURLs(gateways ...*ContentGateway) ([]string, error) {
+	contenthash, err := r.Contenthash()
+	if err != nil {
+		return nil, err
+	}
+	if len(contenthash) == 0 {
+		return nil, errors.New("no contenthash")
+	}
+
+	return ContenthashURLs(r.domain, contenthash, gateways...)
+}
+
+// serves returns true if the gateway serves content of the given protocol
+// for the given name.
+func (g *ContentGateway) serves(protocol string, name string) bool {
+	if strings.Contains(g.Template, "{name}") {
+		if name == "" || (g.TLD != "" && !strings.HasSuffix(name, "."+g.TLD)) {
+			return false
+		}
+	}
+	for _, gatewayProtocol := range g.Protocols {
+		if gatewayProtocol == protocol {
+			return true
+		}
+	}
+
+	return false
+}
+
+// contenthashIdentifier returns the gateway protocol and content identifier
+// of an EIP-1577 contenthash.
+func contenthashIdentifier(contenthash []byte) (string, string, error) {
+	data, codec, err := multicodec.RemoveCodec(contenthash)
+	if err != nil {
+		return "", "", err
+	}
+	if codec == arweaveCodec {
+		return "arweave", base64.RawURLEncoding.EncodeToString(data), nil
+	}
+	codecName, err := multicodec.Name(codec)
+	if err != nil {
+		return "", "", err
+	}
+
+	switch codecName {
+	case "ipfs-ns":
+		thisCID, err := cid.Parse(data)
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to parse CID")
+		}
+		str, err := cid.NewCidV1(thisCID.Type(), thisCID.Hash()).StringOfBase(multibase.Base32)
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to obtain base32 representation")
+		}
+		return "ipfs", str, nil
+	case "ipns-ns":
+		thisCID, err := cid.Parse(data)
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to parse CID")
+		}
+		str, err := cid.NewCidV1(thisCID.Type(), thisCID.Hash()).StringOfBase(multibase.Base36)
+		if err != nil {
+			return "", "", errors.Wrap(err, "failed to obtain base36 representation")
+		}
+		return "ipns", str, nil
+	case "swarm-ns":
+		id, offset := binary.Uvarint(data)
+		if id == 0 {
+			return "", "", wrapError(ErrFormatUnsupported, "unknown CID")
+		}
+		data, _, err := multicodec.RemoveCodec(data[offset:])
+		if err != nil {
+			return "", "", err
+		}
+		decodedMHash, err := multihash.Decode(data)
+		if err != nil {
+			return "", "", err
+		}
+		return "bzz", fmt.Sprintf("%x", decodedMHash.Digest), nil
+	default:
+		return "", "", wrapError(ErrFormatUnsupported, "no gateway serves %s content", codecName)
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestContenthashURLs(t *testing.T) {
+	custom := &ContentGateway{
+		Template:  "https://gateway.example.com/{protocol}/{id}?name={name}",
+		Protocols: []string{"ipfs"},
+	}
+
+	tests := []struct {
+		name        string
+		domain      string
+		contenthash []byte
+		gateways    []*ContentGateway
+		urls        []string
+		err         string
+		errIs       error
+	}{
+		{
+			name:        "IPFSCIDv0",
+			domain:      "vitalik.eth",
+			contenthash: _hexStr("e3010170122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f"),
+			urls: []string{
+				"https://vitalik.eth.limo/",
+				"https://ipfs.io/ipfs/bafybeibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4/",
+				"https://bafybeibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4.ipfs.dweb.link/",
+			},
+		},
+		{
+			name:        "IPFSNotEth",
+			domain:      "example.xyz",
+			contenthash: _hexStr("e3010170122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f"),
+			gateways:    []*ContentGateway{EthLimoGateway, DwebLinkGateway},
+			urls: []string{
+				"https://bafybeibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4.ipfs.dweb.link/",
+			},
+		},
+		{
+			name:        "IPNS",
+			domain:      "test.eth",
+			contenthash: _hexStr("e5010172002408011220950b8f62b925ecc50247cc8de1084b43f854fbc452894d9a1a97d7f27d0addb8"),
+			gateways:    []*ContentGateway{IPFSGateway, DwebLinkGateway},
+			urls: []string{
+				"https://ipfs.io/ipns/k51qzi5uqu5djwbl0zcd4g9onue26a8nq97c0m9wp6kir1gibuyjxpkqpoxwag/",
+				"https://k51qzi5uqu5djwbl0zcd4g9onue26a8nq97c0m9wp6kir1gibuyjxpkqpoxwag.ipns.dweb.link/",
+			},
+		},
+		{
+			name:        "Swarm",
+			domain:      "test.eth",
+			contenthash: _hexStr("e40101fa011b20d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162"),
+			urls: []string{
+				"https://test.eth.limo/",
+				"https://api.gateway.ethswarm.org/bzz/d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162/",
+			},
+		},
+		{
+			name:        "Arweave",
+			domain:      "test.eth",
+			contenthash: _hexStr("90b2ca05000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"),
+			gateways:    []*ContentGateway{ArweaveGateway},
+			urls: []string{
+				"https://arweave.net/AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8",
+			},
+		},
+		{
+			name:        "Custom",
+			domain:      "test.eth",
+			contenthash: _hexStr("e3010170122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f"),
+			gateways:    []*ContentGateway{custom},
+			urls: []string{
+				"https://gateway.example.com/ipfs/bafybeibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4?name=test.eth",
+			},
+		},
+		{
+			name:        "Unicode",
+			domain:      "ñandú.eth",
+			contenthash: _hexStr("e3010170122029f2d17be6139079dc48696d1f582a8530eb9805b561eda517e22a892c7e3f1f"),
+			gateways:    []*ContentGateway{EthLimoGateway},
+			urls:        []string{"https://xn--and-6ma2c.eth.limo/"},
+		},
+		{
+			name:        "NoGateway",
+			domain:      "test.eth",
+			contenthash: _hexStr("e40101fa011b20d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162"),
+			gateways:    []*ContentGateway{IPFSGateway},
+			err:         "no gateway serves bzz content",
+			errIs:       ErrFormatUnsupported,
+		},
+		{
+			name:        "Onion",
+			domain:      "test.eth",
+			contenthash: _hexStr("bc037a716b746c776934666563766f367269"),
+			err:         "no gateway serves onion content",
+			errIs:       ErrFormatUnsupported,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			urls, err := ContenthashURLs(test.domain, test.contenthash, test.gateways...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				if test.errIs != nil {
+					require.True(t, errors.Is(err, test.errIs))
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.urls, urls)
+		})
+	}
+}
@@ -20,7 +20,9 @@ import (
 	"encoding/binary"
 	"encoding/hex"
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/ipfs/go-cid"
 	"github.com/multiformats/go-multibase"
@@ -29,13 +31,163 @@ import (
 	"github.com/wealdtech/go-multicodec"
 )
 
+// ContenthashCodec converts between the text and binary forms of EIP-1577
+// contenthashes for a protocol.  The binary form is the protocol's multicodec
+// value as a varint followed by the protocol's data.
+type ContenthashCodec struct {
+	// Code is the multicodec value of the protocol, for example 0xe3 for
+	// ipfs-ns.
+	Code uint64
+	// Names are the names of the protocol in the text form, for example
+	// "ipfs" for "ipfs://..." and "/ipfs/...".
+	Names []string
+	// Encode converts the data of the text form, which follows the name, in
+	// to the data of the binary form, which follows the code.
+	Encode func(data string) ([]byte, error)
+	// Decode converts the data of the binary form in to the text form in
+	// full, for example "ipfs://..." or "/ipfs/...".
+	Decode func(data []byte) (string, error)
+}
+
+var (
+	contenthashCodecsMu sync.RWMutex
+	// contenthashCodecs are the codecs keyed by multicodec value.
+	contenthashCodecs = map[uint64]*ContenthashCodec{}
+	// contenthashCodecNames are the multicodec values of the codecs keyed by
+	// name.
+	contenthashCodecNames = map[string]uint64{}
+)
+
+func init() {
+	for _, codec := range []*ContenthashCodec{
+		{Code: multicodec.MustID("ipfs-ns"), Names: []string{"ipfs"}, Encode: encodeIPFSContenthash("IPFS"), Decode: decodeIPFSContenthash},
+		{Code: multicodec.MustID("ipns-ns"), Names: []string{"ipns"}, Encode: encodeIPFSContenthash("IPNS"), Decode: decodeIPNSContenthash},
+		{Code: multicodec.MustID("swarm-ns"), Names: []string{"swarm", "bzz"}, Encode: encodeSwarmContenthash, Decode: decodeSwarmContenthash},
+		{Code: multicodec.MustID("onion"), Names: []string{"onion"}, Encode: encodeOnionContenthash(16), Decode: decodeOnionContenthash("onion")},
+		{Code: multicodec.MustID("onion3"), Names: []string{"onion3"}, Encode: encodeOnionContenthash(56), Decode: decodeOnionContenthash("onion3")},
+		{Code: multicodec.MustID("skynet-ns"), Names: []string{"sia"}, Encode: encodeSkynetContenthash, Decode: decodeSkynetContenthash},
+	} {
+		if err := RegisterContenthashCodec(codec); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// RegisterContenthashCodec registers the codec for a protocol, so that
+// contenthashes of the protocol are converted by StringToContenthash and
+// ContenthashToString.  The codec replaces any existing codec with the same
+// multicodec value, including the built-in codecs for IPFS, IPNS, Swarm, Tor
+// and Skynet.
+func RegisterContenthashCodec(codec *ContenthashCodec) error {
+	if codec == nil {
+		return errors.New("no codec supplied")
+	}
+	if codec.Encode == nil || codec.Decode == nil {
+		return errors.New("codec requires both encode and decode functions")
+	}
+	if len(codec.Names) == 0 {
+		return errors.New("no codec names supplied")
+	}
+	for _, name := range codec.Names {
+		if name == "" || strings.ContainsAny(name, "/:") {
+			return fmt.Errorf("invalid codec name %q", name)
+		}
+	}
+
+	contenthashCodecsMu.Lock()
+	defer contenthashCodecsMu.Unlock()
+
+	for _, name := range codec.Names {
+		if code, exists := contenthashCodecNames[name]; exists && code != codec.Code {
+			return fmt.Errorf("codec name %s is in use by codec 0x%x", name, code)
+		}
+	}
+
+	unregisterContenthashCodec(codec.Code)
+	// Take a copy so that later changes by the caller have no effect.
+	registered := *codec
+	registered.Names = append([]string{}, codec.Names...)
+	contenthashCodecs[codec.Code] = &registered
+	for _, name := range registered.Names {
+		contenthashCodecNames[name] = codec.Code
+	}
+
+	return nil
+}
+
+// UnregisterContenthashCodec removes the codec for a multicodec value.
+func UnregisterContenthashCodec(code uint64) {
+	contenthashCodecsMu.Lock()
+	unregisterContenthashCodec(code)
+	contenthashCodecsMu.Unlock()
+}
+
+// unregisterContenthashCodec removes the codec for a multicodec value.  It
+// must be called with the lock held.
+func unregisterContenthashCodec(code uint64) {
+	existing, exists := contenthashCodecs[code]
+	if !exists {
+		return
+	}
+	for _, name := range existing.Names {
+		delete(contenthashCodecNames, name)
+	}
+	delete(contenthashCodecs, code)
+}
+
+// ContenthashCodecFor returns the codec for a multicodec value, if one has
+// been registered.
+func ContenthashCodecFor(code uint64) (*ContenthashCodec, bool) {
+	contenthashCodecsMu.RLock()
+	defer contenthashCodecsMu.RUnlock()
+
+	codec, exists := contenthashCodecs[code]
+	if !exists {
+		return nil, false
+	}
+	// Return a copy so that the caller cannot alter the registered codec.
+	res := *codec
+	res.Names = append([]string{}, codec.Names...)
+
+	return &res, true
+}
+
+// ContenthashCodecs returns the multicodec values of the registered codecs,
+// in increasing order.
+func ContenthashCodecs() []uint64 {
+	contenthashCodecsMu.RLock()
+	defer contenthashCodecsMu.RUnlock()
+
+	codes := make([]uint64, 0, len(contenthashCodecs))
+	for code := range contenthashCodecs {
+		codes = append(codes, code)
+	}
+	sort.Slice(codes, func(i, j int) bool { return codes[i] < codes[j] })
+
+	return codes
+}
+
+// contenthashCodecNamed returns the codec with the given name, if one has
+// been registered.
+func contenthashCodecNamed(name string) (*ContenthashCodec, bool) {
+	contenthashCodecsMu.RLock()
+	defer contenthashCodecsMu.RUnlock()
+
+	code, exists := contenthashCodecNames[name]
+	if !exists {
+		return nil, false
+	}
+
+	return contenthashCodecs[code], true
+}
+
 // StringToContenthash turns EIP-1577 text format in to EIP-1577 binary format.
 func StringToContenthash(text string) ([]byte, error) {
 	if text == "" {
 		return nil, errors.New("no content hash")
 	}
 
-	var codec string
+	var codecName string
 	var data string
 	if strings.Contains(text, "://") {
 		// URL style.
@@ -43,7 +195,7 @@ func StringToContenthash(text string) ([]byte, error) {
 		if len(bits) != 2 {
 			return nil, fmt.Errorf("invalid content hash")
 		}
-		codec = bits[0]
+		codecName = bits[0]
 		data = bits[1]
 	} else {
 		// Path style.
@@ -51,186 +203,174 @@ func StringToContenthash(text string) ([]byte, error) {
 		if len(bits) != 3 {
 			return nil, errors.New("invalid content hash")
 		}
-		codec = bits[1]
+		codecName = bits[1]
 		data = bits[2]
 	}
-	if codec == "" {
+	if codecName == "" {
 		return nil, errors.New("codec missing")
 	}
 	if data == "" {
 		return nil, errors.New("data missing")
 	}
 
-	res := make([]byte, 0)
-	switch codec {
-	case "ipfs":
-		content, err := cid.Parse(data)
+	codec, exists := contenthashCodecNamed(codecName)
+	if !exists {
+		return nil, wrapError(ErrFormatUnsupported, "unknown codec %s", codecName)
+	}
+	encoded, err := codec.Encode(data)
+	if err != nil {
+		return nil, err
+	}
+
+	res := binary.AppendUvarint(make([]byte, 0, binary.MaxVarintLen64+len(encoded)), codec.Code)
+	return append(res, encoded...), nil
+}
+
+// ContenthashToString turns EIP-1577 binary format in to EIP-1577 text format.
+func ContenthashToString(bytes []byte) (string, error) {
+	data, code, err := multicodec.RemoveCodec(bytes)
+	if err != nil {
+		return "", err
+	}
+
+	codec, exists := ContenthashCodecFor(code)
+	if !exists {
+		codecName, err := multicodec.Name(code)
 		if err != nil {
-			return nil, errors.Wrap(err, "invalid IPFS data")
-		}
-		// Namespace.
-		buf := make([]byte, binary.MaxVarintLen64)
-		size := binary.PutUvarint(buf, multicodec.MustID("ipfs-ns"))
-		res = append(res, buf[0:size]...)
-		if data[0:2] == "Qm" {
-			// CID v0 needs additional headers.
-			size = binary.PutUvarint(buf, 1)
-			res = append(res, buf[0:size]...)
-			size = binary.PutUvarint(buf, multicodec.MustID("dag-pb"))
-			res = append(res, buf[0:size]...)
-			res = append(res, content.Bytes()...)
-		} else {
-			res = append(res, content.Bytes()...)
+			return "", err
 		}
-	case "ipns":
+		return "", wrapError(ErrFormatUnsupported, "unknown codec name %s", codecName)
+	}
+
+	return codec.Decode(data)
+}
+
+// encodeIPFSContenthash returns a function that encodes the CID of IPFS or
+// IPNS content.
+func encodeIPFSContenthash(protocol string) func(data string) ([]byte, error) {
+	return func(data string) ([]byte, error) {
 		content, err := cid.Parse(data)
 		if err != nil {
-			return nil, errors.Wrap(err, "invalid IPNS data")
+			return nil, errors.Wrap(err, fmt.Sprintf("invalid %s data", protocol))
 		}
-		// Namespace.
-		buf := make([]byte, binary.MaxVarintLen64)
-		size := binary.PutUvarint(buf, multicodec.MustID("ipns-ns"))
-		res = append(res, buf[0:size]...)
-		if data[0:2] == "Qm" {
+		if strings.HasPrefix(data, "Qm") {
 			// CID v0 needs additional headers.
-			size = binary.PutUvarint(buf, 1)
-			res = append(res, buf[0:size]...)
-			size = binary.PutUvarint(buf, multicodec.MustID("dag-pb"))
-			res = append(res, buf[0:size]...)
-			res = append(res, content.Bytes()...)
-		} else {
-			res = append(res, content.Bytes()...)
-		}
-	case "swarm", "bzz":
-		// Namespace.
-		buf := make([]byte, binary.MaxVarintLen64)
-		size := binary.PutUvarint(buf, multicodec.MustID("swarm-ns"))
-		res = append(res, buf[0:size]...)
-		size = binary.PutUvarint(buf, 1)
-		res = append(res, buf[0:size]...)
-		size = binary.PutUvarint(buf, multicodec.MustID("swarm-manifest"))
-		res = append(res, buf[0:size]...)
-		// Hash.
-		hashData, err := hex.DecodeString(data)
-		if err != nil {
-			return nil, errors.Wrap(err, "invalid hex")
-		}
-		hash, err := multihash.Encode(hashData, multihash.KECCAK_256)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to hash")
-		}
-		res = append(res, hash...)
-	case "onion":
-		// Codec.
-		buf := make([]byte, binary.MaxVarintLen64)
-		size := binary.PutUvarint(buf, multicodec.MustID("onion"))
-		res = append(res, buf[0:size]...)
-
-		// Address.
-		if len(data) != 16 {
-			return nil, errors.New("onion address should be 16 characters")
-		}
-		res = append(res, []byte(data)...)
-	case "onion3":
-		// Codec.
-		buf := make([]byte, binary.MaxVarintLen64)
-		size := binary.PutUvarint(buf, multicodec.MustID("onion3"))
-		res = append(res, buf[0:size]...)
-
-		// Address.
-		if len(data) != 56 {
-			return nil, errors.New("onion address should be 56 characters")
+			res := binary.AppendUvarint(nil, 1)
+			res = binary.AppendUvarint(res, multicodec.MustID("dag-pb"))
+			return append(res, content.Bytes()...), nil
 		}
-		res = append(res, []byte(data)...)
-	case "sia":
-		// Codec.
-		buf := make([]byte, binary.MaxVarintLen64)
-		size := binary.PutUvarint(buf, multicodec.MustID("skynet-ns"))
-		res = append(res, buf[0:size]...)
-
-		// Skylink.
-		var err error
-		var decoded []byte
-		switch len(data) {
-		case 46:
-			decoded, err = base64.RawURLEncoding.DecodeString(data)
-			if err != nil {
-				return nil, errors.New("skylink not correctly encoded")
-			}
-		case 55:
-			decoded, err = base32.HexEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(data))
-			if err != nil {
-				return nil, errors.New("skylink not correctly encoded")
-			}
-		default:
-			return nil, errors.New("skylinks should be either 46 or 55 characters, depending on whether it is base64 or base32 encoded")
-		}
-		res = append(res, decoded...)
-	default:
-		return nil, fmt.Errorf("unknown codec %s", codec)
+
+		return content.Bytes(), nil
 	}
+}
 
-	return res, nil
+// decodeIPFSContenthash decodes the CID of IPFS content.
+func decodeIPFSContenthash(data []byte) (string, error) {
+	thisCID, err := cid.Parse(data)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse CID")
+	}
+	str, err := thisCID.StringOfBase(multibase.Base36)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to obtain base36 representation")
+	}
+
+	return fmt.Sprintf("/ipfs/%s", str), nil
 }
 
-// ContenthashToString turns EIP-1577 binary format in to EIP-1577 text format.
-func ContenthashToString(bytes []byte) (string, error) {
-	data, codec, err := multicodec.RemoveCodec(bytes)
+// decodeIPNSContenthash decodes the CID of IPNS content.
+func decodeIPNSContenthash(data []byte) (string, error) {
+	thisCID, err := cid.Parse(data)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to parse CID")
+	}
+	res, err := multibase.Encode(multibase.Base36, thisCID.Bytes())
+	if err != nil {
+		return "", errors.Wrap(err, "unknown multibase")
+	}
+
+	return fmt.Sprintf("/ipns/%s", res), nil
+}
+
+// encodeSwarmContenthash encodes the hash of a Swarm manifest.
+func encodeSwarmContenthash(data string) ([]byte, error) {
+	res := binary.AppendUvarint(nil, 1)
+	res = binary.AppendUvarint(res, multicodec.MustID("swarm-manifest"))
+	hashData, err := hex.DecodeString(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid hex")
+	}
+	hash, err := multihash.Encode(hashData, multihash.KECCAK_256)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to hash")
+	}
+
+	return append(res, hash...), nil
+}
+
+// decodeSwarmContenthash decodes the hash of a Swarm manifest.
+func decodeSwarmContenthash(data []byte) (string, error) {
+	id, offset := binary.Uvarint(data)
+	if id == 0 {
+		return "", wrapError(ErrFormatUnsupported, "unknown CID")
+	}
+	data, subCodec, err := multicodec.RemoveCodec(data[offset:])
+	if err != nil {
+		return "", err
+	}
+	_, err = multicodec.Name(subCodec)
 	if err != nil {
 		return "", err
 	}
-	codecName, err := multicodec.Name(codec)
+	decodedMHash, err := multihash.Decode(data)
 	if err != nil {
 		return "", err
 	}
 
-	switch codecName {
-	case "ipfs-ns":
-		thisCID, err := cid.Parse(data)
-		if err != nil {
-			return "", errors.Wrap(err, "failed to parse CID")
-		}
-		str, err := thisCID.StringOfBase(multibase.Base36)
-		if err != nil {
-			return "", errors.Wrap(err, "failed to obtain base36 representation")
-		}
-		return fmt.Sprintf("/ipfs/%s", str), nil
-	case "ipns-ns":
-		thisCID, err := cid.Parse(data)
-		if err != nil {
-			return "", errors.Wrap(err, "failed to parse CID")
-		}
-		res, err := multibase.Encode(multibase.Base36, thisCID.Bytes())
-		if err != nil {
-			return "", errors.Wrap(err, "unknown multibase")
-		}
-		return fmt.Sprintf("/ipns/%s", res), nil
-	case "swarm-ns":
-		id, offset := binary.Uvarint(data)
-		if id == 0 {
-			return "", fmt.Errorf("unknown CID")
-		}
-		data, subCodec, err := multicodec.RemoveCodec(data[offset:])
-		if err != nil {
-			return "", err
+	return fmt.Sprintf("bzz://%x", decodedMHash.Digest), nil
+}
+
+// encodeOnionContenthash returns a function that encodes a Tor onion
+// address of the given length.
+func encodeOnionContenthash(length int) func(data string) ([]byte, error) {
+	return func(data string) ([]byte, error) {
+		if len(data) != length {
+			return nil, fmt.Errorf("onion address should be %d characters", length)
 		}
-		_, err = multicodec.Name(subCodec)
+
+		return []byte(data), nil
+	}
+}
+
+// decodeOnionContenthash returns a function that decodes a Tor onion address.
+func decodeOnionContenthash(name string) func(data []byte) (string, error) {
+	return func(data []byte) (string, error) {
+		return fmt.Sprintf("%s://%s", name, string(data)), nil
+	}
+}
+
+// encodeSkynetContenthash encodes a Skynet skylink, which may be base64 or
+// base32 encoded.
+func encodeSkynetContenthash(data string) ([]byte, error) {
+	switch len(data) {
+	case 46:
+		decoded, err := base64.RawURLEncoding.DecodeString(data)
 		if err != nil {
-			return "", err
+			return nil, errors.New("skylink not correctly encoded")
 		}
-		decodedMHash, err := multihash.Decode(data)
+		return decoded, nil
+	case 55:
+		decoded, err := base32.HexEncoding.WithPadding(base32.NoPadding).DecodeString(strings.ToUpper(data))
 		if err != nil {
-			return "", err
+			return nil, errors.New("skylink not correctly encoded")
 		}
-		return fmt.Sprintf("bzz://%x", decodedMHash.Digest), nil
-	case "onion":
-		return fmt.Sprintf("onion://%s", string(data)), nil
-	case "onion3":
-		return fmt.Sprintf("onion3://%s", string(data)), nil
-	case "skynet-ns":
-		skylink := base64.RawURLEncoding.EncodeToString(data)
-		return fmt.Sprintf("sia://%s", skylink), nil
+		return decoded, nil
 	default:
-		return "", fmt.Errorf("unknown codec name %s", codecName)
+		return nil, errors.New("skylinks should be either 46 or 55 characters, depending on whether it is base64 or base32 encoded")
 	}
 }
+
+// decodeSkynetContenthash decodes a Skynet skylink.
+func decodeSkynetContenthash(data []byte) (string, error) {
+	return fmt.Sprintf("sia://%s", base64.RawURLEncoding.EncodeToString(data)), nil
+}
@@ -15,12 +15,14 @@
 package ens
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-multicodec"
 )
 
 func _hexStr(input string) []byte {
@@ -168,3 +170,87 @@ func TestContenthash(t *testing.T) {
 		})
 	}
 }
+
+func TestContenthashCodecsMulticodecTable(t *testing.T) {
+	// The built-in codecs, keyed by their names in the multicodec table,
+	// with a content identifier in the text form of each.
+	codecs := map[string]string{
+		"ipfs-ns":   "/ipfs/k2jmtxseqz46solsx2rmxavgbzp6ij1t1kiq1or8a00c2g9bx1for0gv",
+		"ipns-ns":   "/ipns/k51qzi5uqu5djwbl0zcd4g9onue26a8nq97c0m9wp6kir1gibuyjxpkqpoxwag",
+		"swarm-ns":  "bzz://d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162",
+		"onion":     "onion://zqktlwi4fecvo6ri",
+		"onion3":    "onion3://p53lf57qovyuvwsc6xnrppyply3vtqm7l6pcobkmyqsiofyeznfu5uqd",
+		"skynet-ns": "sia://CABAB_1Dt0FJsxqsu_J4TodNCbCGvtFf1Uys_3EgzOlTcg",
+	}
+
+	codes := ContenthashCodecs()
+	require.Len(t, codes, len(codecs))
+	for _, code := range codes {
+		name, err := multicodec.Name(code)
+		require.NoError(t, err)
+		repr, exists := codecs[name]
+		require.True(t, exists, "unexpected codec %s", name)
+		t.Run(name, func(t *testing.T) {
+			codec, exists := ContenthashCodecFor(code)
+			require.True(t, exists)
+			require.Equal(t, multicodec.MustID(name), codec.Code)
+
+			bin, err := StringToContenthash(repr)
+			require.NoError(t, err)
+			data, binCode, err := multicodec.RemoveCodec(bin)
+			require.NoError(t, err)
+			require.Equal(t, code, binCode)
+			res, err := codec.Decode(data)
+			require.NoError(t, err)
+			require.Equal(t, repr, res)
+
+			res, err = ContenthashToString(bin)
+			require.NoError(t, err)
+			require.Equal(t, repr, res)
+		})
+	}
+}
+
+func TestRegisterContenthashCodec(t *testing.T) {
+	arweave := &ContenthashCodec{
+		Code:  0xb29910,
+		Names: []string{"ar", "arweave"},
+		Encode: func(data string) ([]byte, error) {
+			return base64.RawURLEncoding.DecodeString(data)
+		},
+		Decode: func(data []byte) (string, error) {
+			return "ar://" + base64.RawURLEncoding.EncodeToString(data), nil
+		},
+	}
+
+	require.EqualError(t, RegisterContenthashCodec(nil), "no codec supplied")
+	require.EqualError(t, RegisterContenthashCodec(&ContenthashCodec{Code: 0xb29910, Names: []string{"ar"}}), "codec requires both encode and decode functions")
+	require.EqualError(t, RegisterContenthashCodec(&ContenthashCodec{Code: 0xb29910, Encode: arweave.Encode, Decode: arweave.Decode}), "no codec names supplied")
+	require.EqualError(t, RegisterContenthashCodec(&ContenthashCodec{Code: 0xb29910, Names: []string{"a/r"}, Encode: arweave.Encode, Decode: arweave.Decode}), `invalid codec name "a/r"`)
+	require.EqualError(t, RegisterContenthashCodec(&ContenthashCodec{Code: 0xb29910, Names: []string{"ipfs"}, Encode: arweave.Encode, Decode: arweave.Decode}), "codec name ipfs is in use by codec 0xe3")
+
+	_, err := StringToContenthash("ar://HUNxLLDnc_LFbxhJc6uVt8cDwZBWd8uUcyNMJdYNLhg")
+	require.ErrorIs(t, err, ErrFormatUnsupported)
+
+	require.NoError(t, RegisterContenthashCodec(arweave))
+	defer UnregisterContenthashCodec(0xb29910)
+	arweave.Names[0] = "changed"
+
+	bin, err := StringToContenthash("arweave://HUNxLLDnc_LFbxhJc6uVt8cDwZBWd8uUcyNMJdYNLhg")
+	require.NoError(t, err)
+	require.Equal(t, _hexStr("90b2ca051d43712cb0e773f2c56f184973ab95b7c703c1905677cb9473234c25d60d2e18"), bin)
+	res, err := ContenthashToString(bin)
+	require.NoError(t, err)
+	require.Equal(t, "ar://HUNxLLDnc_LFbxhJc6uVt8cDwZBWd8uUcyNMJdYNLhg", res)
+
+	// Replacing a codec removes its previous names.
+	require.NoError(t, RegisterContenthashCodec(&ContenthashCodec{Code: 0xb29910, Names: []string{"arweave"}, Encode: arweave.Encode, Decode: arweave.Decode}))
+	_, err = StringToContenthash("ar://HUNxLLDnc_LFbxhJc6uVt8cDwZBWd8uUcyNMJdYNLhg")
+	require.ErrorIs(t, err, ErrFormatUnsupported)
+
+	UnregisterContenthashCodec(0xb29910)
+	_, err = ContenthashToString(bin)
+	require.Error(t, err)
+	_, exists := ContenthashCodecFor(0xb29910)
+	require.False(t, exists)
+}
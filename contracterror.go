@@ -0,0 +1,285 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/wealdtech/go-ens/v3/contracts/ethregistrarcontroller"
+	"github.com/wealdtech/go-ens/v3/contracts/l2controller"
+	"github.com/wealdtech/go-ens/v3/contracts/namewrapper"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+	"github.com/wealdtech/go-ens/v3/contracts/reverseregistrar"
+	"github.com/wealdtech/go-ens/v3/contracts/universalresolver"
+)
+
+// ContractError is a custom error or revert reason returned by a contract.
+type ContractError struct {
+	// Name is the name of the error, for example "CommitmentTooNew".
+	Name string
+	// Signature is the canonical signature of the error, for example
+	// "CommitmentTooNew(bytes32)".
+	Signature string
+	// Args are the decoded arguments of the error.
+	Args []interface{}
+	// Data is the raw revert data.
+	Data []byte
+}
+
+// Custom errors raised by the ENS contracts.  These can be matched with
+// errors.Is() regardless of the arguments that the contract supplied, for
+// example:
+//
+//	if errors.Is(err, ens.ErrCommitmentTooNew) {
+//		// Wait and try again.
+//	}
+var (
+	// ErrUnauthorised is raised when the sender is not authorised to act on
+	// a name.
+	ErrUnauthorised = &ContractError{Name: "Unauthorised"}
+	// ErrCommitmentTooNew is raised when a registration is sent before its
+	// commitment has matured.
+	ErrCommitmentTooNew = &ContractError{Name: "CommitmentTooNew"}
+	// ErrCommitmentTooOld is raised when a registration is sent after its
+	// commitment has expired.
+	ErrCommitmentTooOld = &ContractError{Name: "CommitmentTooOld"}
+	// ErrUnexpiredCommitmentExists is raised when a commitment is sent again
+	// before the original has expired.
+	ErrUnexpiredCommitmentExists = &ContractError{Name: "UnexpiredCommitmentExists"}
+	// ErrDurationTooShort is raised when a registration is shorter than the
+	// minimum registration duration.
+	ErrDurationTooShort = &ContractError{Name: "DurationTooShort"}
+	// ErrNameNotAvailable is raised when registering a name that is not
+	// available.
+	ErrNameNotAvailable = &ContractError{Name: "NameNotAvailable"}
+	// ErrInsufficientValue is raised when a registration or renewal is sent
+	// with less than the rent price.
+	ErrInsufficientValue = &ContractError{Name: "InsufficientValue"}
+	// ErrResolverRequiredWhenDataSupplied is raised when a registration
+	// supplies records without a resolver.
+	ErrResolverRequiredWhenDataSupplied = &ContractError{Name: "ResolverRequiredWhenDataSupplied"}
+	// ErrOperationProhibited is raised when a fuse burned on a wrapped name
+	// prohibits the operation.
+	ErrOperationProhibited = &ContractError{Name: "OperationProhibited"}
+	// ErrResolverNotFound is raised by the universal resolver when a name
+	// does not have a resolver.
+	ErrResolverNotFound = &ContractError{Name: "ResolverNotFound"}
+	// ErrReverseAddressMismatch is raised by the universal resolver when the
+	// name held for an address does not resolve back to the address.
+	ErrReverseAddressMismatch = &ContractError{Name: "ReverseAddressMismatch"}
+)
+
+// customErrorsABIs holds the custom errors raised by the ENS contracts that
+// are not present in the bundled ABIs.  Errors with the same name but
+// different arguments are held in separate ABIs.
+var customErrorsABIs = []string{
+	// ETH registrar controller.
+	`[
+	{"type":"error","name":"CommitmentTooNew","inputs":[{"name":"commitment","type":"bytes32"}]},
+	{"type":"error","name":"CommitmentTooOld","inputs":[{"name":"commitment","type":"bytes32"}]},
+	{"type":"error","name":"DurationTooShort","inputs":[{"name":"duration","type":"uint256"}]},
+	{"type":"error","name":"InsufficientValue","inputs":[]},
+	{"type":"error","name":"MaxCommitmentAgeTooHigh","inputs":[]},
+	{"type":"error","name":"MaxCommitmentAgeTooLow","inputs":[]},
+	{"type":"error","name":"NameNotAvailable","inputs":[{"name":"name","type":"string"}]},
+	{"type":"error","name":"ResolverRequiredWhenDataSupplied","inputs":[]},
+	{"type":"error","name":"UnexpiredCommitmentExists","inputs":[{"name":"commitment","type":"bytes32"}]},
+	{"type":"error","name":"Unauthorised","inputs":[{"name":"node","type":"bytes32"}]}
+	]`,
+	// Name wrapper.
+	`[
+	{"type":"error","name":"CannotUpgrade","inputs":[]},
+	{"type":"error","name":"IncompatibleParent","inputs":[]},
+	{"type":"error","name":"IncorrectTargetOwner","inputs":[{"name":"owner","type":"address"}]},
+	{"type":"error","name":"IncorrectTokenType","inputs":[]},
+	{"type":"error","name":"LabelMismatch","inputs":[{"name":"labelHash","type":"bytes32"},{"name":"expectedLabelhash","type":"bytes32"}]},
+	{"type":"error","name":"LabelTooLong","inputs":[{"name":"label","type":"string"}]},
+	{"type":"error","name":"LabelTooShort","inputs":[]},
+	{"type":"error","name":"NameIsNotWrapped","inputs":[]},
+	{"type":"error","name":"OperationProhibited","inputs":[{"name":"node","type":"bytes32"}]},
+	{"type":"error","name":"Unauthorised","inputs":[{"name":"node","type":"bytes32"},{"name":"addr","type":"address"}]}
+	]`,
+	// Universal resolver.
+	`[
+	{"type":"error","name":"ReverseAddressMismatch","inputs":[{"name":"primary","type":"string"},{"name":"primaryAddress","type":"bytes"}]}
+	]`,
+}
+
+// bundledMetaData holds the bundled contract ABIs, which are searched for
+// custom errors.
+var bundledMetaData = []*bind.MetaData{
+	ethregistrarcontroller.ContractMetaData,
+	l2controller.ContractMetaData,
+	namewrapper.ContractMetaData,
+	registry.ContractMetaData,
+	resolver.ContractMetaData,
+	reverseregistrar.ContractMetaData,
+	universalresolver.ContractMetaData,
+}
+
+var (
+	knownErrors     map[[4]byte]abi.Error
+	knownErrorsOnce sync.Once
+)
+
+// knownError obtains the custom error with the given selector.
+func knownError(selector [4]byte) (abi.Error, bool) {
+	knownErrorsOnce.Do(func() {
+		knownErrors = make(map[[4]byte]abi.Error)
+		add := func(parsed *abi.ABI) {
+			for _, e := range parsed.Errors {
+				var id [4]byte
+				copy(id[:], e.ID[:4])
+				knownErrors[id] = e
+			}
+		}
+		for _, metaData := range bundledMetaData {
+			if parsed, err := metaData.GetAbi(); err == nil {
+				add(parsed)
+			}
+		}
+		for _, errorsABI := range customErrorsABIs {
+			if parsed, err := abi.JSON(strings.NewReader(errorsABI)); err == nil {
+				add(&parsed)
+			}
+		}
+	})
+	e, exists := knownErrors[selector]
+
+	return e, exists
+}
+
+var (
+	revertSelector = [4]byte{0x08, 0xc3, 0x79, 0xa0}
+	panicSelector  = [4]byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// panicReasons are the descriptions of the compiler's panic codes.
+var panicReasons = map[uint64]string{
+	0x00: "generic panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow",
+	0x12: "division by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array",
+	0x31: "pop from empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "uninitialized function",
+}
+
+// DecodeContractError decodes revert data returned by a contract.  It
+// handles Error(string) revert reasons, Panic(uint256) codes and the custom
+// errors raised by the ENS contracts.
+func DecodeContractError(data []byte) (*ContractError, error) {
+	if len(data) < 4 {
+		return nil, errors.New("revert data too short")
+	}
+	var selector [4]byte
+	copy(selector[:], data[:4])
+
+	switch selector {
+	case revertSelector:
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return nil, err
+		}
+		return &ContractError{Name: "Error", Signature: "Error(string)", Args: []interface{}{reason}, Data: data}, nil
+	case panicSelector:
+		if len(data) != 36 {
+			return nil, errors.New("invalid panic data")
+		}
+		return &ContractError{Name: "Panic", Signature: "Panic(uint256)", Args: []interface{}{new(big.Int).SetBytes(data[4:])}, Data: data}, nil
+	}
+
+	e, exists := knownError(selector)
+	if !exists {
+		return nil, fmt.Errorf("unknown error selector %s", hexutil.Encode(selector[:]))
+	}
+	args, err := e.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", e.Sig, err)
+	}
+
+	return &ContractError{Name: e.Name, Signature: e.Sig, Args: args, Data: data}, nil
+}
+
+// Error returns the error message.  Revert reasons are returned as-is, and
+// custom errors are returned with their arguments.
+func (e *ContractError) Error() string {
+	switch e.Signature {
+	case "Error(string)":
+		if len(e.Args) == 1 {
+			return fmt.Sprintf("%v", e.Args[0])
+		}
+	case "Panic(uint256)":
+		if len(e.Args) == 1 {
+			if code, isInt := e.Args[0].(*big.Int); isInt {
+				if reason, exists := panicReasons[code.Uint64()]; code.IsUint64() && exists {
+					return fmt.Sprintf("panic: %s (0x%x)", reason, code)
+				}
+				return fmt.Sprintf("panic: 0x%x", code)
+			}
+		}
+	}
+
+	args := make([]string, len(e.Args))
+	for i := range e.Args {
+		args[i] = formatErrorArg(e.Args[i])
+	}
+
+	return fmt.Sprintf("%s(%s)", e.Name, strings.Join(args, ", "))
+}
+
+// Is reports if the target is a contract error with the same name.  If the
+// target has a signature that must match as well.
+func (e *ContractError) Is(target error) bool {
+	t, isContractError := target.(*ContractError)
+	if !isContractError {
+		return false
+	}
+	if t.Signature != "" && t.Signature != e.Signature {
+		return false
+	}
+
+	return t.Name == e.Name
+}
+
+// formatErrorArg formats a decoded error argument for display.
+func formatErrorArg(arg interface{}) string {
+	switch v := arg.(type) {
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	case [4]byte:
+		return hexutil.Encode(v[:])
+	case []byte:
+		return hexutil.Encode(v)
+	case common.Address:
+		return v.Hex()
+	case string:
+		return fmt.Sprintf("%q", v)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
@@ -0,0 +1,165 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeContractError(t *testing.T) {
+	commitment := common.HexToHash("0x1234")
+	node := common.HexToHash("0x5678")
+	addr := common.HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+
+	tests := []struct {
+		name   string
+		data   []byte
+		err    string
+		target *ContractError
+		res    string
+		args   []interface{}
+	}{
+		{
+			name: "Short",
+			data: []byte{0x01, 0x02},
+			err:  "revert data too short",
+		},
+		{
+			name: "Unknown",
+			data: []byte{0x01, 0x02, 0x03, 0x04},
+			err:  "unknown error selector 0x01020304",
+		},
+		{
+			name: "Truncated",
+			data: customErrorData("CommitmentTooNew(bytes32)"),
+			err:  "failed to decode CommitmentTooNew(bytes32): abi: attempting to unmarshal an empty string while arguments are expected",
+		},
+		{
+			name: "Reason",
+			data: revertReasonData("not authorised"),
+			res:  "not authorised",
+			args: []interface{}{"not authorised"},
+		},
+		{
+			name: "Panic",
+			data: customErrorData("Panic(uint256)", []byte{0x11}),
+			res:  "panic: arithmetic overflow (0x11)",
+			args: []interface{}{big.NewInt(0x11)},
+		},
+		{
+			name:   "CommitmentTooNew",
+			data:   customErrorData("CommitmentTooNew(bytes32)", commitment.Bytes()),
+			target: ErrCommitmentTooNew,
+			res:    "CommitmentTooNew(0x0000000000000000000000000000000000000000000000000000000000001234)",
+			args:   []interface{}{[32]byte(commitment)},
+		},
+		{
+			name:   "DurationTooShort",
+			data:   customErrorData("DurationTooShort(uint256)", big.NewInt(86400).Bytes()),
+			target: ErrDurationTooShort,
+			res:    "DurationTooShort(86400)",
+			args:   []interface{}{big.NewInt(86400)},
+		},
+		{
+			name:   "InsufficientValue",
+			data:   customErrorData("InsufficientValue()"),
+			target: ErrInsufficientValue,
+			res:    "InsufficientValue()",
+			args:   []interface{}{},
+		},
+		{
+			name:   "UnauthorisedController",
+			data:   customErrorData("Unauthorised(bytes32)", node.Bytes()),
+			target: ErrUnauthorised,
+			res:    "Unauthorised(0x0000000000000000000000000000000000000000000000000000000000005678)",
+			args:   []interface{}{[32]byte(node)},
+		},
+		{
+			name:   "UnauthorisedWrapper",
+			data:   customErrorData("Unauthorised(bytes32,address)", node.Bytes(), addr.Bytes()),
+			target: ErrUnauthorised,
+			res:    "Unauthorised(0x0000000000000000000000000000000000000000000000000000000000005678, 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed)",
+			args:   []interface{}{[32]byte(node), addr},
+		},
+		{
+			name:   "UniversalResolver",
+			data:   customErrorData("ResolverNotFound()"),
+			target: ErrResolverNotFound,
+			res:    "ResolverNotFound()",
+			args:   []interface{}{},
+		},
+		{
+			name:   "ReverseAddressMismatch",
+			data:   customErrorData("ReverseAddressMismatch(string,bytes)", common.LeftPadBytes([]byte{0x40}, 32), common.LeftPadBytes([]byte{0x80}, 32), common.LeftPadBytes([]byte{0x09}, 32), common.RightPadBytes([]byte("alice.eth"), 32), common.LeftPadBytes([]byte{0x01}, 32), common.RightPadBytes([]byte{0x01}, 32)),
+			target: ErrReverseAddressMismatch,
+			res:    `ReverseAddressMismatch("alice.eth", 0x01)`,
+			args:   []interface{}{"alice.eth", []byte{0x01}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := DecodeContractError(test.data)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.res, res.Error())
+			require.Equal(t, test.args, res.Args)
+			require.Equal(t, test.data, res.Data)
+			if test.target != nil {
+				require.ErrorIs(t, res, test.target)
+				require.NotErrorIs(t, res, ErrNameNotAvailable)
+			}
+		})
+	}
+}
+
+func TestRevertErrorCustom(t *testing.T) {
+	m := newMockENS()
+	opts := testTransactOpts(t)
+	m.register("custom.eth", opts.From, opts.From)
+	node := mustNameHash("custom.eth")
+	m.backend.contracts[m.resolverAddr].on("setText", func(_ []interface{}) ([]interface{}, error) {
+		return nil, &mockRevertError{data: customErrorData("Unauthorised(bytes32,address)", node[:], opts.From.Bytes())}
+	})
+
+	resolver, err := NewResolver(m.backend, "custom.eth", EthereumMainnet)
+	require.NoError(t, err)
+
+	_, err = Simulate(m.backend, opts, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		return resolver.SetText(opts, "url", "value")
+	})
+	require.ErrorIs(t, err, ErrUnauthorised)
+	var contractErr *ContractError
+	require.ErrorAs(t, err, &contractErr)
+	require.Equal(t, "Unauthorised(bytes32,address)", contractErr.Signature)
+	require.Equal(t, opts.From, contractErr.Args[1])
+
+	// Errors from generated bindings can be decoded directly.
+	_, err = resolver.SetText(opts, "url", "value")
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrUnauthorised))
+	require.ErrorIs(t, DecodeRevertError(err), ErrUnauthorised)
+	require.NoError(t, DecodeRevertError(nil))
+}
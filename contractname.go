@@ -0,0 +1,162 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wealdtech/go-ens/v3/contracts/ownable"
+	"github.com/wealdtech/go-ens/v3/contracts/reverseregistrar"
+	"github.com/wealdtech/go-ens/v3/contracts/safe"
+)
+
+// ContractOwner obtains the ERC-173 owner of a contract.
+func ContractOwner(backend bind.ContractBackend, address common.Address) (common.Address, error) {
+	contract, err := ownable.NewContract(address, backend)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	owner, err := contract.Owner(nil)
+	if err != nil {
+		return UnknownAddress, fmt.Errorf("%s does not implement owner()", address.Hex())
+	}
+	return owner, nil
+}
+
+// SafeOwners obtains the owners of a Safe multisig.  It returns an error if
+// the address is not a Safe.
+func SafeOwners(backend bind.ContractBackend, address common.Address) ([]common.Address, error) {
+	contract, err := safe.NewContract(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	owners, err := contract.GetOwners(nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a Safe", address.Hex())
+	}
+	return owners, nil
+}
+
+// SetNameForContract sets the primary name of a contract that implements
+// ERC-173.  The transacting account must be the owner of the contract, and
+// the name must resolve to the contract.
+//
+// If the contract is owned by a Safe of which the transacting account is an
+// owner an error is returned; in this situation the transaction must be
+// proposed to the Safe using the data from SetNameForContractCalldata.
+func (r *ReverseRegistrar) SetNameForContract(opts *bind.TransactOpts, contract common.Address, name string, chainId ChainId) (*types.Transaction, error) {
+	owner, err := r.checkContractOwner(opts, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := r.DefaultResolverAddress()
+	if err != nil {
+		return nil, err
+	}
+	return r.SetVerifiedNameForAddr(opts, contract, owner, resolver, name, chainId)
+}
+
+// ClaimForContract claims the reverse record of a contract that implements
+// ERC-173 for the contract's owner, without setting a name, so that the owner
+// can manage the record directly.  The transacting account must be the owner
+// of the contract.
+//
+// As with SetNameForContract, contracts owned by a Safe must be claimed
+// through the Safe, using the data from ClaimForContractCalldata.
+func (r *ReverseRegistrar) ClaimForContract(opts *bind.TransactOpts, contract common.Address) (*types.Transaction, error) {
+	owner, err := r.checkContractOwner(opts, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := r.DefaultResolverAddress()
+	if err != nil {
+		return nil, err
+	}
+	return r.ClaimForAddr(opts, contract, owner, resolver)
+}
+
+// checkContractOwner checks that the transacting account is the ERC-173 owner
+// of a contract, returning the owner.
+func (r *ReverseRegistrar) checkContractOwner(opts *bind.TransactOpts, contract common.Address) (common.Address, error) {
+	if opts == nil {
+		return UnknownAddress, errors.New("transaction options required")
+	}
+	owner, err := ContractOwner(r.backend, contract)
+	if err != nil {
+		return UnknownAddress, err
+	}
+
+	if owner != opts.From {
+		if owners, err := SafeOwners(r.backend, owner); err == nil {
+			for _, safeOwner := range owners {
+				if safeOwner == opts.From {
+					return UnknownAddress, fmt.Errorf("%s is owned by Safe %s; submit the transaction through the Safe", contract.Hex(), owner.Hex())
+				}
+			}
+		}
+		return UnknownAddress, fmt.Errorf("%s is not the owner of %s", opts.From.Hex(), contract.Hex())
+	}
+
+	return owner, nil
+}
+
+// SetNameForContractCalldata returns the data for a transaction to the
+// reverse registrar that sets the primary name of a contract, for submission
+// by the contract's owner through a multisig such as a Safe.
+func (r *ReverseRegistrar) SetNameForContractCalldata(contract common.Address, name string, chainId ChainId) ([]byte, error) {
+	owner, err := ContractOwner(r.backend, contract)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.verifyForward(name, contract, chainId); err != nil {
+		return nil, err
+	}
+	resolver, err := r.DefaultResolverAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	contractABI, err := reverseregistrar.ContractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return contractABI.Pack("setNameForAddr", contract, owner, resolver, name)
+}
+
+// ClaimForContractCalldata returns the data for a transaction to the reverse
+// registrar that claims the reverse record of a contract for its owner, for
+// submission by the contract's owner through a multisig such as a Safe.
+func (r *ReverseRegistrar) ClaimForContractCalldata(contract common.Address) ([]byte, error) {
+	owner, err := ContractOwner(r.backend, contract)
+	if err != nil {
+		return nil, err
+	}
+	resolver, err := r.DefaultResolverAddress()
+	if err != nil {
+		return nil, err
+	}
+
+	contractABI, err := reverseregistrar.ContractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return contractABI.Pack("claimForAddr", contract, owner, resolver)
+}
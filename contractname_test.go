@@ -0,0 +1,111 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/ownable"
+	"github.com/wealdtech/go-ens/v3/contracts/reverseregistrar"
+	"github.com/wealdtech/go-ens/v3/contracts/safe"
+)
+
+func TestSetNameForContract(t *testing.T) {
+	m := newMockENS()
+	registrarAddr := common.HexToAddress("0xa58E81fe9b61B5c3fE2AFD33CF304c454AbFc7Cb")
+	var setFor common.Address
+	var claimed []interface{}
+	m.backend.deploy(registrarAddr, reverseregistrar.ContractABI).
+		on("defaultResolver", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{m.resolverAddr}, nil
+		}).
+		on("setNameForAddr", func(args []interface{}) ([]interface{}, error) {
+			setFor = args[0].(common.Address)
+			return []interface{}{[32]byte{}}, nil
+		}).
+		on("claimForAddr", func(args []interface{}) ([]interface{}, error) {
+			claimed = args
+			return []interface{}{[32]byte{}}, nil
+		})
+	m.register("addr.reverse", registrarAddr, UnknownAddress)
+
+	opts := testTransactOpts(t)
+	ownedContract := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	safeContract := common.HexToAddress("0x00000000000000000000000000000000000000c2")
+	safeAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	notOwnable := common.HexToAddress("0x00000000000000000000000000000000000000c3")
+
+	m.backend.deploy(ownedContract, ownable.ContractABI).
+		on("owner", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{opts.From}, nil
+		})
+	m.backend.deploy(safeContract, ownable.ContractABI).
+		on("owner", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{safeAddr}, nil
+		})
+	m.backend.deploy(safeAddr, safe.ContractABI).
+		on("getOwners", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{[]common.Address{opts.From}}, nil
+		})
+	m.register("protocol.eth", opts.From, ownedContract)
+	m.register("vault.eth", opts.From, safeContract)
+
+	registrar, err := NewReverseRegistrar(m.backend, EthereumMainnet)
+	require.NoError(t, err)
+
+	_, err = registrar.SetNameForContract(nil, ownedContract, "protocol.eth", EthereumMainnet)
+	require.EqualError(t, err, "transaction options required")
+
+	_, err = registrar.SetNameForContract(opts, notOwnable, "protocol.eth", EthereumMainnet)
+	require.EqualError(t, err, notOwnable.Hex()+" does not implement owner()")
+
+	_, err = registrar.SetNameForContract(opts, ownedContract, "vault.eth", EthereumMainnet)
+	require.EqualError(t, err, "vault.eth resolves to "+safeContract.Hex()+" not "+ownedContract.Hex())
+
+	_, err = registrar.SetNameForContract(opts, safeContract, "vault.eth", EthereumMainnet)
+	require.EqualError(t, err, safeContract.Hex()+" is owned by Safe "+safeAddr.Hex()+"; submit the transaction through the Safe")
+
+	_, err = registrar.SetNameForContract(opts, ownedContract, "protocol.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, ownedContract, setFor)
+	require.Len(t, m.backend.sent, 1)
+
+	data, err := registrar.SetNameForContractCalldata(safeContract, "vault.eth", EthereumMainnet)
+	require.NoError(t, err)
+	contractABI, err := reverseregistrar.ContractMetaData.GetAbi()
+	require.NoError(t, err)
+	args, err := contractABI.Methods["setNameForAddr"].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, safeContract, args[0].(common.Address))
+	require.Equal(t, safeAddr, args[1].(common.Address))
+	require.Equal(t, "vault.eth", args[3].(string))
+
+	// Claiming the reverse record without setting a name.
+	_, err = registrar.ClaimForContract(nil, ownedContract)
+	require.EqualError(t, err, "transaction options required")
+	_, err = registrar.ClaimForContract(opts, safeContract)
+	require.EqualError(t, err, safeContract.Hex()+" is owned by Safe "+safeAddr.Hex()+"; submit the transaction through the Safe")
+	_, err = registrar.ClaimForContract(opts, ownedContract)
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{ownedContract, opts.From, m.resolverAddr}, claimed)
+
+	data, err = registrar.ClaimForContractCalldata(safeContract)
+	require.NoError(t, err)
+	args, err = contractABI.Methods["claimForAddr"].Inputs.Unpack(data[4:])
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{safeContract, safeAddr, m.resolverAddr}, args)
+}
@@ -0,0 +1,3 @@
+package ethregistrarcontroller
+
+//go:generate abigen -abi contract.abi -out contract.go -pkg ethregistrarcontroller -type Contract
@@ -0,0 +1,695 @@
+// Code generated - DO NOT EDIT.
+// This file is a generated binding and any manual changes will be lost.
+
+package l2controller
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+
+	ethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var (
+	_ = errors.New
+	_ = big.NewInt
+	_ = strings.NewReader
+	_ = ethereum.NotFound
+	_ = bind.Bind
+	_ = common.Big1
+	_ = types.BloomLookup
+	_ = event.NewSubscription
+	_ = abi.ConvertType
+)
+
+// IPriceOraclePrice is an auto generated low-level Go binding around an user-defined struct.
+type IPriceOraclePrice struct {
+	Base    *big.Int
+	Premium *big.Int
+}
+
+// RegistrarControllerRegisterRequest is an auto generated low-level Go binding around an user-defined struct.
+type RegistrarControllerRegisterRequest struct {
+	Name          string
+	Owner         common.Address
+	Duration      *big.Int
+	Resolver      common.Address
+	Data          [][]byte
+	ReverseRecord bool
+}
+
+// ContractMetaData contains all meta data concerning the Contract contract.
+var ContractMetaData = &bind.MetaData{
+	ABI: "[{\"inputs\":[],\"name\":\"MIN_REGISTRATION_DURATION\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"}],\"name\":\"available\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"}],\"name\":\"valid\",\"outputs\":[{\"internalType\":\"bool\",\"name\":\"\",\"type\":\"bool\"}],\"stateMutability\":\"pure\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"duration\",\"type\":\"uint256\"}],\"name\":\"registerPrice\",\"outputs\":[{\"internalType\":\"uint256\",\"name\":\"\",\"type\":\"uint256\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"duration\",\"type\":\"uint256\"}],\"name\":\"rentPrice\",\"outputs\":[{\"components\":[{\"internalType\":\"uint256\",\"name\":\"base\",\"type\":\"uint256\"},{\"internalType\":\"uint256\",\"name\":\"premium\",\"type\":\"uint256\"}],\"internalType\":\"structIPriceOracle.Price\",\"name\":\"price\",\"type\":\"tuple\"}],\"stateMutability\":\"view\",\"type\":\"function\"},{\"inputs\":[{\"components\":[{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"internalType\":\"uint256\",\"name\":\"duration\",\"type\":\"uint256\"},{\"internalType\":\"address\",\"name\":\"resolver\",\"type\":\"address\"},{\"internalType\":\"bytes[]\",\"name\":\"data\",\"type\":\"bytes[]\"},{\"internalType\":\"bool\",\"name\":\"reverseRecord\",\"type\":\"bool\"}],\"internalType\":\"structRegistrarController.RegisterRequest\",\"name\":\"request\",\"type\":\"tuple\"}],\"name\":\"register\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"inputs\":[{\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"internalType\":\"uint256\",\"name\":\"duration\",\"type\":\"uint256\"}],\"name\":\"renew\",\"outputs\":[],\"stateMutability\":\"payable\",\"type\":\"function\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"label\",\"type\":\"bytes32\"},{\"indexed\":true,\"internalType\":\"address\",\"name\":\"owner\",\"type\":\"address\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"expires\",\"type\":\"uint256\"}],\"name\":\"NameRegistered\",\"type\":\"event\"},{\"anonymous\":false,\"inputs\":[{\"indexed\":false,\"internalType\":\"string\",\"name\":\"name\",\"type\":\"string\"},{\"indexed\":true,\"internalType\":\"bytes32\",\"name\":\"label\",\"type\":\"bytes32\"},{\"indexed\":false,\"internalType\":\"uint256\",\"name\":\"expires\",\"type\":\"uint256\"}],\"name\":\"NameRenewed\",\"type\":\"event\"}]",
+}
+
+// ContractABI is the input ABI used to generate the binding from.
+// Deprecated: Use ContractMetaData.ABI instead.
+var ContractABI = ContractMetaData.ABI
+
+// Contract is an auto generated Go binding around an Ethereum contract.
+type Contract struct {
+	ContractCaller     // Read-only binding to the contract
+	ContractTransactor // Write-only binding to the contract
+	ContractFilterer   // Log filterer for contract events
+}
+
+// ContractCaller is an auto generated read-only Go binding around an Ethereum contract.
+type ContractCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ContractTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type ContractTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ContractFilterer is an auto generated log filtering Go binding around an Ethereum contract events.
+type ContractFilterer struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// ContractSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type ContractSession struct {
+	Contract     *Contract         // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// ContractCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type ContractCallerSession struct {
+	Contract *ContractCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts   // Call options to use throughout this session
+}
+
+// ContractTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type ContractTransactorSession struct {
+	Contract     *ContractTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts   // Transaction auth options to use throughout this session
+}
+
+// ContractRaw is an auto generated low-level Go binding around an Ethereum contract.
+type ContractRaw struct {
+	Contract *Contract // Generic contract binding to access the raw methods on
+}
+
+// ContractCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type ContractCallerRaw struct {
+	Contract *ContractCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// ContractTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type ContractTransactorRaw struct {
+	Contract *ContractTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewContract creates a new instance of Contract, bound to a specific deployed contract.
+func NewContract(address common.Address, backend bind.ContractBackend) (*Contract, error) {
+	contract, err := bindContract(address, backend, backend, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &Contract{ContractCaller: ContractCaller{contract: contract}, ContractTransactor: ContractTransactor{contract: contract}, ContractFilterer: ContractFilterer{contract: contract}}, nil
+}
+
+// NewContractCaller creates a new read-only instance of Contract, bound to a specific deployed contract.
+func NewContractCaller(address common.Address, caller bind.ContractCaller) (*ContractCaller, error) {
+	contract, err := bindContract(address, caller, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractCaller{contract: contract}, nil
+}
+
+// NewContractTransactor creates a new write-only instance of Contract, bound to a specific deployed contract.
+func NewContractTransactor(address common.Address, transactor bind.ContractTransactor) (*ContractTransactor, error) {
+	contract, err := bindContract(address, nil, transactor, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractTransactor{contract: contract}, nil
+}
+
+// NewContractFilterer creates a new log filterer instance of Contract, bound to a specific deployed contract.
+func NewContractFilterer(address common.Address, filterer bind.ContractFilterer) (*ContractFilterer, error) {
+	contract, err := bindContract(address, nil, nil, filterer)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractFilterer{contract: contract}, nil
+}
+
+// bindContract binds a generic wrapper to an already deployed contract.
+func bindContract(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor, filterer bind.ContractFilterer) (*bind.BoundContract, error) {
+	parsed, err := ContractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, *parsed, caller, transactor, filterer), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Contract *ContractRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Contract.Contract.ContractCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Contract *ContractRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Contract.Contract.ContractTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Contract *ContractRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Contract.Contract.ContractTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_Contract *ContractCallerRaw) Call(opts *bind.CallOpts, result *[]interface{}, method string, params ...interface{}) error {
+	return _Contract.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_Contract *ContractTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _Contract.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_Contract *ContractTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _Contract.Contract.contract.Transact(opts, method, params...)
+}
+
+// MINREGISTRATIONDURATION is a free data retrieval call binding the contract method 0x8a95b09f.
+//
+// Solidity: function MIN_REGISTRATION_DURATION() view returns(uint256)
+func (_Contract *ContractCaller) MINREGISTRATIONDURATION(opts *bind.CallOpts) (*big.Int, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "MIN_REGISTRATION_DURATION")
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// MINREGISTRATIONDURATION is a free data retrieval call binding the contract method 0x8a95b09f.
+//
+// Solidity: function MIN_REGISTRATION_DURATION() view returns(uint256)
+func (_Contract *ContractSession) MINREGISTRATIONDURATION() (*big.Int, error) {
+	return _Contract.Contract.MINREGISTRATIONDURATION(&_Contract.CallOpts)
+}
+
+// MINREGISTRATIONDURATION is a free data retrieval call binding the contract method 0x8a95b09f.
+//
+// Solidity: function MIN_REGISTRATION_DURATION() view returns(uint256)
+func (_Contract *ContractCallerSession) MINREGISTRATIONDURATION() (*big.Int, error) {
+	return _Contract.Contract.MINREGISTRATIONDURATION(&_Contract.CallOpts)
+}
+
+// Available is a free data retrieval call binding the contract method 0xaeb8ce9b.
+//
+// Solidity: function available(string name) view returns(bool)
+func (_Contract *ContractCaller) Available(opts *bind.CallOpts, name string) (bool, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "available", name)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Available is a free data retrieval call binding the contract method 0xaeb8ce9b.
+//
+// Solidity: function available(string name) view returns(bool)
+func (_Contract *ContractSession) Available(name string) (bool, error) {
+	return _Contract.Contract.Available(&_Contract.CallOpts, name)
+}
+
+// Available is a free data retrieval call binding the contract method 0xaeb8ce9b.
+//
+// Solidity: function available(string name) view returns(bool)
+func (_Contract *ContractCallerSession) Available(name string) (bool, error) {
+	return _Contract.Contract.Available(&_Contract.CallOpts, name)
+}
+
+// RegisterPrice is a free data retrieval call binding the contract method 0xe72c1e55.
+//
+// Solidity: function registerPrice(string name, uint256 duration) view returns(uint256)
+func (_Contract *ContractCaller) RegisterPrice(opts *bind.CallOpts, name string, duration *big.Int) (*big.Int, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "registerPrice", name, duration)
+
+	if err != nil {
+		return *new(*big.Int), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(*big.Int)).(**big.Int)
+
+	return out0, err
+
+}
+
+// RegisterPrice is a free data retrieval call binding the contract method 0xe72c1e55.
+//
+// Solidity: function registerPrice(string name, uint256 duration) view returns(uint256)
+func (_Contract *ContractSession) RegisterPrice(name string, duration *big.Int) (*big.Int, error) {
+	return _Contract.Contract.RegisterPrice(&_Contract.CallOpts, name, duration)
+}
+
+// RegisterPrice is a free data retrieval call binding the contract method 0xe72c1e55.
+//
+// Solidity: function registerPrice(string name, uint256 duration) view returns(uint256)
+func (_Contract *ContractCallerSession) RegisterPrice(name string, duration *big.Int) (*big.Int, error) {
+	return _Contract.Contract.RegisterPrice(&_Contract.CallOpts, name, duration)
+}
+
+// RentPrice is a free data retrieval call binding the contract method 0x83e7f6ff.
+//
+// Solidity: function rentPrice(string name, uint256 duration) view returns((uint256,uint256) price)
+func (_Contract *ContractCaller) RentPrice(opts *bind.CallOpts, name string, duration *big.Int) (IPriceOraclePrice, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "rentPrice", name, duration)
+
+	if err != nil {
+		return *new(IPriceOraclePrice), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(IPriceOraclePrice)).(*IPriceOraclePrice)
+
+	return out0, err
+
+}
+
+// RentPrice is a free data retrieval call binding the contract method 0x83e7f6ff.
+//
+// Solidity: function rentPrice(string name, uint256 duration) view returns((uint256,uint256) price)
+func (_Contract *ContractSession) RentPrice(name string, duration *big.Int) (IPriceOraclePrice, error) {
+	return _Contract.Contract.RentPrice(&_Contract.CallOpts, name, duration)
+}
+
+// RentPrice is a free data retrieval call binding the contract method 0x83e7f6ff.
+//
+// Solidity: function rentPrice(string name, uint256 duration) view returns((uint256,uint256) price)
+func (_Contract *ContractCallerSession) RentPrice(name string, duration *big.Int) (IPriceOraclePrice, error) {
+	return _Contract.Contract.RentPrice(&_Contract.CallOpts, name, duration)
+}
+
+// Valid is a free data retrieval call binding the contract method 0x9791c097.
+//
+// Solidity: function valid(string name) pure returns(bool)
+func (_Contract *ContractCaller) Valid(opts *bind.CallOpts, name string) (bool, error) {
+	var out []interface{}
+	err := _Contract.contract.Call(opts, &out, "valid", name)
+
+	if err != nil {
+		return *new(bool), err
+	}
+
+	out0 := *abi.ConvertType(out[0], new(bool)).(*bool)
+
+	return out0, err
+
+}
+
+// Valid is a free data retrieval call binding the contract method 0x9791c097.
+//
+// Solidity: function valid(string name) pure returns(bool)
+func (_Contract *ContractSession) Valid(name string) (bool, error) {
+	return _Contract.Contract.Valid(&_Contract.CallOpts, name)
+}
+
+// Valid is a free data retrieval call binding the contract method 0x9791c097.
+//
+// Solidity: function valid(string name) pure returns(bool)
+func (_Contract *ContractCallerSession) Valid(name string) (bool, error) {
+	return _Contract.Contract.Valid(&_Contract.CallOpts, name)
+}
+
+// Register is a paid mutator transaction binding the contract method 0xc7c79676.
+//
+// Solidity: function register((string,address,uint256,address,bytes[],bool) request) payable returns()
+func (_Contract *ContractTransactor) Register(opts *bind.TransactOpts, request RegistrarControllerRegisterRequest) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "register", request)
+}
+
+// Register is a paid mutator transaction binding the contract method 0xc7c79676.
+//
+// Solidity: function register((string,address,uint256,address,bytes[],bool) request) payable returns()
+func (_Contract *ContractSession) Register(request RegistrarControllerRegisterRequest) (*types.Transaction, error) {
+	return _Contract.Contract.Register(&_Contract.TransactOpts, request)
+}
+
+// Register is a paid mutator transaction binding the contract method 0xc7c79676.
+//
+// Solidity: function register((string,address,uint256,address,bytes[],bool) request) payable returns()
+func (_Contract *ContractTransactorSession) Register(request RegistrarControllerRegisterRequest) (*types.Transaction, error) {
+	return _Contract.Contract.Register(&_Contract.TransactOpts, request)
+}
+
+// Renew is a paid mutator transaction binding the contract method 0xacf1a841.
+//
+// Solidity: function renew(string name, uint256 duration) payable returns()
+func (_Contract *ContractTransactor) Renew(opts *bind.TransactOpts, name string, duration *big.Int) (*types.Transaction, error) {
+	return _Contract.contract.Transact(opts, "renew", name, duration)
+}
+
+// Renew is a paid mutator transaction binding the contract method 0xacf1a841.
+//
+// Solidity: function renew(string name, uint256 duration) payable returns()
+func (_Contract *ContractSession) Renew(name string, duration *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.Renew(&_Contract.TransactOpts, name, duration)
+}
+
+// Renew is a paid mutator transaction binding the contract method 0xacf1a841.
+//
+// Solidity: function renew(string name, uint256 duration) payable returns()
+func (_Contract *ContractTransactorSession) Renew(name string, duration *big.Int) (*types.Transaction, error) {
+	return _Contract.Contract.Renew(&_Contract.TransactOpts, name, duration)
+}
+
+// ContractNameRegisteredIterator is returned from FilterNameRegistered and is used to iterate over the raw logs and unpacked data for NameRegistered events raised by the Contract contract.
+type ContractNameRegisteredIterator struct {
+	Event *ContractNameRegistered // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ContractNameRegisteredIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ContractNameRegistered)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ContractNameRegistered)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ContractNameRegisteredIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ContractNameRegisteredIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ContractNameRegistered represents a NameRegistered event raised by the Contract contract.
+type ContractNameRegistered struct {
+	Name    string
+	Label   [32]byte
+	Owner   common.Address
+	Expires *big.Int
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterNameRegistered is a free log retrieval operation binding the contract event 0x0667086d08417333ce63f40d5bc2ef6fd330e25aaaf317b7c489541f8fe600fa.
+//
+// Solidity: event NameRegistered(string name, bytes32 indexed label, address indexed owner, uint256 expires)
+func (_Contract *ContractFilterer) FilterNameRegistered(opts *bind.FilterOpts, label [][32]byte, owner []common.Address) (*ContractNameRegisteredIterator, error) {
+
+	var labelRule []interface{}
+	for _, labelItem := range label {
+		labelRule = append(labelRule, labelItem)
+	}
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _Contract.contract.FilterLogs(opts, "NameRegistered", labelRule, ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractNameRegisteredIterator{contract: _Contract.contract, event: "NameRegistered", logs: logs, sub: sub}, nil
+}
+
+// WatchNameRegistered is a free log subscription operation binding the contract event 0x0667086d08417333ce63f40d5bc2ef6fd330e25aaaf317b7c489541f8fe600fa.
+//
+// Solidity: event NameRegistered(string name, bytes32 indexed label, address indexed owner, uint256 expires)
+func (_Contract *ContractFilterer) WatchNameRegistered(opts *bind.WatchOpts, sink chan<- *ContractNameRegistered, label [][32]byte, owner []common.Address) (event.Subscription, error) {
+
+	var labelRule []interface{}
+	for _, labelItem := range label {
+		labelRule = append(labelRule, labelItem)
+	}
+	var ownerRule []interface{}
+	for _, ownerItem := range owner {
+		ownerRule = append(ownerRule, ownerItem)
+	}
+
+	logs, sub, err := _Contract.contract.WatchLogs(opts, "NameRegistered", labelRule, ownerRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ContractNameRegistered)
+				if err := _Contract.contract.UnpackLog(event, "NameRegistered", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseNameRegistered is a log parse operation binding the contract event 0x0667086d08417333ce63f40d5bc2ef6fd330e25aaaf317b7c489541f8fe600fa.
+//
+// Solidity: event NameRegistered(string name, bytes32 indexed label, address indexed owner, uint256 expires)
+func (_Contract *ContractFilterer) ParseNameRegistered(log types.Log) (*ContractNameRegistered, error) {
+	event := new(ContractNameRegistered)
+	if err := _Contract.contract.UnpackLog(event, "NameRegistered", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
+
+// ContractNameRenewedIterator is returned from FilterNameRenewed and is used to iterate over the raw logs and unpacked data for NameRenewed events raised by the Contract contract.
+type ContractNameRenewedIterator struct {
+	Event *ContractNameRenewed // Event containing the contract specifics and raw log
+
+	contract *bind.BoundContract // Generic contract to use for unpacking event data
+	event    string              // Event name to use for unpacking event data
+
+	logs chan types.Log        // Log channel receiving the found contract events
+	sub  ethereum.Subscription // Subscription for errors, completion and termination
+	done bool                  // Whether the subscription completed delivering logs
+	fail error                 // Occurred error to stop iteration
+}
+
+// Next advances the iterator to the subsequent event, returning whether there
+// are any more events found. In case of a retrieval or parsing error, false is
+// returned and Error() can be queried for the exact failure.
+func (it *ContractNameRenewedIterator) Next() bool {
+	// If the iterator failed, stop iterating
+	if it.fail != nil {
+		return false
+	}
+	// If the iterator completed, deliver directly whatever's available
+	if it.done {
+		select {
+		case log := <-it.logs:
+			it.Event = new(ContractNameRenewed)
+			if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+				it.fail = err
+				return false
+			}
+			it.Event.Raw = log
+			return true
+
+		default:
+			return false
+		}
+	}
+	// Iterator still in progress, wait for either a data or an error event
+	select {
+	case log := <-it.logs:
+		it.Event = new(ContractNameRenewed)
+		if err := it.contract.UnpackLog(it.Event, it.event, log); err != nil {
+			it.fail = err
+			return false
+		}
+		it.Event.Raw = log
+		return true
+
+	case err := <-it.sub.Err():
+		it.done = true
+		it.fail = err
+		return it.Next()
+	}
+}
+
+// Error returns any retrieval or parsing error occurred during filtering.
+func (it *ContractNameRenewedIterator) Error() error {
+	return it.fail
+}
+
+// Close terminates the iteration process, releasing any pending underlying
+// resources.
+func (it *ContractNameRenewedIterator) Close() error {
+	it.sub.Unsubscribe()
+	return nil
+}
+
+// ContractNameRenewed represents a NameRenewed event raised by the Contract contract.
+type ContractNameRenewed struct {
+	Name    string
+	Label   [32]byte
+	Expires *big.Int
+	Raw     types.Log // Blockchain specific contextual infos
+}
+
+// FilterNameRenewed is a free log retrieval operation binding the contract event 0x93bc1a84707231b1d9552157299797c64a1a8c5bc79f05153716630c9c4936fc.
+//
+// Solidity: event NameRenewed(string name, bytes32 indexed label, uint256 expires)
+func (_Contract *ContractFilterer) FilterNameRenewed(opts *bind.FilterOpts, label [][32]byte) (*ContractNameRenewedIterator, error) {
+
+	var labelRule []interface{}
+	for _, labelItem := range label {
+		labelRule = append(labelRule, labelItem)
+	}
+
+	logs, sub, err := _Contract.contract.FilterLogs(opts, "NameRenewed", labelRule)
+	if err != nil {
+		return nil, err
+	}
+	return &ContractNameRenewedIterator{contract: _Contract.contract, event: "NameRenewed", logs: logs, sub: sub}, nil
+}
+
+// WatchNameRenewed is a free log subscription operation binding the contract event 0x93bc1a84707231b1d9552157299797c64a1a8c5bc79f05153716630c9c4936fc.
+//
+// Solidity: event NameRenewed(string name, bytes32 indexed label, uint256 expires)
+func (_Contract *ContractFilterer) WatchNameRenewed(opts *bind.WatchOpts, sink chan<- *ContractNameRenewed, label [][32]byte) (event.Subscription, error) {
+
+	var labelRule []interface{}
+	for _, labelItem := range label {
+		labelRule = append(labelRule, labelItem)
+	}
+
+	logs, sub, err := _Contract.contract.WatchLogs(opts, "NameRenewed", labelRule)
+	if err != nil {
+		return nil, err
+	}
+	return event.NewSubscription(func(quit <-chan struct{}) error {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case log := <-logs:
+				// New log arrived, parse the event and forward to the user
+				event := new(ContractNameRenewed)
+				if err := _Contract.contract.UnpackLog(event, "NameRenewed", log); err != nil {
+					return err
+				}
+				event.Raw = log
+
+				select {
+				case sink <- event:
+				case err := <-sub.Err():
+					return err
+				case <-quit:
+					return nil
+				}
+			case err := <-sub.Err():
+				return err
+			case <-quit:
+				return nil
+			}
+		}
+	}), nil
+}
+
+// ParseNameRenewed is a log parse operation binding the contract event 0x93bc1a84707231b1d9552157299797c64a1a8c5bc79f05153716630c9c4936fc.
+//
+// Solidity: event NameRenewed(string name, bytes32 indexed label, uint256 expires)
+func (_Contract *ContractFilterer) ParseNameRenewed(log types.Log) (*ContractNameRenewed, error) {
+	event := new(ContractNameRenewed)
+	if err := _Contract.contract.UnpackLog(event, "NameRenewed", log); err != nil {
+		return nil, err
+	}
+	event.Raw = log
+	return event, nil
+}
@@ -0,0 +1,3 @@
+package l2controller
+
+//go:generate abigen -abi contract.abi -out contract.go -pkg l2controller -type Contract
@@ -0,0 +1,3 @@
+package multicall3
+
+//go:generate abigen -abi contract.abi -out contract.go -pkg multicall3 -type Contract
@@ -0,0 +1,3 @@
+package namewrapper
+
+//go:generate abigen -abi contract.abi -out contract.go -pkg namewrapper -type Contract
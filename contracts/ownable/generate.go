@@ -0,0 +1,3 @@
+package ownable
+
+//go:generate abigen -abi contract.abi -out contract.go -pkg ownable -type Contract
@@ -0,0 +1,3 @@
+package priceoracle
+
+//go:generate abigen -abi contract.abi -out contract.go -pkg priceoracle -type Contract
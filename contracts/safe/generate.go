@@ -0,0 +1,3 @@
+package safe
+
+//go:generate abigen -abi contract.abi -out contract.go -pkg safe -type Contract
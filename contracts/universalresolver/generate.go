@@ -0,0 +1,3 @@
+package universalresolver
+
+//go:generate abigen -abi contract.abi -out contract.go -pkg universalresolver -type Contract
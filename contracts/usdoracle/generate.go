@@ -0,0 +1,3 @@
+package usdoracle
+
+//go:generate abigen -abi contract.abi -out contract.go -pkg usdoracle -type Contract
@@ -0,0 +1,118 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// LogAddresses returns the addresses involved in logs, in the order in which
+// they first appear.  These are the addresses of the contracts that emitted the
+// logs, along with any topics that hold an address, such as the indexed from
+// and to of an ERC-20 Transfer event.  A topic is taken to hold an address if
+// its first 12 bytes are zero and its remaining 20 bytes are not, so indexed
+// values such as small token IDs may also be returned; these will not have a
+// name.
+func LogAddresses(logs []types.Log) []common.Address {
+	addresses := make([]common.Address, 0)
+	seen := make(map[common.Address]bool)
+	add := func(address common.Address) {
+		if !seen[address] {
+			seen[address] = true
+			addresses = append(addresses, address)
+		}
+	}
+	for i := range logs {
+		add(logs[i].Address)
+		// The first topic is the event signature.
+		for j := 1; j < len(logs[i].Topics); j++ {
+			if address, isAddress := topicAddress(logs[i].Topics[j]); isAddress {
+				add(address)
+			}
+		}
+	}
+
+	return addresses
+}
+
+// ReceiptAddresses returns the addresses involved in transaction receipts, in
+// the order in which they first appear.  These are the addresses of any
+// contracts created along with the addresses involved in the receipts' logs.
+func ReceiptAddresses(receipts ...*types.Receipt) []common.Address {
+	logs := make([]types.Log, 0)
+	for _, receipt := range receipts {
+		if receipt == nil {
+			continue
+		}
+		if receipt.ContractAddress != UnknownAddress {
+			// Created contracts are added as logs without topics so that
+			// their ordering is preserved.
+			logs = append(logs, types.Log{Address: receipt.ContractAddress})
+		}
+		for _, log := range receipt.Logs {
+			if log != nil {
+				logs = append(logs, *log)
+			}
+		}
+	}
+
+	return LogAddresses(logs)
+}
+
+// topicAddress returns the address held in a topic, if it holds one.
+func topicAddress(topic common.Hash) (common.Address, bool) {
+	if !bytes.Equal(topic[:12], make([]byte, 12)) {
+		return UnknownAddress, false
+	}
+	address := common.BytesToAddress(topic[12:])
+	if address == UnknownAddress {
+		return UnknownAddress, false
+	}
+
+	return address, true
+}
+
+// LogNames returns the ENS names of the addresses involved in logs, as found by
+// LogAddresses, for example to decorate the events shown by a block explorer.
+// Addresses without a name are not present in the map; see ReverseResolveMany
+// for details.
+func LogNames(backend bind.ContractBackend, logs []types.Log, chainId ChainId, opts ...CallOption) (map[common.Address]string, error) {
+	return ReverseResolveMany(backend, LogAddresses(logs), chainId, opts...)
+}
+
+// ReceiptNames returns the ENS names of the addresses involved in transaction
+// receipts, as found by ReceiptAddresses.  Addresses without a name are not
+// present in the map; see ReverseResolveMany for details.
+func ReceiptNames(backend bind.ContractBackend, receipts []*types.Receipt, chainId ChainId, opts ...CallOption) (map[common.Address]string, error) {
+	return ReverseResolveMany(backend, ReceiptAddresses(receipts...), chainId, opts...)
+}
+
+// LogNames returns the ENS names of the addresses involved in logs; see the
+// package-level LogNames for details.  Cached results are used if the client
+// caches results and the call is for the latest state.
+func (c *Client) LogNames(logs []types.Log, opts ...CallOption) (map[common.Address]string, error) {
+	return c.ReverseResolveMany(LogAddresses(logs), opts...)
+}
+
+// ReceiptNames returns the ENS names of the addresses involved in transaction
+// receipts; see the package-level ReceiptNames for details.  Cached results
+// are used if the client caches results and the call is for the latest state.
+func (c *Client) ReceiptNames(receipts []*types.Receipt, opts ...CallOption) (map[common.Address]string, error) {
+	return c.ReverseResolveMany(ReceiptAddresses(receipts...), opts...)
+}
@@ -0,0 +1,172 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/universalresolver"
+)
+
+func TestLogAddresses(t *testing.T) {
+	token := common.HexToAddress("0x000000000000000000000000000000000000700c")
+	from := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	to := common.HexToAddress("0x000000000000000000000000000000000000b0b0")
+	created := common.HexToAddress("0x000000000000000000000000000000000000c0de")
+	transfer := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+	tests := []struct {
+		name     string
+		logs     []types.Log
+		receipts []*types.Receipt
+		res      []common.Address
+	}{
+		{
+			name: "Empty",
+			res:  []common.Address{},
+		},
+		{
+			name: "Transfer",
+			logs: []types.Log{
+				{Address: token, Topics: []common.Hash{transfer, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())}},
+			},
+			res: []common.Address{token, from, to},
+		},
+		{
+			name: "NonAddressTopics",
+			logs: []types.Log{
+				{Address: token, Topics: []common.Hash{transfer, common.BytesToHash(from.Bytes()), common.HexToHash("0xff00000000000000000000000000000000000000000000000000000000000001"), transfer, {}}},
+			},
+			res: []common.Address{token, from},
+		},
+		{
+			name: "Duplicates",
+			logs: []types.Log{
+				{Address: token, Topics: []common.Hash{transfer, common.BytesToHash(from.Bytes()), common.BytesToHash(to.Bytes())}},
+				{Address: token, Topics: []common.Hash{transfer, common.BytesToHash(to.Bytes()), common.BytesToHash(from.Bytes())}},
+			},
+			res: []common.Address{token, from, to},
+		},
+		{
+			name: "Receipts",
+			receipts: []*types.Receipt{
+				{ContractAddress: created},
+				nil,
+				{Logs: []*types.Log{{Address: token, Topics: []common.Hash{transfer, common.BytesToHash(created.Bytes()), common.BytesToHash(to.Bytes())}}}},
+			},
+			res: []common.Address{created, token, to},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.receipts != nil {
+				require.Equal(t, test.res, ReceiptAddresses(test.receipts...))
+				return
+			}
+			require.Equal(t, test.res, LogAddresses(test.logs))
+		})
+	}
+}
+
+func TestReverseResolveMany(t *testing.T) {
+	m := newMockENS()
+	deployMockMulticall3(m.backend)
+	named := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	unverified := common.HexToAddress("0x000000000000000000000000000000000000b0b0")
+	offchain := common.HexToAddress("0x0000000000000000000000000000000000000ff1")
+	unnamed := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	noResolver := common.HexToAddress("0x000000000000000000000000000000000000dead")
+	mismatch := common.HexToAddress("0x000000000000000000000000000000000000bad0")
+	m.register("alice.eth", named, named)
+	m.setReverse(named, "alice.eth")
+	m.register("offchain.eth", offchain, offchain)
+	m.setReverse(offchain, "offchain.eth")
+
+	// reverses counts the reverse resolutions by the universal resolver.
+	reverses := 0
+	m.backend.deploy(ChainConfigFor(EthereumMainnet).UniversalResolver, universalresolver.ContractABI).
+		on("reverse", func(args []interface{}) ([]interface{}, error) {
+			reverses++
+			reverseName, err := DNSDecodeName(args[0].([]byte))
+			if err != nil {
+				return nil, err
+			}
+			switch reverseName {
+			case ReverseName(named, 60):
+				return []interface{}{"alice.eth", named, m.resolverAddr, m.resolverAddr}, nil
+			case ReverseName(unverified, 60):
+				return []interface{}{"alice.eth", named, m.resolverAddr, m.resolverAddr}, nil
+			case ReverseName(offchain, 60):
+				return nil, errors.New("execution reverted")
+			case ReverseName(noResolver, 60):
+				return nil, &mockRevertError{data: customErrorData("ResolverNotFound()")}
+			case ReverseName(mismatch, 60):
+				return nil, &mockRevertError{data: customErrorData("ReverseAddressMismatch(string,bytes)", []byte{0x40}, []byte{0x80}, []byte{0x09}, common.RightPadBytes([]byte("alice.eth"), 32), []byte{0x14}, common.RightPadBytes(named.Bytes(), 32))}
+			default:
+				return []interface{}{"", UnknownAddress, UnknownAddress, UnknownAddress}, nil
+			}
+		})
+
+	addresses := []common.Address{named, unverified, offchain, unnamed, noResolver, mismatch, named}
+	expected := map[common.Address]string{
+		named:    "alice.eth",
+		offchain: "offchain.eth",
+	}
+
+	// The universal resolver answers all but the offchain address in a single
+	// call, including the addresses for which it reverts because they do not
+	// have a name.
+	calls := m.backend.callCount()
+	res, err := ReverseResolveMany(m.backend, addresses, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+	withUniversalResolver := m.backend.callCount() - calls
+	require.Equal(t, 7, reverses)
+
+	// Without the universal resolver each address is resolved individually.
+	calls = m.backend.callCount()
+	res, err = ReverseResolveMany(m.backend, addresses, EthereumMainnet, WithoutUniversalResolver())
+	require.NoError(t, err)
+	require.Equal(t, map[common.Address]string{named: "alice.eth", offchain: "offchain.eth"}, res)
+	require.Greater(t, m.backend.callCount()-calls, withUniversalResolver)
+
+	// Logs are decorated through a caching client.
+	client, err := NewClient(m.backend, WithCache(nil))
+	require.NoError(t, err)
+	logs := []types.Log{
+		{Address: offchain, Topics: []common.Hash{{}, common.BytesToHash(named.Bytes()), common.BytesToHash(unnamed.Bytes())}},
+	}
+	res, err = client.LogNames(logs)
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+	calls = m.backend.callCount()
+	res, err = client.ReceiptNames([]*types.Receipt{{Logs: []*types.Log{&logs[0]}}})
+	require.NoError(t, err)
+	require.Equal(t, expected, res)
+	// Both the named and unnamed addresses are cached.
+	require.Equal(t, calls, m.backend.callCount())
+
+	// Per-call options apply to lookups through the cache.
+	reverses = 0
+	res, err = client.ReverseResolveMany([]common.Address{noResolver}, WithoutUniversalResolver())
+	require.NoError(t, err)
+	require.Empty(t, res)
+	require.Zero(t, reverses)
+}
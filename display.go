@@ -0,0 +1,257 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+const (
+	zwj                  = '‍'
+	emojiPresentation    = '️'
+	keycap               = '⃣'
+	displayEllipsis      = "…"
+	greekSmallLetterXi   = 'ξ'
+	greekCapitalLetterXi = 'Ξ'
+)
+
+// textPresentationEmoji are the emoji that are displayed as text unless
+// followed by the emoji presentation selector U+FE0F.  Normalization removes
+// the selector, so it is restored by Beautify.
+var textPresentationEmoji = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x00a9, Hi: 0x00ae, Stride: 5},
+		{Lo: 0x203c, Hi: 0x2049, Stride: 13},
+		{Lo: 0x2122, Hi: 0x2139, Stride: 23},
+		{Lo: 0x2194, Hi: 0x2199, Stride: 1},
+		{Lo: 0x21a9, Hi: 0x21aa, Stride: 1},
+		{Lo: 0x2328, Hi: 0x23cf, Stride: 167},
+		{Lo: 0x23ed, Hi: 0x23ef, Stride: 1},
+		{Lo: 0x23f1, Hi: 0x23f2, Stride: 1},
+		{Lo: 0x23f8, Hi: 0x23fa, Stride: 1},
+		{Lo: 0x24c2, Hi: 0x24c2, Stride: 1},
+		{Lo: 0x25aa, Hi: 0x25ab, Stride: 1},
+		{Lo: 0x25b6, Hi: 0x25c0, Stride: 10},
+		{Lo: 0x25fb, Hi: 0x25fc, Stride: 1},
+		{Lo: 0x2600, Hi: 0x2604, Stride: 1},
+		{Lo: 0x260e, Hi: 0x2611, Stride: 3},
+		{Lo: 0x2618, Hi: 0x261d, Stride: 5},
+		{Lo: 0x2620, Hi: 0x2620, Stride: 1},
+		{Lo: 0x2622, Hi: 0x2623, Stride: 1},
+		{Lo: 0x2626, Hi: 0x262a, Stride: 4},
+		{Lo: 0x262e, Hi: 0x262f, Stride: 1},
+		{Lo: 0x2638, Hi: 0x263a, Stride: 1},
+		{Lo: 0x2640, Hi: 0x2642, Stride: 2},
+		{Lo: 0x265f, Hi: 0x2660, Stride: 1},
+		{Lo: 0x2663, Hi: 0x2663, Stride: 1},
+		{Lo: 0x2665, Hi: 0x2666, Stride: 1},
+		{Lo: 0x2668, Hi: 0x2668, Stride: 1},
+		{Lo: 0x267b, Hi: 0x267e, Stride: 3},
+		{Lo: 0x2692, Hi: 0x2692, Stride: 1},
+		{Lo: 0x2694, Hi: 0x2697, Stride: 1},
+		{Lo: 0x2699, Hi: 0x2699, Stride: 1},
+		{Lo: 0x269b, Hi: 0x269c, Stride: 1},
+		{Lo: 0x26a0, Hi: 0x26a7, Stride: 7},
+		{Lo: 0x26b0, Hi: 0x26b1, Stride: 1},
+		{Lo: 0x26c8, Hi: 0x26cf, Stride: 7},
+		{Lo: 0x26d1, Hi: 0x26d3, Stride: 2},
+		{Lo: 0x26e9, Hi: 0x26e9, Stride: 1},
+		{Lo: 0x26f0, Hi: 0x26f1, Stride: 1},
+		{Lo: 0x26f4, Hi: 0x26f4, Stride: 1},
+		{Lo: 0x26f7, Hi: 0x26f9, Stride: 1},
+		{Lo: 0x2702, Hi: 0x2702, Stride: 1},
+		{Lo: 0x2708, Hi: 0x2709, Stride: 1},
+		{Lo: 0x270c, Hi: 0x270d, Stride: 1},
+		{Lo: 0x270f, Hi: 0x2712, Stride: 3},
+		{Lo: 0x2714, Hi: 0x2716, Stride: 2},
+		{Lo: 0x271d, Hi: 0x2721, Stride: 4},
+		{Lo: 0x2733, Hi: 0x2734, Stride: 1},
+		{Lo: 0x2744, Hi: 0x2747, Stride: 3},
+		{Lo: 0x2763, Hi: 0x2764, Stride: 1},
+		{Lo: 0x27a1, Hi: 0x27a1, Stride: 1},
+		{Lo: 0x2934, Hi: 0x2935, Stride: 1},
+		{Lo: 0x2b05, Hi: 0x2b07, Stride: 1},
+		{Lo: 0x3030, Hi: 0x303d, Stride: 13},
+		{Lo: 0x3297, Hi: 0x3299, Stride: 2},
+	},
+	R32: []unicode.Range32{
+		{Lo: 0x1f170, Hi: 0x1f171, Stride: 1},
+		{Lo: 0x1f17e, Hi: 0x1f17f, Stride: 1},
+		{Lo: 0x1f202, Hi: 0x1f237, Stride: 53},
+		{Lo: 0x1f321, Hi: 0x1f321, Stride: 1},
+		{Lo: 0x1f324, Hi: 0x1f32c, Stride: 1},
+		{Lo: 0x1f336, Hi: 0x1f37d, Stride: 71},
+		{Lo: 0x1f396, Hi: 0x1f397, Stride: 1},
+		{Lo: 0x1f399, Hi: 0x1f39b, Stride: 1},
+		{Lo: 0x1f39e, Hi: 0x1f39f, Stride: 1},
+		{Lo: 0x1f3cb, Hi: 0x1f3ce, Stride: 1},
+		{Lo: 0x1f3d4, Hi: 0x1f3df, Stride: 1},
+		{Lo: 0x1f3f3, Hi: 0x1f3f7, Stride: 2},
+		{Lo: 0x1f43f, Hi: 0x1f441, Stride: 2},
+		{Lo: 0x1f4fd, Hi: 0x1f4fd, Stride: 1},
+		{Lo: 0x1f549, Hi: 0x1f54a, Stride: 1},
+		{Lo: 0x1f56f, Hi: 0x1f570, Stride: 1},
+		{Lo: 0x1f573, Hi: 0x1f579, Stride: 1},
+		{Lo: 0x1f587, Hi: 0x1f587, Stride: 1},
+		{Lo: 0x1f58a, Hi: 0x1f58d, Stride: 1},
+		{Lo: 0x1f590, Hi: 0x1f590, Stride: 1},
+		{Lo: 0x1f5a5, Hi: 0x1f5a8, Stride: 3},
+		{Lo: 0x1f5b1, Hi: 0x1f5b2, Stride: 1},
+		{Lo: 0x1f5bc, Hi: 0x1f5bc, Stride: 1},
+		{Lo: 0x1f5c2, Hi: 0x1f5c4, Stride: 1},
+		{Lo: 0x1f5d1, Hi: 0x1f5d3, Stride: 1},
+		{Lo: 0x1f5dc, Hi: 0x1f5de, Stride: 1},
+		{Lo: 0x1f5e1, Hi: 0x1f5e3, Stride: 2},
+		{Lo: 0x1f5e8, Hi: 0x1f5ef, Stride: 7},
+		{Lo: 0x1f5f3, Hi: 0x1f5fa, Stride: 7},
+		{Lo: 0x1f6cb, Hi: 0x1f6cb, Stride: 1},
+		{Lo: 0x1f6cd, Hi: 0x1f6cf, Stride: 1},
+		{Lo: 0x1f6e0, Hi: 0x1f6e5, Stride: 1},
+		{Lo: 0x1f6e9, Hi: 0x1f6e9, Stride: 1},
+		{Lo: 0x1f6f0, Hi: 0x1f6f3, Stride: 3},
+	},
+	LatinOffset: 1,
+}
+
+// Beautify normalizes a name and then formats it for display as described in
+// ENSIP-15: the emoji presentation selector is restored to emoji that would
+// otherwise be displayed as text, including keycaps, and in labels that are
+// not Greek the lower-case xi "ξ" is displayed as the upper-case "Ξ".
+//
+// The beautified name normalizes to the same name, so it has the same hash,
+// but it should only be used for display.
+func Beautify(name string) (string, error) {
+	normalizedName, err := Normalize(name)
+	if err != nil {
+		return "", err
+	}
+
+	labels := strings.Split(normalizedName, ".")
+	for i := range labels {
+		labels[i] = beautifyLabel(labels[i])
+	}
+
+	return strings.Join(labels, "."), nil
+}
+
+func beautifyLabel(label string) string {
+	runes := []rune(label)
+	greek := isGreekLabel(runes)
+
+	var builder strings.Builder
+	for i, r := range runes {
+		if r == greekSmallLetterXi && !greek {
+			r = greekCapitalLetterXi
+		}
+		builder.WriteRune(r)
+
+		switch {
+		case i+1 < len(runes) && runes[i+1] == keycap:
+			// Keycaps are a digit, '#' or '*' followed by the keycap mark.
+			if (r >= '0' && r <= '9') || r == '#' || r == '*' {
+				builder.WriteRune(emojiPresentation)
+			}
+		case unicode.Is(textPresentationEmoji, r):
+			builder.WriteRune(emojiPresentation)
+		}
+	}
+
+	return builder.String()
+}
+
+// isGreekLabel returns true if a label contains Greek letters other than xi,
+// which is commonly used outside of Greek labels as a symbol for ether.
+func isGreekLabel(label []rune) bool {
+	for _, r := range label {
+		if r != greekSmallLetterXi && unicode.Is(unicode.Greek, r) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// DisplayName beautifies a name and shortens it to at most maxLength
+// characters for display, by replacing the middle of the name with an
+// ellipsis.  The top-level domain is kept where possible, for example
+// "averyveryverylongname.eth" with a maximum length of 12 is displayed as
+// "aver…ame.eth".  Emoji sequences are counted as single characters and are
+// not split.
+func DisplayName(name string, maxLength int) (string, error) {
+	if maxLength < 1 {
+		return "", errors.New("maximum length must be positive")
+	}
+
+	beautifiedName, err := Beautify(name)
+	if err != nil {
+		return "", err
+	}
+
+	chars := displayChars(beautifiedName)
+	if len(chars) <= maxLength {
+		return beautifiedName, nil
+	}
+
+	// Keep the top-level domain if there is room for at least one character
+	// either side of the ellipsis.
+	var tld []string
+	if dot := strings.LastIndexByte(beautifiedName, '.'); dot != -1 {
+		tld = displayChars(beautifiedName[dot:])
+		if maxLength-len(tld)-1 >= 2 {
+			chars = chars[:len(chars)-len(tld)]
+		} else {
+			tld = nil
+		}
+	}
+
+	available := maxLength - len(tld) - 1
+	if available < 1 {
+		return strings.Join(chars[:maxLength], ""), nil
+	}
+	head := (available + 1) / 2
+	tail := available - head
+
+	return strings.Join(chars[:head], "") + displayEllipsis + strings.Join(chars[len(chars)-tail:], "") + strings.Join(tld, ""), nil
+}
+
+// displayChars splits a string in to the characters that are displayed,
+// keeping emoji sequences together.
+func displayChars(input string) []string {
+	runes := []rune(input)
+	chars := make([]string, 0, len(runes))
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) {
+			r := runes[end]
+			if r == emojiPresentation || r == keycap || (r >= 0x1f3fb && r <= 0x1f3ff) {
+				// Presentation selectors, keycaps and skin tone modifiers.
+				end++
+				continue
+			}
+			if r == zwj && end+1 < len(runes) {
+				// Zero-width joiners join the following character.
+				end += 2
+				continue
+			}
+			break
+		}
+		chars = append(chars, string(runes[start:end]))
+		start = end
+	}
+
+	return chars
+}
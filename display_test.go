@@ -0,0 +1,161 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBeautify(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		output string
+	}{
+		{
+			name:   "Empty",
+			input:  "",
+			output: "",
+		},
+		{
+			name:   "Plain",
+			input:  "Foo.ETH",
+			output: "foo.eth",
+		},
+		{
+			name:   "TextEmoji",
+			input:  "❤.eth",
+			output: "❤️.eth",
+		},
+		{
+			name:   "TextEmojiWithSelector",
+			input:  "❤️.eth",
+			output: "❤️.eth",
+		},
+		{
+			name:   "EmojiPresentation",
+			input:  "🚀.eth",
+			output: "🚀.eth",
+		},
+		{
+			name:   "Keycap",
+			input:  "1⃣2.eth",
+			output: "1️⃣2.eth",
+		},
+		{
+			name:   "ZWJSequence",
+			input:  "❤‍🔥.eth",
+			output: "❤️‍🔥.eth",
+		},
+		{
+			name:   "Xi",
+			input:  "ξth.eth",
+			output: "Ξth.eth",
+		},
+		{
+			name:   "XiInGreekLabel",
+			input:  "ξένος.eth",
+			output: "ξένος.eth",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := Beautify(test.input)
+			require.NoError(t, err)
+			require.Equal(t, test.output, output)
+
+			// The beautified name must hash to the same value.
+			expected, err := NameHash(test.input)
+			require.NoError(t, err)
+			hash, err := NameHash(output)
+			require.NoError(t, err)
+			require.Equal(t, expected, hash)
+		})
+	}
+}
+
+func TestDisplayName(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		maxLength int
+		output    string
+		err       string
+	}{
+		{
+			name:      "BadLength",
+			input:     "foo.eth",
+			maxLength: 0,
+			err:       "maximum length must be positive",
+		},
+		{
+			name:      "Short",
+			input:     "foo.eth",
+			maxLength: 12,
+			output:    "foo.eth",
+		},
+		{
+			name:      "Exact",
+			input:     "foobar.eth",
+			maxLength: 10,
+			output:    "foobar.eth",
+		},
+		{
+			name:      "Long",
+			input:     "averyveryverylongname.eth",
+			maxLength: 12,
+			output:    "aver…ame.eth",
+		},
+		{
+			name:      "Subdomain",
+			input:     "sub.averyverylongname.eth",
+			maxLength: 10,
+			output:    "sub…me.eth",
+		},
+		{
+			name:      "Emoji",
+			input:     "❤❤❤❤❤❤❤❤.eth",
+			maxLength: 8,
+			output:    "❤️❤️…❤️.eth",
+		},
+		{
+			name:      "NoRoomForTLD",
+			input:     "averyveryverylongname.eth",
+			maxLength: 5,
+			output:    "av…th",
+		},
+		{
+			name:      "Tiny",
+			input:     "averyveryverylongname.eth",
+			maxLength: 1,
+			output:    "a",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			output, err := DisplayName(test.input, test.maxLength)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.output, output)
+		})
+	}
+}
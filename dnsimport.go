@@ -0,0 +1,153 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// ens1Prefix is the prefix of the TXT records that declare the resolver of
+// a DNS name.
+const ens1Prefix = "ENS1 "
+
+// IsDNSName returns true if the name is in a DNS top-level domain rather than
+// an ENS-native domain such as .eth.  Names in DNS domains are either
+// imported in to the registry with DNSSEC proofs through the DNS registrar,
+// or resolved without import through an ENS1 TXT record.
+func IsDNSName(name string) bool {
+	idx := strings.LastIndexByte(name, '.')
+	if idx == -1 {
+		return false
+	}
+	switch name[idx+1:] {
+	case "", "eth", "reverse":
+		return false
+	default:
+		return true
+	}
+}
+
+// ENS1Record is the content of an ENS1 TXT record, which declares the
+// resolver of a DNS name that has not been imported in to the registry.  The
+// record is held in the TXT records of the _ens subdomain of the name, in
+// the form "ENS1 <resolver> [<context>]".
+type ENS1Record struct {
+	// Resolver is the address of the resolver, or UnknownAddress if the
+	// resolver is given as a name.
+	Resolver common.Address
+	// ResolverName is the ENS name of the resolver, or "" if the resolver is
+	// given as an address.
+	ResolverName string
+	// Context is data for the resolver, for example the records of the name
+	// for resolvers that hold them in the TXT record itself.
+	Context string
+}
+
+// ParseENS1Record parses the content of an ENS1 TXT record.
+func ParseENS1Record(txt string) (*ENS1Record, error) {
+	if !strings.HasPrefix(txt, ens1Prefix) {
+		return nil, errors.New("not an ENS1 record")
+	}
+	parts := strings.SplitN(strings.TrimSpace(txt[len(ens1Prefix):]), " ", 2)
+	if parts[0] == "" {
+		return nil, errors.New("ENS1 record does not declare a resolver")
+	}
+
+	res := &ENS1Record{
+		Resolver: UnknownAddress,
+	}
+	if strings.HasPrefix(parts[0], "0x") {
+		if !common.IsHexAddress(parts[0]) {
+			return nil, errors.New("invalid resolver address in ENS1 record")
+		}
+		res.Resolver = common.HexToAddress(parts[0])
+	} else {
+		res.ResolverName = parts[0]
+	}
+	if len(parts) > 1 {
+		res.Context = strings.TrimSpace(parts[1])
+	}
+
+	return res, nil
+}
+
+// LookupENS1Record looks up the ENS1 TXT record of a DNS name with the given
+// DNS resolver, or net.DefaultResolver if it is nil.
+//
+// Records obtained from DNS are not validated with DNSSEC, so this should only
+// be used for diagnostics, such as checking the configuration of a name.
+// Resolve obtains the record through the offchain DNS resolver of the
+// name's top-level domain, which validates it before use.
+func LookupENS1Record(ctx context.Context, resolver *net.Resolver, name string) (*ENS1Record, error) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	txts, err := resolver.LookupTXT(ctx, "_ens."+name)
+	if err != nil {
+		return nil, err
+	}
+	for _, txt := range txts {
+		if strings.HasPrefix(txt, ens1Prefix) {
+			return ParseENS1Record(txt)
+		}
+	}
+
+	return nil, wrapError(ErrNoResolver, "%s does not have an ENS1 record", name)
+}
+
+// resolveDNSName resolves a DNS name that has not been imported in to the
+// registry.  Top-level domains that support this have a resolver that
+// supports wildcard resolution (ENSIP-10), which obtains the ENS1 record of
+// the name with an offchain lookup and passes the request to the resolver
+// that it declares, so the backend must follow offchain lookups.
+func resolveDNSName(backend bind.ContractBackend, name string, nameHash [32]byte, chainId ChainId, o *callOptions) (common.Address, error) {
+	resolverAddr, _, err := findResolver(backend, name, chainId, o)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	if resolverAddr == UnknownAddress {
+		return UnknownAddress, ErrUnregisteredName
+	}
+
+	ctx, span := startSpan(o.ctx, "ens.resolver.Resolve", attribute.String("ens.resolver", resolverAddr.Hex()))
+	address, offchain, err := extendedResolveAddress(backend, resolverAddr, name, nameHash, o.withContext(ctx))
+	endSpan(span, err)
+	if err != nil {
+		if offchain {
+			if !followsOffchainLookups(backend) {
+				return UnknownAddress, fmt.Errorf("%s requires an offchain lookup, which the backend does not follow: %w", name, err)
+			}
+		}
+		if isRevert(err) {
+			// The resolver of the top-level domain does not resolve the
+			// name, so it is treated as unregistered.
+			return UnknownAddress, fmt.Errorf("%w: %s: %w", ErrUnregisteredName, name, err)
+		}
+		return UnknownAddress, fmt.Errorf("failed to resolve %s: %w", name, err)
+	}
+	if address == UnknownAddress {
+		return UnknownAddress, ErrNoAddress
+	}
+
+	return address, nil
+}
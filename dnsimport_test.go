@@ -0,0 +1,175 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsDNSName(t *testing.T) {
+	tests := []struct {
+		name string
+		res  bool
+	}{
+		{name: "", res: false},
+		{name: "eth", res: false},
+		{name: "test.eth", res: false},
+		{name: "a.test.eth", res: false},
+		{name: "1234.addr.reverse", res: false},
+		{name: "example.com", res: true},
+		{name: "sub.example.xyz", res: true},
+		{name: "example.", res: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.res, IsDNSName(test.name))
+		})
+	}
+}
+
+func TestParseENS1Record(t *testing.T) {
+	tests := []struct {
+		name string
+		txt  string
+		res  *ENS1Record
+		err  string
+	}{
+		{
+			name: "Address",
+			txt:  "ENS1 0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63",
+			res:  &ENS1Record{Resolver: common.HexToAddress("0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63")},
+		},
+		{
+			name: "Name",
+			txt:  "ENS1 dnsname.ens.eth",
+			res:  &ENS1Record{Resolver: UnknownAddress, ResolverName: "dnsname.ens.eth"},
+		},
+		{
+			name: "Context",
+			txt:  "ENS1 dnsname.ens.eth a[60]=0x0102030405060708090a0b0c0d0e0f1011121314 t[url]=https://example.com/",
+			res: &ENS1Record{
+				Resolver:     UnknownAddress,
+				ResolverName: "dnsname.ens.eth",
+				Context:      "a[60]=0x0102030405060708090a0b0c0d0e0f1011121314 t[url]=https://example.com/",
+			},
+		},
+		{
+			name: "NotENS1",
+			txt:  "v=spf1 -all",
+			err:  "not an ENS1 record",
+		},
+		{
+			name: "NoResolver",
+			txt:  "ENS1 ",
+			err:  "ENS1 record does not declare a resolver",
+		},
+		{
+			name: "BadAddress",
+			txt:  "ENS1 0x1234",
+			err:  "invalid resolver address in ENS1 record",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := ParseENS1Record(test.txt)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.res, res)
+		})
+	}
+}
+
+func TestResolveDNSName(t *testing.T) {
+	imported := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+	gasless := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	offchainDNSResolverAddr := common.HexToAddress("0x000000000000000000000000000000000000d115")
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(&GatewayResponse{Data: hexutil.Encode(common.LeftPadBytes(gasless.Bytes(), 32))})
+	}))
+	defer gateway.Close()
+	unavailableGateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer unavailableGateway.Close()
+
+	m := newMockENS()
+	m.register("imported.com", imported, imported)
+	m.register("com", UnknownAddress, UnknownAddress)
+	m.mu.Lock()
+	m.resolvers[mustNameHash("com")] = offchainDNSResolverAddr
+	m.mu.Unlock()
+	m.backend.deploy(offchainDNSResolverAddr, mockExtendedResolverABI).
+		on("resolve", func(args []interface{}) ([]interface{}, error) {
+			name, err := DNSDecodeName(args[0].([]byte))
+			if err != nil {
+				return nil, err
+			}
+			urls := []string{gateway.URL}
+			switch name {
+			case "gasless.com":
+			case "unavailable.com":
+				urls = []string{unavailableGateway.URL}
+			default:
+				return nil, &mockRevertError{data: revertReasonData("no ENS1 record")}
+			}
+			return nil, &mockRevertError{data: offchainLookupData(&OffchainLookup{
+				Sender:           offchainDNSResolverAddr,
+				URLs:             urls,
+				CallbackFunction: [4]byte(crypto.Keccak256([]byte("resolveCallback(bytes,bytes)"))[:4]),
+			})}
+		}).
+		on("resolveCallback", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{args[0].([]byte)}, nil
+		})
+
+	ccipBackend, err := NewCCIPReadBackend(m.backend, nil)
+	require.NoError(t, err)
+
+	address, err := Resolve(ccipBackend, "imported.com", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, imported, address)
+
+	address, err = Resolve(ccipBackend, "gasless.com", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, gasless, address)
+
+	_, err = Resolve(ccipBackend, "unknown.com", EthereumMainnet)
+	require.ErrorIs(t, err, ErrUnregisteredName)
+
+	// Failure to reach the gateway does not mean that the name is unregistered.
+	_, err = Resolve(ccipBackend, "unavailable.com", EthereumMainnet)
+	require.ErrorContains(t, err, "failed to resolve unavailable.com")
+	require.NotErrorIs(t, err, ErrUnregisteredName)
+
+	_, err = Resolve(ccipBackend, "unknown.xyz", EthereumMainnet)
+	require.ErrorIs(t, err, ErrUnregisteredName)
+
+	_, err = Resolve(m.backend, "gasless.com", EthereumMainnet)
+	require.ErrorContains(t, err, "gasless.com requires an offchain lookup, which the backend does not follow")
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// maxDNSLabelLength is the maximum length of a label in DNS wire format, as
+// the length is held in a single byte.
+const maxDNSLabelLength = 255
+
+// DNSEncodeName normalises a name and encodes it in DNS wire format, as used
+// by wildcard resolution (ENSIP-10), the name wrapper and DNSSEC proofs.
+// Each label is preceded by its length and the name is terminated by a
+// zero-length label; the empty name encodes to a single zero byte.
+//
+// Labels must not be empty, and must be no longer than 255 bytes.  ENS does
+// not apply the 63-byte limit on DNS labels.
+func DNSEncodeName(name string) ([]byte, error) {
+	name, err := NormaliseDomain(name)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return []byte{0x00}, nil
+	}
+
+	labels := strings.Split(name, ".")
+	res := make([]byte, 0, len(name)+2)
+	for _, label := range labels {
+		if label == "" {
+			return nil, errors.New("name contains an empty label")
+		}
+		if len(label) > maxDNSLabelLength {
+			return nil, fmt.Errorf("label %q is longer than %d bytes", label, maxDNSLabelLength)
+		}
+		res = append(res, byte(len(label)))
+		res = append(res, label...)
+	}
+
+	return append(res, 0x00), nil
+}
+
+// DNSDecodeName decodes a name in DNS wire format.  The name is returned as
+// encoded, without normalisation.
+func DNSDecodeName(data []byte) (string, error) {
+	labels := make([]string, 0)
+	offset := 0
+	for {
+		if offset >= len(data) {
+			return "", errors.New("name not terminated")
+		}
+		length := int(data[offset])
+		offset++
+		if length == 0 {
+			break
+		}
+		if offset+length > len(data) {
+			return "", errors.New("label overruns data")
+		}
+		label := string(data[offset : offset+length])
+		if strings.Contains(label, ".") {
+			return "", fmt.Errorf("label %q contains a period", label)
+		}
+		labels = append(labels, label)
+		offset += length
+	}
+	if offset != len(data) {
+		return "", errors.New("data after end of name")
+	}
+
+	return strings.Join(labels, "."), nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDNSEncodeName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		encoded []byte
+		err     string
+	}{
+		{
+			name:    "Root",
+			input:   "",
+			encoded: []byte{0x00},
+		},
+		{
+			name:    "TLD",
+			input:   "eth",
+			encoded: common.FromHex("0x0365746800"),
+		},
+		{
+			name:    "Name",
+			input:   "Foo.ETH",
+			encoded: common.FromHex("0x03666f6f0365746800"),
+		},
+		{
+			name:    "LongLabel",
+			input:   strings.Repeat("a", 100) + ".eth",
+			encoded: append(append([]byte{100}, []byte(strings.Repeat("a", 100))...), common.FromHex("0x0365746800")...),
+		},
+		{
+			name:  "TooLongLabel",
+			input: strings.Repeat("a", 256) + ".eth",
+			err:   "label \"" + strings.Repeat("a", 256) + "\" is longer than 255 bytes",
+		},
+		{
+			name:  "EmptyLabel",
+			input: "foo..eth",
+			err:   "name contains an empty label",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			encoded, err := DNSEncodeName(test.input)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.encoded, encoded)
+
+			decoded, err := DNSDecodeName(encoded)
+			require.NoError(t, err)
+			require.Equal(t, strings.ToLower(test.input), decoded)
+		})
+	}
+}
+
+func TestDNSDecodeName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   []byte
+		decoded string
+		err     string
+	}{
+		{
+			name:    "Root",
+			input:   []byte{0x00},
+			decoded: "",
+		},
+		{
+			name:    "Name",
+			input:   common.FromHex("0x03666f6f0365746800"),
+			decoded: "foo.eth",
+		},
+		{
+			name:  "Empty",
+			input: []byte{},
+			err:   "name not terminated",
+		},
+		{
+			name:  "NotTerminated",
+			input: common.FromHex("0x03666f6f03657468"),
+			err:   "name not terminated",
+		},
+		{
+			name:  "Overrun",
+			input: common.FromHex("0x05666f6f00"),
+			err:   "label overruns data",
+		},
+		{
+			name:  "TrailingData",
+			input: common.FromHex("0x03666f6f0001"),
+			err:   "data after end of name",
+		},
+		{
+			name:  "Period",
+			input: common.FromHex("0x03612e6200"),
+			err:   "label \"a.b\" contains a period",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			decoded, err := DNSDecodeName(test.input)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.decoded, decoded)
+		})
+	}
+}
@@ -137,7 +137,9 @@ func DNSWireFormatDomainHash(domain string) [32]byte {
 	return hash
 }
 
-// DNSWireFormat turns a domain name in to wire format.
+// DNSWireFormat turns a domain name in to wire format.  The domain is not
+// normalised or validated; DNSEncodeName should be used for user-supplied
+// names.
 func DNSWireFormat(domain string) []byte {
 	// Remove leading and trailing dots.
 	domain = strings.TrimLeft(domain, ".")
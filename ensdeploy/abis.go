@@ -0,0 +1,60 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ensdeploy
+
+// The ABIs below hold the constructors and administrative functions used to
+// deploy and wire the contracts, which are not part of the bindings in the
+// contracts package.
+
+const registryABI = `[
+	{"type":"constructor","inputs":[]},
+	{"type":"function","name":"setSubnodeOwner","stateMutability":"nonpayable","inputs":[{"name":"node","type":"bytes32"},{"name":"label","type":"bytes32"},{"name":"owner","type":"address"}],"outputs":[{"name":"","type":"bytes32"}]}
+]`
+
+const reverseRegistrarABI = `[
+	{"type":"constructor","inputs":[{"name":"ensAddr","type":"address"}]},
+	{"type":"function","name":"setController","stateMutability":"nonpayable","inputs":[{"name":"controller","type":"address"},{"name":"enabled","type":"bool"}],"outputs":[]},
+	{"type":"function","name":"setDefaultResolver","stateMutability":"nonpayable","inputs":[{"name":"resolver","type":"address"}],"outputs":[]}
+]`
+
+const baseRegistrarABI = `[
+	{"type":"constructor","inputs":[{"name":"_ens","type":"address"},{"name":"_baseNode","type":"bytes32"}]},
+	{"type":"function","name":"addController","stateMutability":"nonpayable","inputs":[{"name":"controller","type":"address"}],"outputs":[]}
+]`
+
+const metadataServiceABI = `[
+	{"type":"constructor","inputs":[{"name":"_metaDataUri","type":"string"}]}
+]`
+
+const nameWrapperABI = `[
+	{"type":"constructor","inputs":[{"name":"_ens","type":"address"},{"name":"_registrar","type":"address"},{"name":"_metadataService","type":"address"}]},
+	{"type":"function","name":"setController","stateMutability":"nonpayable","inputs":[{"name":"controller","type":"address"},{"name":"active","type":"bool"}],"outputs":[]}
+]`
+
+const dummyOracleABI = `[
+	{"type":"constructor","inputs":[{"name":"_value","type":"int256"}]}
+]`
+
+const priceOracleABI = `[
+	{"type":"constructor","inputs":[{"name":"_usdOracle","type":"address"},{"name":"_rentPrices","type":"uint256[]"}]}
+]`
+
+const controllerABI = `[
+	{"type":"constructor","inputs":[{"name":"_base","type":"address"},{"name":"_prices","type":"address"},{"name":"_minCommitmentAge","type":"uint256"},{"name":"_maxCommitmentAge","type":"uint256"},{"name":"_reverseRegistrar","type":"address"},{"name":"_nameWrapper","type":"address"},{"name":"_ens","type":"address"}]}
+]`
+
+const publicResolverABI = `[
+	{"type":"constructor","inputs":[{"name":"_ens","type":"address"},{"name":"wrapperAddress","type":"address"},{"name":"_trustedETHController","type":"address"},{"name":"_trustedReverseRegistrar","type":"address"}]}
+]`
@@ -0,0 +1,165 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ensdeploy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// Artifacts are the creation bytecode of the ENS contracts, as built from the
+// ENS contracts repository.
+type Artifacts struct {
+	// Registry is the bytecode of ENSRegistry.
+	Registry []byte
+	// ReverseRegistrar is the bytecode of ReverseRegistrar.
+	ReverseRegistrar []byte
+	// BaseRegistrar is the bytecode of BaseRegistrarImplementation.
+	BaseRegistrar []byte
+	// MetadataService is the bytecode of StaticMetadataService.
+	MetadataService []byte
+	// NameWrapper is the bytecode of NameWrapper.
+	NameWrapper []byte
+	// DummyOracle is the bytecode of DummyOracle, the ether price feed of
+	// the price oracle.  It is not required if an existing price oracle is
+	// used.
+	DummyOracle []byte
+	// PriceOracle is the bytecode of StablePriceOracle.  It is not required
+	// if an existing price oracle is used.
+	PriceOracle []byte
+	// Controller is the bytecode of ETHRegistrarController.
+	Controller []byte
+	// PublicResolver is the bytecode of PublicResolver.
+	PublicResolver []byte
+}
+
+// artifactFiles are the names of the contracts' artifacts.
+var artifactFiles = map[string]func(*Artifacts) *[]byte{
+	"ENSRegistry":                 func(a *Artifacts) *[]byte { return &a.Registry },
+	"ReverseRegistrar":            func(a *Artifacts) *[]byte { return &a.ReverseRegistrar },
+	"BaseRegistrarImplementation": func(a *Artifacts) *[]byte { return &a.BaseRegistrar },
+	"StaticMetadataService":       func(a *Artifacts) *[]byte { return &a.MetadataService },
+	"NameWrapper":                 func(a *Artifacts) *[]byte { return &a.NameWrapper },
+	"DummyOracle":                 func(a *Artifacts) *[]byte { return &a.DummyOracle },
+	"StablePriceOracle":           func(a *Artifacts) *[]byte { return &a.PriceOracle },
+	"ETHRegistrarController":      func(a *Artifacts) *[]byte { return &a.Controller },
+	"PublicResolver":              func(a *Artifacts) *[]byte { return &a.PublicResolver },
+}
+
+// LoadArtifacts loads the contracts' bytecode from a directory, searched
+// recursively for files named after the contracts, such as
+// ENSRegistry.json.  Both Hardhat artifacts, with the bytecode as a hex
+// string, and Foundry artifacts, with the bytecode as an object, are
+// understood.  Contracts without an artifact are left empty.
+func LoadArtifacts(dir string) (*Artifacts, error) {
+	artifacts := &Artifacts{}
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(path) != ".json" {
+			return nil
+		}
+		field, exists := artifactFiles[filepath.Base(path[:len(path)-len(".json")])]
+		if !exists {
+			return nil
+		}
+		bytecode, err := loadArtifact(path)
+		if err != nil {
+			return fmt.Errorf("failed to load %s: %w", path, err)
+		}
+		*field(artifacts) = bytecode
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return artifacts, nil
+}
+
+// loadArtifact loads the bytecode from an artifact.
+func loadArtifact(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var artifact struct {
+		Bytecode json.RawMessage `json:"bytecode"`
+	}
+	if err := json.Unmarshal(data, &artifact); err != nil {
+		return nil, err
+	}
+	if len(artifact.Bytecode) == 0 {
+		return nil, errors.New("no bytecode")
+	}
+
+	var bytecode hexutil.Bytes
+	if artifact.Bytecode[0] == '{' {
+		var object struct {
+			Object hexutil.Bytes `json:"object"`
+		}
+		if err := json.Unmarshal(artifact.Bytecode, &object); err != nil {
+			return nil, err
+		}
+		bytecode = object.Object
+	} else if err := json.Unmarshal(artifact.Bytecode, &bytecode); err != nil {
+		return nil, err
+	}
+	if len(bytecode) == 0 {
+		return nil, errors.New("no bytecode")
+	}
+
+	return bytecode, nil
+}
+
+// check ensures that the bytecode required for a deployment is present.
+func (a *Artifacts) check(priceOracle bool) error {
+	if a == nil {
+		return errors.New("no artifacts supplied")
+	}
+	type artifact struct {
+		name     string
+		bytecode []byte
+	}
+	required := []artifact{
+		{"ENSRegistry", a.Registry},
+		{"ReverseRegistrar", a.ReverseRegistrar},
+		{"BaseRegistrarImplementation", a.BaseRegistrar},
+		{"StaticMetadataService", a.MetadataService},
+		{"NameWrapper", a.NameWrapper},
+		{"ETHRegistrarController", a.Controller},
+		{"PublicResolver", a.PublicResolver},
+	}
+	if priceOracle {
+		required = append(required,
+			artifact{"DummyOracle", a.DummyOracle},
+			artifact{"StablePriceOracle", a.PriceOracle},
+		)
+	}
+	for _, artifact := range required {
+		if len(artifact.bytecode) == 0 {
+			return fmt.Errorf("no bytecode for %s", artifact.name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,362 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ensdeploy deploys the ENS contracts to a simulated backend or
+// private chain, so that registration and resolution can be tested end to end
+// without a fork of mainnet:
+//
+//	backend := backends.NewSimulatedBackend(alloc, gasLimit)
+//	artifacts, err := ensdeploy.LoadArtifacts("ens-contracts/artifacts")
+//	...
+//	deployment, err := ensdeploy.Deploy(opts, backend, artifacts, 1337)
+//	...
+//	err = ens.RegisterChainConfig(deployment.ChainConfig)
+//
+// The contracts' bytecode is not part of this module, and is supplied as
+// artifacts built from the ENS contracts repository.
+package ensdeploy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// Backend is a backend to which contracts can be deployed.  Simulated
+// backends are committed after each transaction.
+type Backend interface {
+	bind.ContractBackend
+	bind.DeployBackend
+}
+
+// committer is a backend that mines transactions on demand, such as the
+// simulated backend.
+type committer interface {
+	Commit() common.Hash
+}
+
+// Deployment is a deployment of the ENS contracts.
+type Deployment struct {
+	// Registry is the address of the ENS registry.
+	Registry common.Address
+	// ReverseRegistrar is the address of the reverse registrar.
+	ReverseRegistrar common.Address
+	// BaseRegistrar is the address of the .eth base registrar.
+	BaseRegistrar common.Address
+	// MetadataService is the address of the name wrapper's metadata service.
+	MetadataService common.Address
+	// NameWrapper is the address of the name wrapper.
+	NameWrapper common.Address
+	// PriceOracle is the address of the registrar controller's price oracle.
+	PriceOracle common.Address
+	// Controller is the address of the .eth registrar controller.
+	Controller common.Address
+	// PublicResolver is the address of the public resolver, which is the
+	// reverse registrar's default resolver.
+	PublicResolver common.Address
+	// ChainConfig is the configuration for the chain, ready to be registered
+	// with ens.RegisterChainConfig.
+	ChainConfig *ens.ChainConfig
+}
+
+// Option is an option for a deployment.
+type Option func(*options)
+
+type options struct {
+	metadataURI      string
+	priceOracle      common.Address
+	usdPrice         *big.Int
+	rentPrices       []*big.Int
+	minCommitmentAge time.Duration
+	maxCommitmentAge time.Duration
+}
+
+// WithMetadataURI sets the URI returned by the name wrapper's metadata
+// service.  The default is empty.
+func WithMetadataURI(uri string) Option {
+	return func(o *options) {
+		o.metadataURI = uri
+	}
+}
+
+// WithPriceOracle uses an existing price oracle for the registrar controller,
+// rather than deploying one.
+func WithPriceOracle(address common.Address) Option {
+	return func(o *options) {
+		o.priceOracle = address
+	}
+}
+
+// WithUSDPrice sets the price of ether in US dollars, with 8 decimals, used by
+// the deployed price oracle.  The default is $1,600.
+func WithUSDPrice(price *big.Int) Option {
+	return func(o *options) {
+		o.usdPrice = price
+	}
+}
+
+// WithRentPrices sets the rent prices of the deployed price oracle, in
+// attodollars per second for names of one, two, three, four, and five or more
+// characters.  The defaults are those on Ethereum mainnet.
+func WithRentPrices(prices []*big.Int) Option {
+	return func(o *options) {
+		o.rentPrices = prices
+	}
+}
+
+// WithCommitmentAges sets the minimum and maximum ages of registration
+// commitments accepted by the registrar controller.  The defaults are those
+// on Ethereum mainnet, one minute and one day.
+func WithCommitmentAges(minAge time.Duration, maxAge time.Duration) Option {
+	return func(o *options) {
+		o.minCommitmentAge = minAge
+		o.maxCommitmentAge = maxAge
+	}
+}
+
+// defaultRentPrices are the rent prices on Ethereum mainnet.
+var defaultRentPrices = []*big.Int{
+	big.NewInt(0),
+	big.NewInt(0),
+	big.NewInt(20294266869609),
+	big.NewInt(5073566717402),
+	big.NewInt(158548959919),
+}
+
+// deployer carries out the transactions of a deployment.
+type deployer struct {
+	opts    *bind.TransactOpts
+	backend Backend
+}
+
+// Deploy deploys the ENS contracts and wires them together, returning their
+// addresses and the configuration for the chain.  The account of opts owns the
+// registry's root and the reverse namespace, and is the owner of the
+// contracts that have one.  Transactions are sent one at a time and waited
+// for, so opts should not have a nonce set.
+//
+// The contracts are deployed in the order required by their constructors:
+// the registry, the reverse registrar (which is given addr.reverse), the base
+// registrar (which is given eth), the name wrapper, the price oracle, the
+// registrar controller and the public resolver.  The name wrapper and
+// registrar controller are then made controllers of the base registrar, and
+// the registrar controller a controller of the name wrapper and reverse
+// registrar, and the public resolver is set as the reverse registrar's
+// default resolver.
+func Deploy(opts *bind.TransactOpts, backend Backend, artifacts *Artifacts, chainId ens.ChainId, deployOpts ...Option) (*Deployment, error) {
+	if opts == nil {
+		return nil, errors.New("no transaction options supplied")
+	}
+	if backend == nil {
+		return nil, errors.New("no backend supplied")
+	}
+	if chainId == 0 {
+		return nil, errors.New("no chain ID supplied")
+	}
+	o := &options{
+		usdPrice:         big.NewInt(160000000000),
+		rentPrices:       defaultRentPrices,
+		minCommitmentAge: time.Minute,
+		maxCommitmentAge: 24 * time.Hour,
+	}
+	for _, opt := range deployOpts {
+		opt(o)
+	}
+	if o.maxCommitmentAge <= o.minCommitmentAge {
+		return nil, errors.New("maximum commitment age must be greater than minimum commitment age")
+	}
+	if err := artifacts.check(o.priceOracle == ens.UnknownAddress); err != nil {
+		return nil, err
+	}
+
+	d := &deployer{
+		opts:    opts,
+		backend: backend,
+	}
+	res := &Deployment{}
+	var err error
+
+	rootNode := [32]byte{}
+	reverseNode, err := ens.NameHash("reverse")
+	if err != nil {
+		return nil, err
+	}
+	ethNode, err := ens.NameHash("eth")
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Registry, err = d.deploy("ENSRegistry", artifacts.Registry, registryABI); err != nil {
+		return nil, err
+	}
+	if res.ReverseRegistrar, err = d.deploy("ReverseRegistrar", artifacts.ReverseRegistrar, reverseRegistrarABI, res.Registry); err != nil {
+		return nil, err
+	}
+	if err := d.transact("ENSRegistry", res.Registry, registryABI, "setSubnodeOwner", rootNode, labelHash("reverse"), opts.From); err != nil {
+		return nil, err
+	}
+	if err := d.transact("ENSRegistry", res.Registry, registryABI, "setSubnodeOwner", reverseNode, labelHash("addr"), res.ReverseRegistrar); err != nil {
+		return nil, err
+	}
+
+	if res.BaseRegistrar, err = d.deploy("BaseRegistrarImplementation", artifacts.BaseRegistrar, baseRegistrarABI, res.Registry, ethNode); err != nil {
+		return nil, err
+	}
+	if err := d.transact("ENSRegistry", res.Registry, registryABI, "setSubnodeOwner", rootNode, labelHash("eth"), res.BaseRegistrar); err != nil {
+		return nil, err
+	}
+
+	if res.MetadataService, err = d.deploy("StaticMetadataService", artifacts.MetadataService, metadataServiceABI, o.metadataURI); err != nil {
+		return nil, err
+	}
+	if res.NameWrapper, err = d.deploy("NameWrapper", artifacts.NameWrapper, nameWrapperABI, res.Registry, res.BaseRegistrar, res.MetadataService); err != nil {
+		return nil, err
+	}
+	if err := d.transact("BaseRegistrarImplementation", res.BaseRegistrar, baseRegistrarABI, "addController", res.NameWrapper); err != nil {
+		return nil, err
+	}
+
+	res.PriceOracle = o.priceOracle
+	if res.PriceOracle == ens.UnknownAddress {
+		usdOracle, err := d.deploy("DummyOracle", artifacts.DummyOracle, dummyOracleABI, o.usdPrice)
+		if err != nil {
+			return nil, err
+		}
+		if res.PriceOracle, err = d.deploy("StablePriceOracle", artifacts.PriceOracle, priceOracleABI, usdOracle, o.rentPrices); err != nil {
+			return nil, err
+		}
+	}
+
+	if res.Controller, err = d.deploy("ETHRegistrarController", artifacts.Controller, controllerABI,
+		res.BaseRegistrar,
+		res.PriceOracle,
+		big.NewInt(int64(o.minCommitmentAge.Seconds())),
+		big.NewInt(int64(o.maxCommitmentAge.Seconds())),
+		res.ReverseRegistrar,
+		res.NameWrapper,
+		res.Registry,
+	); err != nil {
+		return nil, err
+	}
+	if err := d.transact("BaseRegistrarImplementation", res.BaseRegistrar, baseRegistrarABI, "addController", res.Controller); err != nil {
+		return nil, err
+	}
+	if err := d.transact("NameWrapper", res.NameWrapper, nameWrapperABI, "setController", res.Controller, true); err != nil {
+		return nil, err
+	}
+	if err := d.transact("ReverseRegistrar", res.ReverseRegistrar, reverseRegistrarABI, "setController", res.Controller, true); err != nil {
+		return nil, err
+	}
+
+	if res.PublicResolver, err = d.deploy("PublicResolver", artifacts.PublicResolver, publicResolverABI, res.Registry, res.NameWrapper, res.Controller, res.ReverseRegistrar); err != nil {
+		return nil, err
+	}
+	if err := d.transact("ReverseRegistrar", res.ReverseRegistrar, reverseRegistrarABI, "setDefaultResolver", res.PublicResolver); err != nil {
+		return nil, err
+	}
+
+	res.ChainConfig = &ens.ChainConfig{
+		ChainId:             chainId,
+		Registry:            res.Registry,
+		ReverseRegistrar:    res.ReverseRegistrar,
+		ReverseNamespace:    "addr.reverse",
+		Root:                "eth",
+		ETHController:       res.Controller,
+		RegistrarController: res.Controller,
+		NameWrapper:         res.NameWrapper,
+	}
+
+	return res, nil
+}
+
+// deploy deploys a contract, waiting for it to be mined.
+func (d *deployer) deploy(name string, bytecode []byte, contractABI string, params ...interface{}) (common.Address, error) {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return ens.UnknownAddress, err
+	}
+	_, tx, _, err := bind.DeployContract(d.transactOpts(), parsed, bytecode, d.backend, params...)
+	if err != nil {
+		return ens.UnknownAddress, fmt.Errorf("failed to deploy %s: %w", name, err)
+	}
+	receipt, err := d.wait(tx)
+	if err != nil {
+		return ens.UnknownAddress, fmt.Errorf("failed to deploy %s: %w", name, err)
+	}
+
+	return receipt.ContractAddress, nil
+}
+
+// transact sends a transaction to a contract, waiting for it to be mined.
+func (d *deployer) transact(name string, address common.Address, contractABI string, method string, params ...interface{}) error {
+	parsed, err := abi.JSON(strings.NewReader(contractABI))
+	if err != nil {
+		return err
+	}
+	contract := bind.NewBoundContract(address, parsed, d.backend, d.backend, d.backend)
+	tx, err := contract.Transact(d.transactOpts(), method, params...)
+	if err != nil {
+		return fmt.Errorf("failed to call %s.%s: %w", name, method, err)
+	}
+	if _, err := d.wait(tx); err != nil {
+		return fmt.Errorf("failed to call %s.%s: %w", name, method, err)
+	}
+
+	return nil
+}
+
+// transactOpts returns the options for a transaction.  The nonce is obtained
+// afresh for each transaction.
+func (d *deployer) transactOpts() *bind.TransactOpts {
+	opts := *d.opts
+	opts.Nonce = nil
+
+	return &opts
+}
+
+// wait waits for a transaction to be mined, committing it first if the
+// backend mines on demand.
+func (d *deployer) wait(tx *types.Transaction) (*types.Receipt, error) {
+	if committer, isCommitter := d.backend.(committer); isCommitter {
+		committer.Commit()
+	}
+	ctx := d.opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	receipt, err := bind.WaitMined(ctx, d.backend, tx)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, fmt.Errorf("transaction %s failed", tx.Hash().Hex())
+	}
+
+	return receipt, nil
+}
+
+// labelHash returns the hash of a label.
+func labelHash(label string) [32]byte {
+	return [32]byte(crypto.Keccak256Hash([]byte(label)))
+}
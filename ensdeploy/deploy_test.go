@@ -0,0 +1,196 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ensdeploy
+
+import (
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// stubBytecode deploys a contract whose code is a single STOP, so that every
+// call to it succeeds.  It allows the deployment's transactions to be tested
+// without the ENS contracts' bytecode.
+var stubBytecode = common.FromHex("0x60016000f3")
+
+func stubArtifacts() *Artifacts {
+	return &Artifacts{
+		Registry:         stubBytecode,
+		ReverseRegistrar: stubBytecode,
+		BaseRegistrar:    stubBytecode,
+		MetadataService:  stubBytecode,
+		NameWrapper:      stubBytecode,
+		DummyOracle:      stubBytecode,
+		PriceOracle:      stubBytecode,
+		Controller:       stubBytecode,
+		PublicResolver:   stubBytecode,
+	}
+}
+
+func newSimulatedBackend(t *testing.T) (*backends.SimulatedBackend, *bind.TransactOpts) {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1337))
+	require.NoError(t, err)
+	backend := backends.NewSimulatedBackend(core.GenesisAlloc{
+		opts.From: {Balance: new(big.Int).Lsh(big.NewInt(1), 100)},
+	}, 30000000)
+	t.Cleanup(func() {
+		_ = backend.Close()
+	})
+
+	return backend, opts
+}
+
+func TestDeploy(t *testing.T) {
+	backend, opts := newSimulatedBackend(t)
+
+	deployment, err := Deploy(opts, backend, stubArtifacts(), 1337)
+	require.NoError(t, err)
+
+	addresses := map[common.Address]bool{}
+	for _, address := range []common.Address{
+		deployment.Registry,
+		deployment.ReverseRegistrar,
+		deployment.BaseRegistrar,
+		deployment.MetadataService,
+		deployment.NameWrapper,
+		deployment.PriceOracle,
+		deployment.Controller,
+		deployment.PublicResolver,
+	} {
+		require.NotEqual(t, ens.UnknownAddress, address)
+		addresses[address] = true
+	}
+	require.Len(t, addresses, 8)
+
+	// The deployer's transactions are 9 deployments and 8 wiring calls.
+	nonce, err := backend.PendingNonceAt(opts.Context, opts.From)
+	require.NoError(t, err)
+	require.Equal(t, uint64(17), nonce)
+
+	require.Equal(t, &ens.ChainConfig{
+		ChainId:             1337,
+		Registry:            deployment.Registry,
+		ReverseRegistrar:    deployment.ReverseRegistrar,
+		ReverseNamespace:    "addr.reverse",
+		Root:                "eth",
+		ETHController:       deployment.Controller,
+		RegistrarController: deployment.Controller,
+		NameWrapper:         deployment.NameWrapper,
+	}, deployment.ChainConfig)
+}
+
+func TestDeployOptions(t *testing.T) {
+	backend, opts := newSimulatedBackend(t)
+	priceOracle := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+
+	tests := []struct {
+		name      string
+		opts      *bind.TransactOpts
+		artifacts *Artifacts
+		options   []Option
+		err       string
+	}{
+		{
+			name:      "OptsMissing",
+			artifacts: stubArtifacts(),
+			err:       "no transaction options supplied",
+		},
+		{
+			name: "ArtifactsMissing",
+			opts: opts,
+			err:  "no artifacts supplied",
+		},
+		{
+			name:      "BytecodeMissing",
+			opts:      opts,
+			artifacts: &Artifacts{Registry: stubBytecode},
+			err:       "no bytecode for ReverseRegistrar",
+		},
+		{
+			name: "PriceOracleBytecodeMissing",
+			opts: opts,
+			artifacts: func() *Artifacts {
+				artifacts := stubArtifacts()
+				artifacts.PriceOracle = nil
+				return artifacts
+			}(),
+			err: "no bytecode for StablePriceOracle",
+		},
+		{
+			name:      "CommitmentAgesInvalid",
+			opts:      opts,
+			artifacts: stubArtifacts(),
+			options:   []Option{WithCommitmentAges(time.Hour, time.Minute)},
+			err:       "maximum commitment age must be greater than minimum commitment age",
+		},
+		{
+			name: "PriceOracle",
+			opts: opts,
+			artifacts: func() *Artifacts {
+				artifacts := stubArtifacts()
+				artifacts.DummyOracle = nil
+				artifacts.PriceOracle = nil
+				return artifacts
+			}(),
+			options: []Option{WithPriceOracle(priceOracle), WithMetadataURI("https://example.com/{id}")},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			deployment, err := Deploy(test.opts, backend, test.artifacts, 1337, test.options...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, priceOracle, deployment.PriceOracle)
+		})
+	}
+}
+
+func TestLoadArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "registry", "ENSRegistry.sol"), 0o700))
+	// Hardhat artifact.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "registry", "ENSRegistry.sol", "ENSRegistry.json"), []byte(`{"contractName":"ENSRegistry","bytecode":"0x60016000f3"}`), 0o600))
+	// Foundry artifact.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "PublicResolver.json"), []byte(`{"bytecode":{"object":"0x60016000f3"}}`), 0o600))
+	// Unrelated files are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "Other.json"), []byte(`{}`), 0o600))
+
+	artifacts, err := LoadArtifacts(dir)
+	require.NoError(t, err)
+	require.Equal(t, stubBytecode, artifacts.Registry)
+	require.Equal(t, stubBytecode, artifacts.PublicResolver)
+	require.Empty(t, artifacts.NameWrapper)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "NameWrapper.json"), []byte(`{"bytecode":"0x"}`), 0o600))
+	_, err = LoadArtifacts(dir)
+	require.ErrorContains(t, err, "no bytecode")
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ensdeploy
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+// artifactsDir is the directory of the pinned ENS contracts' artifacts,
+// fetched with testdata/fetch-artifacts.sh.
+var artifactsDir = filepath.Join("testdata", "ens-contracts")
+
+func TestEndToEnd(t *testing.T) {
+	if _, err := os.Stat(artifactsDir); os.IsNotExist(err) {
+		t.Skipf("no ENS contracts' artifacts in %s; run testdata/fetch-artifacts.sh", artifactsDir)
+	}
+	artifacts, err := LoadArtifacts(artifactsDir)
+	require.NoError(t, err)
+
+	backend, opts := newSimulatedBackend(t)
+	chainId := ens.ChainId(1337)
+	deployment, err := Deploy(opts, backend, artifacts, chainId)
+	require.NoError(t, err)
+	require.NoError(t, ens.RegisterChainConfig(deployment.ChainConfig))
+
+	// Register a name through the controller, setting its address and the
+	// reverse record of the owner.
+	controller, err := ens.NewRegistrarControllerAt(backend, "eth", deployment.Controller)
+	require.NoError(t, err)
+	available, err := controller.IsAvailable("e2e.eth")
+	require.NoError(t, err)
+	require.True(t, available)
+
+	node, err := ens.NameHash("e2e.eth")
+	require.NoError(t, err)
+	resolverABI, err := resolver.ContractMetaData.GetAbi()
+	require.NoError(t, err)
+	setAddr, err := resolverABI.Pack("setAddr", node, opts.From)
+	require.NoError(t, err)
+	data := [][]byte{setAddr}
+
+	duration := big.NewInt(int64((365 * 24 * time.Hour).Seconds()))
+	var secret [32]byte
+	_, err = rand.Read(secret[:])
+	require.NoError(t, err)
+	commitment, err := controller.Contract.MakeCommitment(nil, "e2e", opts.From, duration, secret, deployment.PublicResolver, data, true, 0)
+	require.NoError(t, err)
+	tx, err := controller.Contract.Commit(opts, commitment)
+	require.NoError(t, err)
+	requireMined(t, backend, tx)
+
+	// Pass the minimum commitment age.
+	require.NoError(t, backend.AdjustTime(2*time.Minute))
+	backend.Commit()
+
+	base, premium, err := controller.RentPrice("e2e.eth", 365*24*time.Hour)
+	require.NoError(t, err)
+	registerOpts := *opts
+	registerOpts.Value = new(big.Int).Add(base, premium)
+	tx, err = controller.Contract.Register(&registerOpts, "e2e", opts.From, duration, secret, deployment.PublicResolver, data, true, 0)
+	require.NoError(t, err)
+	requireMined(t, backend, tx)
+
+	// Resolve the name forward and in reverse.
+	address, err := ens.Resolve(backend, "e2e.eth", chainId)
+	require.NoError(t, err)
+	require.Equal(t, opts.From, address)
+	name, err := ens.ReverseResolve(backend, opts.From, chainId)
+	require.NoError(t, err)
+	require.Equal(t, "e2e.eth", name)
+}
+
+// requireMined mines a transaction and requires that it succeeded.
+func requireMined(t *testing.T, backend *backends.SimulatedBackend, tx *types.Transaction) {
+	t.Helper()
+	backend.Commit()
+	receipt, err := bind.WaitMined(context.Background(), backend, tx)
+	require.NoError(t, err)
+	require.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+}
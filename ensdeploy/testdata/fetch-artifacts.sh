@@ -0,0 +1,26 @@
+#!/bin/sh
+# Fetches the ENS contracts' artifacts used by the end-to-end test, from the
+# pinned release of the @ensdomains/ens-contracts package.
+set -e
+
+VERSION=1.1.4
+DIR=$(cd "$(dirname "$0")" && pwd)
+
+TMP=$(mktemp -d)
+trap 'rm -rf "${TMP}"' EXIT
+
+cd "${TMP}"
+npm pack --silent "@ensdomains/ens-contracts@${VERSION}" >/dev/null
+tar -xzf ensdomains-ens-contracts-${VERSION}.tgz
+
+rm -rf "${DIR}/ens-contracts"
+mkdir -p "${DIR}/ens-contracts"
+for contract in ENSRegistry ReverseRegistrar BaseRegistrarImplementation StaticMetadataService NameWrapper DummyOracle StablePriceOracle ETHRegistrarController PublicResolver; do
+  artifact=$(find package/artifacts -name "${contract}.json" | head -1)
+  if [ -z "${artifact}" ]; then
+    echo "no artifact for ${contract}" >&2
+    exit 1
+  fi
+  cp "${artifact}" "${DIR}/ens-contracts/${contract}.json"
+done
+echo "${VERSION}" > "${DIR}/ens-contracts/VERSION"
@@ -0,0 +1,99 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ensprom provides an implementation of ens.Metrics backed by
+// Prometheus collectors, for use with clients created with ens.WithMetrics.
+package ensprom
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// Metrics is an implementation of ens.Metrics using Prometheus collectors.
+type Metrics struct {
+	resolutionDuration *prometheus.HistogramVec
+	rpcCallDuration    *prometheus.HistogramVec
+	cacheLookups       *prometheus.CounterVec
+	ccipGatewayErrors  *prometheus.CounterVec
+}
+
+var _ ens.Metrics = (*Metrics)(nil)
+
+// New creates Prometheus collectors in the given namespace and registers them
+// with the registerer.
+func New(registerer prometheus.Registerer, namespace string) (*Metrics, error) {
+	m := &Metrics{
+		resolutionDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "ens",
+			Name:      "resolution_duration_seconds",
+			Help:      "The time taken by resolution operations.",
+		}, []string{"operation", "succeeded"}),
+		rpcCallDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "ens",
+			Name:      "rpc_call_duration_seconds",
+			Help:      "The time taken by RPC calls.",
+		}, []string{"method", "succeeded"}),
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ens",
+			Name:      "cache_lookups_total",
+			Help:      "The number of cache lookups.",
+		}, []string{"kind", "hit"}),
+		ccipGatewayErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "ens",
+			Name:      "ccip_gateway_errors_total",
+			Help:      "The number of failed requests to CCIP-Read gateways.",
+		}, []string{"gateway"}),
+	}
+
+	for _, collector := range []prometheus.Collector{
+		m.resolutionDuration,
+		m.rpcCallDuration,
+		m.cacheLookups,
+		m.ccipGatewayErrors,
+	} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// ObserveResolution records the duration of a resolution operation.
+func (m *Metrics) ObserveResolution(operation string, duration time.Duration, err error) {
+	m.resolutionDuration.WithLabelValues(operation, strconv.FormatBool(err == nil)).Observe(duration.Seconds())
+}
+
+// ObserveRPCCall records the duration of a call to a backend method.
+func (m *Metrics) ObserveRPCCall(method string, duration time.Duration, err error) {
+	m.rpcCallDuration.WithLabelValues(method, strconv.FormatBool(err == nil)).Observe(duration.Seconds())
+}
+
+// CacheLookup records a lookup in a cache.
+func (m *Metrics) CacheLookup(kind string, hit bool) {
+	m.cacheLookups.WithLabelValues(kind, strconv.FormatBool(hit)).Inc()
+}
+
+// CCIPGatewayError records a failed request to a CCIP-Read gateway.
+func (m *Metrics) CCIPGatewayError(gateway string) {
+	m.ccipGatewayErrors.WithLabelValues(gateway).Inc()
+}
@@ -0,0 +1,48 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ensprom
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := New(registry, "test")
+	require.NoError(t, err)
+
+	_, err = New(registry, "test")
+	require.Error(t, err)
+
+	m.ObserveResolution("resolve", time.Millisecond, nil)
+	m.ObserveResolution("resolve", time.Millisecond, errors.New("failed"))
+	m.ObserveRPCCall("eth_call", time.Millisecond, nil)
+	for _, hit := range []bool{true, true, false} {
+		m.CacheLookup("addr", hit)
+	}
+	m.CCIPGatewayError("gateway.example.com")
+
+	require.Equal(t, 2, testutil.CollectAndCount(m.resolutionDuration))
+	require.Equal(t, 1, testutil.CollectAndCount(m.rpcCallDuration))
+	require.Equal(t, 2.0, testutil.ToFloat64(m.cacheLookups.WithLabelValues("addr", "true")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.cacheLookups.WithLabelValues("addr", "false")))
+	require.Equal(t, 1.0, testutil.ToFloat64(m.ccipGatewayErrors.WithLabelValues("gateway.example.com")))
+}
@@ -0,0 +1,209 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ensurl parses URLs that refer to content held under ENS names, and
+// turns them in to URLs that can be fetched over HTTP.  This allows browser
+// extensions and proxies to handle ENS URLs with a few lines of Go:
+//
+//	client, err := ens.NewClient(backend)
+//	...
+//	resolution, err := ensurl.Resolve(client, "ens://vitalik.eth/general/2022/12/05/excited.html")
+//	...
+//	resp, err := http.Get(resolution.URLs[0])
+//
+// The following forms of URL are understood:
+//
+//	ens://name/path        the ENS URL scheme
+//	name/path              a bare name, as typed in to an address bar
+//	https://name.limo/path a gateway URL, such as eth.limo or eth.link
+package ensurl
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// Scheme is the URL scheme for ENS names.
+const Scheme = "ens"
+
+// GatewaySuffixes are the suffixes that gateways append to ENS names in
+// their hosts, for example "vitalik.eth.limo" for "vitalik.eth".
+var GatewaySuffixes = []string{".limo", ".link"}
+
+// Target is the ENS name and location of content referred to by a URL.
+type Target struct {
+	// Name is the normalised ENS name.
+	Name string
+	// Path is the path of the content within the name's content, starting
+	// with "/".
+	Path string
+	// RawQuery is the encoded query, without the leading "?".
+	RawQuery string
+	// Fragment is the fragment, without the leading "#".
+	Fragment string
+}
+
+// Parse parses a URL that refers to content held under an ENS name.
+func Parse(rawURL string) (*Target, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return nil, errors.New("no URL supplied")
+	}
+	if !strings.Contains(rawURL, "://") && !strings.HasPrefix(strings.ToLower(rawURL), Scheme+":") {
+		// A bare name.
+		rawURL = Scheme + "://" + rawURL
+	}
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+
+	var host string
+	switch strings.ToLower(parsed.Scheme) {
+	case Scheme:
+		host = parsed.Host
+		if host == "" && parsed.Opaque != "" {
+			// ens:name/path
+			opaque, err := url.Parse(Scheme + "://" + parsed.Opaque)
+			if err != nil {
+				return nil, fmt.Errorf("invalid URL: %w", err)
+			}
+			host = opaque.Host
+			parsed.Path = opaque.Path
+			parsed.RawPath = opaque.RawPath
+		}
+	case "http", "https":
+		host, err = gatewayName(parsed.Hostname())
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported scheme %s", parsed.Scheme)
+	}
+	if host == "" {
+		return nil, errors.New("no ENS name in URL")
+	}
+	if parsed.Port() != "" {
+		return nil, errors.New("ENS URLs cannot have a port")
+	}
+
+	name, err := ens.NormaliseDomain(strings.TrimSuffix(host, "."))
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s: %w", host, err)
+	}
+	if !strings.Contains(name, ".") {
+		return nil, fmt.Errorf("invalid name %s", name)
+	}
+
+	path := parsed.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+
+	return &Target{
+		Name:     name,
+		Path:     path,
+		RawQuery: parsed.RawQuery,
+		Fragment: parsed.Fragment,
+	}, nil
+}
+
+// gatewayName obtains the ENS name from the host of a gateway URL.
+func gatewayName(host string) (string, error) {
+	lowerHost := strings.ToLower(host)
+	for _, suffix := range GatewaySuffixes {
+		if strings.HasSuffix(lowerHost, suffix) {
+			return host[:len(host)-len(suffix)], nil
+		}
+	}
+
+	return "", fmt.Errorf("%s is not an ENS gateway", host)
+}
+
+// String returns the target as an ens:// URL.
+func (t *Target) String() string {
+	return Scheme + "://" + t.Name + t.suffix()
+}
+
+// suffix returns the path, query and fragment of the target.
+func (t *Target) suffix() string {
+	var sb strings.Builder
+	sb.WriteString(t.Path)
+	if t.RawQuery != "" {
+		sb.WriteString("?")
+		sb.WriteString(t.RawQuery)
+	}
+	if t.Fragment != "" {
+		sb.WriteString("#")
+		sb.WriteString((&url.URL{Fragment: t.Fragment}).EscapedFragment())
+	}
+
+	return sb.String()
+}
+
+// Resolution is a URL resolved to the content of its ENS name.
+type Resolution struct {
+	// Target is the parsed URL.
+	Target *Target
+	// Contenthash is the EIP-1577 contenthash of the name.
+	Contenthash []byte
+	// URLs are the URLs from which the content can be fetched, one for each
+	// gateway that serves it.
+	URLs []string
+}
+
+// Resolve parses a URL that refers to content held under an ENS name,
+// obtains the name's contenthash and returns URLs from which the content can
+// be fetched, as per ens.ContenthashURLs.  If no gateways are supplied then
+// ens.DefaultContentGateways are used.
+func Resolve(client *ens.Client, rawURL string, gateways ...*ens.ContentGateway) (*Resolution, error) {
+	if client == nil {
+		return nil, errors.New("no client supplied")
+	}
+	target, err := Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	resolver, err := client.Resolver(target.Name)
+	if err != nil {
+		return nil, err
+	}
+	contenthash, err := resolver.Contenthash()
+	if err != nil {
+		return nil, err
+	}
+	if len(contenthash) == 0 {
+		return nil, fmt.Errorf("%s has no contenthash", target.Name)
+	}
+	urls, err := ens.ContenthashURLs(target.Name, contenthash, gateways...)
+	if err != nil {
+		return nil, err
+	}
+
+	suffix := target.suffix()
+	for i := range urls {
+		urls[i] = strings.TrimSuffix(urls[i], "/") + suffix
+	}
+
+	return &Resolution{
+		Target:      target,
+		Contenthash: contenthash,
+		URLs:        urls,
+	}, nil
+}
@@ -0,0 +1,295 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ensurl
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+// fakeBackend is a backend that serves calls to a registry and a resolver
+// holding fixed contenthashes.
+type fakeBackend struct {
+	bind.ContractBackend
+	registryAddr common.Address
+	resolverAddr common.Address
+	registryABI  abi.ABI
+	resolverABI  abi.ABI
+	contenthash  map[[32]byte][]byte
+}
+
+func newFakeBackend(t *testing.T) *fakeBackend {
+	t.Helper()
+	registryABI, err := abi.JSON(strings.NewReader(registry.ContractABI))
+	require.NoError(t, err)
+	resolverABI, err := abi.JSON(strings.NewReader(resolver.ContractABI))
+	require.NoError(t, err)
+
+	return &fakeBackend{
+		registryAddr: ens.ChainConfigFor(ens.EthereumMainnet).Registry,
+		resolverAddr: common.HexToAddress("0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63"),
+		registryABI:  registryABI,
+		resolverABI:  resolverABI,
+		contenthash:  make(map[[32]byte][]byte),
+	}
+}
+
+func (b *fakeBackend) CodeAt(_ context.Context, contract common.Address, _ *big.Int) ([]byte, error) {
+	if contract == b.registryAddr || contract == b.resolverAddr {
+		return []byte{0x01}, nil
+	}
+	return nil, nil
+}
+
+func (b *fakeBackend) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	var contractABI abi.ABI
+	switch {
+	case call.To == nil:
+		return nil, nil
+	case *call.To == b.registryAddr:
+		contractABI = b.registryABI
+	case *call.To == b.resolverAddr:
+		contractABI = b.resolverABI
+	default:
+		return nil, nil
+	}
+	method, err := contractABI.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, errors.New("execution reverted")
+	}
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	node := args[0].([32]byte)
+	_, registered := b.contenthash[node]
+
+	var res []interface{}
+	switch method.Name {
+	case "owner", "resolver":
+		address := common.Address{}
+		if registered {
+			address = b.resolverAddr
+		}
+		res = []interface{}{address}
+	case "addr":
+		res = []interface{}{common.Address{}}
+	case "contenthash":
+		res = []interface{}{b.contenthash[node]}
+	default:
+		return nil, errors.New("execution reverted")
+	}
+
+	return method.Outputs.Pack(res...)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		url    string
+		err    string
+		target *Target
+		str    string
+	}{
+		{
+			name: "Empty",
+			url:  " ",
+			err:  "no URL supplied",
+		},
+		{
+			name: "UnsupportedScheme",
+			url:  "ftp://vitalik.eth/",
+			err:  "unsupported scheme ftp",
+		},
+		{
+			name: "NotGateway",
+			url:  "https://example.com/",
+			err:  "example.com is not an ENS gateway",
+		},
+		{
+			name: "NoName",
+			url:  "ens:///path",
+			err:  "no ENS name in URL",
+		},
+		{
+			name: "TLD",
+			url:  "ens://eth/",
+			err:  "invalid name eth",
+		},
+		{
+			name: "Port",
+			url:  "ens://vitalik.eth:8080/",
+			err:  "ENS URLs cannot have a port",
+		},
+		{
+			name:   "ENS",
+			url:    "ens://vitalik.eth/general/index.html?a=b#top",
+			target: &Target{Name: "vitalik.eth", Path: "/general/index.html", RawQuery: "a=b", Fragment: "top"},
+			str:    "ens://vitalik.eth/general/index.html?a=b#top",
+		},
+		{
+			name:   "ENSOpaque",
+			url:    "ens:vitalik.eth/general",
+			target: &Target{Name: "vitalik.eth", Path: "/general"},
+			str:    "ens://vitalik.eth/general",
+		},
+		{
+			name:   "ENSNoPath",
+			url:    "ENS://Vitalik.ETH",
+			target: &Target{Name: "vitalik.eth", Path: "/"},
+			str:    "ens://vitalik.eth/",
+		},
+		{
+			name:   "Bare",
+			url:    "vitalik.eth/a%20b",
+			target: &Target{Name: "vitalik.eth", Path: "/a%20b"},
+			str:    "ens://vitalik.eth/a%20b",
+		},
+		{
+			name:   "Unicode",
+			url:    "ens://ñandú.eth/",
+			target: &Target{Name: "ñandú.eth", Path: "/"},
+			str:    "ens://ñandú.eth/",
+		},
+		{
+			name:   "EthLimo",
+			url:    "https://vitalik.eth.limo/general/",
+			target: &Target{Name: "vitalik.eth", Path: "/general/"},
+			str:    "ens://vitalik.eth/general/",
+		},
+		{
+			name:   "EthLink",
+			url:    "http://sub.vitalik.eth.link",
+			target: &Target{Name: "sub.vitalik.eth", Path: "/"},
+			str:    "ens://sub.vitalik.eth/",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			target, err := Parse(test.url)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.target, target)
+			require.Equal(t, test.str, target.String())
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	var err error
+	backend := newFakeBackend(t)
+	backend.contenthash[common.Hash(mustNameHash(t, "content.eth"))], err = ens.StringToContenthash("/ipfs/QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4")
+	require.NoError(t, err)
+	backend.contenthash[common.Hash(mustNameHash(t, "empty.eth"))] = []byte{}
+
+	client, err := ens.NewClient(backend, ens.WithCCIPRead(false))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		client   *ens.Client
+		url      string
+		gateways []*ens.ContentGateway
+		err      string
+		urls     []string
+	}{
+		{
+			name: "NoClient",
+			url:  "ens://content.eth/",
+			err:  "no client supplied",
+		},
+		{
+			name:   "BadURL",
+			client: client,
+			url:    "ftp://content.eth/",
+			err:    "unsupported scheme ftp",
+		},
+		{
+			name:   "Unregistered",
+			client: client,
+			url:    "ens://unregistered.eth/",
+			err:    "unregistered name",
+		},
+		{
+			name:   "NoContenthash",
+			client: client,
+			url:    "ens://empty.eth/",
+			err:    "empty.eth has no contenthash",
+		},
+		{
+			name:     "NoGateway",
+			client:   client,
+			url:      "ens://content.eth/",
+			gateways: []*ens.ContentGateway{ens.ArweaveGateway},
+			err:      "no gateway serves ipfs content",
+		},
+		{
+			name:   "Good",
+			client: client,
+			url:    "https://content.eth.limo/docs/index.html?v=1#intro",
+			urls: []string{
+				"https://content.eth.limo/docs/index.html?v=1#intro",
+				"https://ipfs.io/ipfs/bafybeibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4/docs/index.html?v=1#intro",
+				"https://bafybeibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4.ipfs.dweb.link/docs/index.html?v=1#intro",
+			},
+		},
+		{
+			name:     "Gateway",
+			client:   client,
+			url:      "ens://content.eth",
+			gateways: []*ens.ContentGateway{ens.IPFSGateway},
+			urls: []string{
+				"https://ipfs.io/ipfs/bafybeibj6lixxzqtsb45ysdjnupvqkufgdvzqbnvmhw2kf7cfkesy7r7d4/",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resolution, err := Resolve(test.client, test.url, test.gateways...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.urls, resolution.URLs)
+			require.Equal(t, "content.eth", resolution.Target.Name)
+			require.NotEmpty(t, resolution.Contenthash)
+		})
+	}
+}
+
+func mustNameHash(t *testing.T, name string) [32]byte {
+	t.Helper()
+	hash, err := ens.NameHash(name)
+	require.NoError(t, err)
+	return hash
+}
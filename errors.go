@@ -0,0 +1,65 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoResolver is returned when a name does not have a resolver.
+	ErrNoResolver = errors.New("no resolver")
+	// ErrNotAResolver is returned when the resolver for a name is not a
+	// resolver contract.
+	ErrNotAResolver = errors.New("not a resolver")
+	// ErrNoResolution is returned when an address does not have a name.
+	ErrNoResolution = errors.New("no resolution")
+	// ErrNoAddress is returned when a name does not have an address.
+	ErrNoAddress = errors.New("no address")
+	// ErrUnregisteredName is returned when a name is not registered.
+	ErrUnregisteredName = errors.New("unregistered name")
+	// ErrFormatUnsupported is returned when data is in a format that is not
+	// supported, for example an unknown contenthash codec.
+	ErrFormatUnsupported = errors.New("unsupported format")
+	// ErrInvalidName is returned when a name fails validation.
+	ErrInvalidName = errors.New("invalid name")
+	// ErrCommitmentExpired is returned when a registration commitment is
+	// older than the controller's maximum commitment age.
+	ErrCommitmentExpired = errors.New("commitment expired")
+)
+
+// wrappedError is an error with its own message that wraps another error, so
+// that detailed messages can be matched with errors.Is.
+type wrappedError struct {
+	msg string
+	err error
+}
+
+func (e *wrappedError) Error() string {
+	return e.msg
+}
+
+func (e *wrappedError) Unwrap() error {
+	return e.err
+}
+
+// wrapError creates an error with the given message that wraps err.
+func wrapError(err error, format string, args ...interface{}) error {
+	return &wrappedError{
+		msg: fmt.Sprintf(format, args...),
+		err: err,
+	}
+}
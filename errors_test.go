@@ -0,0 +1,104 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("noaddress.eth", address, UnknownAddress)
+	m.register("noresolver.eth", address, address)
+	m.mu.Lock()
+	m.resolvers[mustNameHash("noresolver.eth")] = UnknownAddress
+	m.mu.Unlock()
+
+	tests := []struct {
+		name string
+		fn   func() error
+		err  error
+		msg  string
+	}{
+		{
+			name: "UnregisteredName",
+			fn: func() error {
+				_, err := Resolve(m.backend, "unregistered.eth", EthereumMainnet)
+				return err
+			},
+			err: ErrUnregisteredName,
+			msg: "unregistered name",
+		},
+		{
+			name: "NoAddress",
+			fn: func() error {
+				_, err := Resolve(m.backend, "noaddress.eth", EthereumMainnet)
+				return err
+			},
+			err: ErrNoAddress,
+			msg: "no address",
+		},
+		{
+			name: "NoResolver",
+			fn: func() error {
+				_, err := Resolve(m.backend, "noresolver.eth", EthereumMainnet)
+				return err
+			},
+			err: ErrNoResolver,
+			msg: "no resolver",
+		},
+		{
+			name: "NotAResolver",
+			fn: func() error {
+				_, err := NewReverseResolverAt(m.backend, address, EthereumMainnet)
+				return err
+			},
+			err: ErrNotAResolver,
+			msg: "not a resolver",
+		},
+		{
+			name: "NoResolution",
+			fn: func() error {
+				m.register("addr.reverse", address, UnknownAddress)
+				m.register("0000000000000000000000000000000000000001.addr.reverse", address, UnknownAddress)
+				_, err := ReverseResolve(m.backend, address, EthereumMainnet)
+				return err
+			},
+			err: ErrNoResolution,
+			msg: "no resolution",
+		},
+		{
+			name: "FormatUnsupported",
+			fn: func() error {
+				_, err := StringToContenthash("bad://foo")
+				return err
+			},
+			err: ErrFormatUnsupported,
+			msg: "unknown codec bad",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := test.fn()
+			require.ErrorIs(t, err, test.err)
+			require.EqualError(t, err, test.msg)
+		})
+	}
+}
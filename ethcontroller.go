@@ -36,6 +36,10 @@ type ETHController struct {
 
 // NewETHController creates a new controller for a given domain.
 func NewETHController(backend bind.ContractBackend, domain string) (*ETHController, error) {
+	if address := ChainConfigFor(EthereumMainnet).ETHController; address != UnknownAddress {
+		return NewETHControllerAt(backend, domain, address)
+	}
+
 	registry, err := NewRegistry(backend, EthereumMainnet)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,227 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// rpcLimitExceededCode is the JSON-RPC error code returned by providers when
+// a request exceeds a rate or resource limit.
+const rpcLimitExceededCode = -32005
+
+// FailoverBackend is a contract backend that spreads requests over a number
+// of upstream backends.  Requests go to the first backend that can serve
+// them; transient failures move on to the next backend, and once all backends
+// have failed the whole set is retried with exponential backoff.
+type FailoverBackend struct {
+	backends []bind.ContractBackend
+
+	// MaxRetries is the number of times the set of backends is retried after
+	// all of them have failed.
+	MaxRetries int
+	// InitialBackoff is the delay before the first retry.
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between retries.
+	MaxBackoff time.Duration
+}
+
+var _ bind.ContractBackend = (*FailoverBackend)(nil)
+
+// NewFailoverBackend creates a failover backend over the supplied backends,
+// which are tried in order.
+func NewFailoverBackend(backends ...bind.ContractBackend) (*FailoverBackend, error) {
+	if len(backends) == 0 {
+		return nil, errors.New("no backends supplied")
+	}
+	for _, backend := range backends {
+		if backend == nil {
+			return nil, errors.New("nil backend supplied")
+		}
+	}
+
+	return &FailoverBackend{
+		backends:       backends,
+		MaxRetries:     3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+	}, nil
+}
+
+// IsTransientError returns true if an error returned by a backend is likely to
+// succeed if retried.  Only errors known to be transient are reported as such:
+// network failures, HTTP responses with status 429 or 5xx, and JSON-RPC errors
+// for exceeded limits.  Other errors, such as reverted calls, are not.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		// Retrying cannot help once the caller has given up.
+		return false
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr.ErrorCode() == rpcLimitExceededCode
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// failover runs the request against each backend in turn until one succeeds
+// or returns a non-transient error.
+func failover[T any](ctx context.Context, b *FailoverBackend, request func(bind.ContractBackend) (T, error)) (T, error) {
+	var res T
+	var err error
+	backoff := b.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		for _, backend := range b.backends {
+			res, err = request(backend)
+			if !IsTransientError(err) {
+				return res, err
+			}
+		}
+		if attempt >= b.MaxRetries {
+			return res, err
+		}
+
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		select {
+		case <-ctx.Done():
+			return res, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if backoff > b.MaxBackoff {
+			backoff = b.MaxBackoff
+		}
+	}
+}
+
+// CodeAt returns the code of the given account.
+func (b *FailoverBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return failover(ctx, b, func(backend bind.ContractBackend) ([]byte, error) {
+		return backend.CodeAt(ctx, contract, blockNumber)
+	})
+}
+
+// CallContract executes an Ethereum contract call with the specified data as the input.
+func (b *FailoverBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return failover(ctx, b, func(backend bind.ContractBackend) ([]byte, error) {
+		return backend.CallContract(ctx, call, blockNumber)
+	})
+}
+
+// HeaderByNumber returns a block header from the current canonical chain.
+func (b *FailoverBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	return failover(ctx, b, func(backend bind.ContractBackend) (*types.Header, error) {
+		return backend.HeaderByNumber(ctx, number)
+	})
+}
+
+// PendingCodeAt returns the code of the given account in the pending state.
+func (b *FailoverBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return failover(ctx, b, func(backend bind.ContractBackend) ([]byte, error) {
+		return backend.PendingCodeAt(ctx, account)
+	})
+}
+
+// PendingNonceAt retrieves the current pending nonce associated with an account.
+func (b *FailoverBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return failover(ctx, b, func(backend bind.ContractBackend) (uint64, error) {
+		return backend.PendingNonceAt(ctx, account)
+	})
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price.
+func (b *FailoverBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return failover(ctx, b, func(backend bind.ContractBackend) (*big.Int, error) {
+		return backend.SuggestGasPrice(ctx)
+	})
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap.
+func (b *FailoverBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return failover(ctx, b, func(backend bind.ContractBackend) (*big.Int, error) {
+		return backend.SuggestGasTipCap(ctx)
+	})
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction.
+func (b *FailoverBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return failover(ctx, b, func(backend bind.ContractBackend) (uint64, error) {
+		return backend.EstimateGas(ctx, call)
+	})
+}
+
+// SendTransaction injects the transaction into the pending pool for execution.
+func (b *FailoverBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := failover(ctx, b, func(backend bind.ContractBackend) (struct{}, error) {
+		return struct{}{}, backend.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+// FilterLogs executes a log filter operation.
+func (b *FailoverBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	return failover(ctx, b, func(backend bind.ContractBackend) ([]types.Log, error) {
+		return backend.FilterLogs(ctx, query)
+	})
+}
+
+// SubscribeFilterLogs creates a background log filtering operation.  The
+// subscription is made on the first backend that accepts it, and is not
+// moved to another backend if it subsequently fails.
+func (b *FailoverBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	var err error
+	for _, backend := range b.backends {
+		var sub ethereum.Subscription
+		sub, err = backend.SubscribeFilterLogs(ctx, query, ch)
+		if err == nil {
+			return sub, nil
+		}
+	}
+	return nil, err
+}
@@ -0,0 +1,223 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net"
+	"net/url"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyBackend is a mock backend whose calls fail a set number of times.
+type flakyBackend struct {
+	*mockBackend
+	mu       sync.Mutex
+	failures int
+	err      error
+	attempts int
+}
+
+func (b *flakyBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	b.attempts++
+	if b.failures != 0 {
+		b.failures--
+		b.mu.Unlock()
+		return nil, b.err
+	}
+	b.mu.Unlock()
+	return b.mockBackend.CallContract(ctx, call, blockNumber)
+}
+
+func TestFailoverBackend(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("failover.eth", address, address)
+	transient := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	tests := []struct {
+		name     string
+		primary  *flakyBackend
+		fallback *flakyBackend
+		err      string
+	}{
+		{
+			name:     "Healthy",
+			primary:  &flakyBackend{mockBackend: m.backend},
+			fallback: &flakyBackend{mockBackend: m.backend},
+		},
+		{
+			name:     "PrimaryDown",
+			primary:  &flakyBackend{mockBackend: m.backend, failures: -1, err: transient},
+			fallback: &flakyBackend{mockBackend: m.backend},
+		},
+		{
+			name:     "BothFlaky",
+			primary:  &flakyBackend{mockBackend: m.backend, failures: 3, err: transient},
+			fallback: &flakyBackend{mockBackend: m.backend, failures: 3, err: rpc.HTTPError{StatusCode: 503}},
+		},
+		{
+			name:     "BothDown",
+			primary:  &flakyBackend{mockBackend: m.backend, failures: -1, err: transient},
+			fallback: &flakyBackend{mockBackend: m.backend, failures: -1, err: transient},
+			err:      "dial tcp: connection refused",
+		},
+		{
+			name:     "Reverted",
+			primary:  &flakyBackend{mockBackend: m.backend, failures: -1, err: errors.New("execution reverted")},
+			fallback: &flakyBackend{mockBackend: m.backend},
+			err:      "execution reverted",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			backend, err := NewFailoverBackend(test.primary, test.fallback)
+			require.NoError(t, err)
+			backend.InitialBackoff = time.Millisecond
+
+			resolved, err := Resolve(backend, "failover.eth", EthereumMainnet)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, address, resolved)
+		})
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	tests := []struct {
+		name      string
+		err       error
+		transient bool
+	}{
+		{
+			name: "Nil",
+		},
+		{
+			name: "Unknown",
+			err:  errors.New("connection refused"),
+		},
+		{
+			name: "Reverted",
+			err:  errors.New("execution reverted"),
+		},
+		{
+			name: "Cancelled",
+			err:  fmt.Errorf("request failed: %w", context.Canceled),
+		},
+		{
+			name: "DeadlineExceeded",
+			err:  &url.Error{Op: "Post", URL: "http://localhost", Err: context.DeadlineExceeded},
+		},
+		{
+			name:      "ConnectionRefused",
+			err:       &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED},
+			transient: true,
+		},
+		{
+			name: "UnsupportedScheme",
+			err:  &url.Error{Op: "Post", URL: "ftp://localhost", Err: errors.New("unsupported protocol scheme")},
+		},
+		{
+			name:      "Timeout",
+			err:       &url.Error{Op: "Post", URL: "http://localhost", Err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}},
+			transient: true,
+		},
+		{
+			name:      "ConnectionReset",
+			err:       fmt.Errorf("read failed: %w", syscall.ECONNRESET),
+			transient: true,
+		},
+		{
+			name:      "UnexpectedEOF",
+			err:       &url.Error{Op: "Post", URL: "http://localhost", Err: io.ErrUnexpectedEOF},
+			transient: true,
+		},
+		{
+			name:      "TooManyRequests",
+			err:       rpc.HTTPError{StatusCode: 429},
+			transient: true,
+		},
+		{
+			name:      "ServerError",
+			err:       rpc.HTTPError{StatusCode: 502},
+			transient: true,
+		},
+		{
+			name: "BadRequest",
+			err:  rpc.HTTPError{StatusCode: 400},
+		},
+		{
+			name:      "LimitExceeded",
+			err:       &mockRPCError{code: rpcLimitExceededCode},
+			transient: true,
+		},
+		{
+			name: "InvalidParams",
+			err:  &mockRPCError{code: -32602},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.transient, IsTransientError(test.err))
+		})
+	}
+}
+
+// mockRPCError is a JSON-RPC error with a given code.
+type mockRPCError struct {
+	code int
+}
+
+func (e *mockRPCError) Error() string  { return fmt.Sprintf("rpc error %d", e.code) }
+func (e *mockRPCError) ErrorCode() int { return e.code }
+
+func TestFailoverBackendCancelled(t *testing.T) {
+	m := newMockENS()
+	primary := &flakyBackend{mockBackend: m.backend, failures: -1, err: &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}}
+	backend, err := NewFailoverBackend(primary)
+	require.NoError(t, err)
+	backend.InitialBackoff = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = backend.CallContract(ctx, ethereum.CallMsg{}, nil)
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, primary.attempts)
+}
+
+func TestNewFailoverBackend(t *testing.T) {
+	_, err := NewFailoverBackend()
+	require.EqualError(t, err, "no backends supplied")
+	_, err = NewFailoverBackend(nil)
+	require.EqualError(t, err, "nil backend supplied")
+}
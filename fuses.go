@@ -0,0 +1,255 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+)
+
+// Fuses are the fuses of a wrapped name.
+type Fuses uint32
+
+// Fuses defined by the name wrapper.  The lower 16 bits are controlled by the
+// owner of a name, the upper 16 bits by the owner of its parent.
+const (
+	FuseCannotUnwrap          Fuses = 1
+	FuseCannotBurnFuses       Fuses = 2
+	FuseCannotTransfer        Fuses = 4
+	FuseCannotSetResolver     Fuses = 8
+	FuseCannotSetTTL          Fuses = 16
+	FuseCannotCreateSubdomain Fuses = 32
+	FuseCannotApprove         Fuses = 64
+	FuseParentCannotControl   Fuses = 1 << 16
+	FuseIsDotEth              Fuses = 1 << 17
+	FuseCanExtendExpiry       Fuses = 1 << 18
+	// FuseCanDoEverything is the absence of fuses.
+	FuseCanDoEverything Fuses = 0
+)
+
+// parentControlledFuses are the fuses that can only be burned by the owner of
+// the parent of a name.
+const parentControlledFuses Fuses = 0xffff0000
+
+// ownerControlledFuses are the fuses that can be burned by the owner of a
+// name.
+const ownerControlledFuses Fuses = 0x0000ffff
+
+// fuseNames are the names of the fuses, as used by the name wrapper.
+var fuseNames = []struct {
+	fuse Fuses
+	name string
+}{
+	{FuseCannotUnwrap, "CANNOT_UNWRAP"},
+	{FuseCannotBurnFuses, "CANNOT_BURN_FUSES"},
+	{FuseCannotTransfer, "CANNOT_TRANSFER"},
+	{FuseCannotSetResolver, "CANNOT_SET_RESOLVER"},
+	{FuseCannotSetTTL, "CANNOT_SET_TTL"},
+	{FuseCannotCreateSubdomain, "CANNOT_CREATE_SUBDOMAIN"},
+	{FuseCannotApprove, "CANNOT_APPROVE"},
+	{FuseParentCannotControl, "PARENT_CANNOT_CONTROL"},
+	{FuseIsDotEth, "IS_DOT_ETH"},
+	{FuseCanExtendExpiry, "CAN_EXTEND_EXPIRY"},
+}
+
+// ParseFuses parses fuse names, for example "CANNOT_UNWRAP", in to fuses.
+func ParseFuses(names ...string) (Fuses, error) {
+	var fuses Fuses
+	for _, name := range names {
+		found := false
+		for _, fuseName := range fuseNames {
+			if strings.EqualFold(name, fuseName.name) {
+				fuses |= fuseName.fuse
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown fuse %s", name)
+		}
+	}
+
+	return fuses, nil
+}
+
+// Has returns true if all of the given fuses are burned.
+func (f Fuses) Has(fuses Fuses) bool {
+	return f&fuses == fuses
+}
+
+// Names returns the names of the burned fuses.  Burned fuses without a name
+// are returned in hex.
+func (f Fuses) Names() []string {
+	names := make([]string, 0)
+	remaining := f
+	for _, fuseName := range fuseNames {
+		if f&fuseName.fuse != 0 {
+			names = append(names, fuseName.name)
+			remaining &^= fuseName.fuse
+		}
+	}
+	for bit := Fuses(1); remaining != 0; bit <<= 1 {
+		if remaining&bit != 0 {
+			names = append(names, fmt.Sprintf("%#x", uint32(bit)))
+			remaining &^= bit
+		}
+	}
+
+	return names
+}
+
+// String returns the names of the burned fuses separated by "|", or
+// "CAN_DO_EVERYTHING" if no fuses are burned.
+func (f Fuses) String() string {
+	if f == FuseCanDoEverything {
+		return "CAN_DO_EVERYTHING"
+	}
+	return strings.Join(f.Names(), "|")
+}
+
+// Permissions are the effective permissions of a wrapped name.
+type Permissions struct {
+	// Locked is true if the name cannot be unwrapped, and so its fuses
+	// are enforced.
+	Locked bool
+	// Emancipated is true if the owner of the parent can no longer control
+	// the name.
+	Emancipated bool
+	// CanUnwrap is true if the owner can unwrap the name.
+	CanUnwrap bool
+	// CanBurnFuses is true if the owner can burn further fuses.
+	CanBurnFuses bool
+	// CanTransfer is true if the owner can transfer the name.
+	CanTransfer bool
+	// CanSetResolver is true if the owner can set the resolver.
+	CanSetResolver bool
+	// CanSetTTL is true if the owner can set the TTL.
+	CanSetTTL bool
+	// CanCreateSubdomain is true if the owner can create new subdomains.
+	CanCreateSubdomain bool
+	// CanApprove is true if the owner can approve an address to manage
+	// subnames.
+	CanApprove bool
+	// CanExtendExpiry is true if the owner can extend the expiry of the name,
+	// rather than only the owner of the parent.
+	CanExtendExpiry bool
+}
+
+// Permissions returns the effective permissions given by the fuses.
+func (f Fuses) Permissions() *Permissions {
+	return &Permissions{
+		Locked:             f.Has(FuseCannotUnwrap),
+		Emancipated:        f.Has(FuseParentCannotControl),
+		CanUnwrap:          !f.Has(FuseCannotUnwrap),
+		CanBurnFuses:       !f.Has(FuseCannotBurnFuses),
+		CanTransfer:        !f.Has(FuseCannotTransfer),
+		CanSetResolver:     !f.Has(FuseCannotSetResolver),
+		CanSetTTL:          !f.Has(FuseCannotSetTTL),
+		CanCreateSubdomain: !f.Has(FuseCannotCreateSubdomain),
+		CanApprove:         !f.Has(FuseCannotApprove),
+		CanExtendExpiry:    f.Has(FuseCanExtendExpiry),
+	}
+}
+
+// Permissions returns the effective permissions of a wrapped name.  The name
+// wrapper clears the fuses of expired names, so expired names have all
+// permissions.
+func (w *NameWrapper) Permissions(name string) (*Permissions, error) {
+	owner, fuses, _, err := w.Data(name)
+	if err != nil {
+		return nil, err
+	}
+	if owner == UnknownAddress {
+		return nil, fmt.Errorf("%s is not wrapped", name)
+	}
+
+	return fuses.Permissions(), nil
+}
+
+// BurnFuses burns owner-controlled fuses on a wrapped name.  Burned fuses
+// cannot be restored until the name expires, so the operation is checked
+// before the transaction is sent: the name must be emancipated, the fuses
+// must be new, and burning CANNOT_SET_RESOLVER requires the name to have a
+// resolver.
+func (w *NameWrapper) BurnFuses(opts *bind.TransactOpts, name string, fuses Fuses) (*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+	if fuses == FuseCanDoEverything {
+		return nil, errors.New("no fuses supplied")
+	}
+	if fuses&^ownerControlledFuses != 0 {
+		return nil, fmt.Errorf("%s cannot be burned by the owner", (fuses &^ ownerControlledFuses).String())
+	}
+	nameHash, err := NameHash(name)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, current, expiry, err := w.Data(name)
+	if err != nil {
+		return nil, err
+	}
+	if owner == UnknownAddress {
+		return nil, fmt.Errorf("%s is not wrapped", name)
+	}
+	if !expiry.After(time.Now()) {
+		return nil, fmt.Errorf("%s has expired", name)
+	}
+	canModify, err := w.Contract.CanModifyName(nil, nameHash, opts.From)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, fmt.Errorf("%s is not authorised to manage %s", opts.From.Hex(), name)
+	}
+	if current.Has(FuseCannotBurnFuses) {
+		return nil, fmt.Errorf("%s has CANNOT_BURN_FUSES burned", name)
+	}
+	if current.Has(fuses) {
+		return nil, fmt.Errorf("%s already burned", fuses.String())
+	}
+	if !current.Has(FuseParentCannotControl) {
+		return nil, fmt.Errorf("%s must have PARENT_CANNOT_CONTROL burned by its parent before fuses can be burned", name)
+	}
+	if !(current | fuses).Has(FuseCannotUnwrap) {
+		return nil, errors.New("CANNOT_UNWRAP must be burned before or with other fuses")
+	}
+	if fuses.Has(FuseCannotSetResolver) {
+		registryAddr, err := w.Contract.Ens(nil)
+		if err != nil {
+			return nil, err
+		}
+		registryContract, err := registry.NewContract(registryAddr, w.backend)
+		if err != nil {
+			return nil, err
+		}
+		resolverAddr, err := registryContract.Resolver(nil, nameHash)
+		if err != nil {
+			return nil, err
+		}
+		if resolverAddr == UnknownAddress {
+			return nil, fmt.Errorf("%s has no resolver; burning CANNOT_SET_RESOLVER would leave it without one", name)
+		}
+	}
+
+	return w.Contract.SetFuses(opts, nameHash, uint16(fuses))
+}
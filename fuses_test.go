@@ -0,0 +1,210 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/namewrapper"
+)
+
+func TestFusesString(t *testing.T) {
+	tests := []struct {
+		name  string
+		fuses Fuses
+		res   string
+	}{
+		{
+			name:  "None",
+			fuses: FuseCanDoEverything,
+			res:   "CAN_DO_EVERYTHING",
+		},
+		{
+			name:  "Single",
+			fuses: FuseCannotUnwrap,
+			res:   "CANNOT_UNWRAP",
+		},
+		{
+			name:  "Multiple",
+			fuses: FuseCannotUnwrap | FuseCannotTransfer | FuseParentCannotControl | FuseIsDotEth,
+			res:   "CANNOT_UNWRAP|CANNOT_TRANSFER|PARENT_CANNOT_CONTROL|IS_DOT_ETH",
+		},
+		{
+			name:  "Unnamed",
+			fuses: FuseCannotUnwrap | 0x80 | 0x80000,
+			res:   "CANNOT_UNWRAP|0x80|0x80000",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			require.Equal(t, test.res, test.fuses.String())
+		})
+	}
+}
+
+func TestParseFuses(t *testing.T) {
+	fuses, err := ParseFuses("CANNOT_UNWRAP", "cannot_transfer")
+	require.NoError(t, err)
+	require.Equal(t, FuseCannotUnwrap|FuseCannotTransfer, fuses)
+
+	_, err = ParseFuses("CANNOT_FLY")
+	require.EqualError(t, err, "unknown fuse CANNOT_FLY")
+}
+
+func TestFusesPermissions(t *testing.T) {
+	permissions := (FuseCannotUnwrap | FuseCannotSetResolver | FuseParentCannotControl).Permissions()
+	require.Equal(t, &Permissions{
+		Locked:             true,
+		Emancipated:        true,
+		CanUnwrap:          false,
+		CanBurnFuses:       true,
+		CanTransfer:        true,
+		CanSetResolver:     false,
+		CanSetTTL:          true,
+		CanCreateSubdomain: true,
+		CanApprove:         true,
+		CanExtendExpiry:    false,
+	}, permissions)
+}
+
+func TestBurnFuses(t *testing.T) {
+	m := newMockENS()
+	opts := testTransactOpts(t)
+	m.register("locked.eth", opts.From, UnknownAddress)
+	m.register("emancipated.eth", opts.From, UnknownAddress)
+	m.register("unlocked.eth", opts.From, UnknownAddress)
+	m.register("fused.eth", opts.From, UnknownAddress)
+	m.mu.Lock()
+	delete(m.resolvers, mustNameHash("emancipated.eth"))
+	m.mu.Unlock()
+
+	expiry := uint64(time.Now().Add(365 * 24 * time.Hour).Unix())
+	data := map[[32]byte][]interface{}{
+		mustNameHash("locked.eth"):      {opts.From, uint32(FuseCannotUnwrap | FuseParentCannotControl), expiry},
+		mustNameHash("emancipated.eth"): {opts.From, uint32(FuseParentCannotControl), expiry},
+		mustNameHash("unlocked.eth"):    {opts.From, uint32(0), expiry},
+		mustNameHash("fused.eth"):       {opts.From, uint32(FuseCannotUnwrap | FuseCannotBurnFuses | FuseParentCannotControl), expiry},
+		mustNameHash("expired.eth"):     {opts.From, uint32(0), uint64(1)},
+	}
+	var burned uint16
+	m.backend.deploy(ChainConfigFor(EthereumMainnet).NameWrapper, namewrapper.ContractABI).
+		on("getData", func(args []interface{}) ([]interface{}, error) {
+			if res, exists := data[common.BigToHash(args[0].(*big.Int))]; exists {
+				return res, nil
+			}
+			return []interface{}{UnknownAddress, uint32(0), uint64(0)}, nil
+		}).
+		on("canModifyName", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{args[1].(common.Address) == opts.From}, nil
+		}).
+		on("ens", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{m.registryAddr}, nil
+		}).
+		on("setFuses", func(args []interface{}) ([]interface{}, error) {
+			burned = args[1].(uint16)
+			return []interface{}{uint32(0)}, nil
+		})
+	wrapper, err := NewNameWrapper(m.backend, EthereumMainnet)
+	require.NoError(t, err)
+
+	permissions, err := wrapper.Permissions("locked.eth")
+	require.NoError(t, err)
+	require.True(t, permissions.Locked)
+	_, err = wrapper.Permissions("unwrapped.eth")
+	require.EqualError(t, err, "unwrapped.eth is not wrapped")
+
+	tests := []struct {
+		name   string
+		domain string
+		fuses  Fuses
+		err    string
+	}{
+		{
+			name:   "None",
+			domain: "locked.eth",
+			err:    "no fuses supplied",
+		},
+		{
+			name:   "ParentControlled",
+			domain: "locked.eth",
+			fuses:  FuseCanExtendExpiry,
+			err:    "CAN_EXTEND_EXPIRY cannot be burned by the owner",
+		},
+		{
+			name:   "Unwrapped",
+			domain: "unwrapped.eth",
+			fuses:  FuseCannotTransfer,
+			err:    "unwrapped.eth is not wrapped",
+		},
+		{
+			name:   "Expired",
+			domain: "expired.eth",
+			fuses:  FuseCannotTransfer,
+			err:    "expired.eth has expired",
+		},
+		{
+			name:   "CannotBurnFuses",
+			domain: "fused.eth",
+			fuses:  FuseCannotTransfer,
+			err:    "fused.eth has CANNOT_BURN_FUSES burned",
+		},
+		{
+			name:   "AlreadyBurned",
+			domain: "locked.eth",
+			fuses:  FuseCannotUnwrap,
+			err:    "CANNOT_UNWRAP already burned",
+		},
+		{
+			name:   "NotEmancipated",
+			domain: "unlocked.eth",
+			fuses:  FuseCannotUnwrap,
+			err:    "unlocked.eth must have PARENT_CANNOT_CONTROL burned by its parent before fuses can be burned",
+		},
+		{
+			name:   "NotLocked",
+			domain: "emancipated.eth",
+			fuses:  FuseCannotTransfer,
+			err:    "CANNOT_UNWRAP must be burned before or with other fuses",
+		},
+		{
+			name:   "NoResolver",
+			domain: "emancipated.eth",
+			fuses:  FuseCannotUnwrap | FuseCannotSetResolver,
+			err:    "emancipated.eth has no resolver; burning CANNOT_SET_RESOLVER would leave it without one",
+		},
+		{
+			name:   "Good",
+			domain: "locked.eth",
+			fuses:  FuseCannotTransfer | FuseCannotSetResolver,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := wrapper.BurnFuses(opts, test.domain, test.fuses)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, uint16(test.fuses), burned)
+		})
+	}
+}
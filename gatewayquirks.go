@@ -0,0 +1,140 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// GatewayQuirks describes how a CCIP-Read gateway departs from EIP-3668, so
+// that offchain lookups to it can be followed regardless.  Offchain subname
+// providers often run gateways that only accept POST requests or answer in
+// their own format.
+//
+// Some quirks are handled for all gateways without being registered: a
+// gateway that rejects a GET request with status 405 is retried with POST,
+// and responses are accepted with the data in a "result" field, as a JSON
+// string or as plain hex as well as in the EIP-3668 format.
+type GatewayQuirks struct {
+	// Post is true if requests are always sent with POST, even if the
+	// gateway's URL contains {data}.
+	Post bool
+	// DecodeResponse obtains the data from the body of a successful
+	// response.  If not supplied the body is decoded as described above.
+	DecodeResponse func(body []byte) ([]byte, error)
+}
+
+var (
+	gatewayQuirksMu sync.RWMutex
+	gatewayQuirks   = map[string]*GatewayQuirks{}
+)
+
+// RegisterGatewayQuirks registers the quirks of the gateways on a host,
+// replacing any existing quirks for the host.  The quirks also apply to
+// gateways on subdomains of the host that do not have quirks of their own.
+func RegisterGatewayQuirks(host string, quirks *GatewayQuirks) error {
+	if quirks == nil {
+		return errors.New("no gateway quirks supplied")
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		return errors.New("no host supplied")
+	}
+	if strings.ContainsAny(host, "/:") {
+		return fmt.Errorf("invalid host %s", host)
+	}
+
+	// Take a copy so that later changes by the caller have no effect.
+	registered := *quirks
+
+	gatewayQuirksMu.Lock()
+	gatewayQuirks[host] = &registered
+	gatewayQuirksMu.Unlock()
+
+	return nil
+}
+
+// UnregisterGatewayQuirks removes the quirks of the gateways on a host.
+func UnregisterGatewayQuirks(host string) {
+	gatewayQuirksMu.Lock()
+	delete(gatewayQuirks, strings.ToLower(strings.TrimSuffix(host, ".")))
+	gatewayQuirksMu.Unlock()
+}
+
+// gatewayQuirksFor returns the quirks of the gateways on a host, or empty
+// quirks if none have been registered.  The port, if any, is ignored.
+func gatewayQuirksFor(host string) *GatewayQuirks {
+	host = strings.ToLower(host)
+	if idx := strings.LastIndexByte(host, ':'); idx >= 0 && !strings.HasSuffix(host, "]") {
+		host = host[:idx]
+	}
+
+	gatewayQuirksMu.RLock()
+	defer gatewayQuirksMu.RUnlock()
+	for candidate := host; candidate != ""; {
+		if quirks, exists := gatewayQuirks[candidate]; exists {
+			return quirks
+		}
+		if idx := strings.IndexByte(candidate, '.'); idx >= 0 {
+			candidate = candidate[idx+1:]
+		} else {
+			candidate = ""
+		}
+	}
+
+	return &GatewayQuirks{}
+}
+
+// decodeGatewayResponse obtains the data from the body of a successful
+// gateway response, which may be in the EIP-3668 format, have the data in a
+// "result" field, or be the data alone as a JSON string or plain hex.
+func decodeGatewayResponse(body []byte) ([]byte, error) {
+	body = bytes.TrimSpace(body)
+	switch {
+	case len(body) == 0:
+		return nil, errors.New("invalid gateway response: empty body")
+	case body[0] == '{':
+		var response struct {
+			Data   *string `json:"data"`
+			Result *string `json:"result"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("invalid gateway response: %w", err)
+		}
+		switch {
+		case response.Data != nil:
+			return hexutil.Decode(*response.Data)
+		case response.Result != nil:
+			return hexutil.Decode(*response.Result)
+		default:
+			return nil, errors.New("invalid gateway response: no data")
+		}
+	case body[0] == '"':
+		var data string
+		if err := json.Unmarshal(body, &data); err != nil {
+			return nil, fmt.Errorf("invalid gateway response: %w", err)
+		}
+		return hexutil.Decode(data)
+	default:
+		return hexutil.Decode(string(body))
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeGatewayResponse(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		data []byte
+		err  string
+	}{
+		{
+			name: "Empty",
+			body: " ",
+			err:  "invalid gateway response: empty body",
+		},
+		{
+			name: "EIP3668",
+			body: `{"data":"0x0102"}`,
+			data: []byte{0x01, 0x02},
+		},
+		{
+			name: "EIP3668Empty",
+			body: `{"data":"0x"}`,
+			data: []byte{},
+		},
+		{
+			name: "Result",
+			body: `{"result":"0x0102"}`,
+			data: []byte{0x01, 0x02},
+		},
+		{
+			name: "NoData",
+			body: `{"message":"ok"}`,
+			err:  "invalid gateway response: no data",
+		},
+		{
+			name: "InvalidJSON",
+			body: `{"data":`,
+			err:  "invalid gateway response: unexpected end of JSON input",
+		},
+		{
+			name: "String",
+			body: `"0x0102"`,
+			data: []byte{0x01, 0x02},
+		},
+		{
+			name: "Hex",
+			body: "0x0102\n",
+			data: []byte{0x01, 0x02},
+		},
+		{
+			name: "NotHex",
+			body: "not found",
+			err:  "hex string without 0x prefix",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			data, err := decodeGatewayResponse([]byte(test.body))
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.data, data)
+		})
+	}
+}
+
+func TestRegisterGatewayQuirks(t *testing.T) {
+	require.EqualError(t, RegisterGatewayQuirks("gateway.example.com", nil), "no gateway quirks supplied")
+	require.EqualError(t, RegisterGatewayQuirks("", &GatewayQuirks{}), "no host supplied")
+	require.EqualError(t, RegisterGatewayQuirks("https://gateway.example.com/", &GatewayQuirks{}), "invalid host https://gateway.example.com/")
+
+	quirks := &GatewayQuirks{Post: true}
+	require.NoError(t, RegisterGatewayQuirks("Example.com.", quirks))
+	defer UnregisterGatewayQuirks("example.com")
+	quirks.Post = false
+
+	require.True(t, gatewayQuirksFor("example.com").Post)
+	require.True(t, gatewayQuirksFor("gateway.EXAMPLE.com:8443").Post)
+	require.False(t, gatewayQuirksFor("example.org").Post)
+	require.False(t, gatewayQuirksFor("notexample.com").Post)
+
+	UnregisterGatewayQuirks("example.com")
+	require.False(t, gatewayQuirksFor("example.com").Post)
+}
+
+func TestCCIPReadBackendGatewayQuirks(t *testing.T) {
+	var method string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		_, _ = w.Write([]byte("result=0102"))
+	}))
+	defer gateway.Close()
+	gatewayURL, err := url.Parse(gateway.URL)
+	require.NoError(t, err)
+
+	require.NoError(t, RegisterGatewayQuirks(gatewayURL.Hostname(), &GatewayQuirks{
+		Post: true,
+		DecodeResponse: func(body []byte) ([]byte, error) {
+			return hex.DecodeString(strings.TrimPrefix(string(body), "result="))
+		},
+	}))
+	defer UnregisterGatewayQuirks(gatewayURL.Hostname())
+
+	backend, err := NewCCIPReadBackend(newMockBackend(), nil)
+	require.NoError(t, err)
+	res, err := backend.fetch(context.Background(), &OffchainLookup{
+		URLs:     []string{gateway.URL + "/{sender}/{data}.json"},
+		CallData: []byte{0x01},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x01, 0x02}, res)
+	require.Equal(t, http.MethodPost, method)
+}
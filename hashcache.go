@@ -0,0 +1,81 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"sync"
+	"time"
+)
+
+// hashCacheTTL is the lifetime of entries in the hash cache.  Hashes never
+// change, so in practice entries only leave the cache when evicted.
+const hashCacheTTL = 365 * 24 * time.Hour
+
+const (
+	cacheKindNameHash  = "namehash"
+	cacheKindLabelHash = "labelhash"
+)
+
+var (
+	hashCacheMu sync.RWMutex
+	hashCache   Cache
+)
+
+// SetHashCache sets a cache for the results of NameHash and LabelHash, which
+// are keyed by the name or label as supplied so that normalization is also
+// avoided.  This benefits applications such as indexers that hash the same
+// names many times, and as the cache is used by NameHash it is shared by all
+// resolution functions.  A bounded cache should be used, for example:
+//
+//	ens.SetHashCache(ens.NewLRUCache(100000))
+//
+// If supplied with nil, hashes are not cached.  This is the default.  As the
+// cache is shared by all clients, lookups in it are not reported to the
+// metrics of any client.
+func SetHashCache(cache Cache) {
+	hashCacheMu.Lock()
+	defer hashCacheMu.Unlock()
+	hashCache = cache
+}
+
+// currentHashCache returns the hash cache, or nil if there is none.
+func currentHashCache() Cache {
+	hashCacheMu.RLock()
+	defer hashCacheMu.RUnlock()
+	return hashCache
+}
+
+// cachedHash returns the hash of input using the given hash function,
+// consulting the hash cache if there is one.  Errors are not cached.
+func cachedHash(kind string, input string, hash func(string) ([32]byte, error)) ([32]byte, error) {
+	cache := currentHashCache()
+	if cache == nil {
+		return hash(input)
+	}
+
+	key := kind + ":" + input
+	value, exists := cache.Get(key)
+	if res, isHash := value.([32]byte); exists && isHash {
+		return res, nil
+	}
+
+	res, err := hash(input)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	cache.Set(key, res, hashCacheTTL)
+
+	return res, nil
+}
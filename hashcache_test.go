@@ -0,0 +1,53 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashCache(t *testing.T) {
+	cache := NewLRUCache(2)
+	SetHashCache(cache)
+	t.Cleanup(func() { SetHashCache(nil) })
+
+	expected, err := nameHash("foo.eth")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		hash, err := NameHash("foo.eth")
+		require.NoError(t, err)
+		require.Equal(t, expected, hash)
+	}
+	require.Equal(t, 1, cache.Len())
+
+	// Entries are keyed by the supplied name.
+	hash, err := NameHash("Foo.eth")
+	require.NoError(t, err)
+	require.Equal(t, expected, hash)
+	require.Equal(t, 2, cache.Len())
+
+	// Label hashes are held separately and the cache is bounded.
+	expected, err = labelHash("foo")
+	require.NoError(t, err)
+	hash, err = LabelHash("foo")
+	require.NoError(t, err)
+	require.Equal(t, expected, hash)
+	require.Equal(t, 2, cache.Len())
+	_, exists := cache.Get(cacheKindLabelHash + ":foo")
+	require.True(t, exists)
+}
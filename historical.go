@@ -0,0 +1,70 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ResolveAt resolves an ENS name in to an Ethereum address as it was at the
+// given block.  The resolver used is the one set for the name at that block,
+// so the backend must be able to serve archival state.
+func ResolveAt(backend bind.ContractBackend, input string, blockNumber *big.Int, chainId ChainId, opts ...CallOption) (common.Address, error) {
+	opts = append(opts, WithBlockNumber(blockNumber))
+	if err := checkRegistryAt(backend, blockNumber, chainId, opts); err != nil {
+		return UnknownAddress, err
+	}
+
+	return Resolve(backend, input, chainId, opts...)
+}
+
+// ReverseResolveAt resolves an address in to an ENS name as it was at the
+// given block.  The backend must be able to serve archival state.
+func ReverseResolveAt(backend bind.ContractBackend, address common.Address, blockNumber *big.Int, chainId ChainId, opts ...CallOption) (string, error) {
+	opts = append(opts, WithBlockNumber(blockNumber))
+	if err := checkRegistryAt(backend, blockNumber, chainId, opts); err != nil {
+		return "", err
+	}
+
+	return ReverseResolve(backend, address, chainId, opts...)
+}
+
+// checkRegistryAt ensures that the registry existed at the given block.
+func checkRegistryAt(backend bind.ContractBackend, blockNumber *big.Int, chainId ChainId, opts []CallOption) error {
+	if blockNumber == nil {
+		return errors.New("block number required")
+	}
+
+	registryAddress, err := RegistryContractAddress(backend, chainId)
+	if err != nil {
+		return err
+	}
+	o, release := newCallOptions(opts)
+	defer release()
+	code, err := backend.CodeAt(o.ctx, registryAddress, blockNumber)
+	if err != nil {
+		return err
+	}
+	if len(code) == 0 {
+		return fmt.Errorf("no registry at block %s", blockNumber.String())
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveBackend serves state from one of two mock deployments depending on
+// the block requested.  There is no state before block 10, the earlier
+// deployment is used up to block 100 and the later one after that.
+type archiveBackend struct {
+	*mockBackend
+	early *mockBackend
+}
+
+func (b *archiveBackend) at(blockNumber *big.Int) *mockBackend {
+	if blockNumber != nil && blockNumber.Cmp(big.NewInt(100)) < 0 {
+		return b.early
+	}
+	return b.mockBackend
+}
+
+func (b *archiveBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if blockNumber != nil && blockNumber.Cmp(big.NewInt(10)) < 0 {
+		return nil, nil
+	}
+	return b.at(blockNumber).CodeAt(ctx, contract, blockNumber)
+}
+
+func (b *archiveBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return b.at(blockNumber).CallContract(ctx, call, blockNumber)
+}
+
+func TestResolveAt(t *testing.T) {
+	early := newMockENS()
+	late := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	updated := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	early.register("history.eth", address, address)
+	early.setReverse(address, "history.eth")
+	late.register("history.eth", address, updated)
+	backend := &archiveBackend{mockBackend: late.backend, early: early.backend}
+
+	tests := []struct {
+		name        string
+		blockNumber *big.Int
+		address     common.Address
+		reverse     string
+		err         string
+	}{
+		{
+			name: "Nil",
+			err:  "block number required",
+		},
+		{
+			name:        "BeforeRegistry",
+			blockNumber: big.NewInt(5),
+			err:         "no registry at block 5",
+		},
+		{
+			name:        "Early",
+			blockNumber: big.NewInt(50),
+			address:     address,
+			reverse:     "history.eth",
+		},
+		{
+			name:        "Late",
+			blockNumber: big.NewInt(500),
+			address:     updated,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resolved, err := ResolveAt(backend, "history.eth", test.blockNumber, EthereumMainnet)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.address, resolved)
+
+			name, err := ReverseResolveAt(backend, address, test.blockNumber, EthereumMainnet)
+			if test.reverse == "" {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, test.reverse, name)
+			}
+		})
+	}
+}
@@ -0,0 +1,356 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wealdtech/go-ens/v3/contracts/baseregistrar"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+// textChangedWithValueABI is the TextChanged event of current public
+// resolvers, which also log the value of the record.
+const textChangedWithValueABI = `[{"type":"event","name":"TextChanged","anonymous":false,"inputs":[{"name":"node","type":"bytes32","indexed":true},{"name":"indexedKey","type":"string","indexed":true},{"name":"key","type":"string","indexed":false},{"name":"value","type":"string","indexed":false}]}]`
+
+// NameEventMeta holds the details of the log that records an event.
+type NameEventMeta struct {
+	// Contract is the contract that emitted the event.
+	Contract common.Address
+	// BlockNumber is the block in which the event occurred.
+	BlockNumber uint64
+	// TransactionHash is the hash of the transaction that generated the event.
+	TransactionHash common.Hash
+	// LogIndex is the index of the log in the block.
+	LogIndex uint
+}
+
+// Meta returns the details of the log that records the event.
+func (m *NameEventMeta) Meta() *NameEventMeta {
+	return m
+}
+
+// NameEvent is an event in the history of a name.  It is one of the
+// *...Event types in this package.
+type NameEvent interface {
+	// Meta returns the details of the log that records the event.
+	Meta() *NameEventMeta
+}
+
+// OwnerChangedEvent is a change of the owner of a name in the registry, who
+// controls its records.
+type OwnerChangedEvent struct {
+	NameEventMeta
+	Owner common.Address
+}
+
+// ResolverChangedEvent is a change of the resolver of a name.
+type ResolverChangedEvent struct {
+	NameEventMeta
+	Resolver common.Address
+}
+
+// TTLChangedEvent is a change of the TTL of a name.
+type TTLChangedEvent struct {
+	NameEventMeta
+	TTL time.Duration
+}
+
+// AddressChangedEvent is a change of an address record of a name.
+type AddressChangedEvent struct {
+	NameEventMeta
+	CoinType uint64
+	Address  []byte
+}
+
+// TextChangedEvent is a change of a text record of a name.  Value is only
+// available for resolvers that log it.
+type TextChangedEvent struct {
+	NameEventMeta
+	Key   string
+	Value string
+}
+
+// ContenthashChangedEvent is a change of the contenthash of a name.
+type ContenthashChangedEvent struct {
+	NameEventMeta
+	Contenthash []byte
+}
+
+// RegisteredEvent is the registration of a name with the registrar.
+type RegisteredEvent struct {
+	NameEventMeta
+	Registrant common.Address
+	Expiry     time.Time
+}
+
+// RenewedEvent is the renewal of the registration of a name.
+type RenewedEvent struct {
+	NameEventMeta
+	Expiry time.Time
+}
+
+// RegistrantChangedEvent is a transfer of the registrar token of a name.
+type RegistrantChangedEvent struct {
+	NameEventMeta
+	Registrant common.Address
+}
+
+// History returns the events in the history of the name from the given
+// block onwards, in chronological order.  The history is reconstructed from
+// the logs of the registry, the resolvers that the name has used and, for
+// .eth second-level names, the registrar.
+//
+// This requires only an RPC connection, but the backend must support log
+// queries over the range of blocks requested.
+func (n *Name) History(ctx context.Context, fromBlock uint64) ([]NameEvent, error) {
+	nameHash, err := NameHash(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	parentHash, err := NameHash(n.Domain)
+	if err != nil {
+		return nil, err
+	}
+	labelHash, err := LabelHash(n.Label)
+	if err != nil {
+		return nil, err
+	}
+
+	registryABI, err := abi.JSON(strings.NewReader(registry.ContractABI))
+	if err != nil {
+		return nil, err
+	}
+	resolverABI, err := abi.JSON(strings.NewReader(resolver.ContractABI))
+	if err != nil {
+		return nil, err
+	}
+	textChangedABI, err := abi.JSON(strings.NewReader(textChangedWithValueABI))
+	if err != nil {
+		return nil, err
+	}
+	registrarABI, err := abi.JSON(strings.NewReader(baseregistrar.ContractABI))
+	if err != nil {
+		return nil, err
+	}
+
+	from := new(big.Int).SetUint64(fromBlock)
+	logs, err := n.backend.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: from,
+		Addresses: []common.Address{n.registry.ContractAddr},
+		Topics: [][]common.Hash{
+			{registryABI.Events["Transfer"].ID, registryABI.Events["NewResolver"].ID, registryABI.Events["NewTTL"].ID},
+			{nameHash},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	newOwnerLogs, err := n.backend.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: from,
+		Addresses: []common.Address{n.registry.ContractAddr},
+		Topics:    [][]common.Hash{{registryABI.Events["NewOwner"].ID}, {parentHash}, {labelHash}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	logs = append(logs, newOwnerLogs...)
+
+	// Records are held by the resolvers that the name has used, including
+	// its current resolver which may have been set before fromBlock.
+	resolvers := make([]common.Address, 0)
+	seen := make(map[common.Address]bool)
+	addResolver := func(address common.Address) {
+		if address != UnknownAddress && !seen[address] {
+			seen[address] = true
+			resolvers = append(resolvers, address)
+		}
+	}
+	for _, log := range logs {
+		if log.Topics[0] == registryABI.Events["NewResolver"].ID && len(log.Data) == 32 {
+			addResolver(common.BytesToAddress(log.Data))
+		}
+	}
+	if current, err := n.registry.Contract.Resolver(&bind.CallOpts{Context: ctx}, nameHash); err == nil {
+		addResolver(current)
+	}
+	if len(resolvers) > 0 {
+		resolverLogs, err := n.backend.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: from,
+			Addresses: resolvers,
+			Topics: [][]common.Hash{
+				{
+					resolverABI.Events["AddrChanged"].ID,
+					resolverABI.Events["AddressChanged"].ID,
+					resolverABI.Events["TextChanged"].ID,
+					textChangedABI.Events["TextChanged"].ID,
+					resolverABI.Events["ContenthashChanged"].ID,
+				},
+				{nameHash},
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, resolverLogs...)
+	}
+
+	if n.Domain == "eth" && n.registrar != nil {
+		transferLogs, err := n.backend.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: from,
+			Addresses: []common.Address{n.registrar.ContractAddr},
+			Topics:    [][]common.Hash{{registrarABI.Events["Transfer"].ID}, nil, nil, {labelHash}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, transferLogs...)
+		registrationLogs, err := n.backend.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: from,
+			Addresses: []common.Address{n.registrar.ContractAddr},
+			Topics:    [][]common.Hash{{registrarABI.Events["NameRegistered"].ID, registrarABI.Events["NameRenewed"].ID}, {labelHash}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, registrationLogs...)
+	}
+
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	decoder := &nameEventDecoder{
+		registryABI:    registryABI,
+		resolverABI:    resolverABI,
+		textChangedABI: textChangedABI,
+		registrarABI:   registrarABI,
+		registrar:      UnknownAddress,
+	}
+	if n.registrar != nil {
+		decoder.registrar = n.registrar.ContractAddr
+	}
+
+	// Resolvers emit both AddrChanged and AddressChanged when the Ethereum
+	// address is set, so AddrChanged is only used if the transaction does
+	// not also contain AddressChanged.
+	addressChangedTxs := make(map[common.Hash]bool)
+	for _, log := range logs {
+		if log.Topics[0] == resolverABI.Events["AddressChanged"].ID {
+			addressChangedTxs[log.TxHash] = true
+		}
+	}
+
+	res := make([]NameEvent, 0, len(logs))
+	for _, log := range logs {
+		if log.Topics[0] == resolverABI.Events["AddrChanged"].ID && addressChangedTxs[log.TxHash] {
+			continue
+		}
+		event := decoder.decode(log)
+		if event != nil {
+			res = append(res, event)
+		}
+	}
+
+	return res, nil
+}
+
+// nameEventDecoder decodes logs in to name events.
+type nameEventDecoder struct {
+	registryABI    abi.ABI
+	resolverABI    abi.ABI
+	textChangedABI abi.ABI
+	registrarABI   abi.ABI
+	registrar      common.Address
+}
+
+// decode decodes a log, returning nil if it is not a name event.
+func (d *nameEventDecoder) decode(log types.Log) NameEvent {
+	meta := NameEventMeta{
+		Contract:        log.Address,
+		BlockNumber:     log.BlockNumber,
+		TransactionHash: log.TxHash,
+		LogIndex:        log.Index,
+	}
+
+	// The registry and the registrar both have Transfer events, with
+	// different signatures.
+	contractABI := d.resolverABI
+	switch {
+	case log.Address == d.registrar:
+		contractABI = d.registrarABI
+	case log.Topics[0] == d.textChangedABI.Events["TextChanged"].ID:
+		contractABI = d.textChangedABI
+	default:
+		if _, err := d.registryABI.EventByID(log.Topics[0]); err == nil {
+			contractABI = d.registryABI
+		}
+	}
+	event, err := contractABI.EventByID(log.Topics[0])
+	if err != nil {
+		return nil
+	}
+	values := make(map[string]interface{})
+	if err := event.Inputs.NonIndexed().UnpackIntoMap(values, log.Data); err != nil {
+		return nil
+	}
+
+	switch {
+	case event.Name == "NewOwner",
+		event.Name == "Transfer" && log.Address != d.registrar:
+		return &OwnerChangedEvent{NameEventMeta: meta, Owner: values["owner"].(common.Address)}
+	case event.Name == "NewResolver":
+		return &ResolverChangedEvent{NameEventMeta: meta, Resolver: values["resolver"].(common.Address)}
+	case event.Name == "NewTTL":
+		return &TTLChangedEvent{NameEventMeta: meta, TTL: time.Duration(values["ttl"].(uint64)) * time.Second}
+	case event.Name == "AddrChanged":
+		return &AddressChangedEvent{NameEventMeta: meta, CoinType: EthereumCoinType, Address: values["a"].(common.Address).Bytes()}
+	case event.Name == "AddressChanged":
+		return &AddressChangedEvent{NameEventMeta: meta, CoinType: values["coinType"].(*big.Int).Uint64(), Address: values["newAddress"].([]byte)}
+	case event.Name == "TextChanged":
+		res := &TextChangedEvent{NameEventMeta: meta, Key: values["key"].(string)}
+		if value, exists := values["value"]; exists {
+			res.Value = value.(string)
+		}
+		return res
+	case event.Name == "ContenthashChanged":
+		return &ContenthashChangedEvent{NameEventMeta: meta, Contenthash: values["hash"].([]byte)}
+	case event.Name == "NameRegistered" && len(log.Topics) > 2:
+		return &RegisteredEvent{
+			NameEventMeta: meta,
+			Registrant:    common.BytesToAddress(log.Topics[2].Bytes()),
+			Expiry:        time.Unix(values["expires"].(*big.Int).Int64(), 0),
+		}
+	case event.Name == "NameRenewed":
+		return &RenewedEvent{NameEventMeta: meta, Expiry: time.Unix(values["expires"].(*big.Int).Int64(), 0)}
+	case event.Name == "Transfer" && len(log.Topics) > 2:
+		return &RegistrantChangedEvent{NameEventMeta: meta, Registrant: common.BytesToAddress(log.Topics[2].Bytes())}
+	default:
+		return nil
+	}
+}
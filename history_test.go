@@ -0,0 +1,98 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/baseregistrar"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+func TestNameHistory(t *testing.T) {
+	m := newMockENS()
+	registrarAddr := common.HexToAddress("0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85")
+	owner := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	newOwner := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	m.register("eth", registrarAddr, UnknownAddress)
+	m.register("history.eth", owner, owner)
+	m.backend.deploy(registrarAddr, baseregistrar.ContractABI).
+		on("supportsInterface", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{true}, nil
+		})
+
+	node := common.Hash(mustNameHash("history.eth"))
+	otherNode := common.Hash(mustNameHash("other.eth"))
+	labelHash, err := LabelHash("history")
+	require.NoError(t, err)
+	label := common.Hash(labelHash)
+	expires := time.Unix(1900000000, 0)
+	renewed := time.Unix(1930000000, 0)
+
+	m.backend.addEvent(registrarAddr, baseregistrar.ContractABI, "NameRegistered", []common.Hash{label, common.BytesToHash(owner.Bytes())}, big.NewInt(expires.Unix()))
+	m.backend.addEvent(registrarAddr, baseregistrar.ContractABI, "Transfer", []common.Hash{{}, common.BytesToHash(owner.Bytes()), label})
+	m.backend.addEvent(m.registryAddr, registry.ContractABI, "NewOwner", []common.Hash{common.Hash(mustNameHash("eth")), label}, owner)
+	m.backend.addEvent(m.registryAddr, registry.ContractABI, "NewResolver", []common.Hash{node}, m.resolverAddr)
+	m.backend.addEvent(m.registryAddr, registry.ContractABI, "NewResolver", []common.Hash{otherNode}, m.resolverAddr)
+	m.backend.addEvent(m.resolverAddr, resolver.ContractABI, "AddrChanged", []common.Hash{node}, owner)
+	m.backend.addEvent(m.resolverAddr, resolver.ContractABI, "AddressChanged", []common.Hash{node}, big.NewInt(60), owner.Bytes())
+	m.backend.addEvent(m.resolverAddr, textChangedWithValueABI, "TextChanged", []common.Hash{node, crypto.Keccak256Hash([]byte("url"))}, "url", "https://example.com/")
+	m.backend.addEvent(m.resolverAddr, resolver.ContractABI, "TextChanged", []common.Hash{node, crypto.Keccak256Hash([]byte("avatar"))}, "avatar")
+	m.backend.addEvent(m.resolverAddr, resolver.ContractABI, "ContenthashChanged", []common.Hash{node}, []byte{0xe3, 0x01})
+	m.backend.addEvent(m.registryAddr, registry.ContractABI, "NewTTL", []common.Hash{node}, uint64(3600))
+	m.backend.addEvent(m.registryAddr, registry.ContractABI, "Transfer", []common.Hash{node}, newOwner)
+	m.backend.addEvent(registrarAddr, baseregistrar.ContractABI, "Transfer", []common.Hash{common.BytesToHash(owner.Bytes()), common.BytesToHash(newOwner.Bytes()), label})
+	m.backend.addEvent(registrarAddr, baseregistrar.ContractABI, "NameRenewed", []common.Hash{label}, big.NewInt(renewed.Unix()))
+
+	reg, err := NewRegistry(m.backend, EthereumMainnet)
+	require.NoError(t, err)
+	registrar, err := NewBaseRegistrar(m.backend, "eth", EthereumMainnet)
+	require.NoError(t, err)
+	name := &Name{backend: m.backend, Name: "history.eth", Domain: "eth", Label: "history", registry: reg, registrar: registrar}
+
+	meta := func(contract common.Address, block uint64) NameEventMeta {
+		return NameEventMeta{Contract: contract, BlockNumber: block}
+	}
+	expected := []NameEvent{
+		&RegisteredEvent{NameEventMeta: meta(registrarAddr, 1), Registrant: owner, Expiry: expires},
+		&RegistrantChangedEvent{NameEventMeta: meta(registrarAddr, 2), Registrant: owner},
+		&OwnerChangedEvent{NameEventMeta: meta(m.registryAddr, 3), Owner: owner},
+		&ResolverChangedEvent{NameEventMeta: meta(m.registryAddr, 4), Resolver: m.resolverAddr},
+		&AddressChangedEvent{NameEventMeta: meta(m.resolverAddr, 7), CoinType: 60, Address: owner.Bytes()},
+		&TextChangedEvent{NameEventMeta: meta(m.resolverAddr, 8), Key: "url", Value: "https://example.com/"},
+		&TextChangedEvent{NameEventMeta: meta(m.resolverAddr, 9), Key: "avatar"},
+		&ContenthashChangedEvent{NameEventMeta: meta(m.resolverAddr, 10), Contenthash: []byte{0xe3, 0x01}},
+		&TTLChangedEvent{NameEventMeta: meta(m.registryAddr, 11), TTL: time.Hour},
+		&OwnerChangedEvent{NameEventMeta: meta(m.registryAddr, 12), Owner: newOwner},
+		&RegistrantChangedEvent{NameEventMeta: meta(registrarAddr, 13), Registrant: newOwner},
+		&RenewedEvent{NameEventMeta: meta(registrarAddr, 14), Expiry: renewed},
+	}
+
+	events, err := name.History(context.Background(), 0)
+	require.NoError(t, err)
+	require.Equal(t, expected, events)
+
+	events, err = name.History(context.Background(), 10)
+	require.NoError(t, err)
+	require.Equal(t, expected[7:], events)
+	require.Equal(t, uint64(10), events[0].Meta().BlockNumber)
+}
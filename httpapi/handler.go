@@ -0,0 +1,294 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package httpapi provides an HTTP handler for ENS resolution, allowing an
+// ENS resolution service to be run with a few lines of Go:
+//
+//	client, err := ens.NewClient(backend, ens.WithCache(nil))
+//	...
+//	handler, err := httpapi.NewHandler(client)
+//	...
+//	err = http.ListenAndServe(":8080", handler)
+//
+// The handler serves the following endpoints, all of which return JSON:
+//
+//	GET /resolve/{name}    the Ethereum address of a name
+//	GET /reverse/{address} the name of an Ethereum address
+//	GET /records/{name}    the address, text and contenthash records of a name
+//
+// The records endpoint returns the Ethereum address and DefaultTextKeys by
+// default; the coin types and text keys returned can be changed with the
+// "coins" and "texts" query parameters, each a comma-separated list of up to
+// MaxRecordKeys entries.
+//
+// Failures of the backend are logged, and reported to clients as an internal
+// error without detail, as errors from the backend can include the URLs of
+// RPC providers and their API keys.
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	ens "github.com/wealdtech/go-ens/v3"
+)
+
+// MaxRecordKeys is the maximum number of coin types, and of text keys,
+// accepted by the records endpoint.
+const MaxRecordKeys = 32
+
+// DefaultTextKeys are the text records returned by the records endpoint if
+// none are requested.
+var DefaultTextKeys = []string{
+	"avatar",
+	"com.github",
+	"com.twitter",
+	"description",
+	"display",
+	"email",
+	"header",
+	"location",
+	"name",
+	"org.telegram",
+	"url",
+}
+
+// ResolveResponse is the response to a resolve request.
+type ResolveResponse struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+// ReverseResponse is the response to a reverse request.
+type ReverseResponse struct {
+	Address string `json:"address"`
+	Name    string `json:"name"`
+}
+
+// RecordsResponse is the response to a records request.  Records that are
+// not set are omitted.
+type RecordsResponse struct {
+	Name     string `json:"name"`
+	Resolver string `json:"resolver"`
+	// Addresses are keyed by coin type.
+	Addresses   map[string]string `json:"addresses,omitempty"`
+	Texts       map[string]string `json:"texts,omitempty"`
+	Contenthash string            `json:"contenthash,omitempty"`
+}
+
+// ErrorResponse is the response to a failed request.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Handler is an HTTP handler for ENS resolution.
+type Handler struct {
+	client   *ens.Client
+	mux      *http.ServeMux
+	errorLog *log.Logger
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// Option is an option for a handler.
+type Option func(*Handler)
+
+// WithErrorLog sets the logger for failures of the backend.  The default is
+// the standard logger.
+func WithErrorLog(logger *log.Logger) Option {
+	return func(h *Handler) {
+		h.errorLog = logger
+	}
+}
+
+// NewHandler creates a handler that resolves with the given client.  Caching
+// and rate limiting are configured on the client.
+func NewHandler(client *ens.Client, opts ...Option) (*Handler, error) {
+	if client == nil {
+		return nil, errors.New("no client supplied")
+	}
+
+	h := &Handler{
+		client:   client,
+		mux:      http.NewServeMux(),
+		errorLog: log.Default(),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.mux.HandleFunc("GET /resolve/{name}", h.resolve)
+	h.mux.HandleFunc("GET /reverse/{address}", h.reverse)
+	h.mux.HandleFunc("GET /records/{name}", h.records)
+
+	return h, nil
+}
+
+// ServeHTTP serves an HTTP request.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) resolve(w http.ResponseWriter, r *http.Request) {
+	name, err := ens.NormaliseDomain(r.PathValue("name"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	address, err := h.client.Resolve(name, ens.WithContext(r.Context()))
+	if err != nil {
+		h.writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &ResolveResponse{
+		Name:    name,
+		Address: address.Hex(),
+	})
+}
+
+func (h *Handler) reverse(w http.ResponseWriter, r *http.Request) {
+	input := r.PathValue("address")
+	if !common.IsHexAddress(input) {
+		h.writeError(w, http.StatusBadRequest, errors.New("invalid address"))
+		return
+	}
+	address := common.HexToAddress(input)
+
+	name, err := h.client.ReverseResolve(address, ens.WithContext(r.Context()))
+	if err != nil {
+		h.writeError(w, errorStatus(err), err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &ReverseResponse{
+		Address: address.Hex(),
+		Name:    name,
+	})
+}
+
+func (h *Handler) records(w http.ResponseWriter, r *http.Request) {
+	name, err := ens.NormaliseDomain(r.PathValue("name"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	coinTypes := []uint64{60}
+	if param := r.URL.Query().Get("coins"); param != "" {
+		coins := strings.Split(param, ",")
+		if len(coins) > MaxRecordKeys {
+			h.writeError(w, http.StatusBadRequest, fmt.Errorf("no more than %d coin types can be requested", MaxRecordKeys))
+			return
+		}
+		coinTypes = coinTypes[:0]
+		for _, coin := range coins {
+			coinType, err := strconv.ParseUint(strings.TrimSpace(coin), 10, 64)
+			if err != nil {
+				h.writeError(w, http.StatusBadRequest, errors.New("invalid coin type"))
+				return
+			}
+			coinTypes = append(coinTypes, coinType)
+		}
+	}
+	textKeys := DefaultTextKeys
+	if param := r.URL.Query().Get("texts"); param != "" {
+		textKeys = strings.Split(param, ",")
+		if len(textKeys) > MaxRecordKeys {
+			h.writeError(w, http.StatusBadRequest, fmt.Errorf("no more than %d text keys can be requested", MaxRecordKeys))
+			return
+		}
+	}
+
+	ctx := ens.WithContext(r.Context())
+	resolver, err := h.client.Resolver(name, ctx)
+	if err != nil {
+		h.writeError(w, errorStatus(err), err)
+		return
+	}
+
+	// The records are read together using multicall.  Resolvers revert for
+	// records they do not support, so such records are omitted.
+	records, err := resolver.Records(&ens.RecordsSpec{
+		CoinTypes:   coinTypes,
+		Texts:       textKeys,
+		Contenthash: true,
+	}, ctx)
+	if err != nil {
+		h.writeError(w, errorStatus(err), err)
+		return
+	}
+
+	res := &RecordsResponse{
+		Name:      name,
+		Resolver:  resolver.ContractAddr.Hex(),
+		Addresses: make(map[string]string),
+		Texts:     records.Texts,
+	}
+	for coinType, address := range records.Addresses {
+		if coinType == 60 && len(address) == common.AddressLength {
+			res.Addresses["60"] = common.BytesToAddress(address).Hex()
+			continue
+		}
+		res.Addresses[strconv.FormatUint(coinType, 10)] = hexutil.Encode(address)
+	}
+	if len(records.Contenthash) > 0 {
+		res.Contenthash, err = ens.ContenthashToString(records.Contenthash)
+		if err != nil {
+			res.Contenthash = hexutil.Encode(records.Contenthash)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, res)
+}
+
+// errorStatus returns the HTTP status for a resolution error.
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, ens.ErrUnregisteredName),
+		errors.Is(err, ens.ErrNoResolver),
+		errors.Is(err, ens.ErrNotAResolver),
+		errors.Is(err, ens.ErrNoAddress),
+		errors.Is(err, ens.ErrNoResolution):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError writes an error response.  Internal errors are logged rather
+// than returned, as they can contain details of the backend.
+func (h *Handler) writeError(w http.ResponseWriter, status int, err error) {
+	if status >= http.StatusInternalServerError {
+		if h.errorLog != nil {
+			h.errorLog.Printf("ens: request failed: %v", err)
+		}
+		writeJSON(w, status, &ErrorResponse{Error: http.StatusText(status)})
+		return
+	}
+	writeJSON(w, status, &ErrorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	// An error here means that the client has gone away, so is ignored.
+	_ = json.NewEncoder(w).Encode(data)
+}
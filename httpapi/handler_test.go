@@ -0,0 +1,285 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	ens "github.com/wealdtech/go-ens/v3"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+// fakeBackend is a backend that serves calls to a registry and a resolver
+// holding fixed records.
+type fakeBackend struct {
+	bind.ContractBackend
+	registryAddr common.Address
+	resolverAddr common.Address
+	registryABI  abi.ABI
+	resolverABI  abi.ABI
+	owners       map[[32]byte]common.Address
+	addrs        map[[32]byte]common.Address
+	coinAddrs    map[[32]byte]map[uint64][]byte
+	texts        map[[32]byte]map[string]string
+	contenthash  map[[32]byte][]byte
+	names        map[[32]byte]string
+	failing      map[[32]byte]bool
+	calls        int
+}
+
+func newFakeBackend(t *testing.T) *fakeBackend {
+	t.Helper()
+	registryABI, err := abi.JSON(strings.NewReader(registry.ContractABI))
+	require.NoError(t, err)
+	resolverABI, err := abi.JSON(strings.NewReader(resolver.ContractABI))
+	require.NoError(t, err)
+
+	return &fakeBackend{
+		registryAddr: ens.ChainConfigFor(ens.EthereumMainnet).Registry,
+		resolverAddr: common.HexToAddress("0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63"),
+		registryABI:  registryABI,
+		resolverABI:  resolverABI,
+		owners:       make(map[[32]byte]common.Address),
+		addrs:        make(map[[32]byte]common.Address),
+		coinAddrs:    make(map[[32]byte]map[uint64][]byte),
+		texts:        make(map[[32]byte]map[string]string),
+		contenthash:  make(map[[32]byte][]byte),
+		names:        make(map[[32]byte]string),
+		failing:      make(map[[32]byte]bool),
+	}
+}
+
+func (b *fakeBackend) node(t *testing.T, name string) [32]byte {
+	t.Helper()
+	node, err := ens.NameHash(name)
+	require.NoError(t, err)
+	b.owners[node] = b.resolverAddr
+	return node
+}
+
+func (b *fakeBackend) CodeAt(_ context.Context, contract common.Address, _ *big.Int) ([]byte, error) {
+	if contract == b.registryAddr || contract == b.resolverAddr {
+		return []byte{0x01}, nil
+	}
+	return nil, nil
+}
+
+func (b *fakeBackend) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	var contractABI abi.ABI
+	switch {
+	case call.To == nil:
+		return nil, nil
+	case *call.To == b.registryAddr:
+		contractABI = b.registryABI
+	case *call.To == b.resolverAddr:
+		contractABI = b.resolverABI
+	default:
+		return nil, nil
+	}
+	method, err := contractABI.MethodById(call.Data[:4])
+	if err != nil {
+		return nil, errors.New("execution reverted")
+	}
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		return nil, err
+	}
+	b.calls++
+	if method.Name == "multicall" {
+		results := make([][]byte, 0)
+		for _, data := range args[0].([][]byte) {
+			b.calls--
+			res, err := b.CallContract(context.Background(), ethereum.CallMsg{To: call.To, Data: data}, nil)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, res)
+		}
+		return method.Outputs.Pack(results)
+	}
+	node := args[0].([32]byte)
+	if b.failing[node] {
+		return nil, errors.New("Post \"https://rpc.example.com/v1/secret-api-key\": connection refused")
+	}
+
+	var res []interface{}
+	switch method.Name {
+	case "owner":
+		res = []interface{}{b.owners[node]}
+	case "resolver":
+		resolverAddr := common.Address{}
+		if _, exists := b.owners[node]; exists {
+			resolverAddr = b.resolverAddr
+		}
+		res = []interface{}{resolverAddr}
+	case "addr":
+		res = []interface{}{b.addrs[node]}
+	case "addr0":
+		coinType := args[1].(*big.Int).Uint64()
+		if address, exists := b.addrs[node]; exists && coinType == 60 {
+			res = []interface{}{address.Bytes()}
+			break
+		}
+		res = []interface{}{b.coinAddrs[node][coinType]}
+	case "text":
+		res = []interface{}{b.texts[node][args[1].(string)]}
+	case "contenthash":
+		res = []interface{}{b.contenthash[node]}
+	case "name":
+		res = []interface{}{b.names[node]}
+	default:
+		return nil, errors.New("execution reverted")
+	}
+
+	return method.Outputs.Pack(res...)
+}
+
+func TestHandler(t *testing.T) {
+	var err error
+	backend := newFakeBackend(t)
+	address := common.HexToAddress("0x00000000000000000000000000000000000A11cE")
+	node := backend.node(t, "http.eth")
+	backend.addrs[node] = address
+	backend.coinAddrs[node] = map[uint64][]byte{0: {0x00, 0x14, 0x01, 0x02}}
+	backend.texts[node] = map[string]string{"url": "https://example.com/", "avatar": "https://example.com/avatar.png"}
+	backend.contenthash[node], err = ens.StringToContenthash("/ipfs/QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4")
+	require.NoError(t, err)
+	backend.names[backend.node(t, fmt.Sprintf("%x.addr.reverse", address.Bytes()))] = "http.eth"
+	backend.node(t, "noaddress.eth")
+	backend.failing[backend.node(t, "failing.eth")] = true
+
+	client, err := ens.NewClient(backend)
+	require.NoError(t, err)
+	var errorLog bytes.Buffer
+	handler, err := NewHandler(client, WithErrorLog(log.New(&errorLog, "", 0)))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		path     string
+		status   int
+		response string
+	}{
+		{
+			name:     "Resolve",
+			path:     "/resolve/HTTP.eth",
+			status:   http.StatusOK,
+			response: `{"name":"http.eth","address":"0x00000000000000000000000000000000000A11cE"}`,
+		},
+		{
+			name:     "ResolveNoAddress",
+			path:     "/resolve/noaddress.eth",
+			status:   http.StatusNotFound,
+			response: `{"error":"no address"}`,
+		},
+		{
+			name:     "ResolveUnregistered",
+			path:     "/resolve/unregistered.eth",
+			status:   http.StatusNotFound,
+			response: `{"error":"unregistered name"}`,
+		},
+		{
+			name:     "Reverse",
+			path:     "/reverse/0x00000000000000000000000000000000000a11ce",
+			status:   http.StatusOK,
+			response: `{"address":"0x00000000000000000000000000000000000A11cE","name":"http.eth"}`,
+		},
+		{
+			name:     "ReverseInvalid",
+			path:     "/reverse/bad",
+			status:   http.StatusBadRequest,
+			response: `{"error":"invalid address"}`,
+		},
+		{
+			name:     "Records",
+			path:     "/records/http.eth",
+			status:   http.StatusOK,
+			response: `{"name":"http.eth","resolver":"0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63","addresses":{"60":"0x00000000000000000000000000000000000A11cE"},"texts":{"avatar":"https://example.com/avatar.png","url":"https://example.com/"},"contenthash":"/ipfs/k2jmtxseqz46solsx2rmxavgbzp6ij1t1kiq1or8a00c2g9bx1for0gv"}`,
+		},
+		{
+			name:     "RecordsRequested",
+			path:     "/records/http.eth?coins=0,2&texts=url",
+			status:   http.StatusOK,
+			response: `{"name":"http.eth","resolver":"0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63","addresses":{"0":"0x00140102"},"texts":{"url":"https://example.com/"},"contenthash":"/ipfs/k2jmtxseqz46solsx2rmxavgbzp6ij1t1kiq1or8a00c2g9bx1for0gv"}`,
+		},
+		{
+			name:     "RecordsTooManyTexts",
+			path:     "/records/http.eth?texts=" + strings.Repeat("a,", MaxRecordKeys) + "a",
+			status:   http.StatusBadRequest,
+			response: `{"error":"no more than 32 text keys can be requested"}`,
+		},
+		{
+			name:     "InternalError",
+			path:     "/resolve/failing.eth",
+			status:   http.StatusInternalServerError,
+			response: `{"error":"Internal Server Error"}`,
+		},
+		{
+			name:     "RecordsBadCoinType",
+			path:     "/records/http.eth?coins=eth",
+			status:   http.StatusBadRequest,
+			response: `{"error":"invalid coin type"}`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, test.path, nil))
+			require.Equal(t, test.status, rec.Code)
+			require.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			require.JSONEq(t, test.response, rec.Body.String())
+		})
+	}
+
+	// The detail of internal errors is logged.
+	require.Contains(t, errorLog.String(), "secret-api-key")
+
+	// Records are read in a single call.
+	calls := backend.calls
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/records/http.eth", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.LessOrEqual(t, backend.calls-calls, 4)
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/resolve/http.eth", nil))
+	require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+
+	_, err = NewHandler(nil)
+	require.EqualError(t, err, "no client supplied")
+}
+
+func TestRecordsResponseJSON(t *testing.T) {
+	data, err := json.Marshal(&RecordsResponse{Name: "empty.eth", Resolver: "0x01"})
+	require.NoError(t, err)
+	require.Equal(t, `{"name":"empty.eth","resolver":"0x01"}`, string(data))
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wealdtech/go-ens/v3/contracts/l2controller"
+)
+
+// L2Controller is the structure for an L2 registrar controller, such as that
+// used by Basenames.  Unlike the .eth controller, L2 controllers register
+// names in a single transaction without a commit/reveal process.
+type L2Controller struct {
+	backend      bind.ContractBackend
+	Contract     *l2controller.Contract
+	ContractAddr common.Address
+	domain       string
+}
+
+// NewL2Controller creates a new L2 controller for a given chain.
+func NewL2Controller(backend bind.ContractBackend, chainId ChainId) (*L2Controller, error) {
+	config := ChainConfigFor(chainId)
+	if config.ChainId != chainId || config.L2Controller == UnknownAddress {
+		return nil, fmt.Errorf("no L2 controller for chain %d", chainId)
+	}
+
+	return NewL2ControllerAt(backend, config.Root, config.L2Controller)
+}
+
+// NewL2ControllerAt creates an L2 controller for a given domain at a given address.
+func NewL2ControllerAt(backend bind.ContractBackend, domain string, address common.Address) (*L2Controller, error) {
+	contract, err := l2controller.NewContract(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &L2Controller{
+		backend:      backend,
+		Contract:     contract,
+		ContractAddr: address,
+		domain:       domain,
+	}, nil
+}
+
+// IsValid returns true if the domain is considered valid by the controller.
+func (c *L2Controller) IsValid(domain string) (bool, error) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return false, fmt.Errorf("invalid name %s", domain)
+	}
+	return c.Contract.Valid(nil, name)
+}
+
+// IsAvailable returns true if the domain is available for registration.
+func (c *L2Controller) IsAvailable(domain string) (bool, error) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return false, fmt.Errorf("invalid name %s", domain)
+	}
+	return c.Contract.Available(nil, name)
+}
+
+// MinRegistrationDuration returns the minimum duration for which a name can be registered.
+func (c *L2Controller) MinRegistrationDuration() (time.Duration, error) {
+	tmp, err := c.Contract.MINREGISTRATIONDURATION(nil)
+	if err != nil {
+		return 0 * time.Second, err
+	}
+
+	return time.Duration(tmp.Int64()) * time.Second, nil
+}
+
+// RegisterPrice returns the price in wei to register the domain for the given duration.
+func (c *L2Controller) RegisterPrice(domain string, duration time.Duration) (*big.Int, error) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s", domain)
+	}
+	return c.Contract.RegisterPrice(nil, name, big.NewInt(int64(duration.Seconds())))
+}
+
+// Register registers a domain.
+// resolver and data are optional; if data is supplied it contains calls to
+// the resolver to set records for the domain.  If reverseRecord is true the
+// reverse record for the owner is set to the domain.
+func (c *L2Controller) Register(opts *bind.TransactOpts,
+	domain string,
+	owner common.Address,
+	duration time.Duration,
+	resolver common.Address,
+	data [][]byte,
+	reverseRecord bool,
+) (
+	*types.Transaction,
+	error,
+) {
+	name, err := c.checkRegistration(opts, domain, duration, resolver, data)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Contract.Register(opts, l2controller.RegistrarControllerRegisterRequest{
+		Name:          name,
+		Owner:         owner,
+		Duration:      big.NewInt(int64(duration.Seconds())),
+		Resolver:      resolver,
+		Data:          data,
+		ReverseRecord: reverseRecord,
+	})
+}
+
+// checkRegistration checks that a registration can be sent, returning the
+// name to register.
+func (c *L2Controller) checkRegistration(opts *bind.TransactOpts,
+	domain string,
+	duration time.Duration,
+	resolver common.Address,
+	data [][]byte,
+) (
+	string,
+	error,
+) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return "", fmt.Errorf("invalid name %s", domain)
+	}
+
+	if opts == nil {
+		return "", errors.New("transaction options required")
+	}
+	if len(data) > 0 && resolver == UnknownAddress {
+		return "", errors.New("resolver required to set records")
+	}
+
+	minDuration, err := c.MinRegistrationDuration()
+	if err != nil {
+		return "", err
+	}
+	if duration < minDuration {
+		return "", fmt.Errorf("duration less than minimum duration of %v", minDuration)
+	}
+
+	price, err := c.RegisterPrice(domain, duration)
+	if err != nil {
+		return "", errors.New("failed to obtain registration price")
+	}
+	if opts.Value == nil || opts.Value.Cmp(price) < 0 {
+		return "", fmt.Errorf("not enough funds to cover registration price of %s wei", price.String())
+	}
+
+	return name, nil
+}
+
+// Renew renews a registered domain.
+func (c *L2Controller) Renew(opts *bind.TransactOpts, domain string, duration time.Duration) (*types.Transaction, error) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s", domain)
+	}
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+
+	return c.Contract.Renew(opts, name, big.NewInt(int64(duration.Seconds())))
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/l2controller"
+)
+
+func testTransactOpts(t *testing.T) *bind.TransactOpts {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	opts, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(1))
+	require.NoError(t, err)
+	return opts
+}
+
+func TestL2ControllerRegister(t *testing.T) {
+	backend := newMockBackend()
+	config := ChainConfigFor(BaseMainnet)
+	var registered l2controller.RegistrarControllerRegisterRequest
+	backend.deploy(config.L2Controller, l2controller.ContractABI).
+		on("MIN_REGISTRATION_DURATION", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(28 * 24 * 60 * 60)}, nil
+		}).
+		on("registerPrice", func(args []interface{}) ([]interface{}, error) {
+			require.Equal(t, "foo", args[0].(string))
+			return []interface{}{big.NewInt(1000)}, nil
+		}).
+		on("register", func(args []interface{}) ([]interface{}, error) {
+			request := args[0].(struct {
+				Name          string         `json:"name"`
+				Owner         common.Address `json:"owner"`
+				Duration      *big.Int       `json:"duration"`
+				Resolver      common.Address `json:"resolver"`
+				Data          [][]byte       `json:"data"`
+				ReverseRecord bool           `json:"reverseRecord"`
+			})
+			registered.Name = request.Name
+			registered.ReverseRecord = request.ReverseRecord
+			return []interface{}{}, nil
+		})
+
+	controller, err := NewL2Controller(backend, BaseMainnet)
+	require.NoError(t, err)
+
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	opts := testTransactOpts(t)
+
+	_, err = controller.Register(opts, "foo.eth", owner, 365*24*time.Hour, UnknownAddress, nil, false)
+	require.EqualError(t, err, "invalid name foo.eth")
+
+	_, err = controller.Register(opts, "foo.base.eth", owner, time.Hour, UnknownAddress, nil, false)
+	require.EqualError(t, err, "duration less than minimum duration of 672h0m0s")
+
+	_, err = controller.Register(opts, "foo.base.eth", owner, 365*24*time.Hour, UnknownAddress, nil, false)
+	require.EqualError(t, err, "not enough funds to cover registration price of 1000 wei")
+
+	opts.Value = big.NewInt(1000)
+	_, err = controller.Register(opts, "foo.base.eth", owner, 365*24*time.Hour, UnknownAddress, nil, true)
+	require.NoError(t, err)
+	require.Equal(t, "foo", registered.Name)
+	require.True(t, registered.ReverseRecord)
+	require.Len(t, backend.sent, 1)
+}
+
+func TestL2ControllerMissing(t *testing.T) {
+	_, err := NewL2Controller(newMockBackend(), EthereumMainnet)
+	require.EqualError(t, err, "no L2 controller for chain 1")
+
+	// The address of the Linea controller must be supplied.
+	_, err = NewL2Controller(newMockBackend(), LineaMainnet)
+	require.EqualError(t, err, "no L2 controller for chain 59144")
+}
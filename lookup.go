@@ -0,0 +1,163 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// HostResolver looks up hosts and addresses.  It is satisfied by both
+// *net.Resolver and *Lookup, so that applications can use DNS and ENS
+// resolution interchangeably.
+type HostResolver interface {
+	// LookupHost looks up the given host, returning its addresses.
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	// LookupAddr looks up the given address, returning the names that map to
+	// it.
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+var (
+	_ HostResolver = (*net.Resolver)(nil)
+	_ HostResolver = (*Lookup)(nil)
+)
+
+// Lookup resolves ENS names and addresses with the semantics of
+// net.Resolver: hosts are ENS names and addresses are Ethereum addresses in
+// hex.  Failed lookups return a *net.DNSError with IsNotFound set.
+type Lookup struct {
+	backend   bind.ContractBackend
+	chainId   ChainId
+	coinTypes []uint64
+}
+
+// NewLookup creates a lookup that returns addresses for the given coin types,
+// in order.  If no coin types are supplied then only Ethereum addresses (coin
+// type 60) are returned.
+func NewLookup(backend bind.ContractBackend, chainId ChainId, coinTypes ...uint64) *Lookup {
+	if len(coinTypes) == 0 {
+		coinTypes = []uint64{60}
+	}
+
+	return &Lookup{
+		backend:   backend,
+		chainId:   chainId,
+		coinTypes: coinTypes,
+	}
+}
+
+// LookupHost returns the addresses of a name for the lookup's coin types.
+// Ethereum and other EVM addresses are returned in checksummed hex; addresses
+// for other coin types are returned as hex of their binary form.
+func (l *Lookup) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addresses, err := l.LookupCoinAddresses(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]string, 0, len(addresses))
+	for _, coinType := range l.coinTypes {
+		if address, exists := addresses[coinType]; exists {
+			res = append(res, formatCoinAddress(coinType, address))
+		}
+	}
+
+	return res, nil
+}
+
+// LookupCoinAddresses returns the addresses of a name for the lookup's coin
+// types, keyed by coin type.
+func (l *Lookup) LookupCoinAddresses(ctx context.Context, host string) (map[uint64][]byte, error) {
+	resolver, err := NewResolver(l.backend, host, l.chainId, WithContext(ctx))
+	if err != nil {
+		return nil, lookupError(err, host)
+	}
+
+	res := make(map[uint64][]byte)
+	for _, coinType := range l.coinTypes {
+		var address []byte
+		if coinType == 60 {
+			// Use addr(bytes32) so that older resolvers are supported.
+			ethAddress, err := resolver.Address(WithContext(ctx))
+			if err != nil {
+				return nil, err
+			}
+			if ethAddress != UnknownAddress {
+				address = ethAddress.Bytes()
+			}
+		} else {
+			// Resolvers without multicoin support revert, which is treated as
+			// no address.
+			address, _ = resolver.MultiAddress(coinType, WithContext(ctx))
+		}
+		if len(address) > 0 {
+			res[coinType] = address
+		}
+	}
+	if len(res) == 0 {
+		return nil, lookupError(ErrNoAddress, host)
+	}
+
+	return res, nil
+}
+
+// LookupAddr returns the name of an Ethereum address, as set by its reverse
+// record.
+func (l *Lookup) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	if !common.IsHexAddress(addr) {
+		return nil, &net.DNSError{Err: "invalid address", Name: addr}
+	}
+
+	name, err := ReverseResolve(l.backend, common.HexToAddress(addr), l.chainId, WithContext(ctx))
+	if errors.Is(err, ErrNotAResolver) {
+		// Addresses without a reverse record do not have a resolver.
+		err = ErrNoResolution
+	}
+	if err != nil {
+		return nil, lookupError(err, addr)
+	}
+
+	return []string{name}, nil
+}
+
+// lookupError converts resolution errors for names or addresses that do not
+// resolve in to the errors returned by net.Resolver.
+func lookupError(err error, name string) error {
+	if errors.Is(err, ErrNoResolver) ||
+		errors.Is(err, ErrNoAddress) ||
+		errors.Is(err, ErrNoResolution) ||
+		errors.Is(err, ErrUnregisteredName) {
+		return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+
+	return err
+}
+
+// formatCoinAddress formats an address for a coin type as text.
+func formatCoinAddress(coinType uint64, address []byte) string {
+	// Coin type 60 is Ethereum; coin types with the top bit set are EVM
+	// chains as per ENSIP-11.
+	if (coinType == 60 || coinType&0x80000000 != 0) && len(address) == common.AddressLength {
+		return common.BytesToAddress(address).Hex()
+	}
+
+	return hexutil.Encode(address)
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookup(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x00000000000000000000000000000000000A11cE")
+	btcAddress := []byte{0x00, 0x14, 0x01, 0x02}
+	baseAddress := common.HexToAddress("0x00000000000000000000000000000000000BA5E0")
+	m.register("lookup.eth", address, address)
+	m.setCoinAddr("lookup.eth", 0, btcAddress)
+	m.setCoinAddr("lookup.eth", 0x80000000|8453, baseAddress.Bytes())
+	m.register("empty.eth", address, UnknownAddress)
+	m.setReverse(address, "lookup.eth")
+
+	lookup := NewLookup(m.backend, EthereumMainnet, 60, 0, 0x80000000|8453)
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		host      string
+		addresses []string
+		notFound  bool
+	}{
+		{
+			name:      "Good",
+			host:      "lookup.eth",
+			addresses: []string{address.Hex(), "0x00140102", baseAddress.Hex()},
+		},
+		{
+			name:     "NoAddresses",
+			host:     "empty.eth",
+			notFound: true,
+		},
+		{
+			name:     "Unregistered",
+			host:     "unregistered.eth",
+			notFound: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			addresses, err := lookup.LookupHost(ctx, test.host)
+			if test.notFound {
+				var dnsErr *net.DNSError
+				require.ErrorAs(t, err, &dnsErr)
+				require.True(t, dnsErr.IsNotFound)
+				require.Equal(t, test.host, dnsErr.Name)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.addresses, addresses)
+		})
+	}
+
+	names, err := lookup.LookupAddr(ctx, address.Hex())
+	require.NoError(t, err)
+	require.Equal(t, []string{"lookup.eth"}, names)
+
+	_, err = lookup.LookupAddr(ctx, "0x0000000000000000000000000000000000000002")
+	var dnsErr *net.DNSError
+	require.ErrorAs(t, err, &dnsErr)
+	require.True(t, dnsErr.IsNotFound)
+
+	_, err = lookup.LookupAddr(ctx, "bad")
+	require.EqualError(t, err, "lookup bad: invalid address")
+
+	// The default coin type is Ethereum.
+	addresses, err := NewLookup(m.backend, EthereumMainnet).LookupHost(ctx, "lookup.eth")
+	require.NoError(t, err)
+	require.Equal(t, []string{address.Hex()}, addresses)
+}
@@ -0,0 +1,221 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// DefaultMetadataEndpoint is the endpoint of the ENS NFT metadata service.
+const DefaultMetadataEndpoint = "https://metadata.ens.domains"
+
+// metadataNetworks are the names used by the metadata service for chains.
+var metadataNetworks = map[ChainId]string{
+	EthereumMainnet:   "mainnet",
+	ChainId(17000):    "holesky",
+	ChainId(11155111): "sepolia",
+}
+
+// MetadataClient is a client for the ENS NFT metadata service, which
+// provides display data for the tokens of the base registrar and the name
+// wrapper.
+type MetadataClient struct {
+	endpoint string
+	network  string
+	client   *http.Client
+}
+
+// NFTMetadata is the metadata of an ENS token.
+type NFTMetadata struct {
+	// Name is the name represented by the token.
+	Name string
+	// Description is a description of the token.
+	Description string
+	// Image is the URL of the token's image.
+	Image string
+	// BackgroundImage is the URL of the token's background image, if any.
+	BackgroundImage string
+	// URL is the URL of the name in the ENS app.
+	URL string
+	// IsNormalized is true if the name is normalized.
+	IsNormalized bool
+	// Length is the length of the name in characters.
+	Length int
+	// SegmentLength is the length of the first label of the name in characters.
+	SegmentLength int
+	// CharacterSet is the character set of the first label of the name, for
+	// example "letter", "digit", "emoji" or "mixed".
+	CharacterSet string
+	// Created is the time at which the name was created, if known.
+	Created time.Time
+	// Registered is the time at which the name was registered, if known.
+	Registered time.Time
+	// Expiry is the time at which the name expires, if known.
+	Expiry time.Time
+	// Attributes are the token's attributes as supplied by the service.
+	Attributes []*NFTAttribute
+}
+
+// NFTAttribute is an attribute of an ENS token.
+type NFTAttribute struct {
+	TraitType   string      `json:"trait_type"`
+	DisplayType string      `json:"display_type"`
+	Value       interface{} `json:"value"`
+}
+
+// NewMetadataClient creates a client for the metadata service for the given
+// chain.  If endpoint is empty then DefaultMetadataEndpoint is used; if client
+// is nil then http.DefaultClient is used.
+func NewMetadataClient(endpoint string, chainId ChainId, client *http.Client) (*MetadataClient, error) {
+	network, exists := metadataNetworks[chainId]
+	if !exists {
+		return nil, fmt.Errorf("no metadata service for chain %d", chainId)
+	}
+	if endpoint == "" {
+		endpoint = DefaultMetadataEndpoint
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	return &MetadataClient{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		network:  network,
+		client:   client,
+	}, nil
+}
+
+// RegistrarMetadata returns the metadata of the base registrar token for a
+// .eth second-level domain.  The token ID is the labelhash of the domain's
+// label.
+func (c *MetadataClient) RegistrarMetadata(ctx context.Context, registrar common.Address, domain string) (*NFTMetadata, error) {
+	label, err := DomainPart(domain, 1)
+	if err != nil {
+		return nil, err
+	}
+	labelHash, err := LabelHash(label)
+	if err != nil {
+		return nil, err
+	}
+	return c.Metadata(ctx, registrar, new(big.Int).SetBytes(labelHash[:]))
+}
+
+// WrapperMetadata returns the metadata of the name wrapper token for a
+// domain.  The token ID is the namehash of the domain.
+func (c *MetadataClient) WrapperMetadata(ctx context.Context, wrapper common.Address, domain string) (*NFTMetadata, error) {
+	nameHash, err := NameHash(domain)
+	if err != nil {
+		return nil, err
+	}
+	return c.Metadata(ctx, wrapper, new(big.Int).SetBytes(nameHash[:]))
+}
+
+// Metadata returns the metadata of a token.
+func (c *MetadataClient) Metadata(ctx context.Context, contract common.Address, tokenID *big.Int) (*NFTMetadata, error) {
+	if tokenID == nil {
+		return nil, errors.New("no token ID supplied")
+	}
+	url := fmt.Sprintf("%s/%s/%s/%s", c.endpoint, c.network, contract.Hex(), tokenID.String())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query metadata service")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var respErr struct {
+			Message string `json:"message"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(body, &respErr) == nil && respErr.Message != "" {
+			return nil, fmt.Errorf("metadata service returned status %d: %s", resp.StatusCode, respErr.Message)
+		}
+		return nil, fmt.Errorf("metadata service returned status %d", resp.StatusCode)
+	}
+
+	var data metadataResponse
+	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "failed to decode metadata")
+	}
+
+	return data.toMetadata(), nil
+}
+
+type metadataResponse struct {
+	Name            string          `json:"name"`
+	Description     string          `json:"description"`
+	Image           string          `json:"image"`
+	BackgroundImage string          `json:"background_image"`
+	URL             string          `json:"url"`
+	IsNormalized    bool            `json:"is_normalized"`
+	NameLength      int             `json:"name_length"`
+	SegmentLength   int             `json:"segment_length"`
+	Attributes      []*NFTAttribute `json:"attributes"`
+}
+
+func (r *metadataResponse) toMetadata() *NFTMetadata {
+	metadata := &NFTMetadata{
+		Name:            r.Name,
+		Description:     r.Description,
+		Image:           r.Image,
+		BackgroundImage: r.BackgroundImage,
+		URL:             r.URL,
+		IsNormalized:    r.IsNormalized,
+		Length:          r.NameLength,
+		SegmentLength:   r.SegmentLength,
+		Attributes:      r.Attributes,
+	}
+	for _, attribute := range r.Attributes {
+		switch attribute.TraitType {
+		case "Character Set":
+			if value, isString := attribute.Value.(string); isString {
+				metadata.CharacterSet = value
+			}
+		case "Created Date":
+			metadata.Created = metadataTime(attribute.Value)
+		case "Registration Date":
+			metadata.Registered = metadataTime(attribute.Value)
+		case "Expiration Date":
+			metadata.Expiry = metadataTime(attribute.Value)
+		}
+	}
+
+	return metadata
+}
+
+// metadataTime converts a date attribute, in milliseconds since the epoch, to
+// a time.
+func metadataTime(value interface{}) time.Time {
+	ms, isNumber := value.(float64)
+	if !isNumber || ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(int64(ms))
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func metadataTestServer(t *testing.T, status int, response string) (*httptest.Server, *string) {
+	t.Helper()
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &path
+}
+
+func TestMetadataClient(t *testing.T) {
+	srv, path := metadataTestServer(t, http.StatusOK, `{
+  "is_normalized": true,
+  "name": "nick.eth",
+  "description": "nick.eth, an ENS name.",
+  "attributes": [
+    {"trait_type": "Created Date", "display_type": "date", "value": 1571924851000},
+    {"trait_type": "Length", "display_type": "number", "value": 4},
+    {"trait_type": "Character Set", "display_type": "string", "value": "letter"},
+    {"trait_type": "Registration Date", "display_type": "date", "value": 1580803395000},
+    {"trait_type": "Expiration Date", "display_type": "date", "value": 2310615393000}
+  ],
+  "name_length": 4,
+  "segment_length": 4,
+  "url": "https://app.ens.domains/name/nick.eth",
+  "image": "https://metadata.ens.domains/mainnet/0x57f1887a8bf19b14fc0df6fd9b2acc9af147ea85/0x5d5727cb0fb76e4944eafb88ec9a3cf0b3c9025a4b2f947729137c5d7f84f68f/image"
+}`)
+
+	client, err := NewMetadataClient(srv.URL, EthereumMainnet, nil)
+	require.NoError(t, err)
+	registrar := common.HexToAddress("0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85")
+	metadata, err := client.RegistrarMetadata(context.Background(), registrar, "nick.eth")
+	require.NoError(t, err)
+	labelHash, err := LabelHash("nick")
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("/mainnet/%s/%s", registrar.Hex(), new(big.Int).SetBytes(labelHash[:]).String()), *path)
+
+	require.Equal(t, "nick.eth", metadata.Name)
+	require.True(t, metadata.IsNormalized)
+	require.Equal(t, 4, metadata.Length)
+	require.Equal(t, "letter", metadata.CharacterSet)
+	require.Equal(t, time.UnixMilli(1571924851000), metadata.Created)
+	require.Equal(t, time.UnixMilli(1580803395000), metadata.Registered)
+	require.Equal(t, time.UnixMilli(2310615393000), metadata.Expiry)
+	require.Len(t, metadata.Attributes, 5)
+
+	wrapper := ChainConfigFor(EthereumMainnet).NameWrapper
+	_, err = client.WrapperMetadata(context.Background(), wrapper, "nick.eth")
+	require.NoError(t, err)
+	nameHash := mustNameHash("nick.eth")
+	require.Equal(t, fmt.Sprintf("/mainnet/%s/%s", wrapper.Hex(), new(big.Int).SetBytes(nameHash[:]).String()), *path)
+}
+
+func TestMetadataClientErrors(t *testing.T) {
+	_, err := NewMetadataClient("", LineaMainnet, nil)
+	require.EqualError(t, err, "no metadata service for chain 59144")
+
+	srv, _ := metadataTestServer(t, http.StatusNotFound, `{"message":"nick.eth is not registered"}`)
+	client, err := NewMetadataClient(srv.URL, EthereumMainnet, nil)
+	require.NoError(t, err)
+	_, err = client.Metadata(context.Background(), UnknownAddress, big.NewInt(1))
+	require.EqualError(t, err, "metadata service returned status 404: nick.eth is not registered")
+
+	_, err = client.Metadata(context.Background(), UnknownAddress, nil)
+	require.EqualError(t, err, "no token ID supplied")
+}
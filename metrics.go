@@ -0,0 +1,187 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Metrics receives instrumentation from go-ens.  It is supplied to a client
+// with WithMetrics, and package ensprom provides an implementation backed by
+// Prometheus collectors.  Implementations must be safe for concurrent use.
+type Metrics interface {
+	// ObserveResolution records the duration of a resolution operation such
+	// as "resolve" or "reverse_resolve", and whether it succeeded.
+	ObserveResolution(operation string, duration time.Duration, err error)
+	// ObserveRPCCall records the duration of a call to a backend method, and
+	// whether it succeeded.
+	ObserveRPCCall(method string, duration time.Duration, err error)
+	// CacheLookup records a lookup in a cache, and whether it was a hit.
+	CacheLookup(kind string, hit bool)
+	// CCIPGatewayError records a failed request to a CCIP-Read gateway.
+	CCIPGatewayError(gateway string)
+}
+
+// noopMetrics is the default metrics implementation, which discards all
+// instrumentation.
+type noopMetrics struct{}
+
+func (noopMetrics) ObserveResolution(string, time.Duration, error) {}
+func (noopMetrics) ObserveRPCCall(string, time.Duration, error)    {}
+func (noopMetrics) CacheLookup(string, bool)                       {}
+func (noopMetrics) CCIPGatewayError(string)                        {}
+
+// withMetrics sets the implementation that receives instrumentation from
+// calls.  It is supplied by clients created with WithMetrics.
+func withMetrics(m Metrics) CallOption {
+	return func(o *callOptions) {
+		if m != nil {
+			o.metrics = m
+		}
+	}
+}
+
+// observeResolution records the duration of a resolution operation started
+// at the given time.
+func (o *callOptions) observeResolution(operation string, started time.Time, err error) {
+	o.metrics.ObserveResolution(operation, time.Since(started), err)
+}
+
+// InstrumentedBackend is a contract backend that reports the count and
+// duration of the requests it makes to an upstream backend.
+type InstrumentedBackend struct {
+	backend bind.ContractBackend
+	metrics Metrics
+}
+
+var _ bind.ContractBackend = (*InstrumentedBackend)(nil)
+
+// NewInstrumentedBackend creates a backend that reports requests to the
+// given metrics implementation.  Clients created with WithMetrics instrument
+// their backend in this way.
+func NewInstrumentedBackend(backend bind.ContractBackend, metrics Metrics) (*InstrumentedBackend, error) {
+	if backend == nil {
+		return nil, errors.New("no backend supplied")
+	}
+	if metrics == nil {
+		return nil, errors.New("no metrics supplied")
+	}
+
+	return &InstrumentedBackend{
+		backend: backend,
+		metrics: metrics,
+	}, nil
+}
+
+// observe records a call to a backend method started at the given time.
+func (b *InstrumentedBackend) observe(method string, started time.Time, err error) {
+	b.metrics.ObserveRPCCall(method, time.Since(started), err)
+}
+
+// CodeAt returns the code of the given account.
+func (b *InstrumentedBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	started := time.Now()
+	res, err := b.backend.CodeAt(ctx, contract, blockNumber)
+	b.observe("eth_getCode", started, err)
+	return res, err
+}
+
+// CallContract executes an Ethereum contract call with the specified data as the input.
+func (b *InstrumentedBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	started := time.Now()
+	res, err := b.backend.CallContract(ctx, call, blockNumber)
+	b.observe("eth_call", started, err)
+	return res, err
+}
+
+// HeaderByNumber returns a block header from the current canonical chain.
+func (b *InstrumentedBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	started := time.Now()
+	res, err := b.backend.HeaderByNumber(ctx, number)
+	b.observe("eth_getBlockByNumber", started, err)
+	return res, err
+}
+
+// PendingCodeAt returns the code of the given account in the pending state.
+func (b *InstrumentedBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	started := time.Now()
+	res, err := b.backend.PendingCodeAt(ctx, account)
+	b.observe("eth_getCode", started, err)
+	return res, err
+}
+
+// PendingNonceAt retrieves the current pending nonce associated with an account.
+func (b *InstrumentedBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	started := time.Now()
+	res, err := b.backend.PendingNonceAt(ctx, account)
+	b.observe("eth_getTransactionCount", started, err)
+	return res, err
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price.
+func (b *InstrumentedBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	started := time.Now()
+	res, err := b.backend.SuggestGasPrice(ctx)
+	b.observe("eth_gasPrice", started, err)
+	return res, err
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap.
+func (b *InstrumentedBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	started := time.Now()
+	res, err := b.backend.SuggestGasTipCap(ctx)
+	b.observe("eth_maxPriorityFeePerGas", started, err)
+	return res, err
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction.
+func (b *InstrumentedBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	started := time.Now()
+	res, err := b.backend.EstimateGas(ctx, call)
+	b.observe("eth_estimateGas", started, err)
+	return res, err
+}
+
+// SendTransaction injects the transaction into the pending pool for execution.
+func (b *InstrumentedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	started := time.Now()
+	err := b.backend.SendTransaction(ctx, tx)
+	b.observe("eth_sendRawTransaction", started, err)
+	return err
+}
+
+// FilterLogs executes a log filter operation.
+func (b *InstrumentedBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	started := time.Now()
+	res, err := b.backend.FilterLogs(ctx, query)
+	b.observe("eth_getLogs", started, err)
+	return res, err
+}
+
+// SubscribeFilterLogs creates a background log filtering operation.
+func (b *InstrumentedBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	started := time.Now()
+	res, err := b.backend.SubscribeFilterLogs(ctx, query, ch)
+	b.observe("eth_subscribe", started, err)
+	return res, err
+}
@@ -0,0 +1,105 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingMetrics is a metrics implementation that records what it
+// receives.
+type recordingMetrics struct {
+	mu          sync.Mutex
+	resolutions map[string]int
+	rpcCalls    map[string]int
+	hits        map[string]int
+	misses      map[string]int
+}
+
+func newRecordingMetrics() *recordingMetrics {
+	return &recordingMetrics{
+		resolutions: make(map[string]int),
+		rpcCalls:    make(map[string]int),
+		hits:        make(map[string]int),
+		misses:      make(map[string]int),
+	}
+}
+
+func (m *recordingMetrics) ObserveResolution(operation string, _ time.Duration, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.resolutions[operation]++
+}
+
+func (m *recordingMetrics) ObserveRPCCall(method string, _ time.Duration, _ error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rpcCalls[method]++
+}
+
+func (m *recordingMetrics) CacheLookup(kind string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if hit {
+		m.hits[kind]++
+	} else {
+		m.misses[kind]++
+	}
+}
+
+func (*recordingMetrics) CCIPGatewayError(string) {}
+
+func TestClientMetrics(t *testing.T) {
+	ens := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	ens.register("metrics.eth", address, address)
+
+	metrics := newRecordingMetrics()
+	client, err := NewClient(ens.backend, WithMetrics(metrics), WithCache(nil), WithUniversalResolver(false))
+	require.NoError(t, err)
+	other, err := NewClient(ens.backend, WithCache(nil), WithUniversalResolver(false))
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err = client.Resolve("metrics.eth")
+		require.NoError(t, err)
+	}
+	_, err = client.Resolve("unregistered.eth")
+	require.Error(t, err)
+
+	// Another client does not report to the metrics.
+	_, err = other.Resolve("metrics.eth")
+	require.NoError(t, err)
+
+	metrics.mu.Lock()
+	defer metrics.mu.Unlock()
+	require.Equal(t, 2, metrics.hits[cacheKindAddress])
+	require.Equal(t, 2, metrics.misses[cacheKindAddress])
+	require.Equal(t, 2, metrics.resolutions["resolve"])
+	require.Positive(t, metrics.rpcCalls["eth_call"])
+}
+
+func TestNewInstrumentedBackend(t *testing.T) {
+	_, err := NewInstrumentedBackend(nil, newRecordingMetrics())
+	require.EqualError(t, err, "no backend supplied")
+
+	_, err = NewInstrumentedBackend(newMockENS().backend, nil)
+	require.EqualError(t, err, "no metrics supplied")
+}
@@ -0,0 +1,391 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+// wellKnownTextKeys are the text record keys that are checked in addition to
+// those found in a resolver's events.
+var wellKnownTextKeys = []string{
+	"avatar",
+	"description",
+	"display",
+	"email",
+	"header",
+	"keywords",
+	"location",
+	"mail",
+	"name",
+	"notice",
+	"phone",
+	"url",
+	"com.discord",
+	"com.github",
+	"com.reddit",
+	"com.twitter",
+	"org.telegram",
+}
+
+// resolverRecords are the records held by a resolver for a name.
+type resolverRecords struct {
+	addresses   map[uint64][]byte
+	texts       map[string]string
+	contenthash []byte
+	abis        map[uint64][]byte
+	pubkeyX     [32]byte
+	pubkeyY     [32]byte
+}
+
+// resolverCall is a call to set a resolver record.
+type resolverCall struct {
+	method string
+	args   []interface{}
+}
+
+// MigrateResolver copies the address, text, contenthash, ABI and public key
+// records of a domain from its current resolver to a new resolver, then sets
+// the new resolver for the domain in the registry.
+//
+// Records are found from the current resolver's events, along with the
+// Ethereum address and well-known text keys.  If the new resolver supports
+// multicall the records are written in a single transaction, otherwise a
+// transaction is sent for each record.  The registry is updated in the final
+// transaction; all returned transactions should be confirmed before relying
+// on the new resolver.
+func MigrateResolver(backend bind.ContractBackend, opts *bind.TransactOpts, domain string, newResolver common.Address, chainId ChainId) ([]*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+	nameHash, err := NameHash(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	registry, err := NewRegistry(backend, chainId)
+	if err != nil {
+		return nil, err
+	}
+	current, err := NewResolver(backend, domain, chainId)
+	if err != nil {
+		return nil, err
+	}
+	if current.ContractAddr == newResolver {
+		return nil, fmt.Errorf("%s already uses resolver %s", domain, newResolver.Hex())
+	}
+	target, err := resolver.NewContract(newResolver, backend)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := target.SupportsInterface(nil, addrInterfaceID); err != nil {
+		return nil, wrapError(ErrNotAResolver, "%s is not a resolver", newResolver.Hex())
+	}
+
+	records, err := readResolverRecords(current, nameHash)
+	if err != nil {
+		return nil, err
+	}
+
+	nextOpts := sequentialOpts(opts)
+	txs, err := writeResolverRecords(target, nextOpts, records.calls(nameHash))
+	if err != nil {
+		return txs, err
+	}
+
+	tx, err := registry.SetResolver(nextOpts(), domain, newResolver)
+	if err != nil {
+		return txs, err
+	}
+	forgetResolver(nameHash)
+
+	return append(txs, tx), nil
+}
+
+// readResolverRecords reads the records for a node from a resolver, using
+// the resolver's events to find the coin types, text keys and ABI content
+// types in use.
+func readResolverRecords(r *Resolver, node [32]byte) (*resolverRecords, error) {
+	records := &resolverRecords{
+		addresses: make(map[uint64][]byte),
+		texts:     make(map[string]string),
+		abis:      make(map[uint64][]byte),
+	}
+	supports := func(interfaceID [4]byte) bool {
+		supported, err := r.Contract.SupportsInterface(nil, interfaceID)
+		return err == nil && supported
+	}
+	filterOpts := &bind.FilterOpts{Start: 0}
+	nodes := [][32]byte{node}
+
+	if supports(multiAddrInterfaceID) {
+		coinTypes := map[uint64]bool{60: true}
+		addressChanges, err := r.Contract.FilterAddressChanged(filterOpts, nodes)
+		if err != nil {
+			return nil, err
+		}
+		for addressChanges.Next() {
+			if addressChanges.Event.CoinType.IsUint64() {
+				coinTypes[addressChanges.Event.CoinType.Uint64()] = true
+			}
+		}
+		if err := addressChanges.Error(); err != nil {
+			return nil, err
+		}
+		for coinType := range coinTypes {
+			address, err := r.Contract.Addr0(nil, node, new(big.Int).SetUint64(coinType))
+			if err != nil {
+				return nil, err
+			}
+			if len(address) > 0 {
+				records.addresses[coinType] = address
+			}
+		}
+	} else if supports(addrInterfaceID) {
+		address, err := r.Contract.Addr(nil, node)
+		if err != nil {
+			return nil, err
+		}
+		if address != UnknownAddress {
+			records.addresses[60] = address.Bytes()
+		}
+	}
+
+	if supports(textInterfaceID) {
+		keys := make(map[string]bool)
+		for _, key := range wellKnownTextKeys {
+			keys[key] = true
+		}
+		textChanges, err := r.Contract.FilterTextChanged(filterOpts, nodes, nil)
+		if err != nil {
+			return nil, err
+		}
+		for textChanges.Next() {
+			keys[textChanges.Event.Key] = true
+		}
+		if err := textChanges.Error(); err != nil {
+			return nil, err
+		}
+		for key := range keys {
+			value, err := r.Contract.Text(nil, node, key)
+			if err != nil {
+				return nil, err
+			}
+			if value != "" {
+				records.texts[key] = value
+			}
+		}
+	}
+
+	if supports(contenthashInterfaceID) {
+		contenthash, err := r.Contract.Contenthash(nil, node)
+		if err != nil {
+			return nil, err
+		}
+		if len(contenthash) > 0 {
+			records.contenthash = contenthash
+		}
+	} else if supportsAny(supports, legacyContentInterfaceIDs) {
+		// Content records of the original public resolvers are migrated as
+		// contenthash records.
+		content, err := r.legacyContent(nil, node)
+		if err != nil {
+			return nil, err
+		}
+		if content != [32]byte{} {
+			records.contenthash, err = LegacyContentToContenthash(content)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if supports(abiInterfaceID) {
+		abiChanges, err := r.Contract.FilterABIChanged(filterOpts, nodes, nil)
+		if err != nil {
+			return nil, err
+		}
+		contentTypes := make(map[uint64]bool)
+		for abiChanges.Next() {
+			if abiChanges.Event.ContentType.IsUint64() {
+				contentTypes[abiChanges.Event.ContentType.Uint64()] = true
+			}
+		}
+		if err := abiChanges.Error(); err != nil {
+			return nil, err
+		}
+		for contentType := range contentTypes {
+			returnedType, data, err := r.Contract.ABI(nil, node, new(big.Int).SetUint64(contentType))
+			if err != nil {
+				return nil, err
+			}
+			if returnedType.IsUint64() && returnedType.Uint64() == contentType && len(data) > 0 {
+				records.abis[contentType] = data
+			}
+		}
+	}
+
+	if supports(pubkeyInterfaceID) {
+		pubkey, err := r.Contract.Pubkey(nil, node)
+		if err != nil {
+			return nil, err
+		}
+		records.pubkeyX = pubkey.X
+		records.pubkeyY = pubkey.Y
+	}
+
+	return records, nil
+}
+
+// supportsAny returns true if any of the interfaces are supported.
+func supportsAny(supports func([4]byte) bool, interfaceIDs [][4]byte) bool {
+	for _, interfaceID := range interfaceIDs {
+		if supports(interfaceID) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// calls returns the calls required to set the records on a resolver, in a
+// deterministic order.
+func (r *resolverRecords) calls(node [32]byte) []resolverCall {
+	calls := make([]resolverCall, 0)
+
+	coinTypes := make([]uint64, 0, len(r.addresses))
+	for coinType := range r.addresses {
+		coinTypes = append(coinTypes, coinType)
+	}
+	sort.Slice(coinTypes, func(i, j int) bool { return coinTypes[i] < coinTypes[j] })
+	for _, coinType := range coinTypes {
+		calls = append(calls, resolverCall{
+			method: "setAddr0",
+			args:   []interface{}{node, new(big.Int).SetUint64(coinType), r.addresses[coinType]},
+		})
+	}
+
+	keys := make([]string, 0, len(r.texts))
+	for key := range r.texts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		calls = append(calls, resolverCall{
+			method: "setText",
+			args:   []interface{}{node, key, r.texts[key]},
+		})
+	}
+
+	if len(r.contenthash) > 0 {
+		calls = append(calls, resolverCall{
+			method: "setContenthash",
+			args:   []interface{}{node, r.contenthash},
+		})
+	}
+
+	contentTypes := make([]uint64, 0, len(r.abis))
+	for contentType := range r.abis {
+		contentTypes = append(contentTypes, contentType)
+	}
+	sort.Slice(contentTypes, func(i, j int) bool { return contentTypes[i] < contentTypes[j] })
+	for _, contentType := range contentTypes {
+		calls = append(calls, resolverCall{
+			method: "setABI",
+			args:   []interface{}{node, new(big.Int).SetUint64(contentType), r.abis[contentType]},
+		})
+	}
+
+	if r.pubkeyX != [32]byte{} || r.pubkeyY != [32]byte{} {
+		calls = append(calls, resolverCall{
+			method: "setPubkey",
+			args:   []interface{}{node, r.pubkeyX, r.pubkeyY},
+		})
+	}
+
+	return calls
+}
+
+// sequentialOpts returns a function that provides a copy of the transaction
+// options for each of a sequence of transactions, incrementing the nonce if
+// one is set.
+func sequentialOpts(opts *bind.TransactOpts) func() *bind.TransactOpts {
+	txOpts := *opts
+	return func() *bind.TransactOpts {
+		res := txOpts
+		if txOpts.Nonce != nil {
+			txOpts.Nonce = new(big.Int).Add(txOpts.Nonce, big.NewInt(1))
+		}
+		return &res
+	}
+}
+
+// writeResolverRecords sends the calls to set records on a resolver.  If the
+// resolver supports multicall the calls are sent in a single transaction,
+// otherwise a transaction is sent for each call.
+func writeResolverRecords(target *resolver.Contract, nextOpts func() *bind.TransactOpts, calls []resolverCall) ([]*types.Transaction, error) {
+	txs := make([]*types.Transaction, 0)
+	if len(calls) == 0 {
+		return txs, nil
+	}
+
+	if supportsMulticall(target) {
+		parsed, err := resolver.ContractMetaData.GetAbi()
+		if err != nil {
+			return nil, err
+		}
+		data := make([][]byte, len(calls))
+		for i, call := range calls {
+			data[i], err = parsed.Pack(call.method, call.args...)
+			if err != nil {
+				return nil, err
+			}
+		}
+		tx, err := target.Multicall(nextOpts(), data)
+		if err != nil {
+			return nil, err
+		}
+		return append(txs, tx), nil
+	}
+
+	raw := &resolver.ContractRaw{Contract: target}
+	for _, call := range calls {
+		tx, err := raw.Transact(nextOpts(), call.method, call.args...)
+		if err != nil {
+			return txs, err
+		}
+		txs = append(txs, tx)
+	}
+
+	return txs, nil
+}
+
+// supportsMulticall returns true if the resolver supports multicall.
+func supportsMulticall(r *resolver.Contract) bool {
+	for _, interfaceID := range [][4]byte{multicallInterfaceID, legacyMulticallInterfaceID} {
+		if supported, err := r.SupportsInterface(nil, interfaceID); err == nil && supported {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+func TestMigrateResolver(t *testing.T) {
+	tests := []struct {
+		name      string
+		multicall bool
+		txs       int
+	}{
+		{
+			name:      "Multicall",
+			multicall: true,
+			txs:       2,
+		},
+		{
+			name: "Individual",
+			txs:  7,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockENS()
+			opts := testTransactOpts(t)
+			node := mustNameHash("migrate.eth")
+			m.register("migrate.eth", opts.From, opts.From)
+			m.setText("migrate.eth", "url", "https://example.com/")
+			m.setText("migrate.eth", "custom.key", "custom value")
+			m.setCoinAddr("migrate.eth", 0, []byte{0x00, 0x14, 0x01, 0x02})
+			m.backend.addEvent(m.resolverAddr, resolver.ContractABI, "AddressChanged", []common.Hash{node}, big.NewInt(0), []byte{0x00, 0x14, 0x01, 0x02})
+			m.backend.addEvent(m.resolverAddr, resolver.ContractABI, "TextChanged", []common.Hash{node, crypto.Keccak256Hash([]byte("custom.key"))}, "custom.key")
+			m.backend.contracts[m.resolverAddr].
+				on("contenthash", func(_ []interface{}) ([]interface{}, error) {
+					return []interface{}{[]byte{0xe3, 0x01}}, nil
+				}).
+				on("pubkey", func(_ []interface{}) ([]interface{}, error) {
+					return []interface{}{[32]byte{0x01}, [32]byte{0x02}}, nil
+				})
+			m.backend.contracts[m.registryAddr].on("setResolver", func(_ []interface{}) ([]interface{}, error) {
+				return []interface{}{}, nil
+			})
+
+			written := make(map[string]int)
+			newResolver := common.HexToAddress("0x0000000000000000000000000000000000000b01")
+			target := m.backend.deploy(newResolver, resolver.ContractABI).
+				on("supportsInterface", func(args []interface{}) ([]interface{}, error) {
+					interfaceID := args[0].([4]byte)
+					isMulticall := interfaceID == multicallInterfaceID || interfaceID == legacyMulticallInterfaceID
+					return []interface{}{!isMulticall || test.multicall}, nil
+				}).
+				on("multicall", func(args []interface{}) ([]interface{}, error) {
+					written["multicall"] = len(args[0].([][]byte))
+					return []interface{}{[][]byte{}}, nil
+				})
+			for _, method := range []string{"setAddr0", "setText", "setContenthash", "setPubkey"} {
+				method := method
+				target.on(method, func(_ []interface{}) ([]interface{}, error) {
+					written[method]++
+					return []interface{}{}, nil
+				})
+			}
+
+			_, err := MigrateResolver(m.backend, opts, "migrate.eth", m.resolverAddr, EthereumMainnet)
+			require.EqualError(t, err, "migrate.eth already uses resolver "+m.resolverAddr.Hex())
+
+			noResolver := common.HexToAddress("0x0000000000000000000000000000000000000b02")
+			_, err = MigrateResolver(m.backend, opts, "migrate.eth", noResolver, EthereumMainnet)
+			require.ErrorIs(t, err, ErrNotAResolver)
+
+			txs, err := MigrateResolver(m.backend, opts, "migrate.eth", newResolver, EthereumMainnet)
+			require.NoError(t, err)
+			require.Len(t, txs, test.txs)
+			require.Len(t, m.backend.sent, test.txs)
+			require.Equal(t, m.registryAddr, *txs[len(txs)-1].To())
+			if test.multicall {
+				// Addresses for coin types 0 and 60, two text records,
+				// contenthash and public key.
+				require.Equal(t, 6, written["multicall"])
+			} else {
+				require.Equal(t, 2, written["setAddr0"])
+				require.Equal(t, 2, written["setText"])
+				require.Equal(t, 1, written["setContenthash"])
+				require.Equal(t, 1, written["setPubkey"])
+			}
+		})
+	}
+}
@@ -0,0 +1,510 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/wealdtech/go-ens/v3/contracts/multicall3"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+// mockMethod is the implementation of a contract method in the mock backend.
+type mockMethod func(args []interface{}) ([]interface{}, error)
+
+// mockContract is a contract in the mock backend.
+type mockContract struct {
+	abi     abi.ABI
+	methods map[string]mockMethod
+}
+
+// on sets the implementation of a method.
+func (c *mockContract) on(name string, fn mockMethod) *mockContract {
+	c.methods[name] = fn
+	return c
+}
+
+// mockBackend is a contract backend that dispatches calls to Go
+// implementations of contract methods, decoding and encoding data with the
+// contracts' ABIs.
+type mockBackend struct {
+	mu        sync.Mutex
+	contracts map[common.Address]*mockContract
+	calls     int
+	sent      []*types.Transaction
+	logs      []types.Log
+	subs      []*mockSubscription
+}
+
+// mockSubscription is a log subscription on the mock backend.
+type mockSubscription struct {
+	query ethereum.FilterQuery
+	ch    chan<- types.Log
+	sub   event.Subscription
+}
+
+func newMockBackend() *mockBackend {
+	return &mockBackend{
+		contracts: make(map[common.Address]*mockContract),
+	}
+}
+
+// deploy places a contract with the given ABI at the given address.
+func (b *mockBackend) deploy(address common.Address, abiJSON string) *mockContract {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+	contract := &mockContract{
+		abi:     parsed,
+		methods: make(map[string]mockMethod),
+	}
+	b.mu.Lock()
+	b.contracts[address] = contract
+	b.mu.Unlock()
+
+	return contract
+}
+
+func (b *mockBackend) callCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.calls
+}
+
+func (b *mockBackend) CodeAt(_ context.Context, contract common.Address, _ *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, exists := b.contracts[contract]; exists {
+		return []byte{0x01}, nil
+	}
+	return nil, nil
+}
+
+func (b *mockBackend) CallContract(_ context.Context, call ethereum.CallMsg, _ *big.Int) ([]byte, error) {
+	b.mu.Lock()
+	b.calls++
+	b.mu.Unlock()
+	if call.To == nil {
+		return nil, nil
+	}
+	return b.dispatch(*call.To, call.Data)
+}
+
+// dispatch runs a call against a contract without counting it, for example
+// for calls made by another contract.
+func (b *mockBackend) dispatch(address common.Address, data []byte) ([]byte, error) {
+	b.mu.Lock()
+	contract := b.contracts[address]
+	b.mu.Unlock()
+	if contract == nil || len(data) < 4 {
+		return nil, nil
+	}
+
+	method, err := contract.abi.MethodById(data[:4])
+	if err != nil {
+		return nil, errors.New("execution reverted")
+	}
+	fn, exists := contract.methods[method.Name]
+	if !exists {
+		return nil, errors.New("execution reverted")
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	res, err := fn(args)
+	if err != nil {
+		return nil, err
+	}
+	return method.Outputs.Pack(res...)
+}
+
+func (b *mockBackend) HeaderByNumber(_ context.Context, _ *big.Int) (*types.Header, error) {
+	return &types.Header{
+		Number:  big.NewInt(1000),
+		BaseFee: big.NewInt(1_000_000_000),
+	}, nil
+}
+
+func (b *mockBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return b.CodeAt(ctx, account, nil)
+}
+
+func (b *mockBackend) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return uint64(len(b.sent)), nil
+}
+
+func (b *mockBackend) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return big.NewInt(2_000_000_000), nil
+}
+
+func (b *mockBackend) SuggestGasTipCap(_ context.Context) (*big.Int, error) {
+	return big.NewInt(1_000_000_000), nil
+}
+
+func (b *mockBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	if _, err := b.CallContract(ctx, call, nil); err != nil {
+		return 0, err
+	}
+	return 100_000, nil
+}
+
+func (b *mockBackend) SendTransaction(_ context.Context, tx *types.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sent = append(b.sent, tx)
+	return nil
+}
+
+func (b *mockBackend) FilterLogs(_ context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	res := make([]types.Log, 0)
+	for _, log := range b.logs {
+		if mockLogMatches(log, query) {
+			res = append(res, log)
+		}
+	}
+	return res, nil
+}
+
+func (b *mockBackend) SubscribeFilterLogs(_ context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	sub := event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		return nil
+	})
+	b.mu.Lock()
+	b.subs = append(b.subs, &mockSubscription{query: query, ch: ch, sub: sub})
+	b.mu.Unlock()
+	return sub, nil
+}
+
+// addEvent adds a log for an event to the backend.  indexed contains the
+// topics for the event's indexed arguments and args its other arguments.
+func (b *mockBackend) addEvent(address common.Address, abiJSON string, name string, indexed []common.Hash, args ...interface{}) {
+	parsed, err := abi.JSON(strings.NewReader(abiJSON))
+	if err != nil {
+		panic(err)
+	}
+	event, exists := parsed.Events[name]
+	if !exists {
+		panic(fmt.Sprintf("unknown event %s", name))
+	}
+	data, err := event.Inputs.NonIndexed().Pack(args...)
+	if err != nil {
+		panic(err)
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.logs = append(b.logs, types.Log{
+		Address:     address,
+		Topics:      append([]common.Hash{event.ID}, indexed...),
+		Data:        data,
+		BlockNumber: uint64(len(b.logs) + 1),
+	})
+}
+
+// emit delivers a log to matching subscriptions, blocking until each has
+// received it.
+func (b *mockBackend) emit(log types.Log) {
+	b.mu.Lock()
+	subs := make([]*mockSubscription, len(b.subs))
+	copy(subs, b.subs)
+	b.mu.Unlock()
+	for _, sub := range subs {
+		if mockLogMatches(log, sub.query) {
+			sub.ch <- log
+		}
+	}
+}
+
+func mockLogMatches(log types.Log, query ethereum.FilterQuery) bool {
+	if query.FromBlock != nil && log.BlockNumber < query.FromBlock.Uint64() {
+		return false
+	}
+	if query.ToBlock != nil && log.BlockNumber > query.ToBlock.Uint64() {
+		return false
+	}
+	if len(query.Addresses) > 0 {
+		found := false
+		for _, address := range query.Addresses {
+			if address == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for i, topics := range query.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		if i >= len(log.Topics) {
+			return false
+		}
+		found := false
+		for _, topic := range topics {
+			if topic == log.Topics[i] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// mockENS is a minimal ENS deployment on a mock backend, with a registry and
+// a single resolver that also acts as the reverse resolver.
+type mockENS struct {
+	backend      *mockBackend
+	registryAddr common.Address
+	resolverAddr common.Address
+	mu           sync.Mutex
+	owners       map[[32]byte]common.Address
+	resolvers    map[[32]byte]common.Address
+	addrs        map[[32]byte]common.Address
+	coinAddrs    map[[32]byte]map[uint64][]byte
+	names        map[[32]byte]string
+	texts        map[[32]byte]map[string]string
+}
+
+func newMockENS() *mockENS {
+	return newMockENSAt(common.HexToAddress("0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"))
+}
+
+// newMockENSAt creates a mock ENS deployment with the registry at the given
+// address.
+func newMockENSAt(registryAddr common.Address) *mockENS {
+	m := &mockENS{
+		backend:      newMockBackend(),
+		registryAddr: registryAddr,
+		resolverAddr: common.HexToAddress("0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63"),
+		owners:       make(map[[32]byte]common.Address),
+		resolvers:    make(map[[32]byte]common.Address),
+		addrs:        make(map[[32]byte]common.Address),
+		coinAddrs:    make(map[[32]byte]map[uint64][]byte),
+		names:        make(map[[32]byte]string),
+		texts:        make(map[[32]byte]map[string]string),
+	}
+
+	m.backend.deploy(m.registryAddr, registry.ContractABI).
+		on("owner", func(args []interface{}) ([]interface{}, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			return []interface{}{m.owners[args[0].([32]byte)]}, nil
+		}).
+		on("resolver", func(args []interface{}) ([]interface{}, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			return []interface{}{m.resolvers[args[0].([32]byte)]}, nil
+		})
+
+	m.backend.deploy(m.resolverAddr, resolver.ContractABI).
+		on("addr", func(args []interface{}) ([]interface{}, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			return []interface{}{m.addrs[args[0].([32]byte)]}, nil
+		}).
+		on("addr0", func(args []interface{}) ([]interface{}, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			node := args[0].([32]byte)
+			coinType := args[1].(*big.Int).Uint64()
+			if addr, exists := m.coinAddrs[node][coinType]; exists {
+				return []interface{}{addr}, nil
+			}
+			if coinType != 60 {
+				return []interface{}{[]byte{}}, nil
+			}
+			addr, exists := m.addrs[node]
+			if !exists {
+				return []interface{}{[]byte{}}, nil
+			}
+			return []interface{}{addr.Bytes()}, nil
+		}).
+		on("name", func(args []interface{}) ([]interface{}, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			return []interface{}{m.names[args[0].([32]byte)]}, nil
+		}).
+		on("text", func(args []interface{}) ([]interface{}, error) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			return []interface{}{m.texts[args[0].([32]byte)][args[1].(string)]}, nil
+		}).
+		on("contenthash", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{[]byte{}}, nil
+		}).
+		on("pubkey", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{[32]byte{}, [32]byte{}}, nil
+		}).
+		on("supportsInterface", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{true}, nil
+		})
+
+	return m
+}
+
+func mustNameHash(name string) [32]byte {
+	hash, err := NameHash(name)
+	if err != nil {
+		panic(err)
+	}
+	return hash
+}
+
+// register registers a name with the given owner and address, using the
+// mock resolver.
+func (m *mockENS) register(name string, owner common.Address, address common.Address) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node := mustNameHash(name)
+	m.owners[node] = owner
+	m.resolvers[node] = m.resolverAddr
+	if address != UnknownAddress {
+		m.addrs[node] = address
+	}
+}
+
+// setText sets a text record for a name.
+func (m *mockENS) setText(name string, key string, value string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node := mustNameHash(name)
+	if _, exists := m.texts[node]; !exists {
+		m.texts[node] = make(map[string]string)
+	}
+	m.texts[node][key] = value
+}
+
+// setCoinAddr sets the address of a name for a coin type.
+func (m *mockENS) setCoinAddr(name string, coinType uint64, address []byte) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	node := mustNameHash(name)
+	if _, exists := m.coinAddrs[node]; !exists {
+		m.coinAddrs[node] = make(map[uint64][]byte)
+	}
+	m.coinAddrs[node][coinType] = address
+}
+
+// setReverse sets the reverse record for an address.
+func (m *mockENS) setReverse(address common.Address, name string) {
+	m.setReverseIn(address, "addr.reverse", name)
+}
+
+// setReverseIn sets the reverse record for an address in the given namespace.
+func (m *mockENS) setReverseIn(address common.Address, namespace string, name string) {
+	reverse := fmt.Sprintf("%s.%s", address.Hex()[2:], namespace)
+	m.register(reverse, address, UnknownAddress)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.names[mustNameHash(reverse)] = name
+}
+
+// deployMockMulticall3 deploys Multicall3 on the mock backend.
+func deployMockMulticall3(b *mockBackend) {
+	b.deploy(Multicall3Address, multicall3.ContractABI).
+		on("aggregate3", func(args []interface{}) ([]interface{}, error) {
+			calls := args[0].([]struct {
+				Target       common.Address `json:"target"`
+				AllowFailure bool           `json:"allowFailure"`
+				CallData     []byte         `json:"callData"`
+			})
+			res := make([]multicall3.Multicall3Result, len(calls))
+			for i, call := range calls {
+				returnData, err := b.dispatch(call.Target, call.CallData)
+				if err != nil && !call.AllowFailure {
+					return nil, errors.New("execution reverted")
+				}
+				if dataErr, isDataErr := err.(rpc.DataError); isDataErr {
+					// Failed calls return their revert data.
+					returnData, _ = hexutil.Decode(dataErr.ErrorData().(string))
+				}
+				res[i] = multicall3.Multicall3Result{Success: err == nil, ReturnData: returnData}
+			}
+			return []interface{}{res}, nil
+		})
+}
+
+// mockRevertError is the error returned by an RPC client for a call that
+// reverted with the given data.
+type mockRevertError struct {
+	data []byte
+}
+
+func (e *mockRevertError) Error() string {
+	if reason, err := abi.UnpackRevert(e.data); err == nil {
+		return "execution reverted: " + reason
+	}
+	return "execution reverted"
+}
+
+func (e *mockRevertError) ErrorData() interface{} {
+	return hexutil.Encode(e.data)
+}
+
+// revertReasonData builds the revert data for a reason string.
+func revertReasonData(reason string) []byte {
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		panic(err)
+	}
+	data, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	if err != nil {
+		panic(err)
+	}
+	return append(crypto.Keccak256([]byte("Error(string)"))[:4], data...)
+}
+
+// customErrorData builds the revert data for a custom error.
+func customErrorData(signature string, args ...[]byte) []byte {
+	data := crypto.Keccak256([]byte(signature))[:4]
+	for _, arg := range args {
+		data = append(data, common.LeftPadBytes(arg, 32)...)
+	}
+	return data
+}
+
+// offchainLookupData builds the revert data for an offchain lookup.
+func offchainLookupData(lookup *OffchainLookup) []byte {
+	data, err := offchainLookupArgs.Pack(lookup.Sender, lookup.URLs, lookup.CallData, lookup.CallbackFunction, lookup.ExtraData)
+	if err != nil {
+		panic(err)
+	}
+	return append(append([]byte{}, offchainLookupSelector...), data...)
+}
@@ -0,0 +1,146 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/ethregistrarcontroller"
+	"github.com/wealdtech/go-ens/v3/contracts/priceoracle"
+	"github.com/wealdtech/go-ens/v3/contracts/usdoracle"
+)
+
+// mockRegistrarController is the .eth registrar controller on a mock backend,
+// optionally with its price oracle and USD oracle.
+type mockRegistrarController struct {
+	backend    *mockBackend
+	controller *RegistrarController
+	// calls holds the arguments of the latest call to each method that
+	// changes state, and of rentPrice.
+	calls map[string][]interface{}
+	// committed is the time at which the commitment was mined, or 0 if it
+	// has not been.
+	committed atomic.Int64
+	// mineCommitments marks commitments as mined and mature when they are
+	// sent, rather than leaving committed to the test.
+	mineCommitments bool
+	// premium is the premium included in rentPrice.
+	premium *big.Int
+}
+
+// newMockRegistrarController creates a registrar controller whose commitments
+// mature after a minute and whose names cost 0.01 ETH a year.  With oracles,
+// the price oracle's premium starts at 100 ETH when a name becomes available
+// and halves each day, and ETH is priced at $2,000.
+func newMockRegistrarController(t *testing.T, withOracles bool) *mockRegistrarController {
+	t.Helper()
+	m := &mockRegistrarController{
+		backend: newMockBackend(),
+		calls:   make(map[string][]interface{}),
+		premium: big.NewInt(0),
+	}
+	oracleAddr := common.HexToAddress("0x0000000000000000000000000000000000000a01")
+	usdOracleAddr := common.HexToAddress("0x0000000000000000000000000000000000000a02")
+
+	m.backend.deploy(ChainConfigFor(EthereumMainnet).RegistrarController, ethregistrarcontroller.ContractABI).
+		on("MIN_REGISTRATION_DURATION", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(28 * 24 * 60 * 60)}, nil
+		}).
+		on("minCommitmentAge", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(60)}, nil
+		}).
+		on("maxCommitmentAge", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(24 * 60 * 60)}, nil
+		}).
+		on("prices", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{oracleAddr}, nil
+		}).
+		on("rentPrice", func(args []interface{}) ([]interface{}, error) {
+			m.calls["rentPrice"] = args
+			return []interface{}{ethregistrarcontroller.IPriceOraclePrice{
+				Base:    big.NewInt(10_000_000_000_000_000),
+				Premium: m.premium,
+			}}, nil
+		}).
+		on("makeCommitment", func(args []interface{}) ([]interface{}, error) {
+			m.calls["makeCommitment"] = args
+			return []interface{}{[32]byte{0x01}}, nil
+		}).
+		on("commit", func(args []interface{}) ([]interface{}, error) {
+			m.calls["commit"] = args
+			if m.mineCommitments {
+				m.committed.Store(time.Now().Add(-2 * time.Minute).Unix())
+			}
+			return []interface{}{}, nil
+		}).
+		on("commitments", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(m.committed.Load())}, nil
+		}).
+		on("register", func(args []interface{}) ([]interface{}, error) {
+			// As on chain, registration fails until the commitment matures.
+			if committed := m.committed.Load(); committed == 0 || time.Since(time.Unix(committed, 0)) < time.Minute {
+				return nil, errors.New("execution reverted")
+			}
+			m.calls["register"] = args
+			return []interface{}{}, nil
+		}).
+		on("renew", func(args []interface{}) ([]interface{}, error) {
+			m.calls["renew"] = args
+			return []interface{}{}, nil
+		})
+	if withOracles {
+		m.backend.deploy(oracleAddr, priceoracle.ContractABI).
+			on("price", func(args []interface{}) ([]interface{}, error) {
+				available := time.Unix(args[1].(*big.Int).Int64(), 0).Add(registrarGracePeriod)
+				days := time.Since(available).Hours() / 24
+				if days < 0 {
+					days = 0
+				}
+				premium, _ := new(big.Float).SetFloat64(100 * math.Pow(0.5, days) * 1e18).Int(nil)
+				return []interface{}{priceoracle.IPriceOraclePrice{
+					Base:    big.NewInt(10_000_000_000_000_000),
+					Premium: premium,
+				}}, nil
+			}).
+			on("usdOracle", func(_ []interface{}) ([]interface{}, error) {
+				return []interface{}{usdOracleAddr}, nil
+			})
+		m.backend.deploy(usdOracleAddr, usdoracle.ContractABI).
+			on("latestAnswer", func(_ []interface{}) ([]interface{}, error) {
+				return []interface{}{big.NewInt(2000_00000000)}, nil
+			}).
+			on("decimals", func(_ []interface{}) ([]interface{}, error) {
+				return []interface{}{uint8(8)}, nil
+			})
+	}
+
+	var err error
+	m.controller, err = NewRegistrarController(m.backend, EthereumMainnet)
+	require.NoError(t, err)
+
+	return m
+}
+
+// contract returns the mock controller contract, for adding methods.
+func (m *mockRegistrarController) contract() *mockContract {
+	return m.backend.contracts[m.controller.ContractAddr]
+}
@@ -15,6 +15,7 @@
 package ens
 
 import (
+	"context"
 	"crypto/rand"
 	"errors"
 	"fmt"
@@ -43,7 +44,7 @@ type Name struct {
 
 // NewName creates an ENS name structure.
 // Note that this does not create the name on-chain.
-func NewName(backend bind.ContractBackend, name string) (*Name, error) {
+func NewName(backend Backend, name string) (*Name, error) {
 	name, err := NormaliseDomain(name)
 	if err != nil {
 		return nil, err
@@ -54,15 +55,16 @@ func NewName(backend bind.ContractBackend, name string) (*Name, error) {
 		return nil, err
 	}
 
-	registry, err := NewRegistry(backend, EthereumMainnet)
+	contractBackend := asContractBackend(backend)
+	registry, err := NewRegistry(contractBackend, EthereumMainnet)
 	if err != nil {
 		return nil, err
 	}
-	registrar, err := NewBaseRegistrar(backend, domain, EthereumMainnet)
+	registrar, err := NewBaseRegistrar(contractBackend, domain, EthereumMainnet)
 	if err != nil {
 		return nil, err
 	}
-	controller, err := NewETHController(backend, domain)
+	controller, err := NewETHController(contractBackend, domain)
 	if err != nil {
 		return nil, err
 	}
@@ -76,7 +78,7 @@ func NewName(backend bind.ContractBackend, name string) (*Name, error) {
 	}
 
 	return &Name{
-		backend:    backend,
+		backend:    contractBackend,
 		Name:       name,
 		Domain:     domain,
 		Label:      label,
@@ -199,9 +201,12 @@ func (n *Name) Expires() (time.Time, error) {
 	return time.Unix(expiryTS.Int64(), 0), nil
 }
 
-// Controller obtains the controller for this name.
+// Controller obtains the controller for this name, being its owner in the
+// registry.
 // The controller can carry out operations on the name such as setting
-// records, but cannot transfer ultimate ownership of the name.
+// records, but cannot transfer ultimate ownership of the name.  The
+// controller of a wrapped name is the name wrapper; use Ownership to obtain
+// the owner of the wrapped name.
 func (n *Name) Controller() (common.Address, error) {
 	return n.registry.Owner(n.Name)
 }
@@ -220,7 +225,7 @@ func (n *Name) SetController(controller common.Address, opts *bind.TransactOpts)
 	}
 
 	// Perhaps we are the registrant.
-	registrant, err := n.Registrant()
+	registrant, err := n.registrarOwner()
 	if err != nil {
 		return nil, err
 	}
@@ -235,7 +240,7 @@ func (n *Name) SetController(controller common.Address, opts *bind.TransactOpts)
 // Reclaim reclaims controller rights by the registrant.
 func (n *Name) Reclaim(opts *bind.TransactOpts) (*types.Transaction, error) {
 	// Ensure the we are the registrant.
-	registrant, err := n.Registrant()
+	registrant, err := n.registrarOwner()
 	if err != nil {
 		return nil, err
 	}
@@ -245,15 +250,38 @@ func (n *Name) Reclaim(opts *bind.TransactOpts) (*types.Transaction, error) {
 	return n.registrar.Reclaim(opts, n.Name, registrant)
 }
 
-// Registrant obtains the registrant for this name.
+// Registrant obtains the registrant for this name, being the owner of its
+// registrar token.  If the name is wrapped the registrar token is held by the
+// name wrapper, in which case the owner of the wrapped token is returned
+// instead.  The registrant of an expired name is UnknownAddress.
 func (n *Name) Registrant() (common.Address, error) {
+	registrant, err := n.registrarOwner()
+	if err != nil {
+		return UnknownAddress, err
+	}
+	wrapper, err := n.wrapper()
+	if err != nil {
+		return UnknownAddress, err
+	}
+	if wrapper == nil || registrant != wrapper.ContractAddr {
+		return registrant, nil
+	}
+	owner, _, _, err := wrapper.Data(n.Name)
+	return owner, err
+}
+
+// registrarOwner obtains the owner of the registrar token for this name,
+// which is the name wrapper if the name is wrapped.
+func (n *Name) registrarOwner() (common.Address, error) {
 	return n.registrar.Owner(n.Label)
 }
 
 // Transfer transfers the registration of this name to a new registrant.
+// This does not change the controller of the name; TransferRegistration
+// transfers both.
 func (n *Name) Transfer(registrant common.Address, opts *bind.TransactOpts) (*types.Transaction, error) {
 	// Ensure the we are the registrant.
-	currentRegistrant, err := n.Registrant()
+	currentRegistrant, err := n.registrarOwner()
 	if err != nil {
 		return nil, err
 	}
@@ -263,6 +291,110 @@ func (n *Name) Transfer(registrant common.Address, opts *bind.TransactOpts) (*ty
 	return n.registrar.SetOwner(opts, n.Label, registrant)
 }
 
+// TransferRegistration transfers the name to a new owner, covering both the
+// registrant and the controller.
+//
+// For an unwrapped name the registrar is asked to reclaim the name on behalf
+// of the new owner, making them the controller, and the registrar token is
+// then transferred to them; two transactions are returned.  For a wrapped
+// name the registrar token and control are both held by the name wrapper, so
+// the wrapped token is transferred in a single transaction.
+func (n *Name) TransferRegistration(newOwner common.Address, opts *bind.TransactOpts) ([]*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+	if newOwner == UnknownAddress {
+		return nil, errors.New("cannot transfer to the zero address")
+	}
+	registrant, err := n.registrarOwner()
+	if err != nil {
+		return nil, err
+	}
+	wrapper, err := n.wrapper()
+	if err != nil {
+		return nil, err
+	}
+
+	if wrapper != nil && registrant == wrapper.ContractAddr {
+		owner, fuses, _, err := wrapper.Data(n.Name)
+		if err != nil {
+			return nil, err
+		}
+		if owner != opts.From {
+			return nil, errors.New("not the owner of the wrapped name")
+		}
+		if fuses.Has(FuseCannotTransfer) {
+			return nil, fmt.Errorf("%s has burned CANNOT_TRANSFER so cannot be transferred", n.Name)
+		}
+		tokenID, err := WrapperTokenID(n.Name)
+		if err != nil {
+			return nil, err
+		}
+		tx, err := wrapper.Contract.SafeTransferFrom(opts, opts.From, newOwner, tokenID, big.NewInt(1), []byte{})
+		if err != nil {
+			return nil, err
+		}
+		return []*types.Transaction{tx}, nil
+	}
+
+	if registrant != opts.From {
+		return nil, errors.New("not the current registrant")
+	}
+	tokenID, err := RegistrarTokenID(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	// Reclaim must come first, as only the registrant can reclaim.
+	nextOpts := sequentialOpts(opts)
+	reclaimTx, err := n.registrar.Contract.Reclaim(nextOpts(), tokenID, newOwner)
+	if err != nil {
+		return nil, err
+	}
+	transferTx, err := n.registrar.Contract.SafeTransferFrom(nextOpts(), opts.From, newOwner, tokenID)
+	if err != nil {
+		return []*types.Transaction{reclaimTx}, err
+	}
+
+	return []*types.Transaction{reclaimTx, transferTx}, nil
+}
+
+// TransferControl transfers control of the name, as held in the registry, to
+// a new controller.  The registrant is unchanged, and can reclaim control at
+// any time; use TransferRegistration to transfer the name outright.  The
+// controller of a wrapped name is the name wrapper, so wrapped names can only
+// be transferred with TransferRegistration.
+func (n *Name) TransferControl(newController common.Address, opts *bind.TransactOpts) (*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+	controller, err := n.Controller()
+	if err != nil {
+		return nil, err
+	}
+	wrapper, err := n.wrapper()
+	if err != nil {
+		return nil, err
+	}
+	if wrapper != nil && controller == wrapper.ContractAddr {
+		return nil, fmt.Errorf("%s is wrapped so is controlled by the name wrapper; use TransferRegistration to transfer it", n.Name)
+	}
+	if controller != opts.From {
+		return nil, errors.New("not the current controller")
+	}
+
+	return n.registry.SetOwner(opts, n.Name, newController)
+}
+
+// wrapper returns the name wrapper, or nil if there is none.
+func (n *Name) wrapper() (*NameWrapper, error) {
+	address := ChainConfigFor(EthereumMainnet).NameWrapper
+	if address == UnknownAddress {
+		return nil, nil
+	}
+
+	return NewNameWrapperAt(n.backend, address)
+}
+
 // RentCost returns the cost of rent in Wei-per-second.
 func (n *Name) RentCost() (*big.Int, error) {
 	return n.controller.RentCost(n.Label)
@@ -283,6 +415,51 @@ func (n *Name) CreateSubdomain(label string, controller common.Address, opts *bi
 	return n.registry.SetSubdomainOwner(opts, n.Name, label, controller)
 }
 
+// CreateSubdomainRecord creates a subdomain on the name with the given
+// owner, resolver and TTL in a single transaction.  The sender must be the
+// owner of the name or an operator approved by the owner.
+func (n *Name) CreateSubdomainRecord(label string, owner common.Address, resolver common.Address, ttl time.Duration, opts *bind.TransactOpts) (*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+
+	// Confirm the subdomain does not already exist.
+	fqdn := fmt.Sprintf("%s.%s", label, n.Name)
+	subdomainController, err := n.registry.Owner(fqdn)
+	if err != nil {
+		return nil, err
+	}
+	if subdomainController != UnknownAddress {
+		return nil, errors.New("that subdomain already exists")
+	}
+
+	// Confirm the sender can create the subdomain.
+	controller, err := n.registry.Owner(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	if controller != opts.From {
+		approved, err := n.registry.IsApprovedForAll(controller, opts.From)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			return nil, fmt.Errorf("%s is not authorised to create subdomains of %s", opts.From.Hex(), n.Name)
+		}
+	}
+
+	return n.registry.SetSubdomainRecord(opts, n.Name, label, owner, resolver, ttl)
+}
+
+// Subdomains returns the direct subdomains of the name, as indexed by the
+// subgraph.
+func (n *Name) Subdomains(ctx context.Context, subgraph *SubgraphClient) ([]*SubgraphDomain, error) {
+	if subgraph == nil {
+		return nil, errors.New("no subgraph client supplied")
+	}
+	return subgraph.SubdomainsOf(ctx, n.Name)
+}
+
 // ResolverAddress fetches the address of the resolver contract for the name.
 func (n *Name) ResolverAddress() (common.Address, error) {
 	return n.registry.ResolverAddress(n.Name)
@@ -33,6 +33,8 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/baseregistrar"
+	"github.com/wealdtech/go-ens/v3/contracts/namewrapper"
 	string2eth "github.com/wealdtech/go-string2eth"
 )
 
@@ -594,3 +596,306 @@ func hasPrivateKey(address common.Address) bool {
 	_, err := crypto.HexToECDSA(os.Getenv(fmt.Sprintf("PRIVATE_KEY_%x", address)))
 	return err == nil
 }
+
+func TestNameCreateSubdomainRecord(t *testing.T) {
+	m := newMockENS()
+	opts := testTransactOpts(t)
+	operator := testTransactOpts(t)
+	m.register("parent.eth", opts.From, UnknownAddress)
+	m.register("taken.parent.eth", opts.From, UnknownAddress)
+	var created []interface{}
+	m.backend.contracts[m.registryAddr].
+		on("isApprovedForAll", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{args[1].(common.Address) == operator.From}, nil
+		}).
+		on("setSubnodeRecord", func(args []interface{}) ([]interface{}, error) {
+			created = args
+			return []interface{}{}, nil
+		})
+
+	registry, err := NewRegistry(m.backend, EthereumMainnet)
+	require.NoError(t, err)
+	name := &Name{backend: m.backend, Name: "parent.eth", registry: registry}
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+
+	_, err = name.CreateSubdomainRecord("taken", owner, m.resolverAddr, time.Hour, opts)
+	require.EqualError(t, err, "that subdomain already exists")
+
+	stranger := testTransactOpts(t)
+	_, err = name.CreateSubdomainRecord("sub", owner, m.resolverAddr, time.Hour, stranger)
+	require.EqualError(t, err, fmt.Sprintf("%s is not authorised to create subdomains of parent.eth", stranger.From.Hex()))
+
+	for _, sender := range []*bind.TransactOpts{opts, operator} {
+		_, err = name.CreateSubdomainRecord("sub", owner, m.resolverAddr, time.Hour, sender)
+		require.NoError(t, err)
+		require.Equal(t, mustNameHash("parent.eth"), created[0])
+		require.Equal(t, owner, created[2])
+		require.Equal(t, m.resolverAddr, created[3])
+		require.Equal(t, uint64(3600), created[4])
+	}
+}
+
+func TestNameSubdomains(t *testing.T) {
+	srv, variables := subgraphTestServer(t, `{"data":{"domains":[{"id":"0x01","name":"a.parent.eth","labelName":"a"}]}}`)
+
+	name := &Name{Name: "parent.eth"}
+	_, err := name.Subdomains(context.Background(), nil)
+	require.EqualError(t, err, "no subgraph client supplied")
+
+	domains, err := name.Subdomains(context.Background(), NewSubgraphClient(srv.URL, nil))
+	require.NoError(t, err)
+	require.Len(t, domains, 1)
+	require.Equal(t, "a.parent.eth", domains[0].Name)
+	require.Equal(t, fmt.Sprintf("%#x", mustNameHash("parent.eth")), (*variables)["parent"])
+}
+
+func TestNameTransferRegistration(t *testing.T) {
+	m := newMockENS()
+	opts := testTransactOpts(t)
+	newOwner := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	registrarAddr := common.HexToAddress("0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85")
+	wrapperAddr := ChainConfigFor(EthereumMainnet).NameWrapper
+	m.register("eth", registrarAddr, UnknownAddress)
+	m.register("plain.eth", opts.From, UnknownAddress)
+	m.register("wrapped.eth", wrapperAddr, UnknownAddress)
+	m.register("locked.eth", wrapperAddr, UnknownAddress)
+
+	registrants := map[string]common.Address{
+		"plain":   opts.From,
+		"wrapped": wrapperAddr,
+		"locked":  wrapperAddr,
+	}
+	calls := make(map[string][]interface{})
+	m.backend.deploy(registrarAddr, baseregistrar.ContractABI).
+		on("supportsInterface", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{true}, nil
+		}).
+		on("ownerOf", func(args []interface{}) ([]interface{}, error) {
+			for label, registrant := range registrants {
+				labelHash, _ := LabelHash(label)
+				if args[0].(*big.Int).Cmp(new(big.Int).SetBytes(labelHash[:])) == 0 {
+					return []interface{}{registrant}, nil
+				}
+			}
+			return nil, errors.New("execution reverted")
+		}).
+		on("reclaim", func(args []interface{}) ([]interface{}, error) {
+			calls["reclaim"] = args
+			return []interface{}{}, nil
+		}).
+		on("safeTransferFrom", func(args []interface{}) ([]interface{}, error) {
+			calls["registrarTransfer"] = args
+			return []interface{}{}, nil
+		})
+	m.backend.deploy(wrapperAddr, namewrapper.ContractABI).
+		on("getData", func(args []interface{}) ([]interface{}, error) {
+			fuses := FuseCannotUnwrap | FuseParentCannotControl | FuseIsDotEth
+			if common.BigToHash(args[0].(*big.Int)) == common.Hash(mustNameHash("locked.eth")) {
+				fuses |= FuseCannotTransfer
+			}
+			return []interface{}{opts.From, uint32(fuses), uint64(2000000000)}, nil
+		}).
+		on("safeTransferFrom", func(args []interface{}) ([]interface{}, error) {
+			calls["wrapperTransfer"] = args
+			return []interface{}{}, nil
+		})
+	m.backend.contracts[m.registryAddr].on("setOwner", func(args []interface{}) ([]interface{}, error) {
+		calls["setOwner"] = args
+		return []interface{}{}, nil
+	})
+
+	newTestName := func(name string) *Name {
+		registry, err := NewRegistry(m.backend, EthereumMainnet)
+		require.NoError(t, err)
+		registrar, err := NewBaseRegistrar(m.backend, "eth", EthereumMainnet)
+		require.NoError(t, err)
+		label, err := DomainPart(name, 1)
+		require.NoError(t, err)
+		return &Name{backend: m.backend, Name: name, Domain: "eth", Label: label, registry: registry, registrar: registrar}
+	}
+	plain := newTestName("plain.eth")
+	wrapped := newTestName("wrapped.eth")
+	locked := newTestName("locked.eth")
+	stranger := testTransactOpts(t)
+
+	_, err := plain.TransferRegistration(newOwner, nil)
+	require.EqualError(t, err, "transaction options required")
+	_, err = plain.TransferRegistration(UnknownAddress, opts)
+	require.EqualError(t, err, "cannot transfer to the zero address")
+	_, err = plain.TransferRegistration(newOwner, stranger)
+	require.EqualError(t, err, "not the current registrant")
+	txs, err := plain.TransferRegistration(newOwner, opts)
+	require.NoError(t, err)
+	require.Len(t, txs, 2)
+	tokenID, err := RegistrarTokenID("plain.eth")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{tokenID, newOwner}, calls["reclaim"])
+	require.Equal(t, []interface{}{opts.From, newOwner, tokenID}, calls["registrarTransfer"])
+
+	_, err = wrapped.TransferRegistration(newOwner, stranger)
+	require.EqualError(t, err, "not the owner of the wrapped name")
+	_, err = locked.TransferRegistration(newOwner, opts)
+	require.EqualError(t, err, "locked.eth has burned CANNOT_TRANSFER so cannot be transferred")
+	txs, err = wrapped.TransferRegistration(newOwner, opts)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	wrappedID, err := WrapperTokenID("wrapped.eth")
+	require.NoError(t, err)
+	require.Equal(t, opts.From, calls["wrapperTransfer"][0])
+	require.Equal(t, newOwner, calls["wrapperTransfer"][1])
+	require.Equal(t, wrappedID, calls["wrapperTransfer"][2])
+
+	_, err = plain.TransferControl(newOwner, nil)
+	require.EqualError(t, err, "transaction options required")
+	_, err = plain.TransferControl(newOwner, stranger)
+	require.EqualError(t, err, "not the current controller")
+	_, err = wrapped.TransferControl(newOwner, opts)
+	require.EqualError(t, err, "wrapped.eth is wrapped so is controlled by the name wrapper; use TransferRegistration to transfer it")
+	_, err = plain.TransferControl(newOwner, opts)
+	require.NoError(t, err)
+	require.Equal(t, mustNameHash("plain.eth"), calls["setOwner"][0])
+	require.Equal(t, newOwner, calls["setOwner"][1])
+}
+
+func TestNameOwnership(t *testing.T) {
+	m := newMockENS()
+	alice := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bob := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	registrarAddr := common.HexToAddress("0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85")
+	wrapperAddr := ChainConfigFor(EthereumMainnet).NameWrapper
+	now := time.Now()
+	m.register("eth", registrarAddr, UnknownAddress)
+	m.register("plain.eth", bob, UnknownAddress)
+	m.register("wrapped.eth", wrapperAddr, UnknownAddress)
+	m.register("held.eth", registrarAddr, UnknownAddress)
+	m.register("grace.eth", bob, UnknownAddress)
+	m.register("expired.eth", bob, UnknownAddress)
+
+	type registration struct {
+		registrant common.Address
+		expiry     time.Time
+	}
+	registrations := map[string]registration{
+		"plain":   {alice, now.Add(365 * 24 * time.Hour)},
+		"wrapped": {wrapperAddr, now.Add(365 * 24 * time.Hour)},
+		"held":    {alice, now.Add(365 * 24 * time.Hour)},
+		"grace":   {alice, now.Add(-24 * time.Hour)},
+		"expired": {alice, now.Add(-100 * 24 * time.Hour)},
+	}
+	registrationFor := func(id *big.Int) (registration, bool) {
+		for label, registration := range registrations {
+			labelHash, _ := LabelHash(label)
+			if id.Cmp(new(big.Int).SetBytes(labelHash[:])) == 0 {
+				return registration, true
+			}
+		}
+		return registration{}, false
+	}
+	m.backend.deploy(registrarAddr, baseregistrar.ContractABI).
+		on("supportsInterface", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{true}, nil
+		}).
+		on("ownerOf", func(args []interface{}) ([]interface{}, error) {
+			registration, exists := registrationFor(args[0].(*big.Int))
+			if !exists || !registration.expiry.After(time.Now()) {
+				return nil, errors.New("execution reverted")
+			}
+			return []interface{}{registration.registrant}, nil
+		}).
+		on("nameExpires", func(args []interface{}) ([]interface{}, error) {
+			registration, exists := registrationFor(args[0].(*big.Int))
+			if !exists {
+				return []interface{}{big.NewInt(0)}, nil
+			}
+			return []interface{}{big.NewInt(registration.expiry.Unix())}, nil
+		})
+	m.backend.deploy(wrapperAddr, namewrapper.ContractABI).
+		on("getData", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{alice, uint32(FuseCannotUnwrap | FuseParentCannotControl | FuseIsDotEth), uint64(now.Add(400 * 24 * time.Hour).Unix())}, nil
+		})
+
+	newTestName := func(name string) *Name {
+		registry, err := NewRegistry(m.backend, EthereumMainnet)
+		require.NoError(t, err)
+		registrar, err := NewBaseRegistrar(m.backend, "eth", EthereumMainnet)
+		require.NoError(t, err)
+		label, err := DomainPart(name, 1)
+		require.NoError(t, err)
+		return &Name{backend: m.backend, Name: name, Domain: "eth", Label: label, registry: registry, registrar: registrar}
+	}
+
+	tests := []struct {
+		name       string
+		controller common.Address
+		registrant common.Address
+		manager    common.Address
+		wrapped    bool
+		expired    bool
+		grace      bool
+		err        string
+	}{
+		{
+			name:       "plain.eth",
+			controller: bob,
+			registrant: alice,
+			manager:    bob,
+		},
+		{
+			name:       "wrapped.eth",
+			controller: wrapperAddr,
+			registrant: alice,
+			manager:    alice,
+			wrapped:    true,
+		},
+		{
+			name:       "held.eth",
+			controller: registrarAddr,
+			registrant: alice,
+			manager:    alice,
+		},
+		{
+			name:       "grace.eth",
+			controller: bob,
+			registrant: UnknownAddress,
+			manager:    bob,
+			expired:    true,
+			grace:      true,
+		},
+		{
+			name:       "expired.eth",
+			controller: bob,
+			registrant: UnknownAddress,
+			manager:    UnknownAddress,
+			expired:    true,
+		},
+		{
+			name: "unregistered.eth",
+			err:  "unregistered name",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			ownership, err := newTestName(test.name).Ownership()
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.name, ownership.Name)
+			require.Equal(t, test.controller, ownership.Controller)
+			require.Equal(t, test.registrant, ownership.Registrant)
+			require.Equal(t, test.manager, ownership.Manager)
+			require.Equal(t, test.wrapped, ownership.Wrapped)
+			require.Equal(t, test.expired, ownership.Expired)
+			require.Equal(t, test.grace, ownership.InGracePeriod)
+		})
+	}
+
+	registrant, err := newTestName("wrapped.eth").Registrant()
+	require.NoError(t, err)
+	require.Equal(t, alice, registrant)
+	controller, err := newTestName("wrapped.eth").Controller()
+	require.NoError(t, err)
+	require.Equal(t, wrapperAddr, controller)
+}
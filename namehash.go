@@ -16,7 +16,9 @@ package ens
 
 import (
 	"strings"
+	"sync"
 
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/pkg/errors"
 	"golang.org/x/net/idna"
 
@@ -42,8 +44,45 @@ func Normalize(input string) (string, error) {
 	return output, nil
 }
 
+// keccakHasher is a keccak state with buffers for hashing names.  Hashers
+// are pooled, as creating a keccak state and the buffers for each label is
+// otherwise the main cost of hashing a name.
+type keccakHasher struct {
+	state crypto.KeccakState
+	// node holds a node hash followed by a labelhash.
+	node [64]byte
+	buf  []byte
+}
+
+var keccakPool = sync.Pool{
+	New: func() interface{} {
+		return &keccakHasher{
+			state: sha3.NewLegacyKeccak256().(crypto.KeccakState),
+			buf:   make([]byte, 0, 64),
+		}
+	},
+}
+
+// hash hashes data in to out.
+func (h *keccakHasher) hash(out []byte, data []byte) {
+	h.state.Reset()
+	// Writes to a keccak state do not fail.
+	_, _ = h.state.Write(data)
+	_, _ = h.state.Read(out)
+}
+
+// hashString hashes a string in to out.
+func (h *keccakHasher) hashString(out []byte, data string) {
+	h.buf = append(h.buf[:0], data...)
+	h.hash(out, h.buf)
+}
+
 // LabelHash generates a simple hash for a piece of a name.
 func LabelHash(label string) ([32]byte, error) {
+	return cachedHash(cacheKindLabelHash, label, labelHash)
+}
+
+func labelHash(label string) ([32]byte, error) {
 	var hash [32]byte
 
 	normalizedLabel, err := Normalize(label)
@@ -51,11 +90,9 @@ func LabelHash(label string) ([32]byte, error) {
 		return [32]byte{}, err
 	}
 
-	sha := sha3.NewLegacyKeccak256()
-	if _, err = sha.Write([]byte(normalizedLabel)); err != nil {
-		return [32]byte{}, errors.Wrap(err, "failed to write hash")
-	}
-	sha.Sum(hash[:0])
+	h := keccakPool.Get().(*keccakHasher)
+	h.hashString(hash[:], normalizedLabel)
+	keccakPool.Put(h)
 
 	return hash, nil
 }
@@ -63,6 +100,10 @@ func LabelHash(label string) ([32]byte, error) {
 // NameHash generates a hash from a name that can be used to
 // look up the name in ENS.
 func NameHash(name string) ([32]byte, error) {
+	return cachedHash(cacheKindNameHash, name, nameHash)
+}
+
+func nameHash(name string) ([32]byte, error) {
 	var hash [32]byte
 
 	if name == "" {
@@ -73,32 +114,22 @@ func NameHash(name string) ([32]byte, error) {
 	if err != nil {
 		return [32]byte{}, err
 	}
-	parts := strings.Split(normalizedName, ".")
-	for i := len(parts) - 1; i >= 0; i-- {
-		if hash, err = nameHashPart(hash, parts[i]); err != nil {
-			return [32]byte{}, err
-		}
-	}
-
-	return hash, nil
-}
-
-func nameHashPart(currentHash [32]byte, name string) ([32]byte, error) {
-	var hash [32]byte
 
-	sha := sha3.NewLegacyKeccak256()
-	if _, err := sha.Write(currentHash[:]); err != nil {
-		return [32]byte{}, errors.Wrap(err, "failed to write hash")
-	}
-	nameSha := sha3.NewLegacyKeccak256()
-	if _, err := nameSha.Write([]byte(name)); err != nil {
-		return [32]byte{}, errors.Wrap(err, "failed to write hash")
-	}
-	nameHash := nameSha.Sum(nil)
-	if _, err := sha.Write(nameHash); err != nil {
-		return [32]byte{}, errors.Wrap(err, "failed to write hash")
+	h := keccakPool.Get().(*keccakHasher)
+	// Hash the labels from the last to the first, without splitting the name.
+	h.node = [64]byte{}
+	end := len(normalizedName)
+	for {
+		start := strings.LastIndexByte(normalizedName[:end], '.') + 1
+		h.hashString(h.node[32:], normalizedName[start:end])
+		h.hash(h.node[:32], h.node[:])
+		if start == 0 {
+			break
+		}
+		end = start - 1
 	}
-	sha.Sum(hash[:0])
+	copy(hash[:], h.node[:32])
+	keccakPool.Put(h)
 
 	return hash, nil
 }
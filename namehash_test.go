@@ -16,7 +16,11 @@ package ens
 
 import (
 	"encoding/hex"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/sha3"
 )
 
 func TestNameHash(t *testing.T) {
@@ -116,3 +120,73 @@ func TestLabelHash(t *testing.T) {
 		}
 	}
 }
+
+// unpooledNameHash is the straightforward namehash algorithm, creating hashers
+// for each label, used as a reference for NameHash.
+func unpooledNameHash(name string) ([32]byte, error) {
+	var hash [32]byte
+	if name == "" {
+		return hash, nil
+	}
+	normalizedName, err := Normalize(name)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	parts := strings.Split(normalizedName, ".")
+	for i := len(parts) - 1; i >= 0; i-- {
+		labelSha := sha3.NewLegacyKeccak256()
+		labelSha.Write([]byte(parts[i]))
+		sha := sha3.NewLegacyKeccak256()
+		sha.Write(hash[:])
+		sha.Write(labelSha.Sum(nil))
+		copy(hash[:], sha.Sum(nil))
+	}
+
+	return hash, nil
+}
+
+// deepName is a name with many labels.
+var deepName = strings.Repeat("sub.", 30) + "example.eth"
+
+func TestNameHashMatchesReference(t *testing.T) {
+	names := []string{"eth", "foo.eth", ".eth", "foo..eth", "a.b.c.d.e.f.g", "ß.eth", deepName}
+	for _, name := range names {
+		expected, err := unpooledNameHash(name)
+		require.NoError(t, err)
+		hash, err := NameHash(name)
+		require.NoError(t, err)
+		require.Equal(t, expected, hash, name)
+	}
+}
+
+func BenchmarkNameHash(b *testing.B) {
+	benchmarks := []struct {
+		name  string
+		input string
+	}{
+		{name: "Short", input: "foo.eth"},
+		{name: "Deep", input: deepName},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = NameHash(bm.input)
+			}
+		})
+		b.Run(bm.name+"Unpooled", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				_, _ = unpooledNameHash(bm.input)
+			}
+		})
+	}
+}
+
+func BenchmarkLabelHash(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = LabelHash("example")
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wealdtech/go-ens/v3/contracts/namewrapper"
+)
+
+// NameWrapper is the structure for the name wrapper contract.
+type NameWrapper struct {
+	backend      bind.ContractBackend
+	Contract     *namewrapper.Contract
+	ContractAddr common.Address
+}
+
+// NewNameWrapper creates a name wrapper for a given chain.
+func NewNameWrapper(backend bind.ContractBackend, chainId ChainId) (*NameWrapper, error) {
+	config := ChainConfigFor(chainId)
+	if config.ChainId != chainId || config.NameWrapper == UnknownAddress {
+		return nil, fmt.Errorf("no name wrapper for chain %d", chainId)
+	}
+
+	return NewNameWrapperAt(backend, config.NameWrapper)
+}
+
+// NewNameWrapperAt creates a name wrapper at a given address.
+func NewNameWrapperAt(backend bind.ContractBackend, address common.Address) (*NameWrapper, error) {
+	contract, err := namewrapper.NewContract(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &NameWrapper{
+		backend:      backend,
+		Contract:     contract,
+		ContractAddr: address,
+	}, nil
+}
+
+// IsWrapped returns true if the name is wrapped.
+func (w *NameWrapper) IsWrapped(name string) (bool, error) {
+	nameHash, err := NameHash(name)
+	if err != nil {
+		return false, err
+	}
+	return w.Contract.IsWrapped(nil, nameHash)
+}
+
+// Data returns the owner, fuses and expiry of a wrapped name.  The owner is
+// UnknownAddress if the name is not wrapped.
+func (w *NameWrapper) Data(name string) (common.Address, Fuses, time.Time, error) {
+	nameHash, err := NameHash(name)
+	if err != nil {
+		return UnknownAddress, 0, time.Time{}, err
+	}
+	data, err := w.Contract.GetData(nil, new(big.Int).SetBytes(nameHash[:]))
+	if err != nil {
+		return UnknownAddress, 0, time.Time{}, err
+	}
+
+	return data.Owner, Fuses(data.Fuses), time.Unix(int64(data.Expiry), 0), nil
+}
+
+// CreateWrappedSubname creates a wrapped subname of a wrapped parent with the
+// given owner, resolver, fuses and expiry.  The fuses and expiry are checked
+// against the parent before the transaction is sent, as the name wrapper
+// would otherwise revert or, in the case of expiry, silently reduce it.
+func (w *NameWrapper) CreateWrappedSubname(opts *bind.TransactOpts,
+	parent string,
+	label string,
+	owner common.Address,
+	resolver common.Address,
+	fuses Fuses,
+	expiry time.Time,
+) (
+	*types.Transaction,
+	error,
+) {
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+	parentHash, err := NameHash(parent)
+	if err != nil {
+		return nil, err
+	}
+	fqdn := fmt.Sprintf("%s.%s", label, parent)
+	if _, err := NameHash(fqdn); err != nil {
+		return nil, err
+	}
+
+	parentOwner, parentFuses, parentExpiry, err := w.Data(parent)
+	if err != nil {
+		return nil, err
+	}
+	if parentOwner == UnknownAddress {
+		return nil, fmt.Errorf("%s is not wrapped", parent)
+	}
+	canModify, err := w.Contract.CanModifyName(nil, parentHash, opts.From)
+	if err != nil {
+		return nil, err
+	}
+	if !canModify {
+		return nil, fmt.Errorf("%s is not authorised to manage %s", opts.From.Hex(), parent)
+	}
+	childOwner, childFuses, _, err := w.Data(fqdn)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSubnameFuses(parent, parentFuses, parentExpiry, childOwner != UnknownAddress, childFuses, fuses, expiry); err != nil {
+		return nil, err
+	}
+
+	return w.Contract.SetSubnodeRecord(opts, parentHash, label, owner, resolver, 0, uint32(fuses), uint64(expiry.Unix()))
+}
+
+// checkSubnameFuses checks that a subname can be created or updated by its
+// parent with the given fuses and expiry, following the rules of the name
+// wrapper.
+func checkSubnameFuses(parent string,
+	parentFuses Fuses,
+	parentExpiry time.Time,
+	exists bool,
+	existingFuses Fuses,
+	fuses Fuses,
+	expiry time.Time,
+) error {
+	if fuses&FuseIsDotEth != 0 {
+		return errors.New("IS_DOT_ETH cannot be burned")
+	}
+	if exists && existingFuses&FuseParentCannotControl != 0 {
+		return errors.New("subname has PARENT_CANNOT_CONTROL burned so cannot be modified by its parent")
+	}
+	if !exists && parentFuses&FuseCannotCreateSubdomain != 0 {
+		return fmt.Errorf("%s has CANNOT_CREATE_SUBDOMAIN burned", parent)
+	}
+	if fuses&^FuseParentCannotControl != 0 && fuses&(FuseParentCannotControl|FuseCannotUnwrap) != FuseParentCannotControl|FuseCannotUnwrap {
+		return errors.New("fuses other than PARENT_CANNOT_CONTROL require PARENT_CANNOT_CONTROL and CANNOT_UNWRAP")
+	}
+	if fuses&parentControlledFuses != 0 && parentFuses&FuseCannotUnwrap == 0 {
+		return fmt.Errorf("parent-controlled fuses require %s to have CANNOT_UNWRAP burned", parent)
+	}
+	if expiry.After(parentExpiry) {
+		return fmt.Errorf("expiry after %s expiry of %s", parent, parentExpiry.UTC().Format(time.RFC3339))
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/namewrapper"
+)
+
+func TestCheckSubnameFuses(t *testing.T) {
+	parentExpiry := time.Unix(2000000000, 0)
+	expiry := time.Unix(1900000000, 0)
+	locked := FuseCannotUnwrap | FuseParentCannotControl | FuseIsDotEth
+
+	tests := []struct {
+		name          string
+		parentFuses   Fuses
+		exists        bool
+		existingFuses Fuses
+		fuses         Fuses
+		expiry        time.Time
+		err           string
+	}{
+		{
+			name:   "NoFuses",
+			expiry: expiry,
+		},
+		{
+			name:        "ParentCannotControl",
+			parentFuses: locked,
+			fuses:       FuseParentCannotControl,
+			expiry:      expiry,
+		},
+		{
+			name:        "Emancipated",
+			parentFuses: locked,
+			fuses:       FuseParentCannotControl | FuseCannotUnwrap | FuseCannotTransfer,
+			expiry:      expiry,
+		},
+		{
+			name:   "IsDotEth",
+			fuses:  FuseIsDotEth,
+			expiry: expiry,
+			err:    "IS_DOT_ETH cannot be burned",
+		},
+		{
+			name:          "ExistingEmancipated",
+			parentFuses:   locked,
+			exists:        true,
+			existingFuses: FuseParentCannotControl,
+			expiry:        expiry,
+			err:           "subname has PARENT_CANNOT_CONTROL burned so cannot be modified by its parent",
+		},
+		{
+			name:        "ParentCannotCreateSubdomain",
+			parentFuses: locked | FuseCannotCreateSubdomain,
+			expiry:      expiry,
+			err:         "parent.eth has CANNOT_CREATE_SUBDOMAIN burned",
+		},
+		{
+			name:        "OwnerFusesWithoutParentCannotControl",
+			parentFuses: locked,
+			fuses:       FuseCannotTransfer,
+			expiry:      expiry,
+			err:         "fuses other than PARENT_CANNOT_CONTROL require PARENT_CANNOT_CONTROL and CANNOT_UNWRAP",
+		},
+		{
+			name:        "OwnerFusesWithoutCannotUnwrap",
+			parentFuses: locked,
+			fuses:       FuseParentCannotControl | FuseCannotTransfer,
+			expiry:      expiry,
+			err:         "fuses other than PARENT_CANNOT_CONTROL require PARENT_CANNOT_CONTROL and CANNOT_UNWRAP",
+		},
+		{
+			name:   "ParentNotLocked",
+			fuses:  FuseParentCannotControl,
+			expiry: expiry,
+			err:    "parent-controlled fuses require parent.eth to have CANNOT_UNWRAP burned",
+		},
+		{
+			name:   "ExpiryAfterParent",
+			expiry: parentExpiry.Add(time.Second),
+			err:    "expiry after parent.eth expiry of 2033-05-18T03:33:20Z",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := checkSubnameFuses("parent.eth", test.parentFuses, parentExpiry, test.exists, test.existingFuses, test.fuses, test.expiry)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestCreateWrappedSubname(t *testing.T) {
+	backend := newMockBackend()
+	opts := testTransactOpts(t)
+	parentNode := mustNameHash("parent.eth")
+	var created []interface{}
+	backend.deploy(ChainConfigFor(EthereumMainnet).NameWrapper, namewrapper.ContractABI).
+		on("getData", func(args []interface{}) ([]interface{}, error) {
+			if common.BigToHash(args[0].(*big.Int)) == common.Hash(parentNode) {
+				return []interface{}{opts.From, uint32(FuseCannotUnwrap | FuseParentCannotControl | FuseIsDotEth), uint64(2000000000)}, nil
+			}
+			return []interface{}{UnknownAddress, uint32(0), uint64(0)}, nil
+		}).
+		on("canModifyName", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{args[1].(common.Address) == opts.From}, nil
+		}).
+		on("setSubnodeRecord", func(args []interface{}) ([]interface{}, error) {
+			created = args
+			return []interface{}{mustNameHash("sub.parent.eth")}, nil
+		})
+
+	wrapper, err := NewNameWrapper(backend, EthereumMainnet)
+	require.NoError(t, err)
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	expiry := time.Unix(1900000000, 0)
+
+	_, err = wrapper.CreateWrappedSubname(opts, "unwrapped.eth", "sub", owner, UnknownAddress, 0, expiry)
+	require.EqualError(t, err, "unwrapped.eth is not wrapped")
+
+	stranger := testTransactOpts(t)
+	_, err = wrapper.CreateWrappedSubname(stranger, "parent.eth", "sub", owner, UnknownAddress, 0, expiry)
+	require.EqualError(t, err, stranger.From.Hex()+" is not authorised to manage parent.eth")
+
+	_, err = wrapper.CreateWrappedSubname(opts, "parent.eth", "sub", owner, UnknownAddress, FuseParentCannotControl|FuseCannotUnwrap, expiry)
+	require.NoError(t, err)
+	require.Equal(t, parentNode, created[0])
+	require.Equal(t, "sub", created[1])
+	require.Equal(t, owner, created[2])
+	require.Equal(t, uint32(FuseParentCannotControl|FuseCannotUnwrap), created[5])
+	require.Equal(t, uint64(1900000000), created[6])
+
+	_, err = NewNameWrapper(backend, LineaMainnet)
+	require.EqualError(t, err, "no name wrapper for chain 59144")
+}
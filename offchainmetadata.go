@@ -0,0 +1,173 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// metadataSelector is the selector of the ENSIP-16 metadata function.
+var metadataSelector = crypto.Keccak256([]byte("metadata(bytes)"))[:4]
+
+// OffchainMetadata is the location of the metadata of a name held by an
+// offchain resolver, as defined by ENSIP-16.
+type OffchainMetadata struct {
+	// Name is the name.
+	Name string
+	// GraphQLURL is the URL of the GraphQL endpoint that holds the metadata
+	// of the name.
+	GraphQLURL string
+}
+
+// OffchainDomain is the metadata of a name as reported by an ENSIP-16
+// GraphQL endpoint.
+type OffchainDomain struct {
+	// Name is the name.
+	Name string
+	// SubdomainCount is the number of subdomains of the name.
+	SubdomainCount uint64
+	// Texts are the keys of the text records set for the name.
+	Texts []string
+	// CoinTypes are the coin types of the addresses set for the name.
+	CoinTypes []uint64
+}
+
+// OffchainMetadata returns the location of the metadata of the domain, for
+// resolvers that support ENSIP-16.  The call may be answered by a gateway if
+// the backend follows offchain lookups.  An error that wraps
+// ErrFormatUnsupported is returned if the resolver does not support ENSIP-16;
+// other failures, such as an unreachable backend, are returned as they are.
+func (r *Resolver) OffchainMetadata(opts ...CallOption) (*OffchainMetadata, error) {
+	encodedName, err := DNSEncodeName(r.domain)
+	if err != nil {
+		return nil, err
+	}
+	bytesType, err := abi.NewType("bytes", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	args, err := abi.Arguments{{Type: bytesType}}.Pack(encodedName)
+	if err != nil {
+		return nil, err
+	}
+
+	o, release := newCallOptions(opts)
+	defer release()
+	callOpts := o.callOpts()
+	msg := ethereum.CallMsg{
+		From: callOpts.From,
+		To:   &r.ContractAddr,
+		Data: append(append([]byte{}, metadataSelector...), args...),
+	}
+	var res []byte
+	if callOpts.Pending {
+		pendingBackend, isPendingBackend := r.backend.(bind.PendingContractCaller)
+		if !isPendingBackend {
+			return nil, bind.ErrNoPendingState
+		}
+		res, err = pendingBackend.PendingCallContract(callOpts.Context, msg)
+	} else {
+		res, err = r.backend.CallContract(callOpts.Context, msg, callOpts.BlockNumber)
+	}
+	if err != nil && !isRevert(err) {
+		return nil, err
+	}
+	if err != nil || len(res) == 0 {
+		return nil, wrapError(ErrFormatUnsupported, "resolver does not support offchain metadata")
+	}
+	values, err := abi.Arguments{{Type: stringType}}.Unpack(res)
+	if err != nil {
+		return nil, wrapError(ErrFormatUnsupported, "invalid offchain metadata")
+	}
+	url := values[0].(string)
+	if url == "" {
+		return nil, wrapError(ErrFormatUnsupported, "resolver does not supply offchain metadata for the name")
+	}
+
+	return &OffchainMetadata{
+		Name:       r.domain,
+		GraphQLURL: url,
+	}, nil
+}
+
+// Query runs a GraphQL query against the metadata endpoint, decoding the data
+// of the response in to res.  If client is nil then http.DefaultClient is used.
+func (m *OffchainMetadata) Query(ctx context.Context, client *http.Client, query string, variables map[string]interface{}, res interface{}) error {
+	return NewSubgraphClient(m.GraphQLURL, client).query(ctx, query, variables, res)
+}
+
+// Domain returns the metadata of the name from the metadata endpoint, using
+// the schema defined by ENSIP-16.  If client is nil then http.DefaultClient
+// is used.
+func (m *OffchainMetadata) Domain(ctx context.Context, client *http.Client) (*OffchainDomain, error) {
+	nameHash, err := NameHash(m.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `query($id: String!) {
+  domain(id: $id) {
+    name
+    subdomainCount
+    resolver { texts coinTypes }
+  }
+}`
+
+	var data struct {
+		Domain *struct {
+			Name           string `json:"name"`
+			SubdomainCount uint64 `json:"subdomainCount"`
+			Resolver       *struct {
+				Texts     []string         `json:"texts"`
+				CoinTypes []subgraphBigInt `json:"coinTypes"`
+			} `json:"resolver"`
+		} `json:"domain"`
+	}
+	if err := m.Query(ctx, client, query, map[string]interface{}{"id": fmt.Sprintf("%#x", nameHash)}, &data); err != nil {
+		return nil, err
+	}
+	if data.Domain == nil {
+		return nil, fmt.Errorf("%s: %w", m.Name, ErrUnregisteredName)
+	}
+
+	res := &OffchainDomain{
+		Name:           data.Domain.Name,
+		SubdomainCount: data.Domain.SubdomainCount,
+		Texts:          make([]string, 0),
+		CoinTypes:      make([]uint64, 0),
+	}
+	if res.Name == "" {
+		res.Name = m.Name
+	}
+	if data.Domain.Resolver != nil {
+		res.Texts = append(res.Texts, data.Domain.Resolver.Texts...)
+		for _, coinType := range data.Domain.Resolver.CoinTypes {
+			res.CoinTypes = append(res.CoinTypes, coinType.Uint64())
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const mockMetadataResolverABI = `[
+{"type":"function","name":"addr","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+{"type":"function","name":"metadata","stateMutability":"view","inputs":[{"name":"name","type":"bytes"}],"outputs":[{"name":"","type":"string"}]}
+]`
+
+func TestOffchainMetadata(t *testing.T) {
+	m := newMockENS()
+	metadataResolverAddr := common.HexToAddress("0x000000000000000000000000000000000000e016")
+	m.backend.deploy(metadataResolverAddr, mockMetadataResolverABI).
+		on("addr", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{UnknownAddress}, nil
+		}).
+		on("metadata", func(args []interface{}) ([]interface{}, error) {
+			name, err := DNSDecodeName(args[0].([]byte))
+			if err != nil {
+				return nil, err
+			}
+			if name == "unreachable.offchain.eth" {
+				return nil, errors.New("connection refused")
+			}
+			if name != "test.offchain.eth" {
+				return []interface{}{""}, nil
+			}
+			return []interface{}{"https://example.com/graphql"}, nil
+		})
+
+	tests := []struct {
+		name     string
+		domain   string
+		resolver common.Address
+		url      string
+		err      string
+		// unsupported is true if the error is ErrFormatUnsupported.
+		unsupported bool
+	}{
+		{
+			name:     "Supported",
+			domain:   "test.offchain.eth",
+			resolver: metadataResolverAddr,
+			url:      "https://example.com/graphql",
+		},
+		{
+			name:        "NoMetadata",
+			domain:      "other.offchain.eth",
+			resolver:    metadataResolverAddr,
+			err:         "resolver does not supply offchain metadata for the name",
+			unsupported: true,
+		},
+		{
+			name:        "Unsupported",
+			domain:      "test.offchain.eth",
+			resolver:    m.resolverAddr,
+			err:         "resolver does not support offchain metadata",
+			unsupported: true,
+		},
+		{
+			name:     "Unreachable",
+			domain:   "unreachable.offchain.eth",
+			resolver: metadataResolverAddr,
+			err:      "connection refused",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resolver, err := NewResolverAt(m.backend, test.domain, test.resolver)
+			require.NoError(t, err)
+			metadata, err := resolver.OffchainMetadata()
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				if test.unsupported {
+					require.ErrorIs(t, err, ErrFormatUnsupported)
+				} else {
+					require.NotErrorIs(t, err, ErrFormatUnsupported)
+				}
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.domain, metadata.Name)
+			require.Equal(t, test.url, metadata.GraphQLURL)
+		})
+	}
+}
+
+func TestOffchainMetadataDomain(t *testing.T) {
+	node := mustNameHash("test.offchain.eth")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]string `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Variables["id"] != fmt.Sprintf("%#x", node) {
+			_, _ = w.Write([]byte(`{"data":{"domain":null}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"domain":{"name":"test.offchain.eth","subdomainCount":2,"resolver":{"texts":["avatar","url"],"coinTypes":["60","0"]}}}}`))
+	}))
+	defer server.Close()
+
+	metadata := &OffchainMetadata{Name: "test.offchain.eth", GraphQLURL: server.URL}
+	domain, err := metadata.Domain(context.Background(), nil)
+	require.NoError(t, err)
+	require.Equal(t, &OffchainDomain{
+		Name:           "test.offchain.eth",
+		SubdomainCount: 2,
+		Texts:          []string{"avatar", "url"},
+		CoinTypes:      []uint64{60, 0},
+	}, domain)
+
+	metadata = &OffchainMetadata{Name: "unknown.offchain.eth", GraphQLURL: server.URL}
+	_, err = metadata.Domain(context.Background(), nil)
+	require.ErrorIs(t, err, ErrUnregisteredName)
+}
@@ -0,0 +1,205 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// nameRegisteredTopics are the topics of the NameRegistered events emitted by
+// current and legacy .eth registrar controllers, which are the only on-chain
+// source of the label for a registrar token.
+var nameRegisteredTopics = []common.Hash{
+	crypto.Keccak256Hash([]byte("NameRegistered(string,bytes32,address,uint256,uint256,uint256)")),
+	crypto.Keccak256Hash([]byte("NameRegistered(string,bytes32,address,uint256,uint256)")),
+}
+
+// OwnedName is a name held by an address.
+type OwnedName struct {
+	// Name is the name, or "" if it could not be found.
+	Name string
+	// TokenID is the ID of the token for the name.
+	TokenID *big.Int
+	// Wrapped is true if the token is held in the name wrapper, and false if
+	// it is held in the base registrar.
+	Wrapped bool
+}
+
+// OwnedNames returns the names held by an address in the base registrar and
+// the name wrapper.  Neither contract supports enumeration, so tokens are
+// found from their transfer logs and checked against their current owner.
+// Labels of registrar tokens are obtained from registration events; names
+// registered by other means are returned with an empty name.
+//
+// This requires only an RPC connection, but the logs are fetched from the
+// genesis block so the backend must support large log queries.  The subgraph
+// is more efficient where it is available.
+func OwnedNames(backend bind.ContractBackend, owner common.Address, chainId ChainId) ([]*OwnedName, error) {
+	config := ChainConfigFor(chainId)
+	opts := &bind.FilterOpts{Context: context.Background()}
+	res := make([]*OwnedName, 0)
+
+	registrar, err := NewBaseRegistrar(backend, config.Root, chainId)
+	if err != nil {
+		return nil, err
+	}
+	transfers, err := registrar.Contract.FilterTransfer(opts, nil, []common.Address{owner}, nil)
+	if err != nil {
+		return nil, err
+	}
+	tokenIDs := make([]*big.Int, 0)
+	for transfers.Next() {
+		tokenIDs = append(tokenIDs, transfers.Event.TokenId)
+	}
+	if err := transfers.Error(); err != nil {
+		return nil, err
+	}
+	registrarNames := make([]*OwnedName, 0)
+	for _, tokenID := range uniqueTokenIDs(tokenIDs) {
+		// Tokens of expired names cannot be queried, and so are skipped.
+		tokenOwner, err := registrar.Contract.OwnerOf(nil, tokenID)
+		if err != nil || tokenOwner != owner {
+			continue
+		}
+		registrarNames = append(registrarNames, &OwnedName{TokenID: tokenID})
+	}
+	if err := nameRegistrarTokens(backend, config.Root, registrarNames); err != nil {
+		return nil, err
+	}
+	res = append(res, registrarNames...)
+
+	if config.NameWrapper == UnknownAddress {
+		return res, nil
+	}
+	wrapper, err := NewNameWrapperAt(backend, config.NameWrapper)
+	if err != nil {
+		return nil, err
+	}
+	tokenIDs = make([]*big.Int, 0)
+	singles, err := wrapper.Contract.FilterTransferSingle(opts, nil, nil, []common.Address{owner})
+	if err != nil {
+		return nil, err
+	}
+	for singles.Next() {
+		tokenIDs = append(tokenIDs, singles.Event.Id)
+	}
+	if err := singles.Error(); err != nil {
+		return nil, err
+	}
+	batches, err := wrapper.Contract.FilterTransferBatch(opts, nil, nil, []common.Address{owner})
+	if err != nil {
+		return nil, err
+	}
+	for batches.Next() {
+		tokenIDs = append(tokenIDs, batches.Event.Ids...)
+	}
+	if err := batches.Error(); err != nil {
+		return nil, err
+	}
+	for _, tokenID := range uniqueTokenIDs(tokenIDs) {
+		tokenOwner, err := wrapper.Contract.OwnerOf(nil, tokenID)
+		if err != nil {
+			return nil, err
+		}
+		if tokenOwner != owner {
+			continue
+		}
+		ownedName := &OwnedName{
+			TokenID: tokenID,
+			Wrapped: true,
+		}
+		wireName, err := wrapper.Contract.Names(nil, common.BigToHash(tokenID))
+		if err == nil {
+			ownedName.Name, _ = DNSDecodeName(wireName)
+		}
+		res = append(res, ownedName)
+	}
+
+	return res, nil
+}
+
+// nameRegistrarTokens sets the names of registrar tokens from the
+// registration events for their labels.
+func nameRegistrarTokens(backend bind.ContractBackend, root string, names []*OwnedName) error {
+	if len(names) == 0 {
+		return nil
+	}
+	labelHashes := make([]common.Hash, len(names))
+	for i := range names {
+		labelHashes[i] = common.BigToHash(names[i].TokenID)
+	}
+	logs, err := backend.FilterLogs(context.Background(), ethereum.FilterQuery{
+		Topics: [][]common.Hash{nameRegisteredTopics, labelHashes},
+	})
+	if err != nil {
+		return err
+	}
+
+	stringType, err := abi.NewType("string", "", nil)
+	if err != nil {
+		return err
+	}
+	args := abi.Arguments{{Type: stringType}}
+	labels := make(map[common.Hash]string)
+	for _, log := range logs {
+		if len(log.Topics) < 2 {
+			continue
+		}
+		values, err := args.Unpack(log.Data)
+		if err != nil || len(values) != 1 {
+			continue
+		}
+		label, isString := values[0].(string)
+		if !isString {
+			continue
+		}
+		// The events are not filtered by address, so confirm that the label
+		// matches the labelhash.
+		if crypto.Keccak256Hash([]byte(label)) != log.Topics[1] {
+			continue
+		}
+		labels[log.Topics[1]] = label
+	}
+	for _, name := range names {
+		if label, exists := labels[common.BigToHash(name.TokenID)]; exists {
+			name.Name = label + "." + root
+		}
+	}
+
+	return nil
+}
+
+// uniqueTokenIDs returns token IDs with duplicates removed, preserving order.
+func uniqueTokenIDs(tokenIDs []*big.Int) []*big.Int {
+	seen := make(map[string]bool)
+	res := make([]*big.Int, 0, len(tokenIDs))
+	for _, tokenID := range tokenIDs {
+		key := tokenID.String()
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		res = append(res, tokenID)
+	}
+
+	return res
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/baseregistrar"
+	"github.com/wealdtech/go-ens/v3/contracts/ethregistrarcontroller"
+	"github.com/wealdtech/go-ens/v3/contracts/namewrapper"
+)
+
+func TestOwnedNames(t *testing.T) {
+	m := newMockENS()
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000a11")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	registrarAddr := common.HexToAddress("0x57f1887a8BF19b14fC0dF6Fd9B2acc9Af147eA85")
+	controllerAddr := ChainConfigFor(EthereumMainnet).RegistrarController
+	wrapperAddr := ChainConfigFor(EthereumMainnet).NameWrapper
+	m.register("eth", registrarAddr, UnknownAddress)
+
+	tokenID := func(label string) *big.Int {
+		labelHash, err := LabelHash(label)
+		require.NoError(t, err)
+		return new(big.Int).SetBytes(labelHash[:])
+	}
+	registrarOwners := map[string]common.Address{
+		tokenID("alpha").String(): owner,
+		tokenID("beta").String():  other,
+		tokenID("gamma").String(): owner,
+	}
+	m.backend.deploy(registrarAddr, baseregistrar.ContractABI).
+		on("supportsInterface", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{true}, nil
+		}).
+		on("ownerOf", func(args []interface{}) ([]interface{}, error) {
+			tokenOwner, exists := registrarOwners[args[0].(*big.Int).String()]
+			if !exists {
+				return nil, errors.New("execution reverted")
+			}
+			return []interface{}{tokenOwner}, nil
+		})
+	wrappedID, err := WrapperTokenID("wrapped.eth")
+	require.NoError(t, err)
+	m.backend.deploy(wrapperAddr, namewrapper.ContractABI).
+		on("ownerOf", func(args []interface{}) ([]interface{}, error) {
+			if args[0].(*big.Int).Cmp(wrappedID) == 0 {
+				return []interface{}{owner}, nil
+			}
+			return []interface{}{UnknownAddress}, nil
+		}).
+		on("names", func(args []interface{}) ([]interface{}, error) {
+			if args[0].([32]byte) == mustNameHash("wrapped.eth") {
+				return []interface{}{DNSWireFormat("wrapped.eth")}, nil
+			}
+			return []interface{}{[]byte{}}, nil
+		})
+
+	transfer := func(label string, to common.Address) {
+		m.backend.addEvent(registrarAddr, baseregistrar.ContractABI, "Transfer",
+			[]common.Hash{{}, common.BytesToHash(to.Bytes()), common.BigToHash(tokenID(label))})
+	}
+	transfer("alpha", owner)
+	transfer("beta", owner)
+	transfer("beta", other)
+	transfer("gamma", owner)
+	transfer("expired", owner)
+	transfer("delta", other)
+
+	registered := func(label string, labelHash common.Hash) {
+		m.backend.addEvent(controllerAddr, ethregistrarcontroller.ContractABI, "NameRegistered",
+			[]common.Hash{labelHash, common.BytesToHash(owner.Bytes())},
+			label, big.NewInt(1), big.NewInt(0), big.NewInt(2000000000))
+	}
+	registered("alpha", common.BigToHash(tokenID("alpha")))
+	// An event with a label that does not match its labelhash is ignored.
+	registered("notgamma", common.BigToHash(tokenID("gamma")))
+
+	m.backend.addEvent(wrapperAddr, namewrapper.ContractABI, "TransferSingle",
+		[]common.Hash{common.BytesToHash(owner.Bytes()), {}, common.BytesToHash(owner.Bytes())},
+		wrappedID, big.NewInt(1))
+	m.backend.addEvent(wrapperAddr, namewrapper.ContractABI, "TransferBatch",
+		[]common.Hash{common.BytesToHash(owner.Bytes()), {}, common.BytesToHash(owner.Bytes())},
+		[]*big.Int{wrappedID, tokenID("gone")}, []*big.Int{big.NewInt(1), big.NewInt(1)})
+
+	names, err := OwnedNames(m.backend, owner, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, []*OwnedName{
+		{Name: "alpha.eth", TokenID: tokenID("alpha")},
+		{TokenID: tokenID("gamma")},
+		{Name: "wrapped.eth", TokenID: wrappedID, Wrapped: true},
+	}, names)
+
+	names, err = OwnedNames(m.backend, common.HexToAddress("0x0000000000000000000000000000000000000c0c"), EthereumMainnet)
+	require.NoError(t, err)
+	require.Empty(t, names)
+}
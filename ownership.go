@@ -0,0 +1,113 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// registrarGracePeriod is the period after expiry during which a name can be
+// renewed, but not registered, as fixed by the base registrar.
+const registrarGracePeriod = 90 * 24 * time.Hour
+
+// Ownership summarises the parties that hold and manage a name.
+type Ownership struct {
+	// Name is the name.
+	Name string
+	// Controller is the owner of the name in the registry.  This is the name
+	// wrapper if the name is wrapped.
+	Controller common.Address
+	// Registrant is the owner of the token for the name: the wrapped token if
+	// the name is wrapped, otherwise the registrar token.
+	Registrant common.Address
+	// Manager is the address that can set records for the name: the
+	// registrant if the name is wrapped, otherwise the controller.
+	Manager common.Address
+	// Wrapped is true if the name is wrapped.
+	Wrapped bool
+	// Expiry is the time at which the registration expires.
+	Expiry time.Time
+	// Expired is true if the registration has expired.
+	Expired bool
+	// InGracePeriod is true if the registration has expired but can still be
+	// renewed.
+	InGracePeriod bool
+}
+
+// Ownership obtains a summary of the ownership of the name.
+//
+// The registry is not updated when a name expires, so the controller of an
+// expired name is the last controller set.  The registrant and manager are
+// UnknownAddress once a name has expired and left its grace period; the
+// registrant of an unwrapped name is also UnknownAddress during the grace
+// period, as the registrar does not report owners of expired tokens.  If the
+// registry shows the registrar itself as the controller then the registrant
+// is reported as the manager, as only they can reclaim control.
+func (n *Name) Ownership() (*Ownership, error) {
+	expiryTS, err := n.registrar.Expiry(n.Label)
+	if err != nil {
+		return nil, err
+	}
+	if expiryTS.Sign() == 0 {
+		return nil, ErrUnregisteredName
+	}
+	controller, err := n.Controller()
+	if err != nil {
+		return nil, err
+	}
+	registrant, err := n.registrarOwner()
+	if err != nil {
+		return nil, err
+	}
+	wrapper, err := n.wrapper()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	ownership := &Ownership{
+		Name:       n.Name,
+		Controller: controller,
+		Registrant: registrant,
+		Manager:    controller,
+		Expiry:     time.Unix(expiryTS.Int64(), 0),
+	}
+	ownership.Expired = !ownership.Expiry.After(now)
+	ownership.InGracePeriod = ownership.Expired && now.Before(ownership.Expiry.Add(registrarGracePeriod))
+
+	switch {
+	case wrapper != nil && (controller == wrapper.ContractAddr || registrant == wrapper.ContractAddr):
+		// The name wrapper holds both the registrar token and control, so
+		// the wrapped token determines the owner.
+		owner, _, _, err := wrapper.Data(n.Name)
+		if err != nil {
+			return nil, err
+		}
+		ownership.Wrapped = true
+		ownership.Registrant = owner
+		ownership.Manager = owner
+	case controller == n.registrar.ContractAddr:
+		ownership.Manager = registrant
+	}
+
+	if ownership.Expired && !ownership.InGracePeriod {
+		ownership.Registrant = UnknownAddress
+		ownership.Manager = UnknownAddress
+	}
+
+	return ownership, nil
+}
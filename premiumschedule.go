@@ -0,0 +1,337 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wealdtech/go-ens/v3/contracts/priceoracle"
+)
+
+// Limits on the search for the time at which a premium falls to a target.
+const (
+	premiumSearchStep      = time.Hour
+	premiumSearchHorizon   = 365 * 24 * time.Hour
+	premiumSearchPrecision = time.Second
+)
+
+// defaultPremiumPollInterval is the interval at which quotes are obtained
+// whilst waiting to register, if no interval is supplied.
+const defaultPremiumPollInterval = time.Minute
+
+// PremiumQuote is the price of registering a name at a given time.
+type PremiumQuote struct {
+	// Time is the time of the quote.
+	Time time.Time
+	// Base is the base registration price, in wei.
+	Base *big.Int
+	// Premium is the premium for recently-expired names, in wei.
+	Premium *big.Int
+	// Price is the total registration price, in wei.
+	Price *big.Int
+}
+
+// PremiumRegistration is a registration of a recently-expired name, to be made
+// once its premium has decayed enough for the price to fall to a maximum.
+type PremiumRegistration struct {
+	// Domain is the domain to register.
+	Domain string
+	// Expiry is the time at which the previous registration expired, as
+	// returned by Name.Expires.
+	Expiry time.Time
+	// Owner is the owner of the new registration.
+	Owner common.Address
+	// Duration is the duration of the new registration.
+	Duration time.Duration
+	// Resolver is the resolver for the name, required if Records or
+	// ReverseRecord are set.
+	Resolver common.Address
+	// Records are the calls to make to the resolver on registration.
+	Records [][]byte
+	// ReverseRecord is true if the reverse record for the owner should be set.
+	ReverseRecord bool
+	// MaxPrice is the maximum price to pay for the registration, in wei.
+	MaxPrice *big.Int
+	// PollInterval is the interval at which quotes are obtained whilst
+	// waiting.  Defaults to one minute.
+	PollInterval time.Duration
+	// PriceBufferPercent is the percentage added to the price for the value
+	// of the register transaction, so that it succeeds if the price rises
+	// before it is mined.  The value can exceed MaxPrice, but the controller
+	// only takes the price and refunds the excess.  Defaults to
+	// DefaultPriceBufferPercent.
+	PriceBufferPercent uint64
+}
+
+// QuoteAt quotes the price of registering a recently-expired domain for the
+// given duration at a given time.  expiry is the time at which the previous
+// registration expired.
+//
+// The controller only quotes current prices, so the controller's price oracle
+// is asked for the price of a name that expired earlier by the time until the
+// quote; this relies on the premium depending only on the time since expiry.
+func (c *RegistrarController) QuoteAt(domain string, expiry time.Time, duration time.Duration, at time.Time) (*PremiumQuote, error) {
+	return c.quoteAt(context.Background(), domain, expiry, duration, at)
+}
+
+// quoteAt quotes the price of registering a recently-expired domain at a
+// given time, using the given context for calls.
+func (c *RegistrarController) quoteAt(ctx context.Context, domain string, expiry time.Time, duration time.Duration, at time.Time) (*PremiumQuote, error) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s", domain)
+	}
+	callOpts := &bind.CallOpts{Context: ctx}
+	oracleAddr, err := c.Contract.Prices(callOpts)
+	if err != nil {
+		return nil, err
+	}
+	oracle, err := priceoracle.NewContract(oracleAddr, c.backend)
+	if err != nil {
+		return nil, err
+	}
+
+	shiftedExpiry := expiry
+	if offset := time.Until(at); offset > 0 {
+		shiftedExpiry = expiry.Add(-offset)
+	}
+	price, err := oracle.Price(callOpts, name, big.NewInt(shiftedExpiry.Unix()), big.NewInt(int64(duration.Seconds())))
+	if err != nil {
+		return nil, err
+	}
+
+	return &PremiumQuote{
+		Time:    at,
+		Base:    price.Base,
+		Premium: price.Premium,
+		Price:   new(big.Int).Add(price.Base, price.Premium),
+	}, nil
+}
+
+// PremiumCrossing calculates the earliest time at which the price of
+// registering a recently-expired domain for the given duration falls to at
+// most maxPrice, returning the quote at that time.  If the price is already at
+// or below maxPrice the current quote is returned.
+func (c *RegistrarController) PremiumCrossing(domain string, expiry time.Time, duration time.Duration, maxPrice *big.Int) (*PremiumQuote, error) {
+	return c.premiumCrossing(context.Background(), domain, expiry, duration, maxPrice)
+}
+
+// premiumCrossing calculates the earliest time at which the price of
+// registering a recently-expired domain falls to at most maxPrice, using the
+// given context for calls.
+func (c *RegistrarController) premiumCrossing(ctx context.Context, domain string, expiry time.Time, duration time.Duration, maxPrice *big.Int) (*PremiumQuote, error) {
+	if maxPrice == nil {
+		return nil, errors.New("no maximum price supplied")
+	}
+	now := time.Now()
+	quote, err := c.quoteAt(ctx, domain, expiry, duration, now)
+	if err != nil {
+		return nil, err
+	}
+	if quote.Price.Cmp(maxPrice) <= 0 {
+		return quote, nil
+	}
+	if quote.Base.Cmp(maxPrice) > 0 {
+		return nil, fmt.Errorf("price will not fall to %s wei; base price is %s wei", maxPrice, quote.Base)
+	}
+
+	// Find a time at which the price is acceptable...
+	low := now
+	var high *PremiumQuote
+	for step := premiumSearchStep; high == nil; step *= 2 {
+		if step > premiumSearchHorizon {
+			return nil, fmt.Errorf("price will not fall to %s wei within %v", maxPrice, premiumSearchHorizon)
+		}
+		quote, err := c.quoteAt(ctx, domain, expiry, duration, now.Add(step))
+		if err != nil {
+			return nil, err
+		}
+		if quote.Price.Cmp(maxPrice) <= 0 {
+			high = quote
+		} else {
+			low = quote.Time
+		}
+	}
+
+	// ...then narrow down on the crossing.
+	for high.Time.Sub(low) > premiumSearchPrecision {
+		quote, err := c.quoteAt(ctx, domain, expiry, duration, low.Add(high.Time.Sub(low)/2))
+		if err != nil {
+			return nil, err
+		}
+		if quote.Price.Cmp(maxPrice) <= 0 {
+			high = quote
+		} else {
+			low = quote.Time
+		}
+	}
+
+	return high, nil
+}
+
+// RegisterAtPrice registers a recently-expired name once its price has fallen
+// to the registration's maximum price.  The commitment is sent ahead of the
+// crossing by the controller's minimum commitment age, so that the name can be
+// registered as soon as the price is acceptable.  Whilst waiting, quotes of
+// the current price are sent to quotes if it is not nil.
+//
+// The value of the register transaction is set to the current price plus the
+// registration's price buffer, and the controller refunds any excess.  The
+// commit and register transactions are returned.
+func (c *RegistrarController) RegisterAtPrice(ctx context.Context,
+	opts *bind.TransactOpts,
+	registration *PremiumRegistration,
+	quotes chan<- *PremiumQuote,
+) (
+	[]*types.Transaction,
+	error,
+) {
+	if registration == nil {
+		return nil, errors.New("no registration supplied")
+	}
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+	name, err := UnqualifiedName(registration.Domain, c.domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s", registration.Domain)
+	}
+	if (len(registration.Records) > 0 || registration.ReverseRecord) && registration.Resolver == UnknownAddress {
+		return nil, errors.New("resolver required to set records")
+	}
+	pollInterval := registration.PollInterval
+	if pollInterval <= 0 {
+		pollInterval = defaultPremiumPollInterval
+	}
+	priceBufferPercent := registration.PriceBufferPercent
+	if priceBufferPercent == 0 {
+		priceBufferPercent = DefaultPriceBufferPercent
+	}
+
+	crossing, err := c.premiumCrossing(ctx, registration.Domain, registration.Expiry, registration.Duration, registration.MaxPrice)
+	if err != nil {
+		return nil, err
+	}
+	callOpts := &bind.CallOpts{Context: ctx}
+	minAgeSecs, err := c.Contract.MinCommitmentAge(callOpts)
+	if err != nil {
+		return nil, err
+	}
+	minAge := time.Duration(minAgeSecs.Int64()) * time.Second
+	maxAgeSecs, err := c.Contract.MaxCommitmentAge(callOpts)
+	if err != nil {
+		return nil, err
+	}
+	maxAge := time.Duration(maxAgeSecs.Int64()) * time.Second
+
+	// Wait until it is time to commit.
+	commitAt := crossing.Time.Add(-minAge)
+	for wait := time.Until(commitAt); wait > 0; wait = time.Until(commitAt) {
+		if _, err := c.sendQuote(ctx, registration, quotes); err != nil {
+			return nil, err
+		}
+		if err := sleepContext(ctx, min(wait, pollInterval)); err != nil {
+			return nil, err
+		}
+	}
+
+	var secret [32]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return nil, err
+	}
+	duration := big.NewInt(int64(registration.Duration.Seconds()))
+	commitment, err := c.Contract.MakeCommitment(callOpts, name, registration.Owner, duration, secret, registration.Resolver, registration.Records, registration.ReverseRecord, 0)
+	if err != nil {
+		return nil, err
+	}
+	nextOpts := sequentialOpts(opts)
+	commitTx, err := c.Contract.Commit(nextOpts(), commitment)
+	if err != nil {
+		return nil, revertError(err)
+	}
+	txs := []*types.Transaction{commitTx}
+
+	// Wait until the commitment has matured and the price is acceptable.
+	var price *big.Int
+	for {
+		quote, err := c.sendQuote(ctx, registration, quotes)
+		if err != nil {
+			return txs, err
+		}
+		committed, err := c.Contract.Commitments(callOpts, commitment)
+		if err != nil {
+			return txs, err
+		}
+		if committed.Sign() > 0 {
+			committedAt := time.Unix(committed.Int64(), 0)
+			if time.Now().After(committedAt.Add(maxAge)) {
+				return txs, errors.New("commitment expired before the price fell")
+			}
+			if time.Now().After(committedAt.Add(minAge)) && quote.Price.Cmp(registration.MaxPrice) <= 0 {
+				price = quote.Price
+				break
+			}
+		}
+		if err := sleepContext(ctx, pollInterval); err != nil {
+			return txs, err
+		}
+	}
+
+	registerOpts := nextOpts()
+	registerOpts.Value = bufferedPrice(price, priceBufferPercent)
+	registerTx, err := c.Contract.Register(registerOpts, name, registration.Owner, duration, secret, registration.Resolver, registration.Records, registration.ReverseRecord, 0)
+	if err != nil {
+		return txs, revertError(err)
+	}
+
+	return append(txs, registerTx), nil
+}
+
+// sendQuote obtains a quote of the current price of a registration, sending
+// it to quotes if it is not nil.
+func (c *RegistrarController) sendQuote(ctx context.Context, registration *PremiumRegistration, quotes chan<- *PremiumQuote) (*PremiumQuote, error) {
+	quote, err := c.quoteAt(ctx, registration.Domain, registration.Expiry, registration.Duration, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if quotes != nil {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case quotes <- quote:
+		}
+	}
+
+	return quote, nil
+}
+
+// sleepContext sleeps for the given duration, returning early with an error
+// if the context is cancelled.
+func sleepContext(ctx context.Context, duration time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(duration):
+		return nil
+	}
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPremiumCrossing(t *testing.T) {
+	controller := newMockRegistrarController(t, true).controller
+	year := 365 * 24 * time.Hour
+	// Available for registration one day ago.
+	expiry := time.Now().Add(-registrarGracePeriod - 24*time.Hour)
+	// 0.01 ETH base plus 1 ETH premium.
+	maxPrice := big.NewInt(1_010_000_000_000_000_000)
+
+	_, err := controller.PremiumCrossing("foo.eth", expiry, year, nil)
+	require.EqualError(t, err, "no maximum price supplied")
+	_, err = controller.PremiumCrossing("foo.eth", expiry, year, big.NewInt(1))
+	require.EqualError(t, err, "price will not fall to 1 wei; base price is 10000000000000000 wei")
+	_, err = controller.PremiumCrossing("foo.xyz", expiry, year, maxPrice)
+	require.EqualError(t, err, "invalid name foo.xyz")
+
+	quote, err := controller.PremiumCrossing("foo.eth", expiry, year, maxPrice)
+	require.NoError(t, err)
+	require.True(t, quote.Price.Cmp(maxPrice) <= 0)
+	require.Equal(t, quote.Price, new(big.Int).Add(quote.Base, quote.Premium))
+	// The premium halves daily from 100 ETH, so reaches 1 ETH log2(100) days
+	// after the name became available.
+	expected := time.Duration((math.Log2(100) - 1) * 24 * float64(time.Hour))
+	require.InDelta(t, expected.Seconds(), time.Until(quote.Time).Seconds(), 5)
+	earlier, err := controller.QuoteAt("foo.eth", expiry, year, quote.Time.Add(-2*time.Second))
+	require.NoError(t, err)
+	require.True(t, earlier.Price.Cmp(maxPrice) > 0)
+
+	// Already below the maximum.
+	quote, err = controller.PremiumCrossing("foo.eth", expiry, year, big.NewInt(0).Mul(maxPrice, big.NewInt(1000)))
+	require.NoError(t, err)
+	require.WithinDuration(t, time.Now(), quote.Time, time.Second)
+}
+
+func TestRegisterAtPrice(t *testing.T) {
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	year := 365 * 24 * time.Hour
+
+	tests := []struct {
+		name         string
+		registration *PremiumRegistration
+		err          string
+	}{
+		{
+			name: "Nil",
+			err:  "no registration supplied",
+		},
+		{
+			name: "InvalidName",
+			registration: &PremiumRegistration{
+				Domain: "foo.xyz",
+			},
+			err: "invalid name foo.xyz",
+		},
+		{
+			name: "RecordsWithoutResolver",
+			registration: &PremiumRegistration{
+				Domain:        "foo.eth",
+				ReverseRecord: true,
+			},
+			err: "resolver required to set records",
+		},
+		{
+			name: "BelowBasePrice",
+			registration: &PremiumRegistration{
+				Domain:   "foo.eth",
+				Expiry:   time.Now().Add(-registrarGracePeriod - 30*24*time.Hour),
+				Owner:    owner,
+				Duration: year,
+				MaxPrice: big.NewInt(1),
+			},
+			err: "price will not fall to 1 wei; base price is 10000000000000000 wei",
+		},
+		{
+			name: "Good",
+			registration: &PremiumRegistration{
+				Domain:       "foo.eth",
+				Expiry:       time.Now().Add(-registrarGracePeriod - 30*24*time.Hour),
+				Owner:        owner,
+				Duration:     year,
+				MaxPrice:     big.NewInt(20_000_000_000_000_000),
+				PollInterval: 10 * time.Millisecond,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockRegistrarController(t, true)
+			m.mineCommitments = true
+			controller, calls := m.controller, m.calls
+			quotes := make(chan *PremiumQuote, 16)
+			txs, err := controller.RegisterAtPrice(context.Background(), testTransactOpts(t), test.registration, quotes)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, txs, 2)
+			require.Equal(t, [32]byte{0x01}, calls["commit"][0])
+			require.Equal(t, "foo", calls["register"][0])
+			require.Equal(t, owner, calls["register"][1])
+			// The value includes the default price buffer of 5%.
+			require.True(t, txs[1].Value().Cmp(bufferedPrice(test.registration.MaxPrice, DefaultPriceBufferPercent)) <= 0)
+			require.True(t, txs[1].Value().Cmp(big.NewInt(10_500_000_000_000_000)) >= 0)
+			require.NotEmpty(t, quotes)
+		})
+	}
+
+	controller := newMockRegistrarController(t, true).controller
+	_, err := controller.RegisterAtPrice(context.Background(), nil, &PremiumRegistration{Domain: "foo.eth"}, nil)
+	require.EqualError(t, err, "transaction options required")
+}
+
+func TestRegisterAtPriceCancelled(t *testing.T) {
+	controller := newMockRegistrarController(t, true).controller
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := controller.RegisterAtPrice(ctx, testTransactOpts(t), &PremiumRegistration{
+		Domain: "foo.eth",
+		// Available for registration one day ago, so the price will not fall
+		// to the maximum for days.
+		Expiry:   time.Now().Add(-registrarGracePeriod - 24*time.Hour),
+		Duration: 365 * 24 * time.Hour,
+		MaxPrice: big.NewInt(1_010_000_000_000_000_000),
+	}, nil)
+	require.ErrorIs(t, err, context.Canceled)
+}
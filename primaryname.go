@@ -0,0 +1,173 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wealdtech/go-ens/v3/contracts/reverseresolver"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	// EthereumCoinType is the coin type for Ethereum mainnet addresses.
+	EthereumCoinType = uint64(60)
+	// DefaultCoinType is the ENSIP-19 coin type for the default EVM address,
+	// used for all EVM chains without a chain-specific record.
+	DefaultCoinType = uint64(0x80000000)
+)
+
+// CoinTypeForChain returns the ENSIP-11 coin type for an EVM chain.
+func CoinTypeForChain(chainId ChainId) uint64 {
+	if chainId == EthereumMainnet {
+		return EthereumCoinType
+	}
+	return DefaultCoinType | uint64(chainId)
+}
+
+// ReverseNamespace returns the ENSIP-19 reverse namespace for a coin type,
+// for example "addr.reverse" for Ethereum or "80002105.reverse" for Base.
+func ReverseNamespace(coinType uint64) string {
+	switch coinType {
+	case EthereumCoinType:
+		return "addr.reverse"
+	case DefaultCoinType:
+		return "default.reverse"
+	default:
+		return fmt.Sprintf("%x.reverse", coinType)
+	}
+}
+
+// ReverseName returns the ENSIP-19 reverse name of an address for a coin type.
+func ReverseName(address common.Address, coinType uint64) string {
+	return fmt.Sprintf("%x.%s", address.Bytes(), ReverseNamespace(coinType))
+}
+
+// PrimaryName obtains the ENSIP-19 primary name of an address for a given chain.
+//
+// For chains other than Ethereum mainnet, the L2's own reverse registrar is
+// checked first if a backend for the L2 is supplied and the chain has a
+// registered configuration.  Following that the chain-specific reverse record
+// on mainnet is checked, then the default EVM reverse record, and finally the
+// mainnet reverse record.
+//
+// A name is only returned if its forward resolution for the chain matches the
+// address.
+func PrimaryName(mainnet bind.ContractBackend, l2 bind.ContractBackend, address common.Address, chainId ChainId) (_ string, err error) {
+	_, span := startSpan(context.Background(), "ens.PrimaryName",
+		attribute.String("ens.address", address.Hex()),
+		attribute.Int64("ens.chain_id", int64(chainId)),
+	)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	coinType := CoinTypeForChain(chainId)
+
+	if chainId != EthereumMainnet {
+		if l2 != nil && ChainConfigFor(chainId).ChainId == chainId {
+			name, err := ReverseResolve(l2, address, chainId)
+			if err == nil && name != "" && verifyPrimaryName(l2, chainId, name, address, EthereumCoinType) {
+				return name, nil
+			}
+		}
+
+		for _, reverseCoinType := range []uint64{coinType, DefaultCoinType} {
+			name, err := reverseNameLookup(mainnet, ReverseName(address, reverseCoinType))
+			if err != nil {
+				return "", err
+			}
+			if name != "" && verifyPrimaryName(mainnet, EthereumMainnet, name, address, coinType) {
+				return name, nil
+			}
+		}
+	}
+
+	name, err := reverseNameLookup(mainnet, ReverseName(address, EthereumCoinType))
+	if err != nil {
+		return "", err
+	}
+	if name != "" && verifyPrimaryName(mainnet, EthereumMainnet, name, address, coinType) {
+		return name, nil
+	}
+
+	return "", ErrNoResolution
+}
+
+// reverseNameLookup looks up the name held for a reverse name in the mainnet registry.
+// It returns an empty string if there is no name.
+func reverseNameLookup(backend bind.ContractBackend, reverseName string) (string, error) {
+	registry, err := NewRegistry(backend, EthereumMainnet)
+	if err != nil {
+		return "", err
+	}
+	resolverAddress, err := registry.ResolverAddress(reverseName)
+	if err != nil {
+		return "", err
+	}
+	if resolverAddress == UnknownAddress {
+		return "", nil
+	}
+
+	contract, err := reverseresolver.NewContract(resolverAddress, backend)
+	if err != nil {
+		return "", err
+	}
+	nameHash, err := NameHash(reverseName)
+	if err != nil {
+		return "", err
+	}
+	name, err := contract.Name(nil, nameHash)
+	if err != nil {
+		// Resolver does not support reverse records.
+		//nolint:nilerr
+		return "", nil
+	}
+
+	return strings.TrimSpace(name), nil
+}
+
+// verifyPrimaryName confirms that the forward resolution of a name for a
+// coin type matches the address.  If there is no address for the coin type
+// then the default EVM address is checked.
+func verifyPrimaryName(backend bind.ContractBackend, chainId ChainId, name string, address common.Address, coinType uint64) bool {
+	resolver, err := NewResolver(backend, name, chainId)
+	if err != nil {
+		return false
+	}
+
+	if coinType == EthereumCoinType {
+		resolved, err := resolver.Address()
+		return err == nil && resolved == address
+	}
+
+	resolved, err := resolver.MultiAddress(coinType)
+	if err != nil {
+		return false
+	}
+	if len(resolved) == 0 {
+		resolved, err = resolver.MultiAddress(DefaultCoinType)
+		if err != nil {
+			return false
+		}
+	}
+
+	return bytes.Equal(resolved, address.Bytes())
+}
@@ -0,0 +1,157 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// optimismMainnet is the ID of an L2 chain without an ENS deployment of its
+// own, whose primary names are held on Ethereum mainnet.
+const optimismMainnet ChainId = 10
+
+func TestReverseName(t *testing.T) {
+	address := common.HexToAddress("0xb8c2C29ee19D8307cb7255e1Cd9CbDE883A267d5")
+	require.Equal(t, uint64(60), CoinTypeForChain(EthereumMainnet))
+	require.Equal(t, uint64(0x80002105), CoinTypeForChain(BaseMainnet))
+	require.Equal(t, "b8c2c29ee19d8307cb7255e1cd9cbde883a267d5.addr.reverse", ReverseName(address, EthereumCoinType))
+	require.Equal(t, "b8c2c29ee19d8307cb7255e1cd9cbde883a267d5.default.reverse", ReverseName(address, DefaultCoinType))
+	require.Equal(t, "b8c2c29ee19d8307cb7255e1cd9cbde883a267d5.80002105.reverse", ReverseName(address, CoinTypeForChain(BaseMainnet)))
+	require.Equal(t, ChainConfigFor(BaseMainnet).ReverseNamespace, ReverseNamespace(CoinTypeForChain(BaseMainnet)))
+}
+
+func TestPrimaryName(t *testing.T) {
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	other := common.HexToAddress("0x0000000000000000000000000000000000000002")
+
+	m := newMockENS()
+	m.register("mainnet.eth", address, address)
+	m.setReverse(address, "mainnet.eth")
+	m.register("op.eth", address, UnknownAddress)
+	m.setCoinAddr("op.eth", CoinTypeForChain(optimismMainnet), address.Bytes())
+	m.setReverseIn(address, "8000000a.reverse", "op.eth")
+	m.register("default.eth", address, UnknownAddress)
+	m.setCoinAddr("default.eth", DefaultCoinType, address.Bytes())
+	m.setReverseIn(address, "default.reverse", "default.eth")
+	m.register("other.eth", other, other)
+	m.setReverse(other, "mainnet.eth")
+
+	// Mainnet record.
+	name, err := PrimaryName(m.backend, nil, address, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, "mainnet.eth", name)
+
+	// Chain-specific record.
+	name, err = PrimaryName(m.backend, nil, address, optimismMainnet)
+	require.NoError(t, err)
+	require.Equal(t, "op.eth", name)
+
+	// Default record.
+	name, err = PrimaryName(m.backend, nil, address, ChainId(42161))
+	require.NoError(t, err)
+	require.Equal(t, "default.eth", name)
+
+	// Forward resolution mismatch.
+	_, err = PrimaryName(m.backend, nil, other, EthereumMainnet)
+	require.EqualError(t, err, "no resolution")
+}
+
+func TestPrimaryNames(t *testing.T) {
+	mainnetAddress := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	defaultAddress := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	opAddress := common.HexToAddress("0x0000000000000000000000000000000000000003")
+	noneAddress := common.HexToAddress("0x0000000000000000000000000000000000000004")
+
+	m := newMockENS()
+	m.register("mainnet.eth", mainnetAddress, mainnetAddress)
+	m.setReverse(mainnetAddress, "mainnet.eth")
+	m.register("op.eth", mainnetAddress, UnknownAddress)
+	m.setCoinAddr("op.eth", CoinTypeForChain(optimismMainnet), mainnetAddress.Bytes())
+	m.setReverseIn(mainnetAddress, "8000000a.reverse", "op.eth")
+	m.register("default.eth", defaultAddress, UnknownAddress)
+	m.setCoinAddr("default.eth", DefaultCoinType, defaultAddress.Bytes())
+	m.setReverseIn(defaultAddress, "default.reverse", "default.eth")
+	m.register("oponly.eth", opAddress, UnknownAddress)
+	m.setCoinAddr("oponly.eth", CoinTypeForChain(optimismMainnet), opAddress.Bytes())
+	m.setReverseIn(opAddress, "8000000a.reverse", "oponly.eth")
+
+	l2s := map[ChainId]bind.ContractBackend{optimismMainnet: nil}
+
+	tests := []struct {
+		name    string
+		address common.Address
+		err     string
+		res     string
+		chainId ChainId
+		names   map[ChainId]string
+	}{
+		{
+			name:    "Mainnet",
+			address: mainnetAddress,
+			res:     "mainnet.eth",
+			chainId: EthereumMainnet,
+			names: map[ChainId]string{
+				EthereumMainnet: "mainnet.eth",
+				optimismMainnet: "op.eth",
+			},
+		},
+		{
+			name:    "Default",
+			address: defaultAddress,
+			res:     "default.eth",
+			names: map[ChainId]string{
+				BaseMainnet:     "default.eth",
+				optimismMainnet: "default.eth",
+			},
+		},
+		{
+			name:    "ChainSpecific",
+			address: opAddress,
+			res:     "oponly.eth",
+			chainId: optimismMainnet,
+			names: map[ChainId]string{
+				optimismMainnet: "oponly.eth",
+			},
+		},
+		{
+			name:    "None",
+			address: noneAddress,
+			err:     "no resolution",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := PrimaryNames(m.backend, l2s, test.address)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.address, res.Address)
+			require.Equal(t, test.res, res.Name)
+			require.Equal(t, test.chainId, res.ChainId)
+			for chainId, name := range test.names {
+				require.Equal(t, name, res.Names[chainId])
+			}
+			_, exists := res.Names[EthereumMainnet]
+			require.Equal(t, test.chainId == EthereumMainnet, exists)
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PrimaryNameSet is the primary names of an address across chains.
+type PrimaryNameSet struct {
+	// Address is the address.
+	Address common.Address
+	// Names are the primary names keyed by chain.  Chains on which the
+	// address does not have a primary name are omitted.
+	Names map[ChainId]string
+	// Name is the single name that best represents the address.
+	Name string
+	// ChainId is the chain for which Name is the primary name, or 0 if Name
+	// is the default EVM name.
+	ChainId ChainId
+}
+
+// PrimaryNames obtains the primary names of an address on Ethereum mainnet
+// and every chain with a registered configuration, along with any chains in
+// l2s.  Chains are queried concurrently.  Where a backend for a chain is
+// supplied in l2s its own reverse registrar is consulted as for PrimaryName,
+// otherwise only the records held on mainnet are used.
+//
+// The best name follows ENSIP-19 precedence: the mainnet primary name, then
+// the default EVM name, and then the primary name on the chain with the
+// lowest ID.
+func PrimaryNames(mainnet bind.ContractBackend, l2s map[ChainId]bind.ContractBackend, address common.Address) (*PrimaryNameSet, error) {
+	chainIds := registeredChainIds()
+	for chainId := range l2s {
+		if ChainConfigFor(chainId).ChainId != chainId {
+			chainIds = append(chainIds, chainId)
+		}
+	}
+	sort.Slice(chainIds, func(i, j int) bool { return chainIds[i] < chainIds[j] })
+
+	var (
+		mu          sync.Mutex
+		wg          sync.WaitGroup
+		names       = make(map[ChainId]string, len(chainIds))
+		defaultName string
+		firstErr    error
+	)
+	record := func(chainId ChainId, name string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case err == nil:
+			if chainId == 0 {
+				defaultName = name
+			} else {
+				names[chainId] = name
+			}
+		case isNoPrimaryName(err):
+		case firstErr == nil:
+			firstErr = fmt.Errorf("failed to obtain primary name for chain %d: %w", chainId, err)
+		}
+	}
+
+	for _, chainId := range chainIds {
+		wg.Add(1)
+		go func(chainId ChainId) {
+			defer wg.Done()
+			name, err := PrimaryName(mainnet, l2s[chainId], address, chainId)
+			record(chainId, name, err)
+		}(chainId)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		name, err := defaultPrimaryName(mainnet, address)
+		record(0, name, err)
+	}()
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	res := &PrimaryNameSet{
+		Address: address,
+		Names:   names,
+	}
+	switch {
+	case names[EthereumMainnet] != "":
+		res.Name = names[EthereumMainnet]
+		res.ChainId = EthereumMainnet
+	case defaultName != "":
+		res.Name = defaultName
+	default:
+		for _, chainId := range chainIds {
+			if name, exists := names[chainId]; exists {
+				res.Name = name
+				res.ChainId = chainId
+				break
+			}
+		}
+	}
+	if res.Name == "" {
+		return nil, ErrNoResolution
+	}
+
+	return res, nil
+}
+
+// defaultPrimaryName obtains the default EVM primary name of an address.
+func defaultPrimaryName(mainnet bind.ContractBackend, address common.Address) (string, error) {
+	name, err := reverseNameLookup(mainnet, ReverseName(address, DefaultCoinType))
+	if err != nil {
+		return "", err
+	}
+	if name == "" || !verifyPrimaryName(mainnet, EthereumMainnet, name, address, DefaultCoinType) {
+		return "", ErrNoResolution
+	}
+
+	return name, nil
+}
+
+// isNoPrimaryName returns true if the error shows that there is no primary
+// name, rather than that the lookup failed.
+func isNoPrimaryName(err error) bool {
+	return errors.Is(err, ErrNoResolution) || errors.Is(err, ErrNoResolver) || errors.Is(err, ErrNotAResolver)
+}
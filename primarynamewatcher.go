@@ -0,0 +1,246 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// reverseClaimedTopic is the topic of the reverse registrar event emitted
+	// when an address claims its reverse node.
+	reverseClaimedTopic = crypto.Keccak256Hash([]byte("ReverseClaimed(address,bytes32)"))
+	// nameChangedTopic is the topic of the resolver event emitted when the
+	// name of a node changes.
+	nameChangedTopic = crypto.Keccak256Hash([]byte("NameChanged(bytes32,string)"))
+)
+
+// PrimaryNameChange is a change of the primary name of an address.
+type PrimaryNameChange struct {
+	// Address is the address whose primary name changed.
+	Address common.Address
+	// Name is the new primary name, or empty if the address no longer has
+	// one.
+	Name string
+	// Previous is the previous primary name, or empty if the address did not
+	// have one.
+	Previous string
+	// BlockNumber is the block of the event that triggered the change.
+	BlockNumber uint64
+	// TransactionHash is the transaction of the event that triggered the
+	// change.
+	TransactionHash common.Hash
+}
+
+// PrimaryNameWatcher watches a set of addresses for changes to their primary
+// names.  It implements ethereum.Subscription.
+type PrimaryNameWatcher struct {
+	backend  bind.ContractBackend
+	chainId  ChainId
+	opts     []CallOption
+	onChange func(*PrimaryNameChange)
+	sub      ethereum.Subscription
+
+	mu        sync.Mutex
+	addresses map[common.Address]string
+	nodes     map[common.Hash]common.Address
+}
+
+// WatchPrimaryNames watches the given addresses for changes to their primary
+// names, calling onChange as they change.  The current primary name of each
+// address is obtained when it is added, and reverse registrar ReverseClaimed
+// and resolver NameChanged events for the addresses then trigger it to be
+// resolved again, with onChange called if the result differs.  Names are
+// resolved with ReverseResolve using the given options.
+//
+// It requires a backend that supports subscriptions, and runs until the
+// context is cancelled or the subscription fails; the returned watcher can
+// also be used to stop it.  onChange is called from a single goroutine, in
+// the order of events.
+func WatchPrimaryNames(ctx context.Context,
+	backend bind.ContractBackend,
+	chainId ChainId,
+	addresses []common.Address,
+	onChange func(*PrimaryNameChange),
+	opts ...CallOption,
+) (
+	*PrimaryNameWatcher,
+	error,
+) {
+	if onChange == nil {
+		return nil, errors.New("no change handler supplied")
+	}
+	w := &PrimaryNameWatcher{
+		backend:   backend,
+		chainId:   chainId,
+		opts:      opts,
+		onChange:  onChange,
+		addresses: make(map[common.Address]string),
+		nodes:     make(map[common.Hash]common.Address),
+	}
+	if err := w.Add(addresses...); err != nil {
+		return nil, err
+	}
+
+	query := ethereum.FilterQuery{
+		Topics: [][]common.Hash{{reverseClaimedTopic, nameChangedTopic}},
+	}
+	logs := make(chan types.Log)
+	sub, err := backend.SubscribeFilterLogs(ctx, query, logs)
+	if err != nil {
+		return nil, err
+	}
+	w.sub = sub
+
+	go func() {
+		for {
+			select {
+			case log := <-logs:
+				w.handleLog(log)
+			case <-sub.Err():
+				return
+			case <-ctx.Done():
+				sub.Unsubscribe()
+				return
+			}
+		}
+	}()
+
+	return w, nil
+}
+
+// Add adds addresses to the watched set, obtaining their current primary
+// names.  onChange is not called for added addresses.
+func (w *PrimaryNameWatcher) Add(addresses ...common.Address) error {
+	for _, address := range addresses {
+		node, err := NameHash(fmt.Sprintf("%x.%s", address.Bytes(), getRegistryAddress(w.chainId)))
+		if err != nil {
+			return err
+		}
+		name, err := w.primaryName(address)
+		if err != nil {
+			return err
+		}
+		w.mu.Lock()
+		w.addresses[address] = name
+		w.nodes[node] = address
+		w.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Remove removes addresses from the watched set.
+func (w *PrimaryNameWatcher) Remove(addresses ...common.Address) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, address := range addresses {
+		delete(w.addresses, address)
+		for node, nodeAddress := range w.nodes {
+			if nodeAddress == address {
+				delete(w.nodes, node)
+			}
+		}
+	}
+}
+
+// Name returns the last known primary name of a watched address, and true if
+// the address is watched.
+func (w *PrimaryNameWatcher) Name(address common.Address) (string, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	name, exists := w.addresses[address]
+
+	return name, exists
+}
+
+// Unsubscribe stops watching for changes.
+func (w *PrimaryNameWatcher) Unsubscribe() {
+	w.sub.Unsubscribe()
+}
+
+// Err returns the channel on which an error is sent if the underlying
+// subscription fails.
+func (w *PrimaryNameWatcher) Err() <-chan error {
+	return w.sub.Err()
+}
+
+// handleLog handles a reverse registrar or resolver event, resolving the
+// primary name of the address to which it relates if it is watched.
+func (w *PrimaryNameWatcher) handleLog(log types.Log) {
+	if len(log.Topics) < 2 {
+		return
+	}
+
+	w.mu.Lock()
+	var address common.Address
+	watched := false
+	switch log.Topics[0] {
+	case reverseClaimedTopic:
+		address = common.BytesToAddress(log.Topics[1].Bytes())
+		_, watched = w.addresses[address]
+	case nameChangedTopic:
+		address, watched = w.nodes[log.Topics[1]]
+	}
+	previous := w.addresses[address]
+	w.mu.Unlock()
+	if !watched {
+		return
+	}
+
+	// Names that cannot be resolved are left as they are, to be resolved
+	// again on the next event for the address.
+	name, err := w.primaryName(address)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	if _, watched = w.addresses[address]; watched {
+		w.addresses[address] = name
+	}
+	w.mu.Unlock()
+	if !watched || name == previous {
+		return
+	}
+
+	w.onChange(&PrimaryNameChange{
+		Address:         address,
+		Name:            name,
+		Previous:        previous,
+		BlockNumber:     log.BlockNumber,
+		TransactionHash: log.TxHash,
+	})
+}
+
+// primaryName obtains the primary name of an address, returning an empty
+// string if it has none.
+func (w *PrimaryNameWatcher) primaryName(address common.Address) (string, error) {
+	name, err := ReverseResolve(w.backend, address, w.chainId, w.opts...)
+	if isNoPrimaryName(err) {
+		return "", nil
+	}
+
+	return name, err
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchPrimaryNames(t *testing.T) {
+	m := newMockENS()
+	alice := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bob := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	carol := common.HexToAddress("0x00000000000000000000000000000000000ca201")
+	m.register("alice.eth", alice, alice)
+	m.setReverse(alice, "alice.eth")
+	m.setReverse(carol, "carol.eth")
+
+	var mu sync.Mutex
+	changes := make([]*PrimaryNameChange, 0)
+	onChange := func(change *PrimaryNameChange) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, change)
+	}
+	received := func() []*PrimaryNameChange {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*PrimaryNameChange{}, changes...)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_, err := WatchPrimaryNames(ctx, m.backend, EthereumMainnet, nil, nil)
+	require.EqualError(t, err, "no change handler supplied")
+	watcher, err := WatchPrimaryNames(ctx, m.backend, EthereumMainnet, []common.Address{alice, bob}, onChange)
+	require.NoError(t, err)
+	name, watched := watcher.Name(alice)
+	require.True(t, watched)
+	require.Equal(t, "alice.eth", name)
+	name, watched = watcher.Name(bob)
+	require.True(t, watched)
+	require.Equal(t, "", name)
+	_, watched = watcher.Name(carol)
+	require.False(t, watched)
+
+	// Bob claims a primary name.
+	m.register("bob.eth", bob, bob)
+	m.setReverse(bob, "bob.eth")
+	m.backend.emit(types.Log{
+		Address:     ChainConfigFor(EthereumMainnet).ReverseRegistrar,
+		Topics:      []common.Hash{reverseClaimedTopic, common.BytesToHash(bob.Bytes()), mustNameHash(fmt.Sprintf("%x.addr.reverse", bob.Bytes()))},
+		BlockNumber: 10,
+	})
+	require.Eventually(t, func() bool { return len(received()) == 1 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, &PrimaryNameChange{Address: bob, Name: "bob.eth", BlockNumber: 10}, received()[0])
+
+	// Alice changes her name, and an unwatched address changes its name.
+	m.register("alice2.eth", alice, alice)
+	m.setReverse(alice, "alice2.eth")
+	m.register("carol2.eth", carol, carol)
+	m.setReverse(carol, "carol2.eth")
+	m.backend.emit(types.Log{
+		Address:     m.resolverAddr,
+		Topics:      []common.Hash{nameChangedTopic, mustNameHash(fmt.Sprintf("%x.addr.reverse", carol.Bytes()))},
+		BlockNumber: 11,
+	})
+	m.backend.emit(types.Log{
+		Address:     m.resolverAddr,
+		Topics:      []common.Hash{nameChangedTopic, mustNameHash(fmt.Sprintf("%x.addr.reverse", alice.Bytes()))},
+		BlockNumber: 12,
+	})
+	require.Eventually(t, func() bool { return len(received()) == 2 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, &PrimaryNameChange{Address: alice, Name: "alice2.eth", Previous: "alice.eth", BlockNumber: 12}, received()[1])
+	name, _ = watcher.Name(alice)
+	require.Equal(t, "alice2.eth", name)
+
+	// Events that do not change the name are ignored.
+	m.backend.emit(types.Log{
+		Address:     m.resolverAddr,
+		Topics:      []common.Hash{nameChangedTopic, mustNameHash(fmt.Sprintf("%x.addr.reverse", alice.Bytes()))},
+		BlockNumber: 13,
+	})
+
+	// Removed addresses are no longer watched, and added addresses are.
+	watcher.Remove(alice)
+	_, watched = watcher.Name(alice)
+	require.False(t, watched)
+	require.NoError(t, watcher.Add(carol))
+	m.setReverse(alice, "alice.eth")
+	m.setReverse(carol, "")
+	for _, address := range []common.Address{alice, carol} {
+		m.backend.emit(types.Log{
+			Address:     m.resolverAddr,
+			Topics:      []common.Hash{nameChangedTopic, mustNameHash(fmt.Sprintf("%x.addr.reverse", address.Bytes()))},
+			BlockNumber: 14,
+		})
+	}
+	require.Eventually(t, func() bool { return len(received()) == 3 }, time.Second, 10*time.Millisecond)
+	require.Equal(t, &PrimaryNameChange{Address: carol, Name: "", Previous: "carol2.eth", BlockNumber: 14}, received()[2])
+
+	watcher.Unsubscribe()
+}
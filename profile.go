@@ -0,0 +1,172 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// ProfileVersion is the version of the profile format.
+const ProfileVersion = 1
+
+// Profile is a portable copy of the records of a name.
+//
+// The JSON form of a profile is:
+//
+//	{
+//	  "version": 1,
+//	  "name": "foo.eth",
+//	  "addresses": {
+//	    "60": "0x5b38da6a701c568545dcfcb03fcb875f56beddc4",
+//	    "0": "0x00147c1ce8f1b5ef0d0c1e0a3dc7d53b1b1f6b5ca4e8"
+//	  },
+//	  "texts": {
+//	    "url": "https://example.com/"
+//	  },
+//	  "contenthash": "/ipfs/bafybeigdyrzt5sfp7udm7hu76uh7y26nf3efuylqabf3oclgtqy55fbzdi"
+//	}
+//
+// Addresses are keyed by SLIP-44 coin type in decimal, with values the
+// hex-encoded address in the binary format held by the resolver.  The
+// contenthash is in EIP-1577 text format.  All fields other than version are
+// optional.
+type Profile struct {
+	Version     int               `json:"version"`
+	Name        string            `json:"name,omitempty"`
+	Addresses   map[string]string `json:"addresses,omitempty"`
+	Texts       map[string]string `json:"texts,omitempty"`
+	Contenthash string            `json:"contenthash,omitempty"`
+}
+
+// ReadProfile reads a profile in JSON format.
+func ReadProfile(r io.Reader) (*Profile, error) {
+	var profile Profile
+	if err := json.NewDecoder(r).Decode(&profile); err != nil {
+		return nil, errors.Wrap(err, "failed to decode profile")
+	}
+	if profile.Version != ProfileVersion {
+		return nil, fmt.Errorf("unsupported profile version %d", profile.Version)
+	}
+
+	return &profile, nil
+}
+
+// Write writes the profile in JSON format.
+func (p *Profile) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(p)
+}
+
+// ExportProfile exports the address, text and contenthash records of the
+// name as a profile.  Records are found as for MigrateResolver.
+func (n *Name) ExportProfile() (*Profile, error) {
+	resolver, err := NewResolver(n.backend, n.Name, EthereumMainnet)
+	if err != nil {
+		return nil, err
+	}
+	nameHash, err := NameHash(n.Name)
+	if err != nil {
+		return nil, err
+	}
+	records, err := readResolverRecords(resolver, nameHash)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &Profile{
+		Version: ProfileVersion,
+		Name:    n.Name,
+	}
+	if len(records.addresses) > 0 {
+		profile.Addresses = make(map[string]string, len(records.addresses))
+		for coinType, address := range records.addresses {
+			profile.Addresses[strconv.FormatUint(coinType, 10)] = hexutil.Encode(address)
+		}
+	}
+	if len(records.texts) > 0 {
+		profile.Texts = records.texts
+	}
+	if len(records.contenthash) > 0 {
+		profile.Contenthash, err = ContenthashToString(records.contenthash)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to convert contenthash")
+		}
+	}
+
+	return profile, nil
+}
+
+// ImportProfile sets the records of the name from a profile, returning the
+// transactions sent.  Records not in the profile are left unchanged; an empty
+// address or text value in the profile clears the record.  If the name's
+// resolver supports multicall the records are set in a single transaction.
+func (n *Name) ImportProfile(profile *Profile, opts *bind.TransactOpts) ([]*types.Transaction, error) {
+	if profile == nil {
+		return nil, errors.New("no profile supplied")
+	}
+	if profile.Version != ProfileVersion {
+		return nil, fmt.Errorf("unsupported profile version %d", profile.Version)
+	}
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+
+	records := &resolverRecords{
+		addresses: make(map[uint64][]byte, len(profile.Addresses)),
+		texts:     profile.Texts,
+	}
+	for key, value := range profile.Addresses {
+		coinType, err := strconv.ParseUint(key, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coin type %s", key)
+		}
+		if value == "" {
+			records.addresses[coinType] = []byte{}
+			continue
+		}
+		address, err := hexutil.Decode(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid address for coin type %d", coinType)
+		}
+		records.addresses[coinType] = address
+	}
+	if profile.Contenthash != "" {
+		contenthash, err := StringToContenthash(profile.Contenthash)
+		if err != nil {
+			return nil, errors.Wrap(err, "invalid contenthash")
+		}
+		records.contenthash = contenthash
+	}
+
+	resolver, err := NewResolver(n.backend, n.Name, EthereumMainnet)
+	if err != nil {
+		return nil, err
+	}
+	nameHash, err := NameHash(n.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	return writeResolverRecords(resolver.Contract, sequentialOpts(opts), records.calls(nameHash))
+}
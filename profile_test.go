@@ -0,0 +1,138 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+func TestProfileRoundTrip(t *testing.T) {
+	m := newMockENS()
+	opts := testTransactOpts(t)
+	m.register("profile.eth", opts.From, opts.From)
+	m.setText("profile.eth", "url", "https://example.com/")
+	contenthash, err := StringToContenthash("/ipfs/QmRAQB6YaCyidP37UdDnjFY5vQuiBrcqdyoW1CuDgwxkD4")
+	require.NoError(t, err)
+	m.backend.contracts[m.resolverAddr].on("contenthash", func(_ []interface{}) ([]interface{}, error) {
+		return []interface{}{contenthash}, nil
+	})
+
+	name := &Name{backend: m.backend, Name: "profile.eth"}
+	profile, err := name.ExportProfile()
+	require.NoError(t, err)
+	require.Equal(t, &Profile{
+		Version:     ProfileVersion,
+		Name:        "profile.eth",
+		Addresses:   map[string]string{"60": strings.ToLower(opts.From.Hex())},
+		Texts:       map[string]string{"url": "https://example.com/"},
+		Contenthash: "/ipfs/k2jmtxseqz46solsx2rmxavgbzp6ij1t1kiq1or8a00c2g9bx1for0gv",
+	}, profile)
+
+	var buf bytes.Buffer
+	require.NoError(t, profile.Write(&buf))
+	read, err := ReadProfile(&buf)
+	require.NoError(t, err)
+	require.Equal(t, profile, read)
+
+	// Import the profile to another name.
+	m.register("copy.eth", opts.From, UnknownAddress)
+	written := make(map[string][]interface{})
+	m.backend.contracts[m.resolverAddr].
+		on("multicall", func(args []interface{}) ([]interface{}, error) {
+			parsed, err := resolver.ContractMetaData.GetAbi()
+			require.NoError(t, err)
+			for _, data := range args[0].([][]byte) {
+				method, err := parsed.MethodById(data[:4])
+				require.NoError(t, err)
+				callArgs, err := method.Inputs.Unpack(data[4:])
+				require.NoError(t, err)
+				written[method.Name] = callArgs
+			}
+			return []interface{}{[][]byte{}}, nil
+		})
+	copyName := &Name{backend: m.backend, Name: "copy.eth"}
+	txs, err := copyName.ImportProfile(read, opts)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	require.Equal(t, big.NewInt(60), written["setAddr0"][1])
+	require.Equal(t, opts.From.Bytes(), written["setAddr0"][2])
+	require.Equal(t, "https://example.com/", written["setText"][2])
+	require.Equal(t, contenthash, written["setContenthash"][1])
+	require.Equal(t, mustNameHash("copy.eth"), written["setText"][0])
+
+	// Empty values clear records.
+	_, err = copyName.ImportProfile(&Profile{
+		Version:   ProfileVersion,
+		Addresses: map[string]string{"60": ""},
+		Texts:     map[string]string{"url": ""},
+	}, opts)
+	require.NoError(t, err)
+	require.Equal(t, []byte{}, written["setAddr0"][2])
+	require.Equal(t, "", written["setText"][2])
+}
+
+func TestProfileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		profile *Profile
+		err     string
+	}{
+		{
+			name:  "InvalidJSON",
+			input: `{`,
+			err:   "failed to decode profile: unexpected EOF",
+		},
+		{
+			name:  "BadVersion",
+			input: `{"version":2}`,
+			err:   "unsupported profile version 2",
+		},
+		{
+			name:    "BadCoinType",
+			profile: &Profile{Version: ProfileVersion, Addresses: map[string]string{"eth": "0x00"}},
+			err:     "invalid coin type eth",
+		},
+		{
+			name:    "BadAddress",
+			profile: &Profile{Version: ProfileVersion, Addresses: map[string]string{"60": "xyz"}},
+			err:     "invalid address for coin type 60",
+		},
+		{
+			name:    "BadContenthash",
+			profile: &Profile{Version: ProfileVersion, Contenthash: "invalid"},
+			err:     "invalid contenthash: invalid content hash",
+		},
+	}
+
+	name := &Name{backend: newMockENS().backend, Name: "profile.eth"}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.profile == nil {
+				_, err := ReadProfile(strings.NewReader(test.input))
+				require.EqualError(t, err, test.err)
+				return
+			}
+			_, err := name.ImportProfile(test.profile, testTransactOpts(t))
+			require.EqualError(t, err, test.err)
+		})
+	}
+}
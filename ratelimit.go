@@ -0,0 +1,164 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitedBackend is a contract backend that limits the rate of requests
+// made to an upstream backend.  Requests over the limit wait until they are
+// allowed, or until their context is cancelled.
+type RateLimitedBackend struct {
+	backend bind.ContractBackend
+	limiter *rate.Limiter
+}
+
+var _ bind.ContractBackend = (*RateLimitedBackend)(nil)
+
+// NewRateLimitedBackend creates a backend that allows up to requestsPerSecond
+// requests to the upstream backend, with bursts of up to burst requests.
+func NewRateLimitedBackend(backend bind.ContractBackend, requestsPerSecond float64, burst int) (*RateLimitedBackend, error) {
+	if backend == nil {
+		return nil, errors.New("no backend supplied")
+	}
+	if requestsPerSecond <= 0 {
+		return nil, errors.New("requests per second must be greater than 0")
+	}
+	if burst < 1 {
+		return nil, errors.New("burst must be at least 1")
+	}
+
+	return &RateLimitedBackend{
+		backend: backend,
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), burst),
+	}, nil
+}
+
+// Unwrap returns the upstream backend.
+func (b *RateLimitedBackend) Unwrap() bind.ContractBackend {
+	return b.backend
+}
+
+// SetLimit changes the rate and burst of the limiter.
+func (b *RateLimitedBackend) SetLimit(requestsPerSecond float64, burst int) {
+	b.limiter.SetLimit(rate.Limit(requestsPerSecond))
+	b.limiter.SetBurst(burst)
+}
+
+// wait waits until a request is allowed.
+func (b *RateLimitedBackend) wait(ctx context.Context) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return b.limiter.Wait(ctx)
+}
+
+// CodeAt returns the code of the given account.
+func (b *RateLimitedBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	if err := b.wait(ctx); err != nil {
+		return nil, err
+	}
+	return b.backend.CodeAt(ctx, contract, blockNumber)
+}
+
+// CallContract executes an Ethereum contract call with the specified data as the input.
+func (b *RateLimitedBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if err := b.wait(ctx); err != nil {
+		return nil, err
+	}
+	return b.backend.CallContract(ctx, call, blockNumber)
+}
+
+// HeaderByNumber returns a block header from the current canonical chain.
+func (b *RateLimitedBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	if err := b.wait(ctx); err != nil {
+		return nil, err
+	}
+	return b.backend.HeaderByNumber(ctx, number)
+}
+
+// PendingCodeAt returns the code of the given account in the pending state.
+func (b *RateLimitedBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	if err := b.wait(ctx); err != nil {
+		return nil, err
+	}
+	return b.backend.PendingCodeAt(ctx, account)
+}
+
+// PendingNonceAt retrieves the current pending nonce associated with an account.
+func (b *RateLimitedBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	if err := b.wait(ctx); err != nil {
+		return 0, err
+	}
+	return b.backend.PendingNonceAt(ctx, account)
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price.
+func (b *RateLimitedBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	if err := b.wait(ctx); err != nil {
+		return nil, err
+	}
+	return b.backend.SuggestGasPrice(ctx)
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap.
+func (b *RateLimitedBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	if err := b.wait(ctx); err != nil {
+		return nil, err
+	}
+	return b.backend.SuggestGasTipCap(ctx)
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction.
+func (b *RateLimitedBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	if err := b.wait(ctx); err != nil {
+		return 0, err
+	}
+	return b.backend.EstimateGas(ctx, call)
+}
+
+// SendTransaction injects the transaction into the pending pool for execution.
+func (b *RateLimitedBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	if err := b.wait(ctx); err != nil {
+		return err
+	}
+	return b.backend.SendTransaction(ctx, tx)
+}
+
+// FilterLogs executes a log filter operation.
+func (b *RateLimitedBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if err := b.wait(ctx); err != nil {
+		return nil, err
+	}
+	return b.backend.FilterLogs(ctx, query)
+}
+
+// SubscribeFilterLogs creates a background log filtering operation.  Only
+// the creation of the subscription counts towards the limit.
+func (b *RateLimitedBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	if err := b.wait(ctx); err != nil {
+		return nil, err
+	}
+	return b.backend.SubscribeFilterLogs(ctx, query, ch)
+}
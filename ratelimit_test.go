@@ -0,0 +1,71 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitedBackend(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("limited.eth", address, address)
+
+	backend, err := NewRateLimitedBackend(m.backend, 1000, 1)
+	require.NoError(t, err)
+	resolved, err := Resolve(backend, "limited.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+
+	// With a slow rate and no burst remaining, requests wait for their context.
+	backend, err = NewRateLimitedBackend(m.backend, 0.001, 1)
+	require.NoError(t, err)
+	_, err = backend.CallContract(context.Background(), ethereum.CallMsg{}, nil)
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = backend.CallContract(ctx, ethereum.CallMsg{}, nil)
+	require.Error(t, err)
+}
+
+func TestNewRateLimitedBackend(t *testing.T) {
+	tests := []struct {
+		name  string
+		rate  float64
+		burst int
+		err   string
+	}{
+		{name: "Good", rate: 10, burst: 5},
+		{name: "ZeroRate", rate: 0, burst: 5, err: "requests per second must be greater than 0"},
+		{name: "ZeroBurst", rate: 10, burst: 0, err: "burst must be at least 1"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewRateLimitedBackend(newMockBackend(), test.rate, test.burst)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,335 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wealdtech/go-ens/v3/contracts/multicall3"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+// Multicall3Address is the address of the Multicall3 contract, which is
+// deployed at the same address on most chains.
+var Multicall3Address = common.HexToAddress("0xcA11bde05977b3631167028862bE2a173976CA11")
+
+// RecordsSpec specifies the records to read with Records.
+type RecordsSpec struct {
+	// CoinTypes are the coin types of the addresses to read.
+	CoinTypes []uint64
+	// Texts are the keys of the text records to read.
+	Texts []string
+	// Contenthash is set if the contenthash should be read.
+	Contenthash bool
+}
+
+// Records are the records of a name read from its resolver.  Records that
+// are not set, or that could not be read, are omitted.
+type Records struct {
+	// Addresses are the addresses keyed by coin type.
+	Addresses map[uint64][]byte
+	// Texts are the text records keyed by key.
+	Texts map[string]string
+	// Contenthash is the contenthash.
+	Contenthash []byte
+}
+
+// Texts obtains multiple text records of a name in a single call.  Keys that
+// do not have a value are omitted from the result.
+func (r *Resolver) Texts(keys []string, opts ...CallOption) (map[string]string, error) {
+	records, err := r.Records(&RecordsSpec{Texts: keys}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return records.Texts, nil
+}
+
+// Records obtains multiple records of a name.  The records are read in a
+// single call using the resolver's multicall if it has one, otherwise using
+// Multicall3, and if neither is available each record is read separately.
+func (r *Resolver) Records(spec *RecordsSpec, opts ...CallOption) (*Records, error) {
+	nameHash, err := NameHash(r.domain)
+	if err != nil {
+		return nil, err
+	}
+
+	calls := make([]resolverCall, 0, len(spec.CoinTypes)+len(spec.Texts)+1)
+	for _, coinType := range spec.CoinTypes {
+		calls = append(calls, resolverCall{method: "addr0", args: []interface{}{nameHash, new(big.Int).SetUint64(coinType)}})
+	}
+	for _, key := range spec.Texts {
+		calls = append(calls, resolverCall{method: "text", args: []interface{}{nameHash, key}})
+	}
+	if spec.Contenthash {
+		calls = append(calls, resolverCall{method: "contenthash", args: []interface{}{nameHash}})
+	}
+
+	o, release := newCallOptions(opts)
+	defer release()
+	results, err := r.readRecords(calls, o)
+	if err != nil {
+		return nil, err
+	}
+
+	records := &Records{
+		Addresses: make(map[uint64][]byte),
+		Texts:     make(map[string]string),
+	}
+	for i, res := range results {
+		if res == nil {
+			continue
+		}
+		switch calls[i].method {
+		case "addr0":
+			if address := res.([]byte); len(address) > 0 {
+				records.Addresses[calls[i].args[1].(*big.Int).Uint64()] = address
+			}
+		case "text":
+			if text := res.(string); text != "" {
+				records.Texts[calls[i].args[1].(string)] = text
+			}
+		case "contenthash":
+			if contenthash := res.([]byte); len(contenthash) > 0 {
+				records.Contenthash = contenthash
+			}
+		}
+	}
+
+	return records, nil
+}
+
+// RecordSet is a set of records to write with SetRecords.  Records that are
+// not included are left unchanged.
+type RecordSet struct {
+	// Addr is the Ethereum address, which is the address for coin type 60.
+	// It is not set if it is UnknownAddress.
+	Addr common.Address
+	// Addresses are addresses keyed by coin type.
+	Addresses map[uint64][]byte
+	// Texts are text records keyed by key.  An empty value clears the record.
+	Texts map[string]string
+	// Contenthash is the contenthash.  It is not set if empty.
+	Contenthash []byte
+}
+
+// SetRecords sets multiple records of a name atomically, in a single
+// transaction using the resolver's multicall.  An error is returned if the
+// resolver does not support multicall.
+func (r *Resolver) SetRecords(opts *bind.TransactOpts, records *RecordSet) (*types.Transaction, error) {
+	if records == nil {
+		return nil, errors.New("no records supplied")
+	}
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+
+	addresses := make(map[uint64][]byte, len(records.Addresses)+1)
+	for coinType, address := range records.Addresses {
+		addresses[coinType] = address
+	}
+	if records.Addr != UnknownAddress {
+		if _, exists := addresses[60]; exists {
+			return nil, errors.New("address for coin type 60 supplied in both Addr and Addresses")
+		}
+		addresses[60] = records.Addr.Bytes()
+	}
+
+	nameHash, err := NameHash(r.domain)
+	if err != nil {
+		return nil, err
+	}
+	calls := (&resolverRecords{
+		addresses:   addresses,
+		texts:       records.Texts,
+		contenthash: records.Contenthash,
+	}).calls(nameHash)
+	if len(calls) == 0 {
+		return nil, errors.New("no records supplied")
+	}
+	if !supportsMulticall(r.Contract) {
+		return nil, errors.New("resolver does not support multicall")
+	}
+
+	txs, err := writeResolverRecords(r.Contract, func() *bind.TransactOpts { return opts }, calls)
+	if err != nil {
+		return nil, err
+	}
+
+	return txs[0], nil
+}
+
+// readRecords carries out calls to read records from the resolver, returning
+// the decoded result of each call, or nil if the call failed.
+func (r *Resolver) readRecords(calls []resolverCall, o *callOptions) ([]interface{}, error) {
+	parsed, err := resolver.ContractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	data := make([][]byte, len(calls))
+	for i, call := range calls {
+		data[i], err = parsed.Pack(call.method, call.args...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var returnData [][]byte
+	if r.wildcard {
+		// Each call passes through the resolve function of the resolver.
+		returnData, err = r.calls(data, o)
+	} else {
+		returnData, err = r.multicall(data, o)
+		if err != nil {
+			returnData, err = r.multicall3(data, o)
+		}
+		if err != nil {
+			returnData, err = r.calls(data, o)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]interface{}, len(calls))
+	for i := range calls {
+		if len(returnData[i]) == 0 {
+			continue
+		}
+		values, err := parsed.Unpack(calls[i].method, returnData[i])
+		if err != nil || len(values) == 0 {
+			continue
+		}
+		results[i] = values[0]
+	}
+
+	return results, nil
+}
+
+// multicall carries out calls with the resolver's multicall.  This fails if
+// any of the calls fails.
+func (r *Resolver) multicall(data [][]byte, o *callOptions) ([][]byte, error) {
+	raw := &resolver.ContractRaw{Contract: r.Contract}
+	var out []interface{}
+	if err := raw.Call(o.callOpts(), &out, "multicall", data); err != nil {
+		return nil, err
+	}
+	returnData := *abi.ConvertType(out[0], new([][]byte)).(*[][]byte)
+	if len(returnData) != len(data) {
+		return nil, errors.New("unexpected number of multicall results")
+	}
+
+	return returnData, nil
+}
+
+// multicall3 carries out calls with Multicall3, allowing individual calls to
+// fail.
+func (r *Resolver) multicall3(data [][]byte, o *callOptions) ([][]byte, error) {
+	calls := make([]multicall3.Multicall3Call3, len(data))
+	for i := range data {
+		calls[i] = multicall3.Multicall3Call3{
+			Target:       r.ContractAddr,
+			AllowFailure: true,
+			CallData:     data[i],
+		}
+	}
+
+	return aggregate3(r.backend, calls, o)
+}
+
+// aggregate3 carries out calls with Multicall3, returning the data returned
+// by each call, or nil if the call failed.
+func aggregate3(backend bind.ContractBackend, calls []multicall3.Multicall3Call3, o *callOptions) ([][]byte, error) {
+	results, err := aggregate3Results(backend, calls, o)
+	if err != nil {
+		return nil, err
+	}
+
+	returnData := make([][]byte, len(results))
+	for i := range results {
+		if results[i].Success {
+			returnData[i] = results[i].ReturnData
+		}
+	}
+
+	return returnData, nil
+}
+
+// aggregate3Results carries out calls with Multicall3, returning the result of
+// each call.  The data of a failed call is its revert data.
+func aggregate3Results(backend bind.ContractBackend, calls []multicall3.Multicall3Call3, o *callOptions) ([]multicall3.Multicall3Result, error) {
+	contract, err := multicall3.NewContract(Multicall3Address, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := &multicall3.ContractRaw{Contract: contract}
+	var out []interface{}
+	if err := raw.Call(o.callOpts(), &out, "aggregate3", calls); err != nil {
+		return nil, err
+	}
+	results := *abi.ConvertType(out[0], new([]multicall3.Multicall3Result)).(*[]multicall3.Multicall3Result)
+	if len(results) != len(calls) {
+		return nil, errors.New("unexpected number of multicall results")
+	}
+
+	return results, nil
+}
+
+// calls carries out calls individually, for backends without Multicall3.
+// Failed calls return nil data, unless no call succeeded in which case the
+// error of the first call is returned.
+func (r *Resolver) calls(data [][]byte, o *callOptions) ([][]byte, error) {
+	opts := o.callOpts()
+	pendingBackend, isPendingBackend := r.backend.(bind.PendingContractCaller)
+	if opts.Pending && !isPendingBackend {
+		return nil, bind.ErrNoPendingState
+	}
+	returnData := make([][]byte, len(data))
+	var firstErr error
+	succeeded := false
+	for i := range data {
+		msg := ethereum.CallMsg{
+			From: opts.From,
+			To:   &r.ContractAddr,
+			Data: data[i],
+		}
+		var err error
+		if opts.Pending {
+			returnData[i], err = pendingBackend.PendingCallContract(opts.Context, msg)
+		} else {
+			returnData[i], err = r.backend.CallContract(opts.Context, msg, opts.BlockNumber)
+		}
+		if err != nil {
+			returnData[i] = nil
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		succeeded = true
+	}
+	if !succeeded && firstErr != nil {
+		return nil, revertError(firstErr)
+	}
+
+	return returnData, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecords(t *testing.T) {
+	address := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	btcAddress := []byte{0x00, 0x14, 0x01, 0x02}
+	contenthash := []byte{0xe3, 0x01, 0x01}
+
+	tests := []struct {
+		name      string
+		multicall bool
+		// multicall3 is set if Multicall3 is deployed.
+		multicall3 bool
+		calls      int
+	}{
+		{
+			name:      "ResolverMulticall",
+			multicall: true,
+			calls:     1,
+		},
+		{
+			name:       "Multicall3",
+			multicall3: true,
+			calls:      2,
+		},
+		{
+			name:  "Individual",
+			calls: 9,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockENS()
+			m.register("records.eth", address, address)
+			m.setCoinAddr("records.eth", 0, btcAddress)
+			m.setText("records.eth", "url", "https://example.com/")
+			m.setText("records.eth", "avatar", "https://example.com/avatar.png")
+			resolverContract := m.backend.contracts[m.resolverAddr]
+			resolverContract.on("contenthash", func(_ []interface{}) ([]interface{}, error) {
+				return []interface{}{contenthash}, nil
+			})
+			if test.multicall {
+				resolverContract.on("multicall", func(args []interface{}) ([]interface{}, error) {
+					res := make([][]byte, 0)
+					for _, data := range args[0].([][]byte) {
+						returnData, err := m.backend.dispatch(m.resolverAddr, data)
+						if err != nil {
+							return nil, err
+						}
+						res = append(res, returnData)
+					}
+					return []interface{}{res}, nil
+				})
+			}
+			if test.multicall3 {
+				deployMockMulticall3(m.backend)
+			}
+
+			resolver, err := NewResolver(m.backend, "records.eth", EthereumMainnet)
+			require.NoError(t, err)
+
+			calls := m.backend.callCount()
+			records, err := resolver.Records(&RecordsSpec{
+				CoinTypes:   []uint64{0, 60, 2},
+				Texts:       []string{"url", "avatar", "description"},
+				Contenthash: true,
+			})
+			require.NoError(t, err)
+			require.Equal(t, test.calls, m.backend.callCount()-calls)
+			require.Equal(t, map[uint64][]byte{0: btcAddress, 60: address.Bytes()}, records.Addresses)
+			require.Equal(t, map[string]string{"url": "https://example.com/", "avatar": "https://example.com/avatar.png"}, records.Texts)
+			require.Equal(t, contenthash, records.Contenthash)
+
+			texts, err := resolver.Texts([]string{"url", "twitter"})
+			require.NoError(t, err)
+			require.Equal(t, map[string]string{"url": "https://example.com/"}, texts)
+		})
+	}
+}
+
+func TestRecordsCallsFail(t *testing.T) {
+	address := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	m := newMockENS()
+	m.register("records.eth", address, address)
+	m.backend.contracts[m.resolverAddr].on("text", func(_ []interface{}) ([]interface{}, error) {
+		return nil, errors.New("connection refused")
+	})
+	resolver, err := NewResolver(m.backend, "records.eth", EthereumMainnet)
+	require.NoError(t, err)
+
+	// No call succeeds, so the error is returned.
+	_, err = resolver.Texts([]string{"url", "avatar"})
+	require.EqualError(t, err, "connection refused")
+
+	// Some calls succeed, so the failed calls are treated as missing records.
+	records, err := resolver.Records(&RecordsSpec{
+		CoinTypes: []uint64{60},
+		Texts:     []string{"url"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, map[uint64][]byte{60: address.Bytes()}, records.Addresses)
+	require.Empty(t, records.Texts)
+}
+
+func TestRecordsPendingUnsupported(t *testing.T) {
+	address := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	m := newMockENS()
+	m.register("records.eth", address, address)
+	resolver, err := NewResolver(m.backend, "records.eth", EthereumMainnet)
+	require.NoError(t, err)
+
+	// Records are not reported as unset if the backend cannot call against
+	// the pending state.
+	_, err = resolver.Records(&RecordsSpec{Texts: []string{"url"}}, WithPending())
+	require.ErrorIs(t, err, bind.ErrNoPendingState)
+}
+
+func TestSetRecords(t *testing.T) {
+	opts := testTransactOpts(t)
+	address := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+
+	tests := []struct {
+		name      string
+		records   *RecordSet
+		multicall bool
+		calls     int
+		err       string
+	}{
+		{
+			name:      "Nil",
+			multicall: true,
+			err:       "no records supplied",
+		},
+		{
+			name:      "Empty",
+			records:   &RecordSet{},
+			multicall: true,
+			err:       "no records supplied",
+		},
+		{
+			name: "DuplicateAddress",
+			records: &RecordSet{
+				Addr:      address,
+				Addresses: map[uint64][]byte{60: address.Bytes()},
+			},
+			multicall: true,
+			err:       "address for coin type 60 supplied in both Addr and Addresses",
+		},
+		{
+			name: "NoMulticall",
+			records: &RecordSet{
+				Addr: address,
+			},
+			err: "resolver does not support multicall",
+		},
+		{
+			name: "Good",
+			records: &RecordSet{
+				Addr:        address,
+				Addresses:   map[uint64][]byte{0: {0x00, 0x14}},
+				Texts:       map[string]string{"url": "https://example.com/", "avatar": ""},
+				Contenthash: []byte{0xe3, 0x01, 0x01},
+			},
+			multicall: true,
+			calls:     5,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockENS()
+			m.register("records.eth", opts.From, address)
+			calls := 0
+			m.backend.contracts[m.resolverAddr].
+				on("supportsInterface", func(args []interface{}) ([]interface{}, error) {
+					interfaceID := args[0].([4]byte)
+					isMulticall := interfaceID == multicallInterfaceID || interfaceID == legacyMulticallInterfaceID
+					return []interface{}{!isMulticall || test.multicall}, nil
+				}).
+				on("multicall", func(args []interface{}) ([]interface{}, error) {
+					calls = len(args[0].([][]byte))
+					return []interface{}{make([][]byte, calls)}, nil
+				})
+
+			resolver, err := NewResolver(m.backend, "records.eth", EthereumMainnet)
+			require.NoError(t, err)
+
+			tx, err := resolver.SetRecords(opts, test.records)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				require.Empty(t, m.backend.sent)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, m.resolverAddr, *tx.To())
+			require.Len(t, m.backend.sent, 1)
+			require.Equal(t, test.calls, calls)
+		})
+	}
+}
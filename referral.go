@@ -0,0 +1,307 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReferralRegistrar is a TLDRegistrar that can credit a referrer with
+// registrations and renewals, for example so that the referrer receives a
+// share of the fee.  Clients created with WithReferrer use these methods.
+type ReferralRegistrar interface {
+	TLDRegistrar
+	// RegisterWithReferrer registers a domain, crediting the referrer.
+	RegisterWithReferrer(opts *bind.TransactOpts,
+		domain string,
+		owner common.Address,
+		duration time.Duration,
+		resolver common.Address,
+		data [][]byte,
+		reverseRecord bool,
+		referrer [32]byte,
+	) (
+		*types.Transaction,
+		error,
+	)
+	// RenewWithReferrer renews a domain, crediting the referrer.
+	RenewWithReferrer(opts *bind.TransactOpts, domain string, duration time.Duration, referrer [32]byte) (*types.Transaction, error)
+}
+
+// ReferrerFromAddress returns the referrer for an address, which is the
+// address left-padded to 32 bytes.
+func ReferrerFromAddress(address common.Address) [32]byte {
+	return common.BytesToHash(address.Bytes())
+}
+
+// referralControllerABI is the ABI of the functions of registrar controllers
+// that accept a referrer.  Fees are split with the referrer by the contract.
+const referralControllerABI = `[
+{"type":"function","name":"makeCommitment","stateMutability":"pure","inputs":[{"name":"registration","type":"tuple","components":[{"name":"label","type":"string"},{"name":"owner","type":"address"},{"name":"duration","type":"uint256"},{"name":"secret","type":"bytes32"},{"name":"resolver","type":"address"},{"name":"data","type":"bytes[]"},{"name":"reverseRecord","type":"uint8"},{"name":"referrer","type":"bytes32"}]}],"outputs":[{"name":"commitment","type":"bytes32"}]},
+{"type":"function","name":"register","stateMutability":"payable","inputs":[{"name":"registration","type":"tuple","components":[{"name":"label","type":"string"},{"name":"owner","type":"address"},{"name":"duration","type":"uint256"},{"name":"secret","type":"bytes32"},{"name":"resolver","type":"address"},{"name":"data","type":"bytes[]"},{"name":"reverseRecord","type":"uint8"},{"name":"referrer","type":"bytes32"}]}],"outputs":[]},
+{"type":"function","name":"renew","stateMutability":"payable","inputs":[{"name":"label","type":"string"},{"name":"duration","type":"uint256"},{"name":"referrer","type":"bytes32"}],"outputs":[]}
+]`
+
+// referralRegistration is the registration request of registrar controllers
+// that accept a referrer.
+type referralRegistration struct {
+	Label         string
+	Owner         common.Address
+	Duration      *big.Int
+	Secret        [32]byte
+	Resolver      common.Address
+	Data          [][]byte
+	ReverseRecord uint8
+	Referrer      [32]byte
+}
+
+// l2ReferralControllerABI is the ABI of the functions of L2 registrar
+// controllers that accept a referrer.
+const l2ReferralControllerABI = `[
+{"type":"function","name":"register","stateMutability":"payable","inputs":[{"name":"request","type":"tuple","components":[{"name":"name","type":"string"},{"name":"owner","type":"address"},{"name":"duration","type":"uint256"},{"name":"resolver","type":"address"},{"name":"data","type":"bytes[]"},{"name":"reverseRecord","type":"bool"},{"name":"referrer","type":"bytes32"}]}],"outputs":[]},
+{"type":"function","name":"renew","stateMutability":"payable","inputs":[{"name":"name","type":"string"},{"name":"duration","type":"uint256"},{"name":"referrer","type":"bytes32"}],"outputs":[]}
+]`
+
+// l2ReferralRegistration is the registration request of L2 registrar
+// controllers that accept a referrer.
+type l2ReferralRegistration struct {
+	Name          string
+	Owner         common.Address
+	Duration      *big.Int
+	Resolver      common.Address
+	Data          [][]byte
+	ReverseRecord bool
+	Referrer      [32]byte
+}
+
+var (
+	_ ReferralRegistrar = (*RegistrarController)(nil)
+	_ ReferralRegistrar = (*L2Controller)(nil)
+)
+
+// referralABI is an ABI of controllers that accept a referrer, parsed on
+// first use.
+type referralABI struct {
+	json   string
+	once   sync.Once
+	parsed abi.ABI
+	err    error
+}
+
+var (
+	referralController   = &referralABI{json: referralControllerABI}
+	l2ReferralController = &referralABI{json: l2ReferralControllerABI}
+)
+
+// bind returns the controller at the given address bound to the ABI.
+func (a *referralABI) bind(address common.Address, backend bind.ContractBackend) (*bind.BoundContract, error) {
+	a.once.Do(func() {
+		a.parsed, a.err = abi.JSON(strings.NewReader(a.json))
+	})
+	if a.err != nil {
+		return nil, a.err
+	}
+
+	return bind.NewBoundContract(address, a.parsed, backend, backend, backend), nil
+}
+
+// referralContract returns the controller bound to the ABI of controllers
+// that accept a referrer.
+func (c *RegistrarController) referralContract() (*bind.BoundContract, error) {
+	return referralController.bind(c.ContractAddr, c.backend)
+}
+
+// newReferralRegistration creates the registration request for a controller
+// that accepts a referrer.
+func newReferralRegistration(name string, state *registrationSessionState, records [][]byte) referralRegistration {
+	registration := referralRegistration{
+		Label:    name,
+		Owner:    state.Owner,
+		Duration: big.NewInt(state.Duration),
+		Secret:   state.Secret,
+		Resolver: state.Resolver,
+		Data:     records,
+		Referrer: *state.Referrer,
+	}
+	if state.ReverseRecord {
+		// Set the Ethereum reverse record.
+		registration.ReverseRecord = 1
+	}
+
+	return registration
+}
+
+// makeReferralCommitment obtains the commitment for a registration with a
+// controller that accepts a referrer.
+func (c *RegistrarController) makeReferralCommitment(registration referralRegistration) ([32]byte, error) {
+	contract, err := c.referralContract()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	var out []interface{}
+	if err := contract.Call(nil, &out, "makeCommitment", registration); err != nil {
+		return [32]byte{}, err
+	}
+	if len(out) != 1 {
+		return [32]byte{}, errors.New("unexpected commitment result")
+	}
+
+	return *abi.ConvertType(out[0], new([32]byte)).(*[32]byte), nil
+}
+
+// RegisterWithReferrer registers a domain with a controller that accepts a
+// referrer, crediting the referrer with the registration.  As with Register
+// this sends the commitment and waits for it to mature before sending the
+// registration.
+func (c *RegistrarController) RegisterWithReferrer(opts *bind.TransactOpts,
+	domain string,
+	owner common.Address,
+	duration time.Duration,
+	resolver common.Address,
+	data [][]byte,
+	reverseRecord bool,
+	referrer [32]byte,
+) (
+	*types.Transaction,
+	error,
+) {
+	return c.register(opts, domain, owner, duration, resolver, data, reverseRecord, &referrer)
+}
+
+// RenewWithReferrer renews a domain with a controller that accepts a
+// referrer, crediting the referrer with the renewal.  If opts.Value is not set
+// it is set to the current renewal price.
+func (c *RegistrarController) RenewWithReferrer(opts *bind.TransactOpts, domain string, duration time.Duration, referrer [32]byte) (*types.Transaction, error) {
+	name, opts, err := c.renewOpts(opts, domain, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := c.referralContract()
+	if err != nil {
+		return nil, err
+	}
+	tx, err := contract.Transact(opts, "renew", name, big.NewInt(int64(duration.Seconds())), referrer)
+	if err != nil {
+		return nil, revertError(err)
+	}
+
+	return tx, nil
+}
+
+// RegisterWithReferrer registers a domain with an L2 controller that accepts
+// a referrer, crediting the referrer with the registration.
+func (c *L2Controller) RegisterWithReferrer(opts *bind.TransactOpts,
+	domain string,
+	owner common.Address,
+	duration time.Duration,
+	resolver common.Address,
+	data [][]byte,
+	reverseRecord bool,
+	referrer [32]byte,
+) (
+	*types.Transaction,
+	error,
+) {
+	name, err := c.checkRegistration(opts, domain, duration, resolver, data)
+	if err != nil {
+		return nil, err
+	}
+
+	contract, err := l2ReferralController.bind(c.ContractAddr, c.backend)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := contract.Transact(opts, "register", l2ReferralRegistration{
+		Name:          name,
+		Owner:         owner,
+		Duration:      big.NewInt(int64(duration.Seconds())),
+		Resolver:      resolver,
+		Data:          data,
+		ReverseRecord: reverseRecord,
+		Referrer:      referrer,
+	})
+	if err != nil {
+		return nil, revertError(err)
+	}
+
+	return tx, nil
+}
+
+// RenewWithReferrer renews a domain with an L2 controller that accepts a
+// referrer, crediting the referrer with the renewal.
+func (c *L2Controller) RenewWithReferrer(opts *bind.TransactOpts, domain string, duration time.Duration, referrer [32]byte) (*types.Transaction, error) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s", domain)
+	}
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+
+	contract, err := l2ReferralController.bind(c.ContractAddr, c.backend)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := contract.Transact(opts, "renew", name, big.NewInt(int64(duration.Seconds())), referrer)
+	if err != nil {
+		return nil, revertError(err)
+	}
+
+	return tx, nil
+}
+
+// SetReferrer sets the referrer credited with the registration, for
+// controllers that accept a referrer.  This changes the commitment, so must
+// be called before the commitment is sent.
+func (s *RegistrationSession) SetReferrer(referrer [32]byte) error {
+	if s.state.Committed != nil {
+		return errors.New("commitment already sent")
+	}
+	name, err := UnqualifiedName(s.state.Domain, s.controller.domain)
+	if err != nil {
+		return err
+	}
+
+	state := *s.state
+	state.Referrer = (*common.Hash)(&referrer)
+	commitment, err := s.controller.makeReferralCommitment(newReferralRegistration(name, &state, s.records()))
+	if err != nil {
+		return err
+	}
+	state.Commitment = commitment
+	s.state = &state
+
+	return s.save()
+}
+
+// Referrer returns the referrer credited with the registration, if any.
+func (s *RegistrationSession) Referrer() ([32]byte, bool) {
+	if s.state.Referrer == nil {
+		return [32]byte{}, false
+	}
+
+	return *s.state.Referrer, true
+}
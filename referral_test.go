@@ -0,0 +1,291 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/l2controller"
+)
+
+// addReferralMethods adds the methods of controllers that accept a referrer
+// to a mock controller, with names prefixed by "referral".
+func addReferralMethods(t *testing.T, contract *mockContract, referralABI string, calls map[string][]interface{}) {
+	t.Helper()
+	parsed, err := abi.JSON(strings.NewReader(referralABI))
+	require.NoError(t, err)
+	for name, method := range parsed.Methods {
+		method.Name = "referral" + strings.ToUpper(name[:1]) + name[1:]
+		contract.abi.Methods[method.Name] = method
+	}
+	contract.
+		on("referralMakeCommitment", func(args []interface{}) ([]interface{}, error) {
+			calls["referralMakeCommitment"] = args
+			return []interface{}{[32]byte{0x02}}, nil
+		}).
+		on("referralRegister", func(args []interface{}) ([]interface{}, error) {
+			calls["referralRegister"] = args
+			return []interface{}{}, nil
+		}).
+		on("referralRenew", func(args []interface{}) ([]interface{}, error) {
+			calls["referralRenew"] = args
+			return []interface{}{}, nil
+		})
+}
+
+func TestReferrerFromAddress(t *testing.T) {
+	address := common.HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+	require.Equal(t, common.HexToHash("0x0000000000000000000000005aaeb6053f3e94c9b9a09f33669435e7ef1beaed"), common.Hash(ReferrerFromAddress(address)))
+}
+
+func TestRegistrationSessionReferrer(t *testing.T) {
+	m := newMockRegistrarController(t, false)
+	m.premium = big.NewInt(5)
+	controller, calls := m.controller, m.calls
+	addReferralMethods(t, m.contract(), referralControllerABI, calls)
+	store, err := NewFileRegistrationStore(t.TempDir())
+	require.NoError(t, err)
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	resolver := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	referrer := ReferrerFromAddress(common.HexToAddress("0x0000000000000000000000000000000000000003"))
+
+	session, err := controller.NewRegistrationSession(store, "foo.eth", owner, 365*24*time.Hour, resolver, nil, true)
+	require.NoError(t, err)
+	_, exists := session.Referrer()
+	require.False(t, exists)
+	require.Equal(t, common.Hash{0x01}, session.Commitment())
+
+	// Setting the referrer changes the commitment, which is persisted.
+	require.NoError(t, session.SetReferrer(referrer))
+	require.Equal(t, common.Hash{0x02}, session.Commitment())
+	registration := abi.ConvertType(calls["referralMakeCommitment"][0], referralRegistration{}).(referralRegistration)
+	require.Equal(t, "foo", registration.Label)
+	require.Equal(t, referrer, registration.Referrer)
+	require.Equal(t, uint8(1), registration.ReverseRecord)
+	resumed, err := controller.ResumeRegistrationSession(store, "foo.eth")
+	require.NoError(t, err)
+	resumedReferrer, exists := resumed.Referrer()
+	require.True(t, exists)
+	require.Equal(t, referrer, resumedReferrer)
+	require.Equal(t, common.Hash{0x02}, resumed.Commitment())
+
+	_, err = resumed.Commit(testTransactOpts(t))
+	require.NoError(t, err)
+	require.Equal(t, [32]byte{0x02}, calls["commit"][0])
+	require.EqualError(t, resumed.SetReferrer(referrer), "commitment already sent")
+
+	m.committed.Store(time.Now().Add(-2 * time.Minute).Unix())
+	tx, err := resumed.Register(testTransactOpts(t))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10_500_000_000_000_006), tx.Value())
+	require.Nil(t, calls["register"])
+	registration = abi.ConvertType(calls["referralRegister"][0], referralRegistration{}).(referralRegistration)
+	require.Equal(t, owner, registration.Owner)
+	require.Equal(t, resumed.state.Secret, common.Hash(registration.Secret))
+	require.Equal(t, referrer, registration.Referrer)
+}
+
+func TestRenewWithReferrer(t *testing.T) {
+	m := newMockRegistrarController(t, false)
+	controller, calls := m.controller, m.calls
+	addReferralMethods(t, m.contract(), referralControllerABI, calls)
+	referrer := ReferrerFromAddress(common.HexToAddress("0x0000000000000000000000000000000000000003"))
+
+	_, err := controller.RenewWithReferrer(nil, "foo.eth", 365*24*time.Hour, referrer)
+	require.EqualError(t, err, "transaction options required")
+	_, err = controller.RenewWithReferrer(testTransactOpts(t), "foo.xyz", 365*24*time.Hour, referrer)
+	require.EqualError(t, err, "invalid name foo.xyz")
+
+	tx, err := controller.RenewWithReferrer(testTransactOpts(t), "foo.eth", 365*24*time.Hour, referrer)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10_000_000_000_000_000), tx.Value())
+	require.Equal(t, "foo", calls["referralRenew"][0])
+	require.Equal(t, big.NewInt(365*24*60*60), calls["referralRenew"][1])
+	require.Equal(t, referrer, calls["referralRenew"][2])
+}
+
+func TestRegistrarControllerRegister(t *testing.T) {
+	m := newMockRegistrarController(t, false)
+	m.premium = big.NewInt(5)
+	controller, calls := m.controller, m.calls
+	addReferralMethods(t, m.contract(), referralControllerABI, calls)
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	resolver := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	referrer := ReferrerFromAddress(common.HexToAddress("0x0000000000000000000000000000000000000003"))
+	year := 365 * 24 * time.Hour
+
+	var registrar ReferralRegistrar = controller
+	_, err := registrar.Register(nil, "foo.eth", owner, year, resolver, nil, true)
+	require.EqualError(t, err, "transaction options required")
+	_, err = registrar.Register(testTransactOpts(t), "foo.xyz", owner, year, resolver, nil, true)
+	require.EqualError(t, err, "invalid name foo.xyz")
+
+	// The commitment is not mined before the context ends.
+	opts := testTransactOpts(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	opts.Context = ctx
+	_, err = registrar.Register(opts, "foo.eth", owner, year, resolver, nil, true)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.NotNil(t, calls["commit"])
+	require.Nil(t, calls["register"])
+
+	m.committed.Store(time.Now().Add(-2 * time.Minute).Unix())
+	tx, err := registrar.Register(testTransactOpts(t), "foo.eth", owner, year, resolver, nil, true)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10_500_000_000_000_006), tx.Value())
+	require.Equal(t, "foo", calls["register"][0])
+	require.Equal(t, owner, calls["register"][1])
+	require.Nil(t, calls["referralRegister"])
+
+	tx, err = registrar.RegisterWithReferrer(testTransactOpts(t), "bar.eth", owner, year, resolver, nil, true, referrer)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10_500_000_000_000_006), tx.Value())
+	registration := abi.ConvertType(calls["referralRegister"][0], referralRegistration{}).(referralRegistration)
+	require.Equal(t, "bar", registration.Label)
+	require.Equal(t, owner, registration.Owner)
+	require.Equal(t, referrer, registration.Referrer)
+	commitment := abi.ConvertType(calls["referralMakeCommitment"][0], referralRegistration{}).(referralRegistration)
+	require.Equal(t, commitment.Secret, registration.Secret)
+}
+
+func TestRegistrarControllerRenew(t *testing.T) {
+	m := newMockRegistrarController(t, false)
+	controller, calls := m.controller, m.calls
+
+	_, err := controller.Renew(nil, "foo.eth", 365*24*time.Hour)
+	require.EqualError(t, err, "transaction options required")
+
+	tx, err := controller.Renew(testTransactOpts(t), "foo.eth", 365*24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10_000_000_000_000_000), tx.Value())
+	require.Equal(t, "foo", calls["renew"][0])
+	require.Equal(t, big.NewInt(365*24*60*60), calls["renew"][1])
+}
+
+func TestL2ControllerReferrer(t *testing.T) {
+	calls := make(map[string][]interface{})
+	backend := newMockBackend()
+	config := ChainConfigFor(BaseMainnet)
+	contract := backend.deploy(config.L2Controller, l2controller.ContractABI).
+		on("MIN_REGISTRATION_DURATION", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(28 * 24 * 60 * 60)}, nil
+		}).
+		on("registerPrice", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{big.NewInt(1000)}, nil
+		})
+	addReferralMethods(t, contract, l2ReferralControllerABI, calls)
+	controller, err := NewL2Controller(backend, BaseMainnet)
+	require.NoError(t, err)
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	referrer := ReferrerFromAddress(common.HexToAddress("0x0000000000000000000000000000000000000003"))
+	year := 365 * 24 * time.Hour
+
+	var registrar ReferralRegistrar = controller
+	opts := testTransactOpts(t)
+	_, err = registrar.RegisterWithReferrer(opts, "foo.base.eth", owner, year, UnknownAddress, nil, false, referrer)
+	require.EqualError(t, err, "not enough funds to cover registration price of 1000 wei")
+
+	opts.Value = big.NewInt(1000)
+	_, err = registrar.RegisterWithReferrer(opts, "foo.base.eth", owner, year, UnknownAddress, nil, true, referrer)
+	require.NoError(t, err)
+	registration := abi.ConvertType(calls["referralRegister"][0], l2ReferralRegistration{}).(l2ReferralRegistration)
+	require.Equal(t, "foo", registration.Name)
+	require.Equal(t, owner, registration.Owner)
+	require.True(t, registration.ReverseRecord)
+	require.Equal(t, referrer, registration.Referrer)
+
+	_, err = registrar.RenewWithReferrer(nil, "foo.base.eth", year, referrer)
+	require.EqualError(t, err, "transaction options required")
+	_, err = registrar.RenewWithReferrer(opts, "foo.base.eth", year, referrer)
+	require.NoError(t, err)
+	require.Equal(t, "foo", calls["referralRenew"][0])
+	require.Equal(t, referrer, calls["referralRenew"][2])
+	require.Len(t, backend.sent, 2)
+}
+
+// mockReferralRegistrar is a TLD registrar that records the referrers it
+// is given.
+type mockReferralRegistrar struct {
+	mockTLDRegistrar
+	referrers [][32]byte
+}
+
+func (r *mockReferralRegistrar) RegisterWithReferrer(opts *bind.TransactOpts,
+	domain string,
+	owner common.Address,
+	duration time.Duration,
+	resolver common.Address,
+	data [][]byte,
+	reverseRecord bool,
+	referrer [32]byte,
+) (
+	*types.Transaction,
+	error,
+) {
+	r.referrers = append(r.referrers, referrer)
+	return r.Register(opts, domain, owner, duration, resolver, data, reverseRecord)
+}
+
+func (r *mockReferralRegistrar) RenewWithReferrer(opts *bind.TransactOpts, domain string, duration time.Duration, referrer [32]byte) (*types.Transaction, error) {
+	r.referrers = append(r.referrers, referrer)
+	return r.Renew(opts, domain, duration)
+}
+
+func TestClientReferrer(t *testing.T) {
+	referralRegistrar := &mockReferralRegistrar{}
+	require.NoError(t, RegisterTLDHandler(EthereumMainnet, &TLDHandler{
+		TLD: "ref",
+		Registrar: func(_ bind.ContractBackend) (TLDRegistrar, error) {
+			return referralRegistrar, nil
+		},
+	}))
+	defer UnregisterTLDHandler(EthereumMainnet, "ref")
+	require.NoError(t, RegisterTLDHandler(EthereumMainnet, &TLDHandler{
+		TLD: "noref",
+		Registrar: func(_ bind.ContractBackend) (TLDRegistrar, error) {
+			return &mockTLDRegistrar{}, nil
+		},
+	}))
+	defer UnregisterTLDHandler(EthereumMainnet, "noref")
+
+	referrer := ReferrerFromAddress(common.HexToAddress("0x0000000000000000000000000000000000000003"))
+	client, err := NewClient(newMockBackend(), WithReferrer(referrer))
+	require.NoError(t, err)
+	opts := testTransactOpts(t)
+	year := 365 * 24 * time.Hour
+
+	_, err = client.Register(opts, "foo.ref", opts.From, year, UnknownAddress, nil, false)
+	require.NoError(t, err)
+	_, err = client.Renew(opts, "foo.ref", year)
+	require.NoError(t, err)
+	require.Equal(t, []string{"foo.ref"}, referralRegistrar.registered)
+	require.Equal(t, []string{"foo.ref"}, referralRegistrar.renewed)
+	require.Equal(t, [][32]byte{referrer, referrer}, referralRegistrar.referrers)
+
+	_, err = client.Register(opts, "foo.noref", opts.From, year, UnknownAddress, nil, false)
+	require.EqualError(t, err, "registrar for foo.noref does not accept a referrer")
+	_, err = client.Renew(opts, "foo.noref", year)
+	require.EqualError(t, err, "registrar for foo.noref does not accept a referrer")
+}
@@ -24,7 +24,7 @@ import (
 // RegistrarContractAddress obtains the registrar contract address for a given domain.
 func RegistrarContractAddress(backend bind.ContractBackend, domain string, chainId ChainId) (common.Address, error) {
 	// Obtain a registry contract.
-	registry, err := NewRegistry(backend, chainId)
+	registry, err := registryFor(backend, domain, chainId)
 	if err != nil {
 		return UnknownAddress, err
 	}
@@ -0,0 +1,181 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wealdtech/go-ens/v3/contracts/ethregistrarcontroller"
+)
+
+// RegistrarController is the structure for the .eth registrar controller that
+// accepts resolver records and reverse records at registration.  It uses the
+// same commit/reveal process as ETHController.
+type RegistrarController struct {
+	backend      bind.ContractBackend
+	Contract     *ethregistrarcontroller.Contract
+	ContractAddr common.Address
+	domain       string
+}
+
+// NewRegistrarController creates a new registrar controller for a given chain.
+func NewRegistrarController(backend bind.ContractBackend, chainId ChainId) (*RegistrarController, error) {
+	config := ChainConfigFor(chainId)
+	if config.ChainId != chainId || config.RegistrarController == UnknownAddress {
+		return nil, fmt.Errorf("no registrar controller for chain %d", chainId)
+	}
+
+	return NewRegistrarControllerAt(backend, config.Root, config.RegistrarController)
+}
+
+// NewRegistrarControllerAt creates a registrar controller for a given domain at a given address.
+func NewRegistrarControllerAt(backend bind.ContractBackend, domain string, address common.Address) (*RegistrarController, error) {
+	contract, err := ethregistrarcontroller.NewContract(address, backend)
+	if err != nil {
+		return nil, err
+	}
+	return &RegistrarController{
+		backend:      backend,
+		Contract:     contract,
+		ContractAddr: address,
+		domain:       domain,
+	}, nil
+}
+
+// IsValid returns true if the domain is considered valid by the controller.
+func (c *RegistrarController) IsValid(domain string) (bool, error) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return false, fmt.Errorf("invalid name %s", domain)
+	}
+	return c.Contract.Valid(nil, name)
+}
+
+// IsAvailable returns true if the domain is available for registration.
+func (c *RegistrarController) IsAvailable(domain string) (bool, error) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return false, fmt.Errorf("invalid name %s", domain)
+	}
+	return c.Contract.Available(nil, name)
+}
+
+// MinRegistrationDuration returns the minimum duration for which a name can be registered.
+func (c *RegistrarController) MinRegistrationDuration() (time.Duration, error) {
+	tmp, err := c.Contract.MINREGISTRATIONDURATION(nil)
+	if err != nil {
+		return 0 * time.Second, err
+	}
+
+	return time.Duration(tmp.Int64()) * time.Second, nil
+}
+
+// RentPrice returns the base price and premium in wei to register or renew
+// the domain for the given duration.
+func (c *RegistrarController) RentPrice(domain string, duration time.Duration) (*big.Int, *big.Int, error) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid name %s", domain)
+	}
+	price, err := c.Contract.RentPrice(nil, name, big.NewInt(int64(duration.Seconds())))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return price.Base, price.Premium, nil
+}
+
+// Register registers a domain, sending the commitment, waiting for it to
+// mature and then sending the registration.  This takes at least the
+// controller's minimum commitment age, and can be bounded by opts.Context;
+// use NewRegistrationSession to send the commitment and registration
+// separately.  If opts.Value is not set it is set to the current
+// registration price.
+func (c *RegistrarController) Register(opts *bind.TransactOpts,
+	domain string,
+	owner common.Address,
+	duration time.Duration,
+	resolver common.Address,
+	data [][]byte,
+	reverseRecord bool,
+) (
+	*types.Transaction,
+	error,
+) {
+	return c.register(opts, domain, owner, duration, resolver, data, reverseRecord, nil)
+}
+
+// Renew renews a registered domain.  If opts.Value is not set it is set to
+// the current renewal price.
+func (c *RegistrarController) Renew(opts *bind.TransactOpts, domain string, duration time.Duration) (*types.Transaction, error) {
+	name, opts, err := c.renewOpts(opts, domain, duration)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.Contract.Renew(opts, name, big.NewInt(int64(duration.Seconds())))
+	if err != nil {
+		return nil, revertError(err)
+	}
+
+	return tx, nil
+}
+
+// renewOpts checks a renewal, returning the name to renew and the
+// transaction options with the value set to the renewal price if it was not
+// already set.
+func (c *RegistrarController) renewOpts(opts *bind.TransactOpts, domain string, duration time.Duration) (string, *bind.TransactOpts, error) {
+	if opts == nil {
+		return "", nil, errors.New("transaction options required")
+	}
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid name %s", domain)
+	}
+	if opts.Value == nil {
+		base, _, err := c.RentPrice(domain, duration)
+		if err != nil {
+			return "", nil, err
+		}
+		renewOpts := *opts
+		renewOpts.Value = base
+		opts = &renewOpts
+	}
+
+	return name, opts, nil
+}
+
+// RentPriceUSD returns the base price and premium in USD to register or renew
+// the domain for the given duration.  The prices are converted from wei using
+// the ETH/USD rate of the controller's price oracle, as used by the controller
+// to convert its USD prices to wei, so are the USD prices that it charges.
+func (c *RegistrarController) RentPriceUSD(domain string, duration time.Duration) (*big.Float, *big.Float, error) {
+	base, premium, err := c.RentPrice(domain, duration)
+	if err != nil {
+		return nil, nil, err
+	}
+	rate, decimals, err := c.usdRate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return weiToUSD(base, rate, decimals), weiToUSD(premium, rate, decimals), nil
+}
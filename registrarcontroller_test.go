@@ -0,0 +1,69 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRentPriceUSD(t *testing.T) {
+	year := 365 * 24 * time.Hour
+
+	tests := []struct {
+		name       string
+		withOracle bool
+		domain     string
+		base       string
+		premium    string
+		err        string
+	}{
+		{
+			name:       "InvalidName",
+			withOracle: true,
+			domain:     "foo.xyz",
+			err:        "invalid name foo.xyz",
+		},
+		{
+			name:   "NoOracle",
+			domain: "foo.eth",
+			err:    "no contract code at given address",
+		},
+		{
+			name:       "Good",
+			withOracle: true,
+			domain:     "foo.eth",
+			// 0.01 ETH at $2,000.
+			base:    "20.00",
+			premium: "0.00",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			controller := newMockRegistrarController(t, test.withOracle).controller
+			base, premium, err := controller.RentPriceUSD(test.domain, year)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.base, base.Text('f', 2))
+			require.Equal(t, test.premium, premium.Text('f', 2))
+		})
+	}
+}
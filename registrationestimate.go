@@ -0,0 +1,280 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wealdtech/go-ens/v3/contracts/ethregistrarcontroller"
+	"github.com/wealdtech/go-ens/v3/contracts/priceoracle"
+	"github.com/wealdtech/go-ens/v3/contracts/usdoracle"
+)
+
+// Approximate gas used by register(), used when it cannot be estimated
+// because no commitment has yet been made.
+const (
+	registerGasBase          = uint64(280_000)
+	registerGasPerRecord     = uint64(30_000)
+	registerGasReverseRecord = uint64(90_000)
+)
+
+// RegistrationEstimate is the estimated cost of registering a name.
+type RegistrationEstimate struct {
+	// CommitGas is the estimated gas used by the commit transaction.
+	CommitGas uint64
+	// RegisterGas is the estimated gas used by the register transaction.
+	RegisterGas uint64
+	// RegisterGasApproximate is true if RegisterGas could not be estimated
+	// by the backend and is an approximation.
+	RegisterGasApproximate bool
+	// GasPrice is the gas price used for the estimate, in wei.
+	GasPrice *big.Int
+	// BasePrice is the base registration price, in wei.
+	BasePrice *big.Int
+	// Premium is the premium for recently-expired names, in wei.
+	Premium *big.Int
+	// Price is the total registration price, in wei.
+	Price *big.Int
+	// GasCost is the cost of gas for both transactions, in wei.
+	GasCost *big.Int
+	// Total is the total amount of ETH required, in wei.
+	Total *big.Int
+	// PriceUSD is the registration price in USD.
+	// This is nil if the controller's price oracle does not provide a rate.
+	PriceUSD *big.Float
+	// TotalUSD is the total amount of ETH required in USD.
+	// This is nil if the controller's price oracle does not provide a rate.
+	TotalUSD *big.Float
+}
+
+// RenewalEstimate is the estimated cost of renewing a name.
+type RenewalEstimate struct {
+	// RenewGas is the estimated gas used by the renew transaction.
+	RenewGas uint64
+	// GasPrice is the gas price used for the estimate, in wei.
+	GasPrice *big.Int
+	// Price is the renewal price, in wei.
+	Price *big.Int
+	// GasCost is the cost of gas for the transaction, in wei.
+	GasCost *big.Int
+	// Total is the total amount of ETH required, in wei.
+	Total *big.Int
+	// PriceUSD is the renewal price in USD.
+	// This is nil if the controller's price oracle does not provide a rate.
+	PriceUSD *big.Float
+	// TotalUSD is the total amount of ETH required in USD.
+	// This is nil if the controller's price oracle does not provide a rate.
+	TotalUSD *big.Float
+}
+
+// EstimateRegistration estimates the full cost of registering a domain,
+// covering both the commit and register transactions.  No transactions are
+// sent.  resolver, records and reverseRecord are as they would be supplied
+// to the registration.
+func (c *RegistrarController) EstimateRegistration(domain string,
+	owner common.Address,
+	duration time.Duration,
+	resolver common.Address,
+	records [][]byte,
+	reverseRecord bool,
+) (
+	*RegistrationEstimate,
+	error,
+) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s", domain)
+	}
+	if (len(records) > 0 || reverseRecord) && resolver == UnknownAddress {
+		return nil, errors.New("resolver required to set records")
+	}
+	minDuration, err := c.MinRegistrationDuration()
+	if err != nil {
+		return nil, err
+	}
+	if duration < minDuration {
+		return nil, fmt.Errorf("duration less than minimum duration of %v", minDuration)
+	}
+
+	basePrice, premium, err := c.RentPrice(domain, duration)
+	if err != nil {
+		return nil, errors.New("failed to obtain registration price")
+	}
+	price := new(big.Int).Add(basePrice, premium)
+
+	var secret [32]byte
+	if _, err := rand.Read(secret[:]); err != nil {
+		return nil, err
+	}
+	commitment, err := c.Contract.MakeCommitment(nil, name, owner, big.NewInt(int64(duration.Seconds())), secret, resolver, records, reverseRecord, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := context.Background()
+	commitGas, err := c.estimateGas(ctx, owner, nil, "commit", commitment)
+	if err != nil {
+		return nil, err
+	}
+	// The register transaction reverts without a matured commitment, in which
+	// case the gas used is approximated.
+	registerGas, err := c.estimateGas(ctx, owner, price, "register", name, owner, big.NewInt(int64(duration.Seconds())), secret, resolver, records, reverseRecord, uint16(0))
+	approximate := false
+	if err != nil {
+		registerGas = registerGasBase + registerGasPerRecord*uint64(len(records))
+		if reverseRecord {
+			registerGas += registerGasReverseRecord
+		}
+		approximate = true
+	}
+
+	gasPrice, err := c.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gasCost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(commitGas+registerGas))
+	total := new(big.Int).Add(price, gasCost)
+
+	estimate := &RegistrationEstimate{
+		CommitGas:              commitGas,
+		RegisterGas:            registerGas,
+		RegisterGasApproximate: approximate,
+		GasPrice:               gasPrice,
+		BasePrice:              basePrice,
+		Premium:                premium,
+		Price:                  price,
+		GasCost:                gasCost,
+		Total:                  total,
+	}
+	if rate, decimals, err := c.usdRate(); err == nil {
+		estimate.PriceUSD = weiToUSD(price, rate, decimals)
+		estimate.TotalUSD = weiToUSD(total, rate, decimals)
+	}
+
+	return estimate, nil
+}
+
+// EstimateRenewal estimates the full cost of renewing a domain.  No
+// transactions are sent.
+func (c *RegistrarController) EstimateRenewal(domain string, from common.Address, duration time.Duration) (*RenewalEstimate, error) {
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s", domain)
+	}
+
+	basePrice, premium, err := c.RentPrice(domain, duration)
+	if err != nil {
+		return nil, errors.New("failed to obtain renewal price")
+	}
+	price := new(big.Int).Add(basePrice, premium)
+
+	ctx := context.Background()
+	renewGas, err := c.estimateGas(ctx, from, price, "renew", name, big.NewInt(int64(duration.Seconds())))
+	if err != nil {
+		return nil, err
+	}
+	gasPrice, err := c.backend.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, err
+	}
+	gasCost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(renewGas))
+	total := new(big.Int).Add(price, gasCost)
+
+	estimate := &RenewalEstimate{
+		RenewGas: renewGas,
+		GasPrice: gasPrice,
+		Price:    price,
+		GasCost:  gasCost,
+		Total:    total,
+	}
+	if rate, decimals, err := c.usdRate(); err == nil {
+		estimate.PriceUSD = weiToUSD(price, rate, decimals)
+		estimate.TotalUSD = weiToUSD(total, rate, decimals)
+	}
+
+	return estimate, nil
+}
+
+// estimateGas estimates the gas used by a call to the controller.
+func (c *RegistrarController) estimateGas(ctx context.Context, from common.Address, value *big.Int, method string, args ...interface{}) (uint64, error) {
+	parsed, err := ethregistrarcontroller.ContractMetaData.GetAbi()
+	if err != nil {
+		return 0, err
+	}
+	data, err := parsed.Pack(method, args...)
+	if err != nil {
+		return 0, err
+	}
+
+	gas, err := c.backend.EstimateGas(ctx, ethereum.CallMsg{
+		From:  from,
+		To:    &c.ContractAddr,
+		Value: value,
+		Data:  data,
+	})
+	if err != nil {
+		return 0, revertError(err)
+	}
+
+	return gas, nil
+}
+
+// usdRate obtains the USD price of 1 ETH from the controller's price oracle,
+// along with the number of decimals in the price.
+func (c *RegistrarController) usdRate() (*big.Int, uint8, error) {
+	oracleAddr, err := c.Contract.Prices(nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	oracle, err := priceoracle.NewContract(oracleAddr, c.backend)
+	if err != nil {
+		return nil, 0, err
+	}
+	usdOracleAddr, err := oracle.UsdOracle(nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	usdOracle, err := usdoracle.NewContract(usdOracleAddr, c.backend)
+	if err != nil {
+		return nil, 0, err
+	}
+	rate, err := usdOracle.LatestAnswer(nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if rate.Sign() <= 0 {
+		return nil, 0, errors.New("invalid USD rate")
+	}
+	decimals, err := usdOracle.Decimals(nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return rate, decimals, nil
+}
+
+// weiToUSD converts an amount in wei to USD given the USD price of 1 ETH.
+func weiToUSD(wei *big.Int, rate *big.Int, decimals uint8) *big.Float {
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(18+int64(decimals)), nil)
+	usd := new(big.Float).SetInt(new(big.Int).Mul(wei, rate))
+	return usd.Quo(usd, new(big.Float).SetInt(scale))
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateRegistration(t *testing.T) {
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	resolver := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	year := 365 * 24 * time.Hour
+
+	tests := []struct {
+		name          string
+		withOracle    bool
+		domain        string
+		duration      time.Duration
+		resolver      common.Address
+		records       [][]byte
+		reverseRecord bool
+		registerGas   uint64
+		total         *big.Int
+		totalUSD      string
+		err           string
+	}{
+		{
+			name:     "InvalidName",
+			domain:   "foo.xyz",
+			duration: year,
+			err:      "invalid name foo.xyz",
+		},
+		{
+			name:     "ShortDuration",
+			domain:   "foo.eth",
+			duration: time.Hour,
+			err:      "duration less than minimum duration of 672h0m0s",
+		},
+		{
+			name:     "RecordsWithoutResolver",
+			domain:   "foo.eth",
+			duration: year,
+			records:  [][]byte{{0x01}},
+			err:      "resolver required to set records",
+		},
+		{
+			name:        "Plain",
+			withOracle:  true,
+			domain:      "foo.eth",
+			duration:    year,
+			registerGas: 280_000,
+			// 0.01 ETH plus 380,000 gas at 2 gwei.
+			total:    big.NewInt(10_760_000_000_000_000),
+			totalUSD: "21.52",
+		},
+		{
+			name:          "RecordsAndReverse",
+			withOracle:    true,
+			domain:        "foo.eth",
+			duration:      year,
+			resolver:      resolver,
+			records:       [][]byte{{0x01}},
+			reverseRecord: true,
+			registerGas:   400_000,
+			// 0.01 ETH plus 500,000 gas at 2 gwei.
+			total:    big.NewInt(11_000_000_000_000_000),
+			totalUSD: "22.00",
+		},
+		{
+			name:        "NoOracle",
+			domain:      "foo.eth",
+			duration:    year,
+			registerGas: 280_000,
+			total:       big.NewInt(10_760_000_000_000_000),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockRegistrarController(t, test.withOracle)
+			controller := m.controller
+
+			estimate, err := controller.EstimateRegistration(test.domain, owner, test.duration, test.resolver, test.records, test.reverseRecord)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "foo", m.calls["rentPrice"][0])
+			require.Equal(t, uint64(100_000), estimate.CommitGas)
+			require.Equal(t, test.registerGas, estimate.RegisterGas)
+			require.True(t, estimate.RegisterGasApproximate)
+			require.Equal(t, big.NewInt(10_000_000_000_000_000), estimate.Price)
+			require.Equal(t, test.total, estimate.Total)
+			if test.totalUSD == "" {
+				require.Nil(t, estimate.PriceUSD)
+				require.Nil(t, estimate.TotalUSD)
+			} else {
+				require.Equal(t, "20.00", estimate.PriceUSD.Text('f', 2))
+				require.Equal(t, test.totalUSD, estimate.TotalUSD.Text('f', 2))
+			}
+		})
+	}
+}
+
+func TestEstimateRenewal(t *testing.T) {
+	m := newMockRegistrarController(t, true)
+	controller := m.controller
+
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	estimate, err := controller.EstimateRenewal("foo.eth", owner, 365*24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, "foo", m.calls["rentPrice"][0])
+	require.Equal(t, uint64(100_000), estimate.RenewGas)
+	require.Equal(t, big.NewInt(10_000_000_000_000_000), estimate.Price)
+	require.Equal(t, big.NewInt(10_200_000_000_000_000), estimate.Total)
+	require.Equal(t, "20.40", estimate.TotalUSD.Text('f', 2))
+
+	_, err = NewRegistrarController(newMockBackend(), LineaMainnet)
+	require.EqualError(t, err, "no registrar controller for chain 59144")
+}
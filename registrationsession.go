@@ -0,0 +1,509 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// registrationPollInterval is the interval at which the commitment is checked
+// whilst waiting to register.
+const registrationPollInterval = 5 * time.Second
+
+// DefaultPriceBufferPercent is the percentage added to the registration price
+// for the value of register transactions, so that they succeed if the price
+// rises before they are mined, for example as the ETH/USD rate changes.  The
+// controller refunds any value above the price.
+const DefaultPriceBufferPercent = 5
+
+// RegistrationStore persists the state of registration sessions so that they
+// can be resumed.  The state includes the registration secret, so should be
+// kept private.
+type RegistrationStore interface {
+	// Save saves the state of a session.
+	Save(key string, data []byte) error
+	// Load loads the state of a session, returning nil if there is none.
+	Load(key string) ([]byte, error)
+	// Delete deletes the state of a session.
+	Delete(key string) error
+}
+
+type fileRegistrationStore struct {
+	dir string
+}
+
+// NewFileRegistrationStore creates a registration store that keeps the state
+// of each session in its own file in the given directory.
+func NewFileRegistrationStore(dir string) (RegistrationStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	return &fileRegistrationStore{
+		dir: dir,
+	}, nil
+}
+
+func (s *fileRegistrationStore) Save(key string, data []byte) error {
+	return os.WriteFile(s.path(key), data, 0o600)
+}
+
+func (s *fileRegistrationStore) Load(key string) ([]byte, error) {
+	data, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	return data, err
+}
+
+func (s *fileRegistrationStore) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	return err
+}
+
+func (s *fileRegistrationStore) path(key string) string {
+	return filepath.Join(s.dir, url.PathEscape(key)+".json")
+}
+
+// memoryRegistrationStore is a registration store that keeps the state of
+// sessions in memory, for sessions that are not resumed.
+type memoryRegistrationStore struct {
+	mu       sync.Mutex
+	sessions map[string][]byte
+}
+
+func (s *memoryRegistrationStore) Save(key string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.sessions == nil {
+		s.sessions = make(map[string][]byte)
+	}
+	s.sessions[key] = data
+
+	return nil
+}
+
+func (s *memoryRegistrationStore) Load(key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.sessions[key], nil
+}
+
+func (s *memoryRegistrationStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, key)
+
+	return nil
+}
+
+// registrationSessionState is the persisted state of a registration session.
+type registrationSessionState struct {
+	Domain        string          `json:"domain"`
+	Owner         common.Address  `json:"owner"`
+	Duration      int64           `json:"duration"`
+	Resolver      common.Address  `json:"resolver"`
+	Records       []hexutil.Bytes `json:"records,omitempty"`
+	ReverseRecord bool            `json:"reverse_record,omitempty"`
+	Secret        common.Hash     `json:"secret"`
+	Commitment    common.Hash     `json:"commitment"`
+	Created       time.Time       `json:"created"`
+	Committed     *time.Time      `json:"committed,omitempty"`
+	CommitTx      *common.Hash    `json:"commit_tx,omitempty"`
+	Referrer      *common.Hash    `json:"referrer,omitempty"`
+}
+
+// RegistrationSession is a commit/reveal registration with the registrar
+// controller whose state is persisted to a store after each step, so that it
+// can be resumed with ResumeRegistrationSession if interrupted.
+type RegistrationSession struct {
+	controller *RegistrarController
+	store      RegistrationStore
+	state      *registrationSessionState
+	// priceBufferPercent is the percentage added to the price for the value
+	// of the register transaction.
+	priceBufferPercent uint64
+}
+
+// NewRegistrationSession starts a registration session for the given domain,
+// generating a secret and commitment and saving them to the store.  Any
+// existing session for the domain in the store is replaced.
+func (c *RegistrarController) NewRegistrationSession(store RegistrationStore,
+	domain string,
+	owner common.Address,
+	duration time.Duration,
+	resolver common.Address,
+	records [][]byte,
+	reverseRecord bool,
+) (
+	*RegistrationSession,
+	error,
+) {
+	if store == nil {
+		return nil, errors.New("no store supplied")
+	}
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s", domain)
+	}
+	if (len(records) > 0 || reverseRecord) && resolver == UnknownAddress {
+		return nil, errors.New("resolver required to set records")
+	}
+
+	state := &registrationSessionState{
+		Domain:        fmt.Sprintf("%s.%s", name, c.domain),
+		Owner:         owner,
+		Duration:      int64(duration.Seconds()),
+		Resolver:      resolver,
+		ReverseRecord: reverseRecord,
+		Created:       time.Now().UTC(),
+	}
+	for _, record := range records {
+		state.Records = append(state.Records, record)
+	}
+	if _, err := rand.Read(state.Secret[:]); err != nil {
+		return nil, err
+	}
+	session := &RegistrationSession{
+		controller:         c,
+		store:              store,
+		state:              state,
+		priceBufferPercent: DefaultPriceBufferPercent,
+	}
+	commitment, err := c.Contract.MakeCommitment(nil, name, owner, big.NewInt(state.Duration), state.Secret, resolver, session.records(), reverseRecord, 0)
+	if err != nil {
+		return nil, err
+	}
+	state.Commitment = commitment
+
+	if err := session.save(); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// ResumeRegistrationSession resumes the registration session for the given
+// domain from the store.  If the session's commitment is older than the
+// controller's maximum commitment age the session is deleted from the store
+// and ErrCommitmentExpired is returned, as the commitment can no longer be
+// used to register.
+func (c *RegistrarController) ResumeRegistrationSession(store RegistrationStore, domain string) (*RegistrationSession, error) {
+	if store == nil {
+		return nil, errors.New("no store supplied")
+	}
+	name, err := UnqualifiedName(domain, c.domain)
+	if err != nil {
+		return nil, fmt.Errorf("invalid name %s", domain)
+	}
+	key := fmt.Sprintf("%s.%s", name, c.domain)
+	data, err := store.Load(key)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, fmt.Errorf("no registration session for %s", key)
+	}
+	state := &registrationSessionState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("invalid registration session for %s: %w", key, err)
+	}
+	if state.Domain != key {
+		return nil, fmt.Errorf("registration session is for %s not %s", state.Domain, key)
+	}
+	session := &RegistrationSession{
+		controller:         c,
+		store:              store,
+		state:              state,
+		priceBufferPercent: DefaultPriceBufferPercent,
+	}
+
+	if _, err := session.committedAt(); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// Domain returns the domain being registered.
+func (s *RegistrationSession) Domain() string {
+	return s.state.Domain
+}
+
+// Commitment returns the commitment for the registration.
+func (s *RegistrationSession) Commitment() common.Hash {
+	return s.state.Commitment
+}
+
+// SetPriceBuffer sets the percentage added to the registration price for the
+// value of the register transaction if the value is not supplied.  It
+// defaults to DefaultPriceBufferPercent.
+func (s *RegistrationSession) SetPriceBuffer(percent uint64) {
+	s.priceBufferPercent = percent
+}
+
+// Committed returns true if the commitment has been sent.
+func (s *RegistrationSession) Committed() bool {
+	return s.state.Committed != nil
+}
+
+// Commit sends the commitment for the registration.  The commitment is only
+// sent once; if it expires a new session must be started.
+func (s *RegistrationSession) Commit(opts *bind.TransactOpts) (*types.Transaction, error) {
+	if s.state.Committed != nil {
+		return nil, errors.New("commitment already sent")
+	}
+
+	tx, err := s.controller.Contract.Commit(opts, s.state.Commitment)
+	if err != nil {
+		return nil, revertError(err)
+	}
+	committed := time.Now().UTC()
+	txHash := tx.Hash()
+	s.state.Committed = &committed
+	s.state.CommitTx = &txHash
+	if err := s.save(); err != nil {
+		return tx, err
+	}
+
+	return tx, nil
+}
+
+// ReadyAt returns the time from which the registration can be sent, being
+// the time at which the commitment was mined plus the controller's minimum
+// commitment age.
+func (s *RegistrationSession) ReadyAt() (time.Time, error) {
+	committedAt, err := s.committedAt()
+	if err != nil {
+		return time.Time{}, err
+	}
+	if committedAt.IsZero() {
+		return time.Time{}, errors.New("commitment not yet mined")
+	}
+	minAge, err := s.controller.Contract.MinCommitmentAge(nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return committedAt.Add(time.Duration(minAge.Int64()) * time.Second), nil
+}
+
+// Register sends the registration.  The commitment must have been mined and
+// be within the controller's commitment age window.  If a referrer has been
+// set it is credited with the registration.  If opts.Value is not set it is
+// set to the current registration price plus the session's price buffer, with
+// any excess refunded by the controller.  The session is deleted from the
+// store once the registration has been sent.
+func (s *RegistrationSession) Register(opts *bind.TransactOpts) (*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+	readyAt, err := s.ReadyAt()
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Before(readyAt) {
+		return nil, fmt.Errorf("commitment not yet mature; registration can be sent from %s", readyAt.UTC().Format(time.RFC3339))
+	}
+
+	name, err := UnqualifiedName(s.state.Domain, s.controller.domain)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Value == nil {
+		price, err := s.controller.Contract.RentPrice(&bind.CallOpts{Context: opts.Context}, name, big.NewInt(s.state.Duration))
+		if err != nil {
+			return nil, err
+		}
+		registerOpts := *opts
+		registerOpts.Value = bufferedPrice(new(big.Int).Add(price.Base, price.Premium), s.priceBufferPercent)
+		opts = &registerOpts
+	}
+
+	var tx *types.Transaction
+	if s.state.Referrer != nil {
+		var contract *bind.BoundContract
+		contract, err = s.controller.referralContract()
+		if err != nil {
+			return nil, err
+		}
+		tx, err = contract.Transact(opts, "register", newReferralRegistration(name, s.state, s.records()))
+	} else {
+		tx, err = s.controller.Contract.Register(opts, name, s.state.Owner, big.NewInt(s.state.Duration), s.state.Secret, s.state.Resolver, s.records(), s.state.ReverseRecord, 0)
+	}
+	if err != nil {
+		return nil, revertError(err)
+	}
+	if err := s.store.Delete(s.state.Domain); err != nil {
+		return tx, err
+	}
+
+	return tx, nil
+}
+
+// awaitReady waits until the commitment has been mined and has reached the
+// controller's minimum commitment age.
+func (s *RegistrationSession) awaitReady(ctx context.Context) error {
+	minAgeSecs, err := s.controller.Contract.MinCommitmentAge(&bind.CallOpts{Context: ctx})
+	if err != nil {
+		return err
+	}
+	minAge := time.Duration(minAgeSecs.Int64()) * time.Second
+	for {
+		committedAt, err := s.committedAt()
+		if err != nil {
+			return err
+		}
+		wait := registrationPollInterval
+		if !committedAt.IsZero() {
+			wait = time.Until(committedAt.Add(minAge))
+			if wait <= 0 {
+				return nil
+			}
+		}
+		if err := sleepContext(ctx, min(wait, registrationPollInterval)); err != nil {
+			return err
+		}
+	}
+}
+
+// register registers a domain in a single call, with a session that is
+// not persisted.
+func (c *RegistrarController) register(opts *bind.TransactOpts,
+	domain string,
+	owner common.Address,
+	duration time.Duration,
+	resolver common.Address,
+	data [][]byte,
+	reverseRecord bool,
+	referrer *[32]byte,
+) (
+	*types.Transaction,
+	error,
+) {
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+	session, err := c.NewRegistrationSession(&memoryRegistrationStore{}, domain, owner, duration, resolver, data, reverseRecord)
+	if err != nil {
+		return nil, err
+	}
+	if referrer != nil {
+		if err := session.SetReferrer(*referrer); err != nil {
+			return nil, err
+		}
+	}
+
+	nextOpts := sequentialOpts(opts)
+	commitOpts := nextOpts()
+	commitOpts.Value = nil
+	if _, err := session.Commit(commitOpts); err != nil {
+		return nil, err
+	}
+	ctx := opts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if err := session.awaitReady(ctx); err != nil {
+		return nil, err
+	}
+
+	return session.Register(nextOpts())
+}
+
+// bufferedPrice returns a price increased by a percentage, rounding up.
+func bufferedPrice(price *big.Int, percent uint64) *big.Int {
+	res := new(big.Int).Mul(price, new(big.Int).SetUint64(100+percent))
+	res.Add(res, big.NewInt(99))
+
+	return res.Div(res, big.NewInt(100))
+}
+
+// committedAt returns the time at which the commitment was mined, or the zero
+// time if it has not been mined.  If the commitment has expired the session
+// is deleted from the store and ErrCommitmentExpired is returned.
+func (s *RegistrationSession) committedAt() (time.Time, error) {
+	if s.state.Committed == nil {
+		return time.Time{}, nil
+	}
+	maxAgeSecs, err := s.controller.Contract.MaxCommitmentAge(nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	maxAge := time.Duration(maxAgeSecs.Int64()) * time.Second
+	timestamp, err := s.controller.Contract.Commitments(nil, s.state.Commitment)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// If the commitment has not been mined the time it was sent is the
+	// earliest it could be, so is used to check for expiry.
+	committedAt := *s.state.Committed
+	if timestamp.Sign() > 0 {
+		committedAt = time.Unix(timestamp.Int64(), 0)
+	}
+	if time.Now().After(committedAt.Add(maxAge)) {
+		if err := s.store.Delete(s.state.Domain); err != nil {
+			return time.Time{}, err
+		}
+		return time.Time{}, ErrCommitmentExpired
+	}
+	if timestamp.Sign() == 0 {
+		return time.Time{}, nil
+	}
+
+	return committedAt, nil
+}
+
+// records returns the records of the registration.
+func (s *RegistrationSession) records() [][]byte {
+	records := make([][]byte, len(s.state.Records))
+	for i := range s.state.Records {
+		records[i] = s.state.Records[i]
+	}
+
+	return records
+}
+
+// save saves the state of the session to the store.
+func (s *RegistrationSession) save() error {
+	data, err := json.Marshal(s.state)
+	if err != nil {
+		return err
+	}
+
+	return s.store.Save(s.state.Domain, data)
+}
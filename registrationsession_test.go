@@ -0,0 +1,128 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistrationSession(t *testing.T) {
+	m := newMockRegistrarController(t, false)
+	m.premium = big.NewInt(5)
+	controller, calls := m.controller, m.calls
+	store, err := NewFileRegistrationStore(t.TempDir())
+	require.NoError(t, err)
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	resolver := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	year := 365 * 24 * time.Hour
+
+	_, err = controller.NewRegistrationSession(nil, "foo.eth", owner, year, resolver, nil, false)
+	require.EqualError(t, err, "no store supplied")
+	_, err = controller.NewRegistrationSession(store, "foo.xyz", owner, year, resolver, nil, false)
+	require.EqualError(t, err, "invalid name foo.xyz")
+	_, err = controller.NewRegistrationSession(store, "foo.eth", owner, year, UnknownAddress, nil, true)
+	require.EqualError(t, err, "resolver required to set records")
+	_, err = controller.ResumeRegistrationSession(store, "foo.eth")
+	require.EqualError(t, err, "no registration session for foo.eth")
+
+	session, err := controller.NewRegistrationSession(store, "foo.eth", owner, year, resolver, [][]byte{{0x01, 0x02}}, true)
+	require.NoError(t, err)
+	require.Equal(t, "foo.eth", session.Domain())
+	require.Equal(t, common.Hash{0x01}, session.Commitment())
+	require.False(t, session.Committed())
+	_, err = session.Register(testTransactOpts(t))
+	require.EqualError(t, err, "commitment not yet mined")
+
+	// Resuming recovers the secret.
+	resumed, err := controller.ResumeRegistrationSession(store, "foo.eth")
+	require.NoError(t, err)
+	require.Equal(t, session.state.Secret, resumed.state.Secret)
+	require.Equal(t, [][]byte{{0x01, 0x02}}, resumed.records())
+
+	// Commit, and resume whilst the commitment is pending.
+	_, err = resumed.Commit(testTransactOpts(t))
+	require.NoError(t, err)
+	require.Equal(t, [32]byte{0x01}, calls["commit"][0])
+	_, err = resumed.Commit(testTransactOpts(t))
+	require.EqualError(t, err, "commitment already sent")
+	resumed, err = controller.ResumeRegistrationSession(store, "foo.eth")
+	require.NoError(t, err)
+	require.True(t, resumed.Committed())
+	_, err = resumed.Register(testTransactOpts(t))
+	require.EqualError(t, err, "commitment not yet mined")
+
+	// Mined, but not yet mature.
+	minedAt := time.Now().Add(-30 * time.Second).Unix()
+	m.committed.Store(minedAt)
+	_, err = resumed.Register(testTransactOpts(t))
+	require.EqualError(t, err, "commitment not yet mature; registration can be sent from "+time.Unix(minedAt+60, 0).UTC().Format(time.RFC3339))
+
+	// Mature.
+	m.committed.Store(time.Now().Add(-2 * time.Minute).Unix())
+	_, err = resumed.Register(nil)
+	require.EqualError(t, err, "transaction options required")
+	resumed.SetPriceBuffer(0)
+	tx, err := resumed.Register(testTransactOpts(t))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10_000_000_000_000_005), tx.Value())
+	require.Equal(t, "foo", calls["register"][0])
+	require.Equal(t, session.state.Secret, common.Hash(calls["register"][3].([32]byte)))
+	require.Equal(t, true, calls["register"][6])
+
+	// The session is removed once registered.
+	_, err = controller.ResumeRegistrationSession(store, "foo.eth")
+	require.EqualError(t, err, "no registration session for foo.eth")
+}
+
+func TestRegistrationSessionExpired(t *testing.T) {
+	m := newMockRegistrarController(t, false)
+	controller := m.controller
+	store, err := NewFileRegistrationStore(t.TempDir())
+	require.NoError(t, err)
+
+	session, err := controller.NewRegistrationSession(store, "foo.eth", common.Address{}, 365*24*time.Hour, UnknownAddress, nil, false)
+	require.NoError(t, err)
+	_, err = session.Commit(testTransactOpts(t))
+	require.NoError(t, err)
+
+	m.committed.Store(time.Now().Add(-25 * time.Hour).Unix())
+	_, err = controller.ResumeRegistrationSession(store, "foo.eth")
+	require.ErrorIs(t, err, ErrCommitmentExpired)
+	data, err := store.Load("foo.eth")
+	require.NoError(t, err)
+	require.Nil(t, data)
+	_, err = session.Register(testTransactOpts(t))
+	require.ErrorIs(t, err, ErrCommitmentExpired)
+}
+
+func TestRegistrationSessionInvalid(t *testing.T) {
+	m := newMockRegistrarController(t, false)
+	controller := m.controller
+	store, err := NewFileRegistrationStore(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save("foo.eth", []byte("not json")))
+	_, err = controller.ResumeRegistrationSession(store, "foo.eth")
+	require.ErrorContains(t, err, "invalid registration session for foo.eth")
+
+	require.NoError(t, store.Save("foo.eth", []byte(`{"domain":"bar.eth"}`)))
+	_, err = controller.ResumeRegistrationSession(store, "foo.eth")
+	require.EqualError(t, err, "registration session is for bar.eth not foo.eth")
+}
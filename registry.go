@@ -17,6 +17,7 @@ package ens
 import (
 	"errors"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
@@ -32,13 +33,9 @@ type ChainId uint64
 const (
 	EthereumMainnet ChainId = 1
 	BaseMainnet     ChainId = 8453
+	LineaMainnet    ChainId = 59144
 )
 
-var chainRegistryContractAddress map[ChainId]common.Address = map[ChainId]common.Address{
-	EthereumMainnet: common.HexToAddress("00000000000C2E074eC69A0dFb2997BA6C7d2e1e"),
-	BaseMainnet:     common.HexToAddress("b94704422c2a1e396835a571837aa5ae53285a95"),
-}
-
 // Registry is the structure for the registry contract.
 type Registry struct {
 	backend      bind.ContractBackend
@@ -47,8 +44,8 @@ type Registry struct {
 }
 
 // NewRegistry obtains the ENS registry.
-func NewRegistry(backend bind.ContractBackend, chainId ChainId) (*Registry, error) {
-	address, err := RegistryContractAddress(backend, chainId)
+func NewRegistry(backend Backend, chainId ChainId) (*Registry, error) {
+	address, err := RegistryContractAddress(asContractBackend(backend), chainId)
 	if err != nil {
 		return nil, err
 	}
@@ -56,13 +53,14 @@ func NewRegistry(backend bind.ContractBackend, chainId ChainId) (*Registry, erro
 }
 
 // NewRegistryAt obtains the ENS registry at a given address.
-func NewRegistryAt(backend bind.ContractBackend, address common.Address) (*Registry, error) {
-	contract, err := registry.NewContract(address, backend)
+func NewRegistryAt(backend Backend, address common.Address) (*Registry, error) {
+	contractBackend := asContractBackend(backend)
+	contract, err := registry.NewContract(address, contractBackend)
 	if err != nil {
 		return nil, err
 	}
 	return &Registry{
-		backend:      backend,
+		backend:      contractBackend,
 		Contract:     contract,
 		ContractAddr: address,
 	}, nil
@@ -92,7 +90,13 @@ func (r *Registry) SetResolver(opts *bind.TransactOpts, name string, address com
 	if err != nil {
 		return nil, err
 	}
-	return r.Contract.SetResolver(opts, nameHash, address)
+	tx, err := r.Contract.SetResolver(opts, nameHash, address)
+	if err != nil {
+		return nil, err
+	}
+	forgetResolver(nameHash)
+
+	return tx, nil
 }
 
 // Resolver returns the resolver for a name.
@@ -126,15 +130,39 @@ func (r *Registry) SetSubdomainOwner(opts *bind.TransactOpts, name string, subna
 	return r.Contract.SetSubnodeOwner(opts, nameHash, labelHash, address)
 }
 
-// RegistryContractAddress obtains the address of the registry contract for a chain.
-func RegistryContractAddress(_ bind.ContractBackend, chainId ChainId) (common.Address, error) {
-	address, ok := chainRegistryContractAddress[chainId]
-	if ok {
-		return address, nil
+// SetRecord sets the owner, resolver and TTL of a name in a single transaction.
+func (r *Registry) SetRecord(opts *bind.TransactOpts, name string, owner common.Address, resolver common.Address, ttl time.Duration) (*types.Transaction, error) {
+	nameHash, err := NameHash(name)
+	if err != nil {
+		return nil, err
+	}
+	return r.Contract.SetRecord(opts, nameHash, owner, resolver, uint64(ttl.Seconds()))
+}
+
+// SetSubdomainRecord sets the owner, resolver and TTL of a subdomain in a
+// single transaction, potentially creating it in the process.
+func (r *Registry) SetSubdomainRecord(opts *bind.TransactOpts, name string, subname string, owner common.Address, resolver common.Address, ttl time.Duration) (*types.Transaction, error) {
+	nameHash, err := NameHash(name)
+	if err != nil {
+		return nil, err
+	}
+	labelHash, err := LabelHash(subname)
+	if err != nil {
+		return nil, err
 	}
+	return r.Contract.SetSubnodeRecord(opts, nameHash, labelHash, owner, resolver, uint64(ttl.Seconds()))
+}
 
-	// Default to Ethereum mainnet.
-	return common.HexToAddress("00000000000C2E074eC69A0dFb2997BA6C7d2e1e"), nil
+// IsApprovedForAll returns true if the operator is approved to manage all
+// names owned by the owner.
+func (r *Registry) IsApprovedForAll(owner common.Address, operator common.Address) (bool, error) {
+	return r.Contract.IsApprovedForAll(nil, owner, operator)
+}
+
+// RegistryContractAddress obtains the address of the registry contract for a chain.
+// Chains without a registered configuration default to Ethereum mainnet.
+func RegistryContractAddress(_ bind.ContractBackend, chainId ChainId) (common.Address, error) {
+	return ChainConfigFor(chainId).Registry, nil
 }
 
 // RegistryContractFromRegistrar obtains the registry contract given an
@@ -156,7 +184,13 @@ func SetResolver(session *registry.ContractSession, name string, resolverAddr *c
 	if err != nil {
 		return nil, err
 	}
-	return session.SetResolver(nameHash, *resolverAddr)
+	tx, err := session.SetResolver(nameHash, *resolverAddr)
+	if err != nil {
+		return nil, err
+	}
+	forgetResolver(nameHash)
+
+	return tx, nil
 }
 
 // SetSubdomainOwner sets the owner for a subdomain of a name.
@@ -21,11 +21,13 @@ import (
 	"io"
 	"math/big"
 	"strings"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var zeroHash = make([]byte, 32)
@@ -37,35 +39,99 @@ var UnknownAddress = common.HexToAddress("00")
 type Resolver struct {
 	Contract     *resolver.Contract
 	ContractAddr common.Address
+	backend      bind.ContractBackend
 	domain       string
+	wildcard     bool
 }
 
 // NewResolver obtains an ENS resolver for a given domain.
-func NewResolver(backend bind.ContractBackend, domain string, chainId ChainId) (*Resolver, error) {
-	registry, err := NewRegistry(backend, chainId)
+func NewResolver(backend Backend, domain string, chainId ChainId, opts ...CallOption) (*Resolver, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	return newResolver(asContractBackend(backend), domain, chainId, o)
+}
+
+func newResolver(backend bind.ContractBackend, domain string, chainId ChainId, o *callOptions) (res *Resolver, err error) {
+	nameHash, err := NameHash(domain)
 	if err != nil {
 		return nil, err
 	}
+	ctx, span := startSpan(o.ctx, "ens.NewResolver", nameAttributes(domain, nameHash)...)
+	defer func() {
+		endSpan(span, err)
+	}()
+	o = o.withContext(ctx)
+
+	if o.historical() {
+		// Cached resolvers are for the latest state.
+	} else if address, wildcard, exists := cachedResolver(chainId, nameHash); exists {
+		span.SetAttributes(attribute.Bool("ens.cached", true))
+		return bindResolver(backend, domain, address, wildcard)
+	}
+
+	var resolver *Resolver
+	resolverAddress, err := lookupResolverAddress(backend, domain, nameHash, chainId, o)
+	switch {
+	case errors.Is(err, ErrUnregisteredName):
+		// The name may be resolved by the resolver of an ancestor.
+		var wildcardErr error
+		resolver, wildcardErr = newWildcardResolver(backend, domain, chainId, o)
+		if wildcardErr != nil {
+			return nil, err
+		}
+		span.SetAttributes(attribute.Bool("ens.wildcard", true))
+	case err != nil:
+		return nil, err
+	default:
+		fetchCtx, fetchSpan := startSpan(ctx, "ens.resolver.Fetch", attribute.String("ens.resolver", resolverAddress.Hex()))
+		resolver, err = newResolverAt(backend, domain, resolverAddress, o.withContext(fetchCtx))
+		endSpan(fetchSpan, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !o.historical() {
+		cacheResolver(chainId, nameHash, resolver.ContractAddr, resolver.wildcard)
+	}
+
+	return resolver, nil
+}
+
+// lookupResolverAddress obtains the resolver address for a registered node
+// from the registry that holds it.
+func lookupResolverAddress(backend bind.ContractBackend, domain string, nameHash [32]byte, chainId ChainId, o *callOptions) (address common.Address, err error) {
+	ctx, span := startSpan(o.ctx, "ens.registry.Lookup")
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	registry, err := registryFor(backend, domain, chainId)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	opts := o.withContext(ctx).callOpts()
 
 	// Ensure the name is registered.
-	ownerAddress, err := registry.Owner(domain)
+	ownerAddress, err := registry.Contract.Owner(opts, nameHash)
 	if err != nil {
-		return nil, err
+		return UnknownAddress, err
 	}
 	if bytes.Equal(ownerAddress.Bytes(), UnknownAddress.Bytes()) {
-		return nil, errors.New("unregistered name")
+		return UnknownAddress, ErrUnregisteredName
 	}
 
 	// Obtain the resolver address for this domain.
-	resolver, err := registry.ResolverAddress(domain)
-	if err != nil {
-		return nil, err
-	}
-	return NewResolverAt(backend, domain, resolver)
+	return registry.Contract.Resolver(opts, nameHash)
 }
 
 // NewResolverAt obtains an ENS resolver at a given address.
-func NewResolverAt(backend bind.ContractBackend, domain string, address common.Address) (*Resolver, error) {
+func NewResolverAt(backend bind.ContractBackend, domain string, address common.Address, opts ...CallOption) (*Resolver, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	return newResolverAt(backend, domain, address, o)
+}
+
+func newResolverAt(backend bind.ContractBackend, domain string, address common.Address, o *callOptions) (*Resolver, error) {
 	contract, err := resolver.NewContract(address, backend)
 	if err != nil {
 		return nil, err
@@ -76,10 +142,10 @@ func NewResolverAt(backend bind.ContractBackend, domain string, address common.A
 	if err != nil {
 		return nil, err
 	}
-	_, err = contract.Addr(nil, nameHash)
+	_, err = contract.Addr(o.callOpts(), nameHash)
 	if err != nil {
-		if err.Error() == "no contract code at given address" {
-			return nil, errors.New("no resolver")
+		if errors.Is(err, bind.ErrNoCode) {
+			return nil, ErrNoResolver
 		}
 		return nil, err
 	}
@@ -87,6 +153,7 @@ func NewResolverAt(backend bind.ContractBackend, domain string, address common.A
 	return &Resolver{
 		Contract:     contract,
 		ContractAddr: address,
+		backend:      backend,
 		domain:       domain,
 	}, nil
 }
@@ -97,12 +164,14 @@ func PublicResolverAddress(backend bind.ContractBackend, chainId ChainId) (commo
 }
 
 // Address returns the Ethereum address of the domain.
-func (r *Resolver) Address() (common.Address, error) {
+func (r *Resolver) Address(opts ...CallOption) (common.Address, error) {
 	nameHash, err := NameHash(r.domain)
 	if err != nil {
 		return UnknownAddress, err
 	}
-	return r.Contract.Addr(nil, nameHash)
+	o, release := newCallOptions(opts)
+	defer release()
+	return r.Contract.Addr(o.callOpts(), nameHash)
 }
 
 // SetAddress sets the Ethereum address of the domain.
@@ -116,12 +185,14 @@ func (r *Resolver) SetAddress(opts *bind.TransactOpts, address common.Address) (
 
 // MultiAddress returns the address of the domain for a given coin type.
 // The coin type is as per https://github.com/satoshilabs/slips/blob/master/slip-0044.md
-func (r *Resolver) MultiAddress(coinType uint64) ([]byte, error) {
+func (r *Resolver) MultiAddress(coinType uint64, opts ...CallOption) ([]byte, error) {
 	nameHash, err := NameHash(r.domain)
 	if err != nil {
 		return nil, err
 	}
-	return r.Contract.Addr0(nil, nameHash, big.NewInt(int64(coinType)))
+	o, release := newCallOptions(opts)
+	defer release()
+	return r.Contract.Addr0(o.callOpts(), nameHash, big.NewInt(int64(coinType)))
 }
 
 // SetMultiAddress sets the iaddress of the domain for a given coin type.
@@ -135,12 +206,14 @@ func (r *Resolver) SetMultiAddress(opts *bind.TransactOpts, coinType uint64, add
 }
 
 // PubKey returns the public key of the domain.
-func (r *Resolver) PubKey() ([32]byte, [32]byte, error) {
+func (r *Resolver) PubKey(opts ...CallOption) ([32]byte, [32]byte, error) {
 	nameHash, err := NameHash(r.domain)
 	if err != nil {
 		return [32]byte{}, [32]byte{}, err
 	}
-	res, err := r.Contract.Pubkey(nil, nameHash)
+	o, release := newCallOptions(opts)
+	defer release()
+	res, err := r.Contract.Pubkey(o.callOpts(), nameHash)
 	return res.X, res.Y, err
 }
 
@@ -154,12 +227,14 @@ func (r *Resolver) SetPubKey(opts *bind.TransactOpts, x [32]byte, y [32]byte) (*
 }
 
 // Contenthash returns the content hash of the domain.
-func (r *Resolver) Contenthash() ([]byte, error) {
+func (r *Resolver) Contenthash(opts ...CallOption) ([]byte, error) {
 	nameHash, err := NameHash(r.domain)
 	if err != nil {
 		return nil, err
 	}
-	return r.Contract.Contenthash(nil, nameHash)
+	o, release := newCallOptions(opts)
+	defer release()
+	return r.Contract.Contenthash(o.callOpts(), nameHash)
 }
 
 // SetContenthash sets the content hash of the domain.
@@ -172,19 +247,88 @@ func (r *Resolver) SetContenthash(opts *bind.TransactOpts, contenthash []byte) (
 }
 
 // InterfaceImplementer returns the address of the contract that implements the given interface for the given domain.
-func (r *Resolver) InterfaceImplementer(interfaceID [4]byte) (common.Address, error) {
+// If the domain does not have an interface record for the interface, but the
+// contract at the domain's address implements the interface as per EIP-165,
+// then the domain's address is returned.
+func (r *Resolver) InterfaceImplementer(interfaceID [4]byte, opts ...CallOption) (common.Address, error) {
 	nameHash, err := NameHash(r.domain)
 	if err != nil {
 		return UnknownAddress, err
 	}
-	return r.Contract.InterfaceImplementer(nil, nameHash, interfaceID)
+	o, release := newCallOptions(opts)
+	defer release()
+	callOpts := o.callOpts()
+	implementer, err := r.Contract.InterfaceImplementer(callOpts, nameHash, interfaceID)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	if implementer != UnknownAddress {
+		return implementer, nil
+	}
+
+	// Fall back to the domain's address, as carried out by the public
+	// resolver, for resolvers that do not.
+	address, err := r.Contract.Addr(callOpts, nameHash)
+	if err != nil || address == UnknownAddress {
+		return UnknownAddress, nil
+	}
+	if !implementsInterface(r.backend, address, interfaceID, callOpts) {
+		return UnknownAddress, nil
+	}
+
+	return address, nil
+}
+
+// SetInterface sets the address of the contract that implements the given
+// interface for the domain.  Setting UnknownAddress removes the record.
+func (r *Resolver) SetInterface(opts *bind.TransactOpts, interfaceID [4]byte, implementer common.Address) (*types.Transaction, error) {
+	nameHash, err := NameHash(r.domain)
+	if err != nil {
+		return nil, err
+	}
+	return r.Contract.SetInterface(opts, nameHash, interfaceID, implementer)
+}
+
+// implementsInterface returns true if the contract at the given address
+// implements an interface, using the detection process of EIP-165.
+func implementsInterface(backend bind.ContractBackend, address common.Address, interfaceID [4]byte, opts *bind.CallOpts) bool {
+	if backend == nil {
+		return false
+	}
+	contract, err := resolver.NewContractCaller(address, backend)
+	if err != nil {
+		return false
+	}
+	for _, check := range []struct {
+		interfaceID [4]byte
+		supported   bool
+	}{
+		{[4]byte{0x01, 0xff, 0xc9, 0xa7}, true},
+		{[4]byte{0xff, 0xff, 0xff, 0xff}, false},
+		{interfaceID, true},
+	} {
+		supported, err := contract.SupportsInterface(opts, check.interfaceID)
+		if err != nil || supported != check.supported {
+			return false
+		}
+	}
+
+	return true
 }
 
 // Resolve resolves an ENS name in to an Etheruem address.
 // This will return an error if the name is not found or otherwise 0.
-func Resolve(backend bind.ContractBackend, input string, chainId ChainId) (common.Address, error) {
+func Resolve(backend Backend, input string, chainId ChainId, opts ...CallOption) (resolved common.Address, err error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	ctx, span := startSpan(o.ctx, "ens.Resolve", attribute.String("ens.name", input))
+	defer func(started time.Time) {
+		endSpan(span, err)
+		o.observeResolution("resolve", started, err)
+	}(time.Now())
+
 	if strings.Contains(input, ".") {
-		return resolveName(backend, input, chainId)
+		return resolveName(asContractBackend(backend), input, chainId, o.withContext(ctx))
 	}
 	if (strings.HasPrefix(input, "0x") && len(input) > 42) || (!strings.HasPrefix(input, "0x") && len(input) > 40) {
 		return UnknownAddress, errors.New("address too long")
@@ -197,7 +341,7 @@ func Resolve(backend bind.ContractBackend, input string, chainId ChainId) (commo
 	return address, nil
 }
 
-func resolveName(backend bind.ContractBackend, input string, chainId ChainId) (common.Address, error) {
+func resolveName(backend bind.ContractBackend, input string, chainId ChainId, o *callOptions) (common.Address, error) {
 	nameHash, err := NameHash(input)
 	if err != nil {
 		return UnknownAddress, err
@@ -205,7 +349,11 @@ func resolveName(backend bind.ContractBackend, input string, chainId ChainId) (c
 	if bytes.Equal(nameHash[:], zeroHash) {
 		return UnknownAddress, errors.New("bad name")
 	}
-	address, err := resolveHash(backend, input, chainId)
+	address, err := resolveHash(backend, input, nameHash, chainId, o)
+	if errors.Is(err, ErrUnregisteredName) && IsDNSName(input) {
+		// DNS names may be resolved without being imported.
+		return resolveDNSName(backend, input, nameHash, chainId, o)
+	}
 	if err != nil {
 		return UnknownAddress, err
 	}
@@ -213,19 +361,26 @@ func resolveName(backend bind.ContractBackend, input string, chainId ChainId) (c
 	return address, nil
 }
 
-func resolveHash(backend bind.ContractBackend, domain string, chainId ChainId) (common.Address, error) {
-	resolver, err := NewResolver(backend, domain, chainId)
+func resolveHash(backend bind.ContractBackend, domain string, nameHash [32]byte, chainId ChainId, o *callOptions) (common.Address, error) {
+	resolver, err := newResolver(backend, domain, chainId, o)
 	if err != nil {
 		return UnknownAddress, err
 	}
 
 	// Resolve the domain.
-	address, err := resolver.Address()
+	ctx, span := startSpan(o.ctx, "ens.resolver.Addr", attribute.String("ens.resolver", resolver.ContractAddr.Hex()))
+	address, err := resolver.Contract.Addr(o.withContext(ctx).callOpts(), nameHash)
+	endSpan(span, err)
 	if err != nil {
+		if resolver.wildcard {
+			// The resolver of the ancestor does not resolve the name, so it
+			// is treated as unregistered.
+			return UnknownAddress, wrapError(ErrUnregisteredName, "%s: %v", domain, err)
+		}
 		return UnknownAddress, err
 	}
 	if bytes.Equal(address.Bytes(), UnknownAddress.Bytes()) {
-		return UnknownAddress, errors.New("no address")
+		return UnknownAddress, ErrNoAddress
 	}
 
 	return address, nil
@@ -241,12 +396,14 @@ func (r *Resolver) SetText(opts *bind.TransactOpts, name string, value string) (
 }
 
 // Text obtains the text associated with a name.
-func (r *Resolver) Text(name string) (string, error) {
+func (r *Resolver) Text(name string, opts ...CallOption) (string, error) {
 	nameHash, err := NameHash(r.domain)
 	if err != nil {
 		return "", err
 	}
-	return r.Contract.Text(nil, nameHash, name)
+	o, release := newCallOptions(opts)
+	defer release()
+	return r.Contract.Text(o.callOpts(), nameHash, name)
 }
 
 // SetABI sets the ABI associated with a name.
@@ -266,7 +423,7 @@ func (r *Resolver) SetABI(opts *bind.TransactOpts, _ string, abi string, content
 		w.Close()
 		data = b.Bytes()
 	default:
-		return nil, errors.New("unsupported content type")
+		return nil, wrapError(ErrFormatUnsupported, "unsupported content type")
 	}
 
 	nameHash, err := NameHash(r.domain)
@@ -277,13 +434,15 @@ func (r *Resolver) SetABI(opts *bind.TransactOpts, _ string, abi string, content
 }
 
 // ABI returns the ABI associated with a name.
-func (r *Resolver) ABI(name string) (string, error) {
+func (r *Resolver) ABI(name string, opts ...CallOption) (string, error) {
 	contentTypes := big.NewInt(3)
 	nameHash, err := NameHash(name)
 	if err != nil {
 		return "", err
 	}
-	contentType, data, err := r.Contract.ABI(nil, nameHash, contentTypes)
+	o, release := newCallOptions(opts)
+	defer release()
+	contentType, data, err := r.Contract.ABI(o.callOpts(), nameHash, contentTypes)
 	var abi string
 	if err == nil {
 		if contentType.Cmp(big.NewInt(1)) == 0 {
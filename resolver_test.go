@@ -22,6 +22,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
 )
 
 var client, _ = ethclient.Dial("https://mainnet.infura.io/v3/831a5442dc2e4536a9f8dee4ea1707a6")
@@ -101,3 +102,59 @@ func TestReverseResolveTestEnsTest(t *testing.T) {
 	require.Nil(t, err, "Error resolving address")
 	assert.Equal(t, expected, actual, "Did not receive expected result")
 }
+
+func TestInterfaceImplementer(t *testing.T) {
+	interfaceID := [4]byte{0x12, 0x34, 0x56, 0x78}
+	implementer := common.HexToAddress("0x000000000000000000000000000000000000abcd")
+	erc165Contract := common.HexToAddress("0x0000000000000000000000000000000000000165")
+	plainContract := common.HexToAddress("0x000000000000000000000000000000000000000f")
+
+	m := newMockENS()
+	m.register("record.eth", erc165Contract, erc165Contract)
+	m.register("erc165.eth", erc165Contract, erc165Contract)
+	m.register("plain.eth", plainContract, plainContract)
+	m.register("none.eth", erc165Contract, UnknownAddress)
+	records := map[[32]byte]common.Address{
+		mustNameHash("record.eth"): implementer,
+	}
+	m.backend.contracts[m.resolverAddr].
+		on("interfaceImplementer", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{records[args[0].([32]byte)]}, nil
+		}).
+		on("setInterface", func(args []interface{}) ([]interface{}, error) {
+			records[args[0].([32]byte)] = args[2].(common.Address)
+			return []interface{}{}, nil
+		})
+	m.backend.deploy(erc165Contract, resolver.ContractABI).
+		on("supportsInterface", func(args []interface{}) ([]interface{}, error) {
+			id := args[0].([4]byte)
+			return []interface{}{id == [4]byte{0x01, 0xff, 0xc9, 0xa7} || id == interfaceID}, nil
+		})
+	m.backend.deploy(plainContract, resolver.ContractABI)
+
+	tests := []struct {
+		name string
+		res  common.Address
+	}{
+		{name: "record.eth", res: implementer},
+		{name: "erc165.eth", res: erc165Contract},
+		{name: "plain.eth", res: UnknownAddress},
+		{name: "none.eth", res: UnknownAddress},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			resolver, err := NewResolver(m.backend, test.name, EthereumMainnet)
+			require.NoError(t, err)
+			res, err := resolver.InterfaceImplementer(interfaceID)
+			require.NoError(t, err)
+			require.Equal(t, test.res, res)
+		})
+	}
+
+	resolver, err := NewResolver(m.backend, "plain.eth", EthereumMainnet)
+	require.NoError(t, err)
+	_, err = resolver.SetInterface(testTransactOpts(t), interfaceID, implementer)
+	require.NoError(t, err)
+	require.Equal(t, implementer, records[mustNameHash("plain.eth")])
+}
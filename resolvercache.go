@@ -0,0 +1,126 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// resolverCacheSweepSize is the number of entries above which expired entries
+// are removed when adding to the resolver cache.
+const resolverCacheSweepSize = 1000
+
+// resolverCacheKey identifies the resolver for a node on a given chain.
+type resolverCacheKey struct {
+	chainId ChainId
+	node    [32]byte
+}
+
+type resolverCacheEntry struct {
+	address  common.Address
+	wildcard bool
+	expires  time.Time
+}
+
+// resolverCache holds the addresses of resolvers keyed by chain and node, so
+// that repeated lookups do not need to consult the registry each time.  It
+// holds addresses rather than bindings so that it does not keep backends
+// alive.
+var resolverCache = struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[resolverCacheKey]resolverCacheEntry
+}{
+	entries: make(map[resolverCacheKey]resolverCacheEntry),
+}
+
+// SetResolverCacheTTL sets the time for which the addresses of resolvers are
+// cached, so that repeated resolution of the same names does not need to
+// consult the registry each time.  If zero, resolvers are not cached.  This is
+// the default.
+//
+// The cache is shared by all backends, keyed by chain and name, so should
+// only be enabled if all backends for a chain are for the same deployment.
+// Resolvers set with Registry.SetResolver are removed from the cache, but
+// changes made elsewhere are only seen once the cached entry expires.
+func SetResolverCacheTTL(ttl time.Duration) {
+	resolverCache.mu.Lock()
+	defer resolverCache.mu.Unlock()
+	resolverCache.ttl = ttl
+	if ttl <= 0 {
+		resolverCache.entries = make(map[resolverCacheKey]resolverCacheEntry)
+	}
+}
+
+// cachedResolver obtains the address of the resolver for a node from the
+// cache, along with whether it is the wildcard resolver of an ancestor.
+func cachedResolver(chainId ChainId, node [32]byte) (common.Address, bool, bool) {
+	key := resolverCacheKey{chainId: chainId, node: node}
+
+	resolverCache.mu.RLock()
+	entry, exists := resolverCache.entries[key]
+	resolverCache.mu.RUnlock()
+	if !exists {
+		return UnknownAddress, false, false
+	}
+	if time.Now().After(entry.expires) {
+		resolverCache.mu.Lock()
+		if current, exists := resolverCache.entries[key]; exists && current.expires == entry.expires {
+			delete(resolverCache.entries, key)
+		}
+		resolverCache.mu.Unlock()
+		return UnknownAddress, false, false
+	}
+
+	return entry.address, entry.wildcard, true
+}
+
+// cacheResolver places the address of the resolver for a node in the cache,
+// if the cache is enabled.
+func cacheResolver(chainId ChainId, node [32]byte, address common.Address, wildcard bool) {
+	resolverCache.mu.Lock()
+	defer resolverCache.mu.Unlock()
+	if resolverCache.ttl <= 0 {
+		return
+	}
+	now := time.Now()
+	if len(resolverCache.entries) >= resolverCacheSweepSize {
+		for k, entry := range resolverCache.entries {
+			if now.After(entry.expires) {
+				delete(resolverCache.entries, k)
+			}
+		}
+	}
+	resolverCache.entries[resolverCacheKey{chainId: chainId, node: node}] = resolverCacheEntry{
+		address:  address,
+		wildcard: wildcard,
+		expires:  now.Add(resolverCache.ttl),
+	}
+}
+
+// forgetResolver removes the cached resolver for a node on all chains, as
+// callers that change a resolver may not know the chain of the registry.
+func forgetResolver(node [32]byte) {
+	resolverCache.mu.Lock()
+	defer resolverCache.mu.Unlock()
+	for key := range resolverCache.entries {
+		if key.node == node {
+			delete(resolverCache.entries, key)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolverCache(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("cached.eth", address, address)
+	m.setReverse(address, "cached.eth")
+
+	// Resolvers are not cached by default.
+	_, err := NewResolver(m.backend, "cached.eth", EthereumMainnet)
+	require.NoError(t, err)
+	calls := m.backend.callCount()
+	_, err = NewResolver(m.backend, "cached.eth", EthereumMainnet)
+	require.NoError(t, err)
+	uncached := m.backend.callCount() - calls
+	require.Positive(t, uncached)
+
+	SetResolverCacheTTL(time.Minute)
+	t.Cleanup(func() { SetResolverCacheTTL(0) })
+
+	_, err = NewResolver(m.backend, "cached.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, "cached.eth", Format(m.backend, address, EthereumMainnet))
+
+	// Subsequent lookups reuse the resolvers, only calling the resolvers
+	// themselves to obtain the name and check that it resolves back.
+	calls = m.backend.callCount()
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resolver, err := NewResolver(m.backend, "cached.eth", EthereumMainnet)
+			require.NoError(t, err)
+			require.Equal(t, "cached.eth", resolver.domain)
+			require.Equal(t, m.resolverAddr, resolver.ContractAddr)
+			require.Equal(t, "cached.eth", Format(m.backend, address, EthereumMainnet))
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, calls+32, m.backend.callCount())
+
+	// Setting the resolver removes it from the cache.
+	registry, err := NewRegistry(m.backend, EthereumMainnet)
+	require.NoError(t, err)
+	opts := testTransactOpts(t)
+	opts.GasLimit = 100_000
+	_, err = registry.SetResolver(opts, "cached.eth", m.resolverAddr)
+	require.NoError(t, err)
+	_, _, exists := cachedResolver(EthereumMainnet, mustNameHash("cached.eth"))
+	require.False(t, exists)
+	calls = m.backend.callCount()
+	_, err = NewResolver(m.backend, "cached.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, calls+uncached, m.backend.callCount())
+
+	// Disabling the cache empties it.
+	SetResolverCacheTTL(0)
+	_, _, exists = cachedResolver(EthereumMainnet, mustNameHash("cached.eth"))
+	require.False(t, exists)
+}
@@ -0,0 +1,179 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+)
+
+// Interface IDs of profiles that identify public resolver versions.
+var (
+	// legacyContentInterfaceIDs are the interface IDs reported for content
+	// records by the original public resolvers, which hold a 32-byte hash
+	// rather than a contenthash.
+	legacyContentInterfaceIDs = [][4]byte{{0xd8, 0x38, 0x9d, 0xc5}, {0x2d, 0xff, 0x69, 0x41}}
+	// versionableInterfaceID is the interface ID of record versions, which
+	// allows all records of a name to be cleared at once.  It was introduced
+	// with the public resolver that supports the name wrapper.
+	versionableInterfaceID = [4]byte{0xd7, 0x00, 0xff, 0x33}
+)
+
+// legacyContentABI is the ABI of the content record of the original public
+// resolvers.
+const legacyContentABI = `[{"type":"function","name":"content","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"bytes32"}]}]`
+
+// ResolverVersion is the generation of the public resolver that a resolver
+// matches.
+type ResolverVersion int
+
+// Resolver versions, from oldest to newest.
+const (
+	// ResolverVersionUnknown is a resolver that does not match a public
+	// resolver, for example a resolver without Ethereum addresses.
+	ResolverVersionUnknown ResolverVersion = iota
+	// ResolverVersionLegacy is the original public resolver, which holds
+	// content records rather than contenthash records.
+	ResolverVersionLegacy
+	// ResolverVersionContenthash is a public resolver with contenthash
+	// records but without addresses for other coin types, text records or
+	// multicall.
+	ResolverVersionContenthash
+	// ResolverVersionMulticoin is a public resolver with addresses for other
+	// coin types, text records and multicall, but without support for the
+	// name wrapper.
+	ResolverVersionMulticoin
+	// ResolverVersionCurrent is the current public resolver, which supports
+	// the name wrapper and record versions.
+	ResolverVersionCurrent
+)
+
+// String returns the name of the resolver version.
+func (v ResolverVersion) String() string {
+	switch v {
+	case ResolverVersionUnknown:
+		return "unknown"
+	case ResolverVersionLegacy:
+		return "legacy"
+	case ResolverVersionContenthash:
+		return "contenthash"
+	case ResolverVersionMulticoin:
+		return "multicoin"
+	case ResolverVersionCurrent:
+		return "current"
+	default:
+		return fmt.Sprintf("unknown version %d", int(v))
+	}
+}
+
+// Version returns the generation of the public resolver that the resolver
+// matches.  It is determined from the interfaces that the resolver reports
+// as per EIP-165, so other resolvers are reported as the generation whose
+// interfaces they match.
+func (r *Resolver) Version(opts ...CallOption) (ResolverVersion, error) {
+	capabilities, err := r.Supports(opts...)
+	if err != nil {
+		return ResolverVersionUnknown, err
+	}
+	o, release := newCallOptions(opts)
+	defer release()
+	callOpts := o.callOpts()
+
+	switch {
+	case !capabilities.Addr:
+		return ResolverVersionUnknown, nil
+	case !capabilities.Contenthash:
+		for _, interfaceID := range legacyContentInterfaceIDs {
+			supported, err := r.Contract.SupportsInterface(callOpts, interfaceID)
+			if err != nil {
+				return ResolverVersionUnknown, err
+			}
+			if supported {
+				return ResolverVersionLegacy, nil
+			}
+		}
+		return ResolverVersionUnknown, nil
+	case !capabilities.MultiAddr || !capabilities.Text || !capabilities.Multicall:
+		return ResolverVersionContenthash, nil
+	}
+
+	versionable, err := r.Contract.SupportsInterface(callOpts, versionableInterfaceID)
+	if err != nil {
+		return ResolverVersionUnknown, err
+	}
+	if versionable {
+		return ResolverVersionCurrent, nil
+	}
+
+	return ResolverVersionMulticoin, nil
+}
+
+// IsDeprecated returns true if the resolver matches a public resolver that
+// has been superseded, so names using it should be migrated to the current
+// public resolver with MigrateResolver.  Resolvers that do not match a public
+// resolver are not reported as deprecated.
+func (r *Resolver) IsDeprecated(opts ...CallOption) (bool, error) {
+	version, err := r.Version(opts...)
+	if err != nil {
+		return false, err
+	}
+
+	return version != ResolverVersionUnknown && version != ResolverVersionCurrent, nil
+}
+
+// LegacyContent returns the content record of the domain, as held by the
+// original public resolvers before contenthash records.  The content record
+// is usually the hash of a Swarm manifest, and can be converted to a
+// contenthash with LegacyContentToContenthash.
+func (r *Resolver) LegacyContent(opts ...CallOption) ([32]byte, error) {
+	nameHash, err := NameHash(r.domain)
+	if err != nil {
+		return [32]byte{}, err
+	}
+
+	o, release := newCallOptions(opts)
+	defer release()
+	return r.legacyContent(o.callOpts(), nameHash)
+}
+
+// legacyContent returns the content record of a node.
+func (r *Resolver) legacyContent(callOpts *bind.CallOpts, node [32]byte) ([32]byte, error) {
+	parsed, err := abi.JSON(strings.NewReader(legacyContentABI))
+	if err != nil {
+		return [32]byte{}, err
+	}
+	contract := bind.NewBoundContract(r.ContractAddr, parsed, r.backend, nil, nil)
+
+	var out []interface{}
+	if err := contract.Call(callOpts, &out, "content", node); err != nil {
+		return [32]byte{}, err
+	}
+
+	return *abi.ConvertType(out[0], new([32]byte)).(*[32]byte), nil
+}
+
+// LegacyContentToContenthash converts a content record of the original public
+// resolvers, taken to be the hash of a Swarm manifest, to a contenthash.
+func LegacyContentToContenthash(content [32]byte) ([]byte, error) {
+	if content == [32]byte{} {
+		return nil, errors.New("no content supplied")
+	}
+
+	return StringToContenthash(fmt.Sprintf("bzz://%x", content))
+}
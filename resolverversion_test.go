@@ -0,0 +1,185 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/require"
+)
+
+const mockLegacyResolverABI = `[
+{"type":"function","name":"addr","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+{"type":"function","name":"content","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"bytes32"}]},
+{"type":"function","name":"supportsInterface","stateMutability":"view","inputs":[{"name":"interfaceID","type":"bytes4"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+func TestResolverVersion(t *testing.T) {
+	multicoin := [][4]byte{
+		addrInterfaceID, multiAddrInterfaceID, textInterfaceID, contenthashInterfaceID,
+		abiInterfaceID, pubkeyInterfaceID, multicallInterfaceID,
+	}
+
+	tests := []struct {
+		name         string
+		interfaceIDs [][4]byte
+		err          error
+		version      ResolverVersion
+		deprecated   bool
+	}{
+		{
+			name:    "None",
+			version: ResolverVersionUnknown,
+		},
+		{
+			name:         "Offchain",
+			interfaceIDs: [][4]byte{extendedInterfaceID},
+			version:      ResolverVersionUnknown,
+		},
+		{
+			name:         "AddrOnly",
+			interfaceIDs: [][4]byte{addrInterfaceID},
+			version:      ResolverVersionUnknown,
+		},
+		{
+			name:         "Legacy",
+			interfaceIDs: [][4]byte{addrInterfaceID, legacyContentInterfaceIDs[0]},
+			version:      ResolverVersionLegacy,
+			deprecated:   true,
+		},
+		{
+			name:         "Contenthash",
+			interfaceIDs: [][4]byte{addrInterfaceID, contenthashInterfaceID, textInterfaceID, legacyMulticallInterfaceID},
+			version:      ResolverVersionContenthash,
+			deprecated:   true,
+		},
+		{
+			name:         "Multicoin",
+			interfaceIDs: multicoin,
+			version:      ResolverVersionMulticoin,
+			deprecated:   true,
+		},
+		{
+			name:         "Current",
+			interfaceIDs: append([][4]byte{versionableInterfaceID}, multicoin...),
+			version:      ResolverVersionCurrent,
+		},
+		{
+			name: "Error",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m := newMockENS()
+			m.register("version.eth", common.Address{0x01}, UnknownAddress)
+			resolver, err := NewResolver(m.backend, "version.eth", EthereumMainnet)
+			require.NoError(t, err)
+			m.backend.contracts[m.resolverAddr].on("supportsInterface", func(args []interface{}) ([]interface{}, error) {
+				if test.err != nil {
+					return nil, test.err
+				}
+				for _, interfaceID := range test.interfaceIDs {
+					if args[0].([4]byte) == interfaceID {
+						return []interface{}{true}, nil
+					}
+				}
+				return []interface{}{false}, nil
+			})
+
+			version, err := resolver.Version()
+			if test.err != nil {
+				require.EqualError(t, err, test.err.Error())
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.version, version)
+
+			deprecated, err := resolver.IsDeprecated()
+			require.NoError(t, err)
+			require.Equal(t, test.deprecated, deprecated)
+		})
+	}
+}
+
+func TestResolverVersionString(t *testing.T) {
+	require.Equal(t, "legacy", ResolverVersionLegacy.String())
+	require.Equal(t, "current", ResolverVersionCurrent.String())
+	require.Equal(t, "unknown version 99", ResolverVersion(99).String())
+}
+
+func TestLegacyContent(t *testing.T) {
+	address := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+	legacyResolverAddr := common.HexToAddress("0x1da022710dF5002339274AaDEe8D58218e9D6AB5")
+	content := common.HexToHash("0xd1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162")
+
+	m := newMockENS()
+	m.backend.deploy(legacyResolverAddr, mockLegacyResolverABI).
+		on("addr", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{address}, nil
+		}).
+		on("content", func(args []interface{}) ([]interface{}, error) {
+			if args[0].([32]byte) != mustNameHash("legacy.eth") {
+				return []interface{}{[32]byte{}}, nil
+			}
+			return []interface{}{[32]byte(content)}, nil
+		}).
+		on("supportsInterface", func(args []interface{}) ([]interface{}, error) {
+			interfaceID := args[0].([4]byte)
+			return []interface{}{interfaceID == addrInterfaceID || interfaceID == legacyContentInterfaceIDs[0]}, nil
+		})
+
+	resolver, err := NewResolverAt(m.backend, "legacy.eth", legacyResolverAddr)
+	require.NoError(t, err)
+
+	version, err := resolver.Version()
+	require.NoError(t, err)
+	require.Equal(t, ResolverVersionLegacy, version)
+
+	res, err := resolver.LegacyContent()
+	require.NoError(t, err)
+	require.Equal(t, [32]byte(content), res)
+
+	contenthash, err := LegacyContentToContenthash(res)
+	require.NoError(t, err)
+	require.Equal(t, "0xe40101fa011b20d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162", hexutil.Encode(contenthash))
+	text, err := ContenthashToString(contenthash)
+	require.NoError(t, err)
+	require.Equal(t, "bzz://d1de9994b4d039f6548d191eb26786769f580809256b4685ef316805265ea162", text)
+
+	_, err = LegacyContentToContenthash([32]byte{})
+	require.EqualError(t, err, "no content supplied")
+
+	// Legacy content is read as a contenthash when migrating.
+	records, err := readResolverRecords(resolver, mustNameHash("legacy.eth"))
+	require.NoError(t, err)
+	require.Equal(t, contenthash, records.contenthash)
+	require.Equal(t, address.Bytes(), records.addresses[60])
+
+	records, err = readResolverRecords(resolver, mustNameHash("other.eth"))
+	require.NoError(t, err)
+	require.Empty(t, records.contenthash)
+
+	// Resolvers without content records return an error.
+	m.register("current.eth", address, address)
+	resolver, err = NewResolver(m.backend, "current.eth", EthereumMainnet)
+	require.NoError(t, err)
+	_, err = resolver.LegacyContent()
+	require.Error(t, err)
+}
@@ -16,6 +16,7 @@ package ens
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
@@ -25,25 +26,21 @@ import (
 
 // ReverseRegistrar is the structure for the reverse registrar.
 type ReverseRegistrar struct {
+	backend      bind.ContractBackend
 	Contract     *reverseregistrar.Contract
 	ContractAddr common.Address
 }
 
-var registryAddress map[ChainId]string = map[ChainId]string{
-	EthereumMainnet: "addr.reverse",
-	BaseMainnet:     "80002105.reverse",
-}
-
 func getRegistryAddress(chainId ChainId) string {
-	n, ok := registryAddress[chainId]
-	if !ok {
-		n = "addr.reverse"
-	}
-	return n
+	return ChainConfigFor(chainId).ReverseNamespace
 }
 
 // NewReverseRegistrar obtains the reverse registrar.
 func NewReverseRegistrar(backend bind.ContractBackend, chainId ChainId) (*ReverseRegistrar, error) {
+	if address := ChainConfigFor(chainId).ReverseRegistrar; address != UnknownAddress {
+		return NewReverseRegistrarAt(backend, address)
+	}
+
 	registry, err := NewRegistry(backend, chainId)
 	if err != nil {
 		return nil, err
@@ -68,6 +65,7 @@ func NewReverseRegistrarAt(backend bind.ContractBackend, address common.Address)
 		return nil, err
 	}
 	return &ReverseRegistrar{
+		backend:      backend,
 		Contract:     contract,
 		ContractAddr: address,
 	}, nil
@@ -78,6 +76,70 @@ func (r *ReverseRegistrar) SetName(opts *bind.TransactOpts, name string) (*types
 	return r.Contract.SetName(opts, name)
 }
 
+// SetVerifiedName sets the name for the transacting account, first confirming
+// that the name resolves to the account.
+func (r *ReverseRegistrar) SetVerifiedName(opts *bind.TransactOpts, name string, chainId ChainId) (*types.Transaction, error) {
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+	if err := r.verifyForward(name, opts.From, chainId); err != nil {
+		return nil, err
+	}
+	return r.Contract.SetName(opts, name)
+}
+
+// SetNameForAddr sets the name for an address.  The transacting account must
+// be the address itself, an operator approved by the address in the
+// registry, or the owner of the contract at the address.
+func (r *ReverseRegistrar) SetNameForAddr(opts *bind.TransactOpts, addr common.Address, owner common.Address, resolver common.Address, name string) (*types.Transaction, error) {
+	return r.Contract.SetNameForAddr(opts, addr, owner, resolver, name)
+}
+
+// SetVerifiedNameForAddr sets the name for an address, first confirming that
+// the name resolves to the address.
+func (r *ReverseRegistrar) SetVerifiedNameForAddr(opts *bind.TransactOpts, addr common.Address, owner common.Address, resolver common.Address, name string, chainId ChainId) (*types.Transaction, error) {
+	if err := r.verifyForward(name, addr, chainId); err != nil {
+		return nil, err
+	}
+	return r.Contract.SetNameForAddr(opts, addr, owner, resolver, name)
+}
+
+// Claim claims the reverse record for the transacting account, setting its
+// owner.
+func (r *ReverseRegistrar) Claim(opts *bind.TransactOpts, owner common.Address) (*types.Transaction, error) {
+	return r.Contract.Claim(opts, owner)
+}
+
+// ClaimWithResolver claims the reverse record for the transacting account,
+// setting its owner and resolver.
+func (r *ReverseRegistrar) ClaimWithResolver(opts *bind.TransactOpts, owner common.Address, resolver common.Address) (*types.Transaction, error) {
+	return r.Contract.ClaimWithResolver(opts, owner, resolver)
+}
+
+// ClaimForAddr claims the reverse record for an address, setting its owner
+// and resolver.  The transacting account must be authorised as per
+// SetNameForAddr.
+func (r *ReverseRegistrar) ClaimForAddr(opts *bind.TransactOpts, addr common.Address, owner common.Address, resolver common.Address) (*types.Transaction, error) {
+	return r.Contract.ClaimForAddr(opts, addr, owner, resolver)
+}
+
+// Node obtains the node of the reverse record for an address.
+func (r *ReverseRegistrar) Node(addr common.Address) ([32]byte, error) {
+	return r.Contract.Node(nil, addr)
+}
+
+// verifyForward confirms that the name resolves to the address.
+func (r *ReverseRegistrar) verifyForward(name string, address common.Address, chainId ChainId) error {
+	resolved, err := Resolve(r.backend, name, chainId)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", name, err)
+	}
+	if resolved != address {
+		return fmt.Errorf("%s resolves to %s not %s", name, resolved.Hex(), address.Hex())
+	}
+	return nil
+}
+
 // DefaultResolverAddress obtains the default resolver address.
 func (r *ReverseRegistrar) DefaultResolverAddress() (common.Address, error) {
 	return r.Contract.DefaultResolver(nil)
@@ -0,0 +1,63 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/reverseregistrar"
+)
+
+func TestReverseRegistrarSetVerifiedName(t *testing.T) {
+	m := newMockENS()
+	registrarAddr := common.HexToAddress("0xa58E81fe9b61B5c3fE2AFD33CF304c454AbFc7Cb")
+	var setName string
+	m.backend.deploy(registrarAddr, reverseregistrar.ContractABI).
+		on("setName", func(args []interface{}) ([]interface{}, error) {
+			setName = args[0].(string)
+			return []interface{}{[32]byte{}}, nil
+		}).
+		on("setNameForAddr", func(args []interface{}) ([]interface{}, error) {
+			setName = args[3].(string)
+			return []interface{}{[32]byte{}}, nil
+		})
+	m.register("addr.reverse", registrarAddr, UnknownAddress)
+
+	opts := testTransactOpts(t)
+	other := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	m.register("mine.eth", opts.From, opts.From)
+	m.register("other.eth", other, other)
+
+	registrar, err := NewReverseRegistrar(m.backend, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, registrarAddr, registrar.ContractAddr)
+
+	_, err = registrar.SetVerifiedName(opts, "other.eth", EthereumMainnet)
+	require.EqualError(t, err, "other.eth resolves to 0x0000000000000000000000000000000000000002 not "+opts.From.Hex())
+
+	_, err = registrar.SetVerifiedName(opts, "unregistered.eth", EthereumMainnet)
+	require.EqualError(t, err, "failed to resolve unregistered.eth: unregistered name")
+
+	_, err = registrar.SetVerifiedName(opts, "mine.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, "mine.eth", setName)
+
+	_, err = registrar.SetVerifiedNameForAddr(opts, other, other, m.resolverAddr, "other.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, "other.eth", setName)
+	require.Len(t, m.backend.sent, 2)
+}
@@ -17,10 +17,14 @@ package ens
 import (
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/wealdtech/go-ens/v3/contracts/multicall3"
 	"github.com/wealdtech/go-ens/v3/contracts/reverseresolver"
+	"github.com/wealdtech/go-ens/v3/contracts/universalresolver"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ReverseResolver is the structure for the reverse resolver contract.
@@ -31,20 +35,62 @@ type ReverseResolver struct {
 }
 
 // NewReverseResolverFor creates a reverse resolver contract for the given address.
-func NewReverseResolverFor(backend bind.ContractBackend, address common.Address, chainId ChainId) (*ReverseResolver, error) {
+func NewReverseResolverFor(backend bind.ContractBackend, address common.Address, chainId ChainId, opts ...CallOption) (*ReverseResolver, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	return newReverseResolverFor(backend, address, chainId, o)
+}
+
+func newReverseResolverFor(backend bind.ContractBackend, address common.Address, chainId ChainId, o *callOptions) (res *ReverseResolver, err error) {
+	n := getRegistryAddress(chainId)
+	domain := fmt.Sprintf("%x.%s", address.Bytes(), n)
+	nameHash, err := NameHash(domain)
+	if err != nil {
+		return nil, err
+	}
+	ctx, span := startSpan(o.ctx, "ens.NewReverseResolver", nameAttributes(domain, nameHash)...)
+	defer func() {
+		endSpan(span, err)
+	}()
+
+	if o.historical() {
+		// Cached resolvers are for the latest state.
+	} else if address, wildcard, exists := cachedResolver(chainId, nameHash); exists && !wildcard {
+		contract, err := reverseresolver.NewContract(address, backend)
+		if err != nil {
+			return nil, err
+		}
+		span.SetAttributes(attribute.Bool("ens.cached", true))
+		return &ReverseResolver{
+			Contract:     contract,
+			ContractAddr: address,
+			ChainId:      chainId,
+		}, nil
+	}
+
 	registry, err := NewRegistry(backend, chainId)
 	if err != nil {
 		return nil, err
 	}
 
 	// Now fetch the resolver.
-	n := getRegistryAddress(chainId)
-	domain := fmt.Sprintf("%x.%s", address.Bytes(), n)
-	contractAddress, err := registry.ResolverAddress(domain)
+	lookupCtx, lookupSpan := startSpan(ctx, "ens.registry.Lookup")
+	contractAddress, err := registry.Contract.Resolver(o.withContext(lookupCtx).callOpts(), nameHash)
+	endSpan(lookupSpan, err)
+	if err != nil {
+		return nil, err
+	}
+	fetchCtx, fetchSpan := startSpan(ctx, "ens.resolver.Fetch", attribute.String("ens.resolver", contractAddress.Hex()))
+	resolver, err := newReverseResolverAt(backend, contractAddress, chainId, o.withContext(fetchCtx))
+	endSpan(fetchSpan, err)
 	if err != nil {
 		return nil, err
 	}
-	return NewReverseResolverAt(backend, contractAddress, chainId)
+	if !o.historical() {
+		cacheResolver(chainId, nameHash, resolver.ContractAddr, false)
+	}
+
+	return resolver, nil
 }
 
 // NewReverseResolver obtains the reverse resolver.
@@ -64,7 +110,13 @@ func NewReverseResolver(backend bind.ContractBackend, chainId ChainId) (*Reverse
 }
 
 // NewReverseResolverAt obtains the reverse resolver at a given address.
-func NewReverseResolverAt(backend bind.ContractBackend, address common.Address, chainId ChainId) (*ReverseResolver, error) {
+func NewReverseResolverAt(backend bind.ContractBackend, address common.Address, chainId ChainId, opts ...CallOption) (*ReverseResolver, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	return newReverseResolverAt(backend, address, chainId, o)
+}
+
+func newReverseResolverAt(backend bind.ContractBackend, address common.Address, chainId ChainId, o *callOptions) (*ReverseResolver, error) {
 	// Instantiate the reverse registrar contract.
 	contract, err := reverseresolver.NewContract(address, backend)
 	if err != nil {
@@ -78,9 +130,9 @@ func NewReverseResolverAt(backend bind.ContractBackend, address common.Address,
 	if err != nil {
 		return nil, err
 	}
-	_, err = contract.Name(nil, nameHash)
-	if err != nil && err.Error() == "no contract code at given address" {
-		return nil, fmt.Errorf("not a resolver")
+	_, err = contract.Name(o.callOpts(), nameHash)
+	if errors.Is(err, bind.ErrNoCode) {
+		return nil, ErrNotAResolver
 	}
 
 	return &ReverseResolver{
@@ -91,41 +143,281 @@ func NewReverseResolverAt(backend bind.ContractBackend, address common.Address,
 }
 
 // Name obtains the name for an address.
-func (r *ReverseResolver) Name(address common.Address) (string, error) {
+func (r *ReverseResolver) Name(address common.Address, opts ...CallOption) (string, error) {
 	ra := getRegistryAddress(r.ChainId)
 	n := fmt.Sprintf("%s.%s", address.Hex()[2:], ra)
 	nameHash, err := NameHash(n)
 	if err != nil {
 		return "", err
 	}
-	return r.Contract.Name(nil, nameHash)
+	o, release := newCallOptions(opts)
+	defer release()
+	return r.Contract.Name(o.callOpts(), nameHash)
 }
 
 // Format provides a string version of an address, reverse resolving it if possible.
-func Format(backend bind.ContractBackend, address common.Address, chainId ChainId) string {
-	result, err := ReverseResolve(backend, address, chainId)
+// If the address does not have a name the checksummed address is returned;
+// this can be changed with the WithShortAddress and WithFallback options.  The
+// WithNoNameCache option avoids repeated reverse resolution of addresses that
+// do not have a name.
+func Format(backend bind.ContractBackend, address common.Address, chainId ChainId, opts ...CallOption) string {
+	o, release := newCallOptions(opts)
+	defer release()
+
+	key := ""
+	if o.noNameCache != nil && !o.historical() {
+		key = fmt.Sprintf("%d:%s:%x", chainId, cacheKindNoName, address)
+		_, exists := o.noNameCache.Get(key)
+		o.metrics.CacheLookup(cacheKindNoName, exists)
+		if exists {
+			return formatAddress(address, o)
+		}
+	}
+
+	result, err := ReverseResolve(backend, address, chainId, opts...)
 	if err != nil {
-		result = address.Hex()
+		if key != "" && (errors.Is(err, ErrNoResolution) || errors.Is(err, ErrNoResolver) || errors.Is(err, ErrNotAResolver)) {
+			o.noNameCache.Set(key, true, o.noNameTTL)
+		}
+		return formatAddress(address, o)
 	}
 	return result
 }
 
+// formatAddress formats an address that does not have a name.
+func formatAddress(address common.Address, o *callOptions) string {
+	switch {
+	case o.fallback != nil:
+		return o.fallback(address)
+	case o.shortAddress:
+		hex := address.Hex()
+		return hex[:6] + "…" + hex[len(hex)-4:]
+	default:
+		return address.Hex()
+	}
+}
+
 // ReverseResolve resolves an address in to an ENS name.
 // This will return an error if the name is not found or otherwise 0.
-func ReverseResolve(backend bind.ContractBackend, address common.Address, chainId ChainId) (string, error) {
-	resolver, err := NewReverseResolverFor(backend, address, chainId)
+//
+// If the chain has a universal resolver the name is obtained from it in a
+// single call.  Otherwise, or with the WithoutUniversalResolver option, or if
+// the universal resolver cannot answer, the name is obtained through the
+// registry and reverse resolver.  Either way the name is only returned if it
+// resolves back to the address.
+func ReverseResolve(backend Backend, address common.Address, chainId ChainId, opts ...CallOption) (name string, err error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	ctx, span := startSpan(o.ctx, "ens.ReverseResolve", attribute.String("ens.address", address.Hex()))
+	defer func(started time.Time) {
+		endSpan(span, err)
+		o.observeResolution("reverse_resolve", started, err)
+	}(time.Now())
+
+	o = o.withContext(ctx)
+	contractBackend := asContractBackend(backend)
+	if !o.noUniversalResolver {
+		if universalResolver, exists := universalResolverFor(contractBackend, chainId); exists {
+			var fallBack bool
+			name, fallBack, err = universalReverseResolve(contractBackend, universalResolver, address, chainId, o)
+			if !fallBack {
+				return name, err
+			}
+		}
+	}
+
+	resolver, err := newReverseResolverFor(contractBackend, address, chainId, o)
 	if err != nil {
 		return "", err
 	}
 
 	// Resolve the name.
-	name, err := resolver.Name(address)
+	nameHash, err := NameHash(fmt.Sprintf("%s.%s", address.Hex()[2:], getRegistryAddress(chainId)))
+	if err != nil {
+		return "", err
+	}
+	nameCtx, nameSpan := startSpan(ctx, "ens.resolver.Name", attribute.String("ens.resolver", resolver.ContractAddr.Hex()))
+	name, err = resolver.Contract.Name(o.withContext(nameCtx).callOpts(), nameHash)
+	endSpan(nameSpan, err)
 	if err != nil {
 		return "", err
 	}
 	if name == "" {
-		err = errors.New("no resolution")
+		return "", ErrNoResolution
+	}
+
+	// Ensure that the name resolves back to the address.
+	resolved, err := resolveName(contractBackend, name, chainId, o)
+	switch {
+	case err == nil && resolved == address:
+		return name, nil
+	case err == nil || errors.Is(err, ErrNoResolution) || errors.Is(err, ErrNoResolver) || errors.Is(err, ErrNotAResolver) || errors.Is(err, ErrUnregisteredName):
+		return "", ErrNoResolution
+	default:
+		return "", err
+	}
+}
+
+// universalReverseResolve resolves an address in to an ENS name using the
+// universal resolver, returning true if the universal resolver could not
+// provide an answer and the name should be obtained through the registry.
+func universalReverseResolve(backend bind.ContractBackend, universalResolver *UniversalResolver, address common.Address, chainId ChainId, o *callOptions) (string, bool, error) {
+	ctx, span := startSpan(o.ctx, "ens.universalResolver.Reverse", attribute.String("ens.universal_resolver", universalResolver.ContractAddr.Hex()))
+	name, resolvedAddress, err := universalResolver.reverse(fmt.Sprintf("%x.%s", address.Bytes(), getRegistryAddress(chainId)), o.withContext(ctx))
+	endSpan(span, err)
+	if err != nil {
+		err = revertError(err)
+		switch {
+		case errors.Is(err, ErrResolverNotFound):
+			return "", false, ErrNoResolver
+		case errors.Is(err, ErrReverseAddressMismatch):
+			return "", false, ErrNoResolution
+		}
+		// The universal resolver may be unable to provide the name, for
+		// example because it requires an offchain lookup.
+		if !o.historical() {
+			checkUniversalResolverError(backend, chainId, err)
+		}
+		return "", true, err
+	}
+	if name == "" || resolvedAddress != address {
+		return "", false, ErrNoResolution
+	}
+
+	return name, false, nil
+}
+
+// reverseBatchSize is the number of addresses reverse resolved in each call to
+// Multicall3.
+const reverseBatchSize = 100
+
+// ReverseResolveMany resolves addresses in to ENS names, returning a map of
+// address to name.  Addresses that do not have a name, or whose name does not
+// resolve back to the address, are not present in the map.
+//
+// If the chain has a universal resolver the names are obtained from it in
+// batches with Multicall3.  Addresses that the universal resolver cannot
+// answer, for example because their names require an offchain lookup, and
+// all addresses on chains without Multicall3 or a universal resolver, are
+// resolved individually with ReverseResolve.  If an individual resolution
+// fails for a reason other than the address not having a name then the first
+// such error is returned along with the names that were obtained.
+func ReverseResolveMany(backend bind.ContractBackend, addresses []common.Address, chainId ChainId, opts ...CallOption) (map[common.Address]string, error) {
+	names, err := reverseResolveMany(backend, addresses, chainId, opts)
+	for address, name := range names {
+		if name == "" {
+			delete(names, address)
+		}
+	}
+
+	return names, err
+}
+
+// reverseResolveMany resolves addresses in to ENS names as per
+// ReverseResolveMany, except that addresses known not to have a name are
+// present in the map with an empty name.
+func reverseResolveMany(backend bind.ContractBackend, addresses []common.Address, chainId ChainId, opts []CallOption) (map[common.Address]string, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+
+	names := make(map[common.Address]string, len(addresses))
+	seen := make(map[common.Address]bool, len(addresses))
+	unique := make([]common.Address, 0, len(addresses))
+	for _, address := range addresses {
+		if !seen[address] {
+			seen[address] = true
+			unique = append(unique, address)
+		}
+	}
+
+	individual := unique
+	if !o.noUniversalResolver {
+		if universalResolver, exists := universalResolverFor(backend, chainId); exists {
+			var err error
+			individual, err = universalReverseResolveMany(backend, universalResolver, unique, chainId, names, o)
+			if err != nil {
+				individual = unique
+			}
+		}
+	}
+
+	var firstErr error
+	for _, address := range individual {
+		name, err := ReverseResolve(backend, address, chainId, opts...)
+		switch {
+		case err == nil:
+			names[address] = name
+		case isNoPrimaryName(err):
+			names[address] = ""
+		case firstErr == nil:
+			firstErr = err
+		}
+	}
+
+	return names, firstErr
+}
+
+// universalReverseResolveMany resolves addresses in to ENS names using the
+// universal resolver through Multicall3, adding the names found to the map,
+// with an empty name for addresses that do not have one, and returning the
+// addresses that the universal resolver could not answer.
+func universalReverseResolveMany(backend bind.ContractBackend, universalResolver *UniversalResolver, addresses []common.Address, chainId ChainId, names map[common.Address]string, o *callOptions) ([]common.Address, error) {
+	universalResolverABI, err := universalresolver.ContractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	registryAddress := getRegistryAddress(chainId)
+
+	unanswered := make([]common.Address, 0)
+	for start := 0; start < len(addresses); start += reverseBatchSize {
+		end := start + reverseBatchSize
+		if end > len(addresses) {
+			end = len(addresses)
+		}
+		batch := addresses[start:end]
+		calls := make([]multicall3.Multicall3Call3, 0, len(batch))
+		for _, address := range batch {
+			encodedName, err := DNSEncodeName(fmt.Sprintf("%x.%s", address.Bytes(), registryAddress))
+			if err != nil {
+				return nil, err
+			}
+			data, err := universalResolverABI.Pack("reverse", encodedName)
+			if err != nil {
+				return nil, err
+			}
+			calls = append(calls, multicall3.Multicall3Call3{Target: universalResolver.ContractAddr, AllowFailure: true, CallData: data})
+		}
+
+		results, err := aggregate3Results(backend, calls, o)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, address := range batch {
+			if !results[i].Success {
+				// Reverts that show the address does not have a name are
+				// answers; others, such as offchain lookups, are not.
+				contractErr, err := DecodeContractError(results[i].ReturnData)
+				if err == nil && (errors.Is(contractErr, ErrResolverNotFound) || errors.Is(contractErr, ErrReverseAddressMismatch)) {
+					names[address] = ""
+				} else {
+					unanswered = append(unanswered, address)
+				}
+				continue
+			}
+			values, err := universalResolverABI.Unpack("reverse", results[i].ReturnData)
+			if err != nil || len(values) < 2 {
+				unanswered = append(unanswered, address)
+				continue
+			}
+			name, _ := values[0].(string)
+			resolvedAddress, _ := values[1].(common.Address)
+			if resolvedAddress != address {
+				name = ""
+			}
+			names[address] = name
+		}
 	}
 
-	return name, err
+	return unanswered, nil
 }
@@ -0,0 +1,321 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Risk is the risk that a name has been chosen to deceive, for example by
+// looking like another name.
+type Risk int
+
+// Risks, in increasing order of severity.
+const (
+	// RiskNone is a name with no known issues.
+	RiskNone Risk = iota
+	// RiskLow is a name that is unusual but unlikely to deceive.
+	RiskLow
+	// RiskMedium is a name that could be confused with another name.
+	RiskMedium
+	// RiskHigh is a name that is very likely to be confused with another name.
+	RiskHigh
+)
+
+// String returns the name of the risk.
+func (r Risk) String() string {
+	switch r {
+	case RiskNone:
+		return "none"
+	case RiskLow:
+		return "low"
+	case RiskMedium:
+		return "medium"
+	case RiskHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("unknown risk %d", int(r))
+	}
+}
+
+// SafetyIssue is a feature of a name that could be used to deceive.
+type SafetyIssue struct {
+	// Label is the label of the name that contains the issue.
+	Label string
+	// Offset is the offset in runes of the issue in the normalized name.
+	Offset int
+	// Risk is the risk of the issue.
+	Risk Risk
+	// Reason describes the issue.
+	Reason string
+}
+
+// SafetyResult is the result of a safety check of a name.
+type SafetyResult struct {
+	// Name is the normalized name.
+	Name string
+	// Risk is the highest risk of the issues found.
+	Risk Risk
+	// Issues are the issues found, in the order that they occur in the name.
+	Issues []*SafetyIssue
+}
+
+// invisibleRunes are runes that survive normalization but are not visible
+// when a name is displayed.  The zero-width joiner is only invisible outside
+// of emoji sequences, so is checked separately.
+var invisibleRunes = map[rune]bool{
+	'‌': true, // Zero-width non-joiner.
+	'⠀': true, // Braille pattern blank.
+}
+
+// latinConfusables are runes from other scripts, and unusual Latin runes,
+// that look like common Latin letters.
+var latinConfusables = map[rune]rune{
+	// Cyrillic.
+	'а': 'a', 'с': 'c', 'ԁ': 'd', 'е': 'e', 'һ': 'h', 'і': 'i', 'ј': 'j',
+	'ӏ': 'l', 'о': 'o', 'р': 'p', 'ԛ': 'q', 'ѕ': 's', 'ԝ': 'w', 'х': 'x',
+	'у': 'y',
+	// Greek.
+	'α': 'a', 'ι': 'i', 'κ': 'k', 'ν': 'v', 'ο': 'o', 'ρ': 'p', 'τ': 't',
+	'υ': 'u', 'χ': 'x',
+	// Latin.
+	'ı': 'i', 'ȷ': 'j', 'ɑ': 'a', 'ɡ': 'g', 'ɩ': 'i', 'ʏ': 'y',
+}
+
+// scriptRange is a range of runes that belong to a single script.
+type scriptRange struct {
+	lo     rune
+	hi     rune
+	script string
+}
+
+// scriptRanges are the ranges of runes that belong to a single script,
+// sorted so that the script of a rune can be found with a binary search.
+// Runes in the Common and Inherited scripts are used by multiple scripts, so
+// are not included.
+var scriptRanges = buildScriptRanges()
+
+// buildScriptRanges builds the script ranges from the unicode package.
+func buildScriptRanges() []scriptRange {
+	ranges := make([]scriptRange, 0)
+	add := func(lo rune, hi rune, stride rune, script string) {
+		if stride == 1 {
+			ranges = append(ranges, scriptRange{lo: lo, hi: hi, script: script})
+			return
+		}
+		for r := lo; r <= hi; r += stride {
+			ranges = append(ranges, scriptRange{lo: r, hi: r, script: script})
+		}
+	}
+	for script, table := range unicode.Scripts {
+		if script == "Common" || script == "Inherited" {
+			continue
+		}
+		for _, r := range table.R16 {
+			add(rune(r.Lo), rune(r.Hi), rune(r.Stride), script)
+		}
+		for _, r := range table.R32 {
+			add(rune(r.Lo), rune(r.Hi), rune(r.Stride), script)
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].lo < ranges[j].lo })
+
+	return ranges
+}
+
+// cjkScripts are scripts that are commonly mixed with each other, and with
+// Latin, in Chinese, Japanese and Korean names.
+var cjkScripts = map[string]bool{
+	"Han":      true,
+	"Hiragana": true,
+	"Katakana": true,
+	"Hangul":   true,
+	"Bopomofo": true,
+}
+
+// SafetyCheck checks a name for features that are commonly used to create
+// names that look like other names, so that applications can warn users
+// before, for example, sending funds to the name.  The checks are for:
+//
+//   - characters that are not visible when the name is displayed
+//   - labels that mix scripts, for example Latin and Cyrillic
+//   - labels that are entirely made up of characters that look like Latin letters
+//   - unusual characters that look like common Latin letters
+//
+// The checks are a subset of those carried out by full ENSIP-15
+// normalization, and a name without issues is not guaranteed to be safe.
+func SafetyCheck(name string) (*SafetyResult, error) {
+	normalizedName, err := Normalize(name)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &SafetyResult{
+		Name:   normalizedName,
+		Issues: make([]*SafetyIssue, 0),
+	}
+	offset := 0
+	for _, label := range strings.Split(normalizedName, ".") {
+		runes := []rune(label)
+		for _, issue := range labelSafetyIssues(runes) {
+			issue.Label = label
+			issue.Offset += offset
+			res.Issues = append(res.Issues, issue)
+			if issue.Risk > res.Risk {
+				res.Risk = issue.Risk
+			}
+		}
+		// Move past the label and its separator.
+		offset += len(runes) + 1
+	}
+
+	return res, nil
+}
+
+// labelSafetyIssues returns the safety issues in a label, with offsets
+// relative to the start of the label.
+func labelSafetyIssues(label []rune) []*SafetyIssue {
+	issues := make([]*SafetyIssue, 0)
+
+	for i, r := range label {
+		if invisibleRunes[r] || (r == zwj && !isEmojiJoin(label, i)) {
+			issues = append(issues, &SafetyIssue{
+				Offset: i,
+				Risk:   RiskHigh,
+				Reason: fmt.Sprintf("invisible character %U", r),
+			})
+		}
+	}
+
+	// Find the scripts used by the label.  Characters such as digits,
+	// hyphens and emoji are common to all scripts and are ignored.
+	scripts := make([]string, len(label))
+	firstScript := ""
+	mixed := false
+	latin := false
+	confusable := true
+	for i, r := range label {
+		scripts[i] = scriptOf(r)
+		if scripts[i] == "" {
+			continue
+		}
+		if firstScript == "" {
+			firstScript = scripts[i]
+		} else if scripts[i] != firstScript && !(cjkScripts[scripts[i]] && cjkScripts[firstScript]) {
+			mixed = true
+		}
+		if scripts[i] == "Latin" {
+			latin = true
+		}
+		if _, exists := latinConfusables[r]; !exists {
+			confusable = false
+		}
+	}
+
+	switch {
+	case mixed:
+		for i, r := range label {
+			if scripts[i] == "" || scripts[i] == firstScript {
+				continue
+			}
+			risk := RiskHigh
+			if cjkScripts[scripts[i]] || cjkScripts[firstScript] {
+				// Latin is commonly used alongside CJK scripts.
+				risk = RiskLow
+			} else if !latin {
+				risk = RiskMedium
+			}
+			issues = append(issues, &SafetyIssue{
+				Offset: i,
+				Risk:   risk,
+				Reason: fmt.Sprintf("%s character %q in %s label", scripts[i], r, firstScript),
+			})
+
+			break
+		}
+	case confusable && firstScript != "" && firstScript != "Latin":
+		issues = append(issues, &SafetyIssue{
+			Offset: 0,
+			Risk:   RiskHigh,
+			Reason: fmt.Sprintf("%s label looks like the Latin %q", firstScript, latinLookalike(label)),
+		})
+	case latin:
+		for i, r := range label {
+			if lookalike, exists := latinConfusables[r]; exists {
+				issues = append(issues, &SafetyIssue{
+					Offset: i,
+					Risk:   RiskMedium,
+					Reason: fmt.Sprintf("character %q looks like %q", r, lookalike),
+				})
+			}
+		}
+	}
+
+	return issues
+}
+
+// isEmojiJoin returns true if the zero-width joiner at the given index of a
+// label joins two emoji.
+func isEmojiJoin(label []rune, index int) bool {
+	if index == 0 || index == len(label)-1 {
+		return false
+	}
+	before := label[index-1]
+	if before == emojiPresentation || (before >= 0x1f3fb && before <= 0x1f3ff) {
+		// Presentation selectors and skin tone modifiers follow emoji.
+		return true
+	}
+
+	return isEmoji(before) && isEmoji(label[index+1])
+}
+
+// isEmoji returns true if the rune is an emoji, approximated as a symbol that
+// is not in a script.
+func isEmoji(r rune) bool {
+	return unicode.Is(textPresentationEmoji, r) || (unicode.IsSymbol(r) && scriptOf(r) == "")
+}
+
+// latinLookalike returns the Latin text that a label looks like.
+func latinLookalike(label []rune) string {
+	var builder strings.Builder
+	for _, r := range label {
+		if lookalike, exists := latinConfusables[r]; exists {
+			r = lookalike
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// scriptOf returns the script of a rune, or an empty string if the rune is
+// used in multiple scripts.
+func scriptOf(r rune) string {
+	if r < unicode.MaxASCII {
+		if unicode.IsLetter(r) {
+			return "Latin"
+		}
+		return ""
+	}
+	i := sort.Search(len(scriptRanges), func(i int) bool { return scriptRanges[i].hi >= r })
+	if i == len(scriptRanges) || scriptRanges[i].lo > r {
+		return ""
+	}
+
+	return scriptRanges[i].script
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSafetyCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		risk   Risk
+		issues []*SafetyIssue
+		err    string
+	}{
+		{
+			name:  "Disallowed",
+			input: "ㅤ.eth",
+			err:   "failed to convert to standard unicode: idna: disallowed rune U+3164",
+		},
+		{
+			name:   "Safe",
+			input:  "PayPal.eth",
+			risk:   RiskNone,
+			issues: []*SafetyIssue{},
+		},
+		{
+			name:   "SingleScript",
+			input:  "привет.eth",
+			risk:   RiskNone,
+			issues: []*SafetyIssue{},
+		},
+		{
+			name:   "EmojiSequence",
+			input:  "👨‍👩‍👧.eth",
+			risk:   RiskNone,
+			issues: []*SafetyIssue{},
+		},
+		{
+			name:  "Invisible",
+			input: "sub.ab‌cd.eth",
+			risk:  RiskHigh,
+			issues: []*SafetyIssue{
+				{Label: "ab‌cd", Offset: 6, Risk: RiskHigh, Reason: "invisible character U+200C"},
+			},
+		},
+		{
+			name:  "StrayJoiner",
+			input: "a‍bc.eth",
+			risk:  RiskHigh,
+			issues: []*SafetyIssue{
+				{Label: "a‍bc", Offset: 1, Risk: RiskHigh, Reason: "invisible character U+200D"},
+			},
+		},
+		{
+			name:  "MixedScript",
+			input: "pаypal.eth",
+			risk:  RiskHigh,
+			issues: []*SafetyIssue{
+				{Label: "pаypal", Offset: 1, Risk: RiskHigh, Reason: "Cyrillic character 'а' in Latin label"},
+			},
+		},
+		{
+			name:  "WholeScriptConfusable",
+			input: "аррӏе.eth",
+			risk:  RiskHigh,
+			issues: []*SafetyIssue{
+				{Label: "аррӏе", Offset: 0, Risk: RiskHigh, Reason: `Cyrillic label looks like the Latin "apple"`},
+			},
+		},
+		{
+			name:  "LatinConfusable",
+			input: "gıthub.eth",
+			risk:  RiskMedium,
+			issues: []*SafetyIssue{
+				{Label: "gıthub", Offset: 1, Risk: RiskMedium, Reason: "character 'ı' looks like 'i'"},
+			},
+		},
+		{
+			name:  "CJK",
+			input: "東京tokyo.eth",
+			risk:  RiskLow,
+			issues: []*SafetyIssue{
+				{Label: "東京tokyo", Offset: 2, Risk: RiskLow, Reason: "Latin character 't' in Han label"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := SafetyCheck(test.input)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.risk, res.Risk)
+			require.Equal(t, test.issues, res.Issues)
+		})
+	}
+}
+
+func TestScriptOf(t *testing.T) {
+	for script, table := range unicode.Scripts {
+		expected := script
+		if script == "Common" || script == "Inherited" {
+			expected = ""
+		}
+		for _, r := range table.R16 {
+			for c := rune(r.Lo); c <= rune(r.Hi); c += rune(r.Stride) {
+				if c < unicode.MaxASCII && unicode.IsLetter(c) {
+					continue
+				}
+				require.Equal(t, expected, scriptOf(c), "%U", c)
+			}
+		}
+		for _, r := range table.R32 {
+			for c := rune(r.Lo); c <= rune(r.Hi); c += rune(r.Stride) {
+				require.Equal(t, expected, scriptOf(c), "%U", c)
+			}
+		}
+	}
+	require.Equal(t, "Latin", scriptOf('a'))
+	require.Equal(t, "", scriptOf('1'))
+	require.Equal(t, "", scriptOf(0x10ffff))
+}
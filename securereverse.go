@@ -0,0 +1,310 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// defaultLogRange is the number of blocks searched for the logs of resolvers
+// if no range is supplied with WithLogRange.
+const defaultLogRange = 10_000
+
+// ReverseResolution is the name of an address together with a report of how
+// it was obtained and verified, for applications that need to record the
+// provenance of the names that they display.
+type ReverseResolution struct {
+	// Address is the address that was reverse resolved.
+	Address common.Address
+	// Name is the name held in the reverse record of the address.
+	Name string
+	// ReverseResolver is the resolver of the reverse record.
+	ReverseResolver common.Address
+	// Resolver is the resolver of the name, or UnknownAddress if the name
+	// does not have a resolver.
+	Resolver common.Address
+	// ResolvedAddress is the Ethereum address of the name, or UnknownAddress
+	// if it could not be obtained.
+	ResolvedAddress common.Address
+	// ForwardMatch is true if the name resolves back to the address.  The
+	// name should not be displayed for the address if this is false.
+	ForwardMatch bool
+	// Wildcard is true if the resolver is set on an ancestor of the name
+	// rather than the name itself, so the name is resolved with ENSIP-10
+	// wildcard resolution.
+	Wildcard bool
+	// Offchain is true if the resolver answers with an offchain lookup, so
+	// the address of the name is supplied by a gateway.
+	Offchain bool
+	// NameChangedBlock is the block in which the reverse record was last
+	// changed, or 0 if it did not change within the range searched.
+	NameChangedBlock uint64
+	// AddrChangedBlock is the block in which the address of the name was
+	// last changed, or 0 if it did not change within the range searched.  It
+	// is always 0 for wildcard and offchain names, which do not emit events
+	// for their records.
+	AddrChangedBlock uint64
+}
+
+// SecureReverseResolve resolves an address in to an ENS name, returning a
+// report of the resolution.  Unlike ReverseResolve an error is not returned
+// if the name does not resolve back to the address; callers must check
+// ForwardMatch before using the name.  An error is returned if the address
+// does not have a reverse record.
+//
+// The blocks in which records last changed are obtained from the logs of the
+// resolvers, searching the range of blocks set with WithLogRange.  They are
+// reported as 0 if the records did not change within the range.
+func SecureReverseResolve(backend bind.ContractBackend, address common.Address, chainId ChainId, opts ...CallOption) (res *ReverseResolution, err error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	ctx, span := startSpan(o.ctx, "ens.SecureReverseResolve", attribute.String("ens.address", address.Hex()))
+	defer func(started time.Time) {
+		endSpan(span, err)
+		o.observeResolution("secure_reverse_resolve", started, err)
+	}(time.Now())
+	o = o.withContext(ctx)
+
+	reverseResolver, err := newReverseResolverFor(backend, address, chainId, o)
+	if err != nil {
+		return nil, err
+	}
+	reverseNode, err := NameHash(fmt.Sprintf("%x.%s", address.Bytes(), getRegistryAddress(chainId)))
+	if err != nil {
+		return nil, err
+	}
+	name, err := reverseResolver.Contract.Name(o.callOpts(), reverseNode)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, ErrNoResolution
+	}
+
+	nameChangedBlock, err := lastChangedBlock(backend, reverseResolver.ContractAddr, reverseNode, "NameChanged", o)
+	if err != nil {
+		return nil, err
+	}
+	res = &ReverseResolution{
+		Address:          address,
+		Name:             name,
+		ReverseResolver:  reverseResolver.ContractAddr,
+		Resolver:         UnknownAddress,
+		ResolvedAddress:  UnknownAddress,
+		NameChangedBlock: nameChangedBlock,
+	}
+
+	node, err := NameHash(name)
+	if err != nil {
+		// The name cannot be resolved, so does not match.
+		return res, nil
+	}
+	resolverAddr, wildcard, err := findResolver(backend, name, chainId, o)
+	if err != nil {
+		return nil, err
+	}
+	if resolverAddr == UnknownAddress {
+		return res, nil
+	}
+	res.Resolver = resolverAddr
+	res.Wildcard = wildcard
+
+	// Resolvers for wildcard names must use extended resolution.  Other
+	// resolvers may only support extended resolution, for example if they
+	// are offchain, so it is tried if a direct lookup fails.
+	resolvedAddress := UnknownAddress
+	err = ErrNoAddress
+	if !wildcard {
+		var contract *resolver.Contract
+		contract, err = resolver.NewContract(resolverAddr, backend)
+		if err != nil {
+			return nil, err
+		}
+		resolvedAddress, err = contract.Addr(o.callOpts(), node)
+	}
+	if err != nil {
+		resolvedAddress, res.Offchain, err = extendedResolveAddress(backend, resolverAddr, name, node, o)
+	}
+	if err == nil {
+		res.ResolvedAddress = resolvedAddress
+		res.ForwardMatch = resolvedAddress == address
+	}
+	if !wildcard && !res.Offchain {
+		res.AddrChangedBlock, err = lastChangedBlock(backend, resolverAddr, node, "AddrChanged", o)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// findResolver finds the resolver for a name as per ENSIP-10, returning true
+// if the resolver is set on an ancestor of the name.
+func findResolver(backend bind.ContractBackend, name string, chainId ChainId, o *callOptions) (common.Address, bool, error) {
+	registry, err := registryFor(backend, name, chainId)
+	if err != nil {
+		return UnknownAddress, false, err
+	}
+	for candidate := name; candidate != ""; {
+		nameHash, err := NameHash(candidate)
+		if err != nil {
+			return UnknownAddress, false, err
+		}
+		resolverAddr, err := registry.Contract.Resolver(o.callOpts(), nameHash)
+		if err != nil {
+			return UnknownAddress, false, err
+		}
+		if resolverAddr != UnknownAddress {
+			return resolverAddr, candidate != name, nil
+		}
+		if idx := strings.IndexByte(candidate, '.'); idx >= 0 {
+			candidate = candidate[idx+1:]
+		} else {
+			candidate = ""
+		}
+	}
+
+	return UnknownAddress, false, nil
+}
+
+// extendedResolveAddress obtains the Ethereum address of a name with the
+// ENSIP-10 resolve function, returning true if the resolver answered with an
+// offchain lookup.  Offchain lookups are only followed if the backend follows
+// them.
+func extendedResolveAddress(backend bind.ContractBackend, resolverAddr common.Address, name string, node [32]byte, o *callOptions) (common.Address, bool, error) {
+	resolverABI, err := abi.JSON(strings.NewReader(resolver.ContractABI))
+	if err != nil {
+		return UnknownAddress, false, err
+	}
+	addrCall, err := resolverABI.Pack("addr", node)
+	if err != nil {
+		return UnknownAddress, false, err
+	}
+	encodedName, err := DNSEncodeName(name)
+	if err != nil {
+		return UnknownAddress, false, err
+	}
+	bytesType, err := abi.NewType("bytes", "", nil)
+	if err != nil {
+		return UnknownAddress, false, err
+	}
+	args := abi.Arguments{{Type: bytesType}, {Type: bytesType}}
+	data, err := args.Pack(encodedName, addrCall)
+	if err != nil {
+		return UnknownAddress, false, err
+	}
+
+	callOpts := o.callOpts()
+	msg := ethereum.CallMsg{
+		From: callOpts.From,
+		To:   &resolverAddr,
+		Data: append(extendedInterfaceID[:], data...),
+	}
+
+	// Call without following offchain lookups first, to find out if the
+	// resolver is offchain.
+	res, err := withoutOffchainLookups(backend).CallContract(callOpts.Context, msg, callOpts.BlockNumber)
+	offchain := false
+	if err != nil {
+		if _, isLookup := offchainLookupFromError(err); !isLookup {
+			return UnknownAddress, false, err
+		}
+		offchain = true
+		res, err = backend.CallContract(callOpts.Context, msg, callOpts.BlockNumber)
+		if err != nil {
+			return UnknownAddress, true, err
+		}
+	}
+
+	values, err := args[:1].Unpack(res)
+	if err != nil {
+		return UnknownAddress, offchain, err
+	}
+	values, err = resolverABI.Methods["addr"].Outputs.Unpack(values[0].([]byte))
+	if err != nil {
+		return UnknownAddress, offchain, err
+	}
+
+	return values[0].(common.Address), offchain, nil
+}
+
+// lastChangedBlock returns the block of the latest event with the given name
+// emitted by a resolver for a node within the call's log range, or 0 if there
+// is none.
+func lastChangedBlock(backend bind.ContractBackend, resolverAddr common.Address, node [32]byte, event string, o *callOptions) (uint64, error) {
+	filterer, err := resolver.NewContractFilterer(resolverAddr, backend)
+	if err != nil {
+		return 0, err
+	}
+	var end uint64
+	if o.blockNumber != nil {
+		end = o.blockNumber.Uint64()
+	} else {
+		header, err := backend.HeaderByNumber(o.ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+		end = header.Number.Uint64()
+	}
+	filterOpts := &bind.FilterOpts{
+		Context: o.ctx,
+		End:     &end,
+	}
+	if end >= o.logRange {
+		filterOpts.Start = end - o.logRange + 1
+	}
+
+	var block uint64
+	switch event {
+	case "AddrChanged":
+		events, err := filterer.FilterAddrChanged(filterOpts, [][32]byte{node})
+		if err != nil {
+			return 0, fmt.Errorf("failed to obtain %s events: %w", event, err)
+		}
+		defer events.Close()
+		for events.Next() {
+			block = events.Event.Raw.BlockNumber
+		}
+		err = events.Error()
+		if err != nil {
+			return 0, fmt.Errorf("failed to obtain %s events: %w", event, err)
+		}
+	case "NameChanged":
+		events, err := filterer.FilterNameChanged(filterOpts, [][32]byte{node})
+		if err != nil {
+			return 0, fmt.Errorf("failed to obtain %s events: %w", event, err)
+		}
+		defer events.Close()
+		for events.Next() {
+			block = events.Event.Raw.BlockNumber
+		}
+		err = events.Error()
+		if err != nil {
+			return 0, fmt.Errorf("failed to obtain %s events: %w", event, err)
+		}
+	}
+
+	return block, nil
+}
@@ -0,0 +1,224 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+const mockExtendedResolverABI = `[
+{"type":"function","name":"addr","stateMutability":"view","inputs":[{"name":"node","type":"bytes32"}],"outputs":[{"name":"","type":"address"}]},
+{"type":"function","name":"resolve","stateMutability":"view","inputs":[{"name":"name","type":"bytes"},{"name":"data","type":"bytes"}],"outputs":[{"name":"","type":"bytes"}]},
+{"type":"function","name":"resolveCallback","stateMutability":"view","inputs":[{"name":"response","type":"bytes"},{"name":"extraData","type":"bytes"}],"outputs":[{"name":"","type":"bytes"}]}
+]`
+
+func TestSecureReverseResolve(t *testing.T) {
+	address := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+	other := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	offchainAddr := common.HexToAddress("0x000000000000000000000000000000000000e10d")
+
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(&GatewayResponse{Data: hexutil.Encode(common.LeftPadBytes(address.Bytes(), 32))})
+	}))
+	defer gateway.Close()
+
+	m := newMockENS()
+	m.register("test.eth", address, address)
+	m.register("other.eth", other, other)
+	m.register("offchain.eth", address, UnknownAddress)
+	m.mu.Lock()
+	m.resolvers[mustNameHash("offchain.eth")] = offchainAddr
+	m.mu.Unlock()
+	m.backend.deploy(offchainAddr, mockExtendedResolverABI).
+		on("resolve", func(_ []interface{}) ([]interface{}, error) {
+			return nil, &mockRevertError{data: offchainLookupData(&OffchainLookup{
+				Sender:           offchainAddr,
+				URLs:             []string{gateway.URL},
+				CallbackFunction: [4]byte(crypto.Keccak256([]byte("resolveCallback(bytes,bytes)"))[:4]),
+			})}
+		}).
+		on("resolveCallback", func(args []interface{}) ([]interface{}, error) {
+			return []interface{}{args[0].([]byte)}, nil
+		})
+
+	reverseNode := mustNameHash(address.Hex()[2:] + ".addr.reverse")
+	m.backend.addEvent(m.resolverAddr, resolver.ContractABI, "AddrChanged", []common.Hash{mustNameHash("test.eth")}, address)
+	m.backend.addEvent(m.resolverAddr, resolver.ContractABI, "NameChanged", []common.Hash{reverseNode}, "test.eth")
+
+	ccipBackend, err := NewCCIPReadBackend(m.backend, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		reverse string
+		res     *ReverseResolution
+	}{
+		{
+			name:    "Match",
+			reverse: "test.eth",
+			res: &ReverseResolution{
+				Address:          address,
+				Name:             "test.eth",
+				ReverseResolver:  m.resolverAddr,
+				Resolver:         m.resolverAddr,
+				ResolvedAddress:  address,
+				ForwardMatch:     true,
+				NameChangedBlock: 2,
+				AddrChangedBlock: 1,
+			},
+		},
+		{
+			name:    "Mismatch",
+			reverse: "other.eth",
+			res: &ReverseResolution{
+				Address:          address,
+				Name:             "other.eth",
+				ReverseResolver:  m.resolverAddr,
+				Resolver:         m.resolverAddr,
+				ResolvedAddress:  other,
+				NameChangedBlock: 2,
+			},
+		},
+		{
+			name:    "Wildcard",
+			reverse: "sub.test.eth",
+			res: &ReverseResolution{
+				Address:          address,
+				Name:             "sub.test.eth",
+				ReverseResolver:  m.resolverAddr,
+				Resolver:         m.resolverAddr,
+				ResolvedAddress:  UnknownAddress,
+				Wildcard:         true,
+				NameChangedBlock: 2,
+			},
+		},
+		{
+			name:    "Offchain",
+			reverse: "offchain.eth",
+			res: &ReverseResolution{
+				Address:          address,
+				Name:             "offchain.eth",
+				ReverseResolver:  m.resolverAddr,
+				Resolver:         offchainAddr,
+				ResolvedAddress:  address,
+				ForwardMatch:     true,
+				Offchain:         true,
+				NameChangedBlock: 2,
+			},
+		},
+		{
+			name:    "NoResolver",
+			reverse: "unknown.xyz",
+			res: &ReverseResolution{
+				Address:          address,
+				Name:             "unknown.xyz",
+				ReverseResolver:  m.resolverAddr,
+				Resolver:         UnknownAddress,
+				ResolvedAddress:  UnknownAddress,
+				NameChangedBlock: 2,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m.setReverse(address, test.reverse)
+			res, err := SecureReverseResolve(ccipBackend, address, EthereumMainnet)
+			require.NoError(t, err)
+			require.Equal(t, test.res, res)
+		})
+	}
+
+	// Backends wrapping the CCIP-Read backend, such as those added by a
+	// client's options, do not hide that the resolver is offchain.
+	timeoutBackend, err := NewTimeoutBackend(ccipBackend, time.Minute)
+	require.NoError(t, err)
+	m.setReverse(address, "offchain.eth")
+	res, err := SecureReverseResolve(timeoutBackend, address, EthereumMainnet)
+	require.NoError(t, err)
+	require.True(t, res.Offchain)
+	require.True(t, res.ForwardMatch)
+}
+
+func TestSecureReverseResolveNoName(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+
+	_, err := SecureReverseResolve(m.backend, address, EthereumMainnet)
+	require.ErrorIs(t, err, ErrNotAResolver)
+
+	m.setReverse(address, "")
+	_, err = SecureReverseResolve(m.backend, address, EthereumMainnet)
+	require.ErrorIs(t, err, ErrNoResolution)
+}
+
+// logLimitedBackend is a mock backend that rejects log queries over more than
+// a given number of blocks.
+type logLimitedBackend struct {
+	*mockBackend
+	limit uint64
+}
+
+func (b *logLimitedBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	if query.FromBlock == nil || query.ToBlock == nil || query.ToBlock.Uint64()-query.FromBlock.Uint64()+1 > b.limit {
+		return nil, errors.New("block range too large")
+	}
+	return b.mockBackend.FilterLogs(ctx, query)
+}
+
+func TestSecureReverseResolveLogRange(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+	m.register("test.eth", address, address)
+	m.setReverse(address, "test.eth")
+	// The mock backend places each log in its own block, numbered from 1.
+	m.backend.addEvent(m.resolverAddr, resolver.ContractABI, "NameChanged", []common.Hash{mustNameHash(address.Hex()[2:] + ".addr.reverse")}, "test.eth")
+	m.backend.addEvent(m.resolverAddr, resolver.ContractABI, "AddrChanged", []common.Hash{mustNameHash("test.eth")}, address)
+	backend := &logLimitedBackend{mockBackend: m.backend, limit: 10_000}
+
+	res, err := SecureReverseResolve(backend, address, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), res.NameChangedBlock)
+	require.Equal(t, uint64(2), res.AddrChangedBlock)
+
+	// Only logs within the range are found.
+	res, err = SecureReverseResolve(backend, address, EthereumMainnet, WithLogRange(1), WithBlockNumber(big.NewInt(2)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), res.NameChangedBlock)
+	require.Equal(t, uint64(2), res.AddrChangedBlock)
+	res, err = SecureReverseResolve(backend, address, EthereumMainnet, WithLogRange(2), WithBlockNumber(big.NewInt(2)))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), res.NameChangedBlock)
+	require.Equal(t, uint64(2), res.AddrChangedBlock)
+
+	// Failed log queries are returned.
+	_, err = SecureReverseResolve(backend, address, EthereumMainnet, WithLogRange(20_000), WithBlockNumber(big.NewInt(15_000)))
+	require.EqualError(t, err, "failed to obtain NameChanged events: block range too large")
+}
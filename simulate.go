@@ -0,0 +1,176 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// simulationGasLimit is the gas limit used to build simulated transactions,
+// so that the transaction can be built even if it would revert.
+const simulationGasLimit = uint64(30_000_000)
+
+// Simulation is the result of a successful simulated write operation.
+type Simulation struct {
+	// Transaction is the transaction that would have been sent.  It has not
+	// been broadcast and its gas limit is not final.
+	Transaction *types.Transaction
+	// Gas is the estimated gas used by the transaction.
+	Gas uint64
+	// ReturnData is the data returned by the call.
+	ReturnData []byte
+}
+
+// RevertError is returned when a simulated operation would revert.
+type RevertError struct {
+	// Reason is the decoded revert reason, if available.
+	Reason string
+	// Data is the raw revert data, if available.
+	Data []byte
+	// Err is the decoded revert data, if it could be decoded.
+	Err *ContractError
+}
+
+// Error returns the error message.
+func (e *RevertError) Error() string {
+	if e.Reason == "" {
+		return "execution reverted"
+	}
+	return fmt.Sprintf("execution reverted: %s", e.Reason)
+}
+
+// Unwrap returns the decoded contract error, allowing it to be matched with
+// errors.Is() and errors.As().
+func (e *RevertError) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// DecodeRevertError converts an error from a contract call or transaction in
+// to a *RevertError if the contract reverted, decoding custom errors and
+// revert reasons where possible.  Other errors are returned unchanged.
+func DecodeRevertError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return revertError(err)
+}
+
+// Simulate carries out a write operation without broadcasting it.  The
+// operation is any function that creates a transaction from transaction
+// options, for example:
+//
+//	sim, err := ens.Simulate(client, opts, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+//		return resolver.SetText(opts, "url", "https://example.com/")
+//	})
+//
+// The transaction is run with eth_call and eth_estimateGas using the exact
+// data that would have been sent.  If it would revert a *RevertError is
+// returned.  If opts does not have a signer the transaction is left unsigned.
+func Simulate(backend bind.ContractBackend, opts *bind.TransactOpts, operation func(*bind.TransactOpts) (*types.Transaction, error)) (*Simulation, error) {
+	if opts == nil {
+		return nil, errors.New("transaction options required")
+	}
+
+	simOpts := *opts
+	simOpts.NoSend = true
+	if simOpts.GasLimit == 0 {
+		simOpts.GasLimit = simulationGasLimit
+	}
+	if simOpts.Signer == nil {
+		simOpts.Signer = func(_ common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			return tx, nil
+		}
+	}
+	ctx := simOpts.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	tx, err := operation(&simOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := ethereum.CallMsg{
+		From:  simOpts.From,
+		To:    tx.To(),
+		Value: tx.Value(),
+		Data:  tx.Data(),
+	}
+	res, err := backend.CallContract(ctx, msg, nil)
+	if err != nil {
+		return nil, revertError(err)
+	}
+	gas, err := backend.EstimateGas(ctx, msg)
+	if err != nil {
+		return nil, revertError(err)
+	}
+
+	return &Simulation{
+		Transaction: tx,
+		Gas:         gas,
+		ReturnData:  res,
+	}, nil
+}
+
+// revertError converts the error from a call in to a *RevertError if the call
+// reverted, decoding the reason where possible.
+func revertError(err error) error {
+	var revert *RevertError
+	if errors.As(err, &revert) {
+		return err
+	}
+	var dataErr rpc.DataError
+	if errors.As(err, &dataErr) {
+		revert := &RevertError{}
+		if data, isString := dataErr.ErrorData().(string); isString {
+			revert.Data, _ = hexutil.Decode(data)
+		}
+		if contractErr, decodeErr := DecodeContractError(revert.Data); decodeErr == nil {
+			revert.Reason = contractErr.Error()
+			revert.Err = contractErr
+		} else {
+			revert.Reason = strings.TrimPrefix(strings.TrimPrefix(dataErr.Error(), "execution reverted"), ": ")
+		}
+		return revert
+	}
+	if strings.HasPrefix(err.Error(), "execution reverted") {
+		return &RevertError{
+			Reason: strings.TrimPrefix(strings.TrimPrefix(err.Error(), "execution reverted"), ": "),
+		}
+	}
+
+	return err
+}
+
+// isRevert returns true if the error from a call shows that the call reverted,
+// as opposed to failing to reach the contract.
+func isRevert(err error) bool {
+	var revert *RevertError
+	return errors.As(revertError(err), &revert)
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulate(t *testing.T) {
+	m := newMockENS()
+	opts := testTransactOpts(t)
+	m.register("simulate.eth", opts.From, opts.From)
+	m.backend.contracts[m.resolverAddr].on("setText", func(args []interface{}) ([]interface{}, error) {
+		switch args[1].(string) {
+		case "bad":
+			return nil, &mockRevertError{data: revertReasonData("not authorised")}
+		case "worse":
+			return nil, errors.New("execution reverted")
+		default:
+			return []interface{}{}, nil
+		}
+	})
+
+	resolver, err := NewResolver(m.backend, "simulate.eth", EthereumMainnet)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		opts *bind.TransactOpts
+		key  string
+		err  string
+	}{
+		{
+			name: "Good",
+			opts: opts,
+			key:  "url",
+		},
+		{
+			name: "Unsigned",
+			opts: &bind.TransactOpts{From: opts.From},
+			key:  "url",
+		},
+		{
+			name: "Reverted",
+			opts: opts,
+			key:  "bad",
+			err:  "execution reverted: not authorised",
+		},
+		{
+			name: "RevertedWithoutReason",
+			opts: opts,
+			key:  "worse",
+			err:  "execution reverted",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			sim, err := Simulate(m.backend, test.opts, func(opts *bind.TransactOpts) (*types.Transaction, error) {
+				return resolver.SetText(opts, test.key, "value")
+			})
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				var revertErr *RevertError
+				require.ErrorAs(t, err, &revertErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, m.resolverAddr, *sim.Transaction.To())
+			require.NotZero(t, sim.Gas)
+		})
+	}
+	require.Empty(t, m.backend.sent)
+}
@@ -0,0 +1,253 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+)
+
+// snapshotVersion is the version of the snapshot file format.
+const snapshotVersion = 1
+
+var (
+	// ErrSnapshotMiss is returned when a call is not present in a snapshot.
+	ErrSnapshotMiss = errors.New("call not present in snapshot")
+	// ErrSnapshotReadOnly is returned when attempting to transact against a snapshot.
+	ErrSnapshotReadOnly = errors.New("snapshot backend is read-only")
+)
+
+// SnapshotBackend is a contract backend that serves contract calls from a
+// snapshot of previously-recorded calls.
+//
+// A snapshot backend created with an upstream backend passes calls through
+// to the upstream and records the results; one without an upstream backend
+// serves calls solely from the snapshot, allowing Resolve() and
+// ReverseResolve() to operate without an RPC connection.
+type SnapshotBackend struct {
+	upstream bind.ContractBackend
+	mu       sync.RWMutex
+	calls    map[string]*snapshotCall
+	code     map[common.Address]hexutil.Bytes
+}
+
+type snapshotCall struct {
+	To     common.Address `json:"to"`
+	Data   hexutil.Bytes  `json:"data"`
+	Result hexutil.Bytes  `json:"result,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+type snapshotFile struct {
+	Version int                              `json:"version"`
+	Calls   []*snapshotCall                  `json:"calls"`
+	Code    map[common.Address]hexutil.Bytes `json:"code"`
+}
+
+// NewSnapshotBackend creates a snapshot backend that records calls made
+// through the upstream backend.
+func NewSnapshotBackend(upstream bind.ContractBackend) *SnapshotBackend {
+	return &SnapshotBackend{
+		upstream: upstream,
+		calls:    make(map[string]*snapshotCall),
+		code:     make(map[common.Address]hexutil.Bytes),
+	}
+}
+
+// LoadSnapshotBackend creates an offline snapshot backend from a snapshot
+// previously written with Export().
+func LoadSnapshotBackend(r io.Reader) (*SnapshotBackend, error) {
+	var file snapshotFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, errors.Wrap(err, "failed to decode snapshot")
+	}
+	if file.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d", file.Version)
+	}
+
+	s := NewSnapshotBackend(nil)
+	for _, call := range file.Calls {
+		s.calls[snapshotKey(call.To, call.Data)] = call
+	}
+	for address, code := range file.Code {
+		s.code[address] = code
+	}
+
+	return s, nil
+}
+
+// CreateSnapshot resolves the given names and reverse resolves the given
+// addresses using the backend, returning a snapshot backend containing all
+// of the calls required to repeat the resolutions offline.
+// Failed resolutions are recorded as such, so will fail in the same way when
+// served from the snapshot.
+func CreateSnapshot(backend bind.ContractBackend, names []string, addresses []common.Address, chainId ChainId) (*SnapshotBackend, error) {
+	s := NewSnapshotBackend(backend)
+	for _, name := range names {
+		//nolint:errcheck
+		Resolve(s, name, chainId)
+	}
+	for _, address := range addresses {
+		//nolint:errcheck
+		ReverseResolve(s, address, chainId)
+	}
+
+	return s, nil
+}
+
+// Export writes the snapshot to the supplied writer.
+func (s *SnapshotBackend) Export(w io.Writer) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	file := &snapshotFile{
+		Version: snapshotVersion,
+		Calls:   make([]*snapshotCall, 0, len(s.calls)),
+		Code:    s.code,
+	}
+	keys := make([]string, 0, len(s.calls))
+	for key := range s.calls {
+		keys = append(keys, key)
+	}
+	// Sort for deterministic output.
+	sort.Strings(keys)
+	for _, key := range keys {
+		file.Calls = append(file.Calls, s.calls[key])
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(file)
+}
+
+// CodeAt returns the code of the given account.
+func (s *SnapshotBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	s.mu.RLock()
+	code, exists := s.code[contract]
+	s.mu.RUnlock()
+	if exists {
+		return code, nil
+	}
+	if s.upstream == nil {
+		return nil, ErrSnapshotMiss
+	}
+
+	code, err := s.upstream.CodeAt(ctx, contract, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.code[contract] = code
+	s.mu.Unlock()
+
+	return code, nil
+}
+
+// CallContract executes an Ethereum contract call.
+func (s *SnapshotBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.To == nil {
+		return nil, errors.New("contract creation not supported")
+	}
+	key := snapshotKey(*call.To, call.Data)
+
+	s.mu.RLock()
+	recorded, exists := s.calls[key]
+	s.mu.RUnlock()
+	if exists {
+		if recorded.Error != "" {
+			return nil, errors.New(recorded.Error)
+		}
+		return recorded.Result, nil
+	}
+	if s.upstream == nil {
+		return nil, ErrSnapshotMiss
+	}
+
+	res, err := s.upstream.CallContract(ctx, call, blockNumber)
+	recorded = &snapshotCall{
+		To:     *call.To,
+		Data:   common.CopyBytes(call.Data),
+		Result: res,
+	}
+	if err != nil {
+		recorded.Error = err.Error()
+	}
+	s.mu.Lock()
+	s.calls[key] = recorded
+	s.mu.Unlock()
+
+	return res, err
+}
+
+// HeaderByNumber is not supported by the snapshot backend.
+func (*SnapshotBackend) HeaderByNumber(_ context.Context, _ *big.Int) (*types.Header, error) {
+	return nil, ErrSnapshotReadOnly
+}
+
+// PendingCodeAt returns the code of the given account.
+func (s *SnapshotBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return s.CodeAt(ctx, account, nil)
+}
+
+// PendingNonceAt is not supported by the snapshot backend.
+func (*SnapshotBackend) PendingNonceAt(_ context.Context, _ common.Address) (uint64, error) {
+	return 0, ErrSnapshotReadOnly
+}
+
+// SuggestGasPrice is not supported by the snapshot backend.
+func (*SnapshotBackend) SuggestGasPrice(_ context.Context) (*big.Int, error) {
+	return nil, ErrSnapshotReadOnly
+}
+
+// SuggestGasTipCap is not supported by the snapshot backend.
+func (*SnapshotBackend) SuggestGasTipCap(_ context.Context) (*big.Int, error) {
+	return nil, ErrSnapshotReadOnly
+}
+
+// EstimateGas is not supported by the snapshot backend.
+func (*SnapshotBackend) EstimateGas(_ context.Context, _ ethereum.CallMsg) (uint64, error) {
+	return 0, ErrSnapshotReadOnly
+}
+
+// SendTransaction is not supported by the snapshot backend.
+func (*SnapshotBackend) SendTransaction(_ context.Context, _ *types.Transaction) error {
+	return ErrSnapshotReadOnly
+}
+
+// FilterLogs is not supported by the snapshot backend.
+func (*SnapshotBackend) FilterLogs(_ context.Context, _ ethereum.FilterQuery) ([]types.Log, error) {
+	return nil, ErrSnapshotReadOnly
+}
+
+// SubscribeFilterLogs is not supported by the snapshot backend.
+func (*SnapshotBackend) SubscribeFilterLogs(_ context.Context, _ ethereum.FilterQuery, _ chan<- types.Log) (ethereum.Subscription, error) {
+	return nil, ErrSnapshotReadOnly
+}
+
+func snapshotKey(to common.Address, data []byte) string {
+	return fmt.Sprintf("%x:%x", to, data)
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot(t *testing.T) {
+	m := newMockENS()
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	address := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	m.register("foo.eth", owner, address)
+	m.setReverse(address, "foo.eth")
+
+	snapshot, err := CreateSnapshot(m.backend, []string{"foo.eth", "unregistered.eth"}, []common.Address{address}, EthereumMainnet)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, snapshot.Export(&buf))
+
+	offline, err := LoadSnapshotBackend(&buf)
+	require.NoError(t, err)
+
+	calls := m.backend.callCount()
+
+	resolved, err := Resolve(offline, "foo.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+
+	_, err = Resolve(offline, "unregistered.eth", EthereumMainnet)
+	require.EqualError(t, err, "unregistered name")
+
+	name, err := ReverseResolve(offline, address, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, "foo.eth", name)
+
+	_, err = Resolve(offline, "other.eth", EthereumMainnet)
+	require.ErrorIs(t, err, ErrSnapshotMiss)
+
+	// Ensure that nothing went to the upstream backend.
+	require.Equal(t, calls, m.backend.callCount())
+}
+
+func TestSnapshotBadVersion(t *testing.T) {
+	_, err := LoadSnapshotBackend(bytes.NewBufferString(`{"version":2}`))
+	require.EqualError(t, err, "unsupported snapshot version 2")
+}
@@ -0,0 +1,398 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+)
+
+// DefaultSubgraphEndpoint is the endpoint of the ENS subgraph on The Graph's
+// hosted service.
+//
+// Deprecated: the hosted service has been retired; use
+// SubgraphGatewayEndpoint with an API key for The Graph's network.
+const DefaultSubgraphEndpoint = "https://api.thegraph.com/subgraphs/name/ensdomains/ens"
+
+// subgraphID is the ID of the ENS subgraph on The Graph's network.
+const subgraphID = "5XqPmWe6gjyrJtFn9cLy237i4cWw2j9HcUJEXsP5qGtH"
+
+// SubgraphGatewayEndpoint returns the endpoint of the ENS subgraph on The
+// Graph's network, queried with the given API key.
+func SubgraphGatewayEndpoint(apiKey string) string {
+	return fmt.Sprintf("https://gateway.thegraph.com/api/%s/subgraphs/id/%s", apiKey, subgraphID)
+}
+
+// subgraphPageSize is the number of entities requested per query.
+const subgraphPageSize = 1000
+
+// SubgraphClient is a client for the ENS subgraph.
+type SubgraphClient struct {
+	endpoint string
+	client   *http.Client
+}
+
+// SubgraphDomain is a domain as reported by the ENS subgraph.
+type SubgraphDomain struct {
+	// NameHash is the namehash of the domain.
+	NameHash common.Hash
+	// Name is the fully-qualified name of the domain, e.g. foo.bar.eth.
+	Name string
+	// LabelName is the first label of the domain, e.g. foo.
+	LabelName string
+	// LabelHash is the hash of the first label of the domain.
+	LabelHash common.Hash
+	// Owner is the owner of the domain in the registry.
+	Owner common.Address
+	// Registrant is the registrant of the domain, if it is a .eth second-level domain.
+	Registrant common.Address
+	// WrappedOwner is the owner of the domain in the name wrapper, if it is wrapped.
+	WrappedOwner common.Address
+	// ResolverAddr is the address of the resolver for the domain.
+	ResolverAddr common.Address
+	// ResolvedAddress is the Ethereum address to which the domain resolves.
+	ResolvedAddress common.Address
+	// Expiry is the time at which the registration expires, if it is a .eth second-level domain.
+	Expiry time.Time
+	// Created is the time at which the domain was created.
+	Created time.Time
+}
+
+// SubgraphRegistrationEvent is a registration event as reported by the ENS subgraph.
+type SubgraphRegistrationEvent struct {
+	// Type is one of "NameRegistered", "NameRenewed" or "NameTransferred".
+	Type string
+	// BlockNumber is the block in which the event occurred.
+	BlockNumber uint64
+	// TransactionHash is the hash of the transaction that generated the event.
+	TransactionHash common.Hash
+	// Registrant is the registrant after the event.  It is only present for
+	// NameRegistered and NameTransferred events.
+	Registrant common.Address
+	// Expiry is the expiry of the registration after the event.  It is only present
+	// for NameRegistered and NameRenewed events.
+	Expiry time.Time
+}
+
+// NewSubgraphClient creates a new subgraph client for an endpoint, for
+// example that returned by SubgraphGatewayEndpoint.  If client is nil then
+// http.DefaultClient is used.
+func NewSubgraphClient(endpoint string, client *http.Client) *SubgraphClient {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &SubgraphClient{
+		endpoint: endpoint,
+		client:   client,
+	}
+}
+
+// NewResolver creates a resolver for the domain using the resolver address
+// held by the subgraph.
+func (d *SubgraphDomain) NewResolver(backend bind.ContractBackend) (*Resolver, error) {
+	if d.ResolverAddr == UnknownAddress {
+		return nil, ErrNoResolver
+	}
+	return NewResolverAt(backend, d.Name, d.ResolverAddr)
+}
+
+// NewName creates a name for the domain.
+func (d *SubgraphDomain) NewName(backend bind.ContractBackend) (*Name, error) {
+	return NewName(backend, d.Name)
+}
+
+const subgraphDomainFields = `
+  id
+  name
+  labelName
+  labelhash
+  owner { id }
+  registrant { id }
+  wrappedOwner { id }
+  resolver { address }
+  resolvedAddress { id }
+  expiryDate
+  createdAt
+`
+
+// NamesOwnedBy returns the domains owned by the given address, either as
+// owner, registrant or wrapped owner.
+func (c *SubgraphClient) NamesOwnedBy(ctx context.Context, owner common.Address) ([]*SubgraphDomain, error) {
+	query := `query($owner: String!, $first: Int!, $lastID: String!) {
+  domains(first: $first, orderBy: id, where: {and: [{id_gt: $lastID}, {or: [{owner: $owner}, {registrant: $owner}, {wrappedOwner: $owner}]}]}) {` +
+		subgraphDomainFields + `}
+}`
+
+	return c.domains(ctx, query, map[string]interface{}{
+		"owner": strings.ToLower(owner.Hex()),
+	})
+}
+
+// SubdomainsOf returns the direct subdomains of the given name.
+func (c *SubgraphClient) SubdomainsOf(ctx context.Context, name string) ([]*SubgraphDomain, error) {
+	nameHash, err := NameHash(name)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `query($parent: String!, $first: Int!, $lastID: String!) {
+  domains(first: $first, orderBy: id, where: {parent: $parent, id_gt: $lastID}) {` +
+		subgraphDomainFields + `}
+}`
+
+	return c.domains(ctx, query, map[string]interface{}{
+		"parent": fmt.Sprintf("%#x", nameHash),
+	})
+}
+
+// RegistrationHistory returns the registration events for the given name, in
+// chronological order.
+func (c *SubgraphClient) RegistrationHistory(ctx context.Context, name string) ([]*SubgraphRegistrationEvent, error) {
+	nameHash, err := NameHash(name)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `query($domain: String!) {
+  registrations(where: {domain: $domain}) {
+    events(orderBy: blockNumber, orderDirection: asc) {
+      __typename
+      blockNumber
+      transactionID
+      ... on NameRegistered { registrant { id } expiryDate }
+      ... on NameRenewed { expiryDate }
+      ... on NameTransferred { newOwner { id } }
+    }
+  }
+}`
+
+	var data struct {
+		Registrations []struct {
+			Events []struct {
+				Typename      string           `json:"__typename"`
+				BlockNumber   uint64           `json:"blockNumber"`
+				TransactionID string           `json:"transactionID"`
+				Registrant    *subgraphAccount `json:"registrant"`
+				NewOwner      *subgraphAccount `json:"newOwner"`
+				ExpiryDate    subgraphBigInt   `json:"expiryDate"`
+			} `json:"events"`
+		} `json:"registrations"`
+	}
+	if err := c.query(ctx, query, map[string]interface{}{"domain": fmt.Sprintf("%#x", nameHash)}, &data); err != nil {
+		return nil, err
+	}
+
+	res := make([]*SubgraphRegistrationEvent, 0)
+	for _, registration := range data.Registrations {
+		for _, event := range registration.Events {
+			item := &SubgraphRegistrationEvent{
+				Type:            event.Typename,
+				BlockNumber:     event.BlockNumber,
+				TransactionHash: common.HexToHash(event.TransactionID),
+				Expiry:          event.ExpiryDate.Time(),
+			}
+			switch {
+			case event.Registrant != nil:
+				item.Registrant = common.HexToAddress(event.Registrant.ID)
+			case event.NewOwner != nil:
+				item.Registrant = common.HexToAddress(event.NewOwner.ID)
+			}
+			res = append(res, item)
+		}
+	}
+
+	return res, nil
+}
+
+// domains runs a domain query, paginating by ID as the subgraph limits the
+// number of entities that can be skipped.  The domains are returned in the
+// order in which they were created.
+func (c *SubgraphClient) domains(ctx context.Context, query string, variables map[string]interface{}) ([]*SubgraphDomain, error) {
+	res := make([]*SubgraphDomain, 0)
+	lastID := ""
+	for {
+		variables["first"] = subgraphPageSize
+		variables["lastID"] = lastID
+		var data struct {
+			Domains []*subgraphDomain `json:"domains"`
+		}
+		if err := c.query(ctx, query, variables, &data); err != nil {
+			return nil, err
+		}
+		for _, domain := range data.Domains {
+			res = append(res, domain.toDomain())
+		}
+		if len(data.Domains) < subgraphPageSize {
+			break
+		}
+		lastID = data.Domains[len(data.Domains)-1].ID
+	}
+	sort.SliceStable(res, func(i int, j int) bool {
+		return res[i].Created.Before(res[j].Created)
+	})
+
+	return res, nil
+}
+
+// query sends a GraphQL query to the subgraph and decodes the data in to res.
+func (c *SubgraphClient) query(ctx context.Context, query string, variables map[string]interface{}, res interface{}) error {
+	if c.endpoint == "" {
+		return errors.New("no subgraph endpoint supplied")
+	}
+	reqBody, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal query")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return errors.Wrap(err, "failed to create request")
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "failed to query subgraph")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("subgraph returned status %d", resp.StatusCode)
+	}
+
+	var respBody struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&respBody); err != nil {
+		return errors.Wrap(err, "failed to decode subgraph response")
+	}
+	if len(respBody.Errors) > 0 {
+		msgs := make([]string, len(respBody.Errors))
+		for i := range respBody.Errors {
+			msgs[i] = respBody.Errors[i].Message
+		}
+		return fmt.Errorf("subgraph returned errors: %s", strings.Join(msgs, "; "))
+	}
+
+	if err := json.Unmarshal(respBody.Data, res); err != nil {
+		return errors.Wrap(err, "failed to decode subgraph data")
+	}
+
+	return nil
+}
+
+type subgraphAccount struct {
+	ID string `json:"id"`
+}
+
+type subgraphResolver struct {
+	Address string `json:"address"`
+}
+
+type subgraphDomain struct {
+	ID              string            `json:"id"`
+	Name            string            `json:"name"`
+	LabelName       string            `json:"labelName"`
+	LabelHash       string            `json:"labelhash"`
+	Owner           *subgraphAccount  `json:"owner"`
+	Registrant      *subgraphAccount  `json:"registrant"`
+	WrappedOwner    *subgraphAccount  `json:"wrappedOwner"`
+	Resolver        *subgraphResolver `json:"resolver"`
+	ResolvedAddress *subgraphAccount  `json:"resolvedAddress"`
+	ExpiryDate      subgraphBigInt    `json:"expiryDate"`
+	CreatedAt       subgraphBigInt    `json:"createdAt"`
+}
+
+func (d *subgraphDomain) toDomain() *SubgraphDomain {
+	res := &SubgraphDomain{
+		NameHash:  common.HexToHash(d.ID),
+		Name:      d.Name,
+		LabelName: d.LabelName,
+		LabelHash: common.HexToHash(d.LabelHash),
+		Expiry:    d.ExpiryDate.Time(),
+		Created:   d.CreatedAt.Time(),
+	}
+	if d.Owner != nil {
+		res.Owner = common.HexToAddress(d.Owner.ID)
+	}
+	if d.Registrant != nil {
+		res.Registrant = common.HexToAddress(d.Registrant.ID)
+	}
+	if d.WrappedOwner != nil {
+		res.WrappedOwner = common.HexToAddress(d.WrappedOwner.ID)
+	}
+	if d.Resolver != nil {
+		res.ResolverAddr = common.HexToAddress(d.Resolver.Address)
+	}
+	if d.ResolvedAddress != nil {
+		res.ResolvedAddress = common.HexToAddress(d.ResolvedAddress.ID)
+	}
+
+	return res
+}
+
+// subgraphBigInt is a subgraph BigInt, which is serialised as a string.
+type subgraphBigInt struct {
+	val *big.Int
+}
+
+func (b *subgraphBigInt) UnmarshalJSON(input []byte) error {
+	if string(input) == "null" {
+		return nil
+	}
+	var str string
+	if err := json.Unmarshal(input, &str); err != nil {
+		return err
+	}
+	val, ok := new(big.Int).SetString(str, 10)
+	if !ok {
+		return fmt.Errorf("invalid integer %s", str)
+	}
+	b.val = val
+
+	return nil
+}
+
+// Uint64 returns the value as a uint64, or 0 if not present.
+func (b subgraphBigInt) Uint64() uint64 {
+	if b.val == nil {
+		return 0
+	}
+	return b.val.Uint64()
+}
+
+// Time returns the value as a unix timestamp, or the zero time if not present.
+func (b subgraphBigInt) Time() time.Time {
+	if b.val == nil {
+		return time.Time{}
+	}
+	return time.Unix(b.val.Int64(), 0)
+}
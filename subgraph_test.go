@@ -0,0 +1,161 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func subgraphTestServer(t *testing.T, response string) (*httptest.Server, *map[string]interface{}) {
+	t.Helper()
+	variables := make(map[string]interface{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		for k, v := range req.Variables {
+			variables[k] = v
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &variables
+}
+
+func TestSubgraphNamesOwnedBy(t *testing.T) {
+	srv, variables := subgraphTestServer(t, `{"data":{"domains":[{
+  "id":"0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f",
+  "name":"foo.eth",
+  "labelName":"foo",
+  "labelhash":"0x41b1a0649752af1b28b3dc29a1556eee781e4a4c3a1f7f53f90fa834de098c4d",
+  "owner":{"id":"0x0904dac3347ea47d208f3fd67402d039a3b99859"},
+  "registrant":{"id":"0xb6e040c9ecaae172a89bd561c5f73e1c48d28cd9"},
+  "wrappedOwner":null,
+  "resolver":{"address":"0x231b0ee14048e9dccd1d247744d114a4eb5e8e63"},
+  "resolvedAddress":null,
+  "expiryDate":"1904119920",
+  "createdAt":"1580000000"
+}]}}`)
+
+	client := NewSubgraphClient(srv.URL, nil)
+	owner := common.HexToAddress("0xb6E040C9ECAaE172a89bD561c5F73e1C48d28cd9")
+	domains, err := client.NamesOwnedBy(context.Background(), owner)
+	require.NoError(t, err)
+	require.Len(t, domains, 1)
+	require.Equal(t, "0xb6e040c9ecaae172a89bd561c5f73e1c48d28cd9", (*variables)["owner"])
+
+	domain := domains[0]
+	require.Equal(t, "foo.eth", domain.Name)
+	require.Equal(t, "foo", domain.LabelName)
+	require.Equal(t, common.HexToHash("0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f"), domain.NameHash)
+	require.Equal(t, common.HexToAddress("0x0904Dac3347eA47d208F3Fd67402D039a3b99859"), domain.Owner)
+	require.Equal(t, owner, domain.Registrant)
+	require.Equal(t, UnknownAddress, domain.WrappedOwner)
+	require.Equal(t, common.HexToAddress("0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63"), domain.ResolverAddr)
+	require.Equal(t, time.Unix(1904119920, 0), domain.Expiry)
+}
+
+func TestSubgraphSubdomainsOf(t *testing.T) {
+	srv, variables := subgraphTestServer(t, `{"data":{"domains":[{"id":"0x01","name":"a.foo.eth","labelName":"a"},{"id":"0x02","name":"b.foo.eth","labelName":"b"}]}}`)
+
+	client := NewSubgraphClient(srv.URL, nil)
+	domains, err := client.SubdomainsOf(context.Background(), "foo.eth")
+	require.NoError(t, err)
+	require.Len(t, domains, 2)
+	require.Equal(t, "0xde9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f", (*variables)["parent"])
+	require.Equal(t, "a.foo.eth", domains[0].Name)
+	require.Equal(t, "b.foo.eth", domains[1].Name)
+	require.True(t, domains[0].Expiry.IsZero())
+}
+
+func TestSubgraphRegistrationHistory(t *testing.T) {
+	srv, _ := subgraphTestServer(t, `{"data":{"registrations":[{"events":[
+  {"__typename":"NameRegistered","blockNumber":100,"transactionID":"0x01","registrant":{"id":"0x0000000000000000000000000000000000000001"},"expiryDate":"2000"},
+  {"__typename":"NameTransferred","blockNumber":200,"transactionID":"0x02","newOwner":{"id":"0x0000000000000000000000000000000000000002"}},
+  {"__typename":"NameRenewed","blockNumber":300,"transactionID":"0x03","expiryDate":"3000"}
+]}]}}`)
+
+	client := NewSubgraphClient(srv.URL, nil)
+	events, err := client.RegistrationHistory(context.Background(), "foo.eth")
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+	require.Equal(t, "NameRegistered", events[0].Type)
+	require.Equal(t, uint64(100), events[0].BlockNumber)
+	require.Equal(t, common.HexToAddress("0x01"), events[0].Registrant)
+	require.Equal(t, time.Unix(2000, 0), events[0].Expiry)
+	require.Equal(t, "NameTransferred", events[1].Type)
+	require.Equal(t, common.HexToAddress("0x02"), events[1].Registrant)
+	require.Equal(t, "NameRenewed", events[2].Type)
+	require.Equal(t, time.Unix(3000, 0), events[2].Expiry)
+}
+
+func TestSubgraphPagination(t *testing.T) {
+	lastIDs := make([]string, 0)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables map[string]interface{} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		lastID := req.Variables["lastID"].(string)
+		lastIDs = append(lastIDs, lastID)
+		domains := make([]map[string]interface{}, 0)
+		switch lastID {
+		case "":
+			// A full page, in ID order, created in reverse order.
+			for i := 0; i < subgraphPageSize; i++ {
+				domains = append(domains, map[string]interface{}{
+					"id":        fmt.Sprintf("0x%04x", i+2),
+					"createdAt": fmt.Sprintf("%d", 2*subgraphPageSize-i),
+				})
+			}
+		case fmt.Sprintf("0x%04x", subgraphPageSize+1):
+			domains = append(domains, map[string]interface{}{"id": "0xffff", "createdAt": "1"})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"domains": domains}})
+	}))
+	defer srv.Close()
+
+	client := NewSubgraphClient(srv.URL, nil)
+	domains, err := client.SubdomainsOf(context.Background(), "foo.eth")
+	require.NoError(t, err)
+	require.Len(t, domains, subgraphPageSize+1)
+	require.Equal(t, []string{"", fmt.Sprintf("0x%04x", subgraphPageSize+1)}, lastIDs)
+	// Domains are returned in the order in which they were created.
+	require.Equal(t, common.HexToHash("0xffff"), domains[0].NameHash)
+	require.Equal(t, common.HexToHash(fmt.Sprintf("0x%04x", subgraphPageSize+1)), domains[1].NameHash)
+
+	_, err = NewSubgraphClient("", nil).SubdomainsOf(context.Background(), "foo.eth")
+	require.EqualError(t, err, "no subgraph endpoint supplied")
+}
+
+func TestSubgraphErrors(t *testing.T) {
+	srv, _ := subgraphTestServer(t, `{"errors":[{"message":"bad query"}]}`)
+
+	client := NewSubgraphClient(srv.URL, nil)
+	_, err := client.SubdomainsOf(context.Background(), "foo.eth")
+	require.EqualError(t, err, "subgraph returned errors: bad query")
+}
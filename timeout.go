@@ -0,0 +1,144 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TimeoutBackend is a contract backend that bounds the time taken by each
+// request to an upstream backend.  Requests that take longer fail with
+// context.DeadlineExceeded.  If the upstream backend follows offchain lookups
+// then the timeout covers the whole call, including requests to gateways.
+type TimeoutBackend struct {
+	backend bind.ContractBackend
+	timeout time.Duration
+}
+
+var _ bind.ContractBackend = (*TimeoutBackend)(nil)
+
+// NewTimeoutBackend creates a backend that allows each request to the
+// upstream backend up to the given time.
+func NewTimeoutBackend(backend bind.ContractBackend, timeout time.Duration) (*TimeoutBackend, error) {
+	if backend == nil {
+		return nil, errors.New("no backend supplied")
+	}
+	if timeout <= 0 {
+		return nil, errors.New("timeout must be greater than 0")
+	}
+
+	return &TimeoutBackend{
+		backend: backend,
+		timeout: timeout,
+	}, nil
+}
+
+// Unwrap returns the upstream backend.
+func (b *TimeoutBackend) Unwrap() bind.ContractBackend {
+	return b.backend
+}
+
+// withTimeout returns a context for a request.
+func (b *TimeoutBackend) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, b.timeout)
+}
+
+// CodeAt returns the code of the given account.
+func (b *TimeoutBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.backend.CodeAt(ctx, contract, blockNumber)
+}
+
+// CallContract executes an Ethereum contract call with the specified data as the input.
+func (b *TimeoutBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.backend.CallContract(ctx, call, blockNumber)
+}
+
+// HeaderByNumber returns a block header from the current canonical chain.
+func (b *TimeoutBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.backend.HeaderByNumber(ctx, number)
+}
+
+// PendingCodeAt returns the code of the given account in the pending state.
+func (b *TimeoutBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.backend.PendingCodeAt(ctx, account)
+}
+
+// PendingNonceAt retrieves the current pending nonce associated with an account.
+func (b *TimeoutBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.backend.PendingNonceAt(ctx, account)
+}
+
+// SuggestGasPrice retrieves the currently suggested gas price.
+func (b *TimeoutBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.backend.SuggestGasPrice(ctx)
+}
+
+// SuggestGasTipCap retrieves the currently suggested gas tip cap.
+func (b *TimeoutBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.backend.SuggestGasTipCap(ctx)
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction.
+func (b *TimeoutBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.backend.EstimateGas(ctx, call)
+}
+
+// SendTransaction injects the transaction into the pending pool for execution.
+func (b *TimeoutBackend) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.backend.SendTransaction(ctx, tx)
+}
+
+// FilterLogs executes a log filter operation.
+func (b *TimeoutBackend) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	ctx, cancel := b.withTimeout(ctx)
+	defer cancel()
+	return b.backend.FilterLogs(ctx, query)
+}
+
+// SubscribeFilterLogs creates a background log filtering operation.  The
+// timeout does not apply, as some backends bind the lifetime of the
+// subscription to its context.
+func (b *TimeoutBackend) SubscribeFilterLogs(ctx context.Context, query ethereum.FilterQuery, ch chan<- types.Log) (ethereum.Subscription, error) {
+	return b.backend.SubscribeFilterLogs(ctx, query, ch)
+}
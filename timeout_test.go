@@ -0,0 +1,154 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+)
+
+// slowBackend is a backend whose contract calls take the given time, unless
+// their context ends first.
+type slowBackend struct {
+	*mockBackend
+	delay time.Duration
+}
+
+func (b *slowBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(b.delay):
+	}
+	return b.mockBackend.CallContract(ctx, call, blockNumber)
+}
+
+func TestNewTimeoutBackend(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend *mockBackend
+		timeout time.Duration
+		err     string
+	}{
+		{
+			name:    "NoBackend",
+			timeout: time.Second,
+			err:     "no backend supplied",
+		},
+		{
+			name:    "ZeroTimeout",
+			backend: newMockBackend(),
+			err:     "timeout must be greater than 0",
+		},
+		{
+			name:    "Good",
+			backend: newMockBackend(),
+			timeout: time.Second,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var err error
+			if test.backend == nil {
+				_, err = NewTimeoutBackend(nil, test.timeout)
+			} else {
+				_, err = NewTimeoutBackend(test.backend, test.timeout)
+			}
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTimeoutBackend(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("timeout.eth", address, address)
+
+	fast, err := NewTimeoutBackend(m.backend, time.Second)
+	require.NoError(t, err)
+	resolved, err := Resolve(fast, "timeout.eth", EthereumMainnet, WithoutUniversalResolver())
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+
+	slow, err := NewTimeoutBackend(&slowBackend{mockBackend: m.backend, delay: time.Minute}, 20*time.Millisecond)
+	require.NoError(t, err)
+	started := time.Now()
+	_, err = slow.CallContract(context.Background(), ethereum.CallMsg{To: &m.registryAddr}, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(started), 10*time.Second)
+}
+
+func TestWithTimeout(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("timeout.eth", address, address)
+	backend := &slowBackend{mockBackend: m.backend, delay: time.Minute}
+
+	started := time.Now()
+	_, err := Resolve(backend, "timeout.eth", EthereumMainnet, WithoutUniversalResolver(), WithTimeout(20*time.Millisecond))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(started), 10*time.Second)
+
+	// A timeout that is not reached has no effect.
+	backend.delay = 0
+	resolved, err := Resolve(backend, "timeout.eth", EthereumMainnet, WithoutUniversalResolver(), WithTimeout(time.Second))
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+}
+
+func TestClientTimeoutGateway(t *testing.T) {
+	resolverAddr := common.HexToAddress("0x00000000000000000000000000000000000cc1b0")
+	released := make(chan struct{})
+	gateway := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-released:
+		}
+	}))
+	defer gateway.Close()
+	defer close(released)
+
+	backend := newMockBackend()
+	backend.deploy(resolverAddr, mockOffchainResolverABI).on("addr", func(_ []interface{}) ([]interface{}, error) {
+		return nil, &mockRevertError{data: offchainLookupData(&OffchainLookup{
+			Sender:           resolverAddr,
+			URLs:             []string{gateway.URL, gateway.URL},
+			CallbackFunction: [4]byte(crypto.Keccak256([]byte("addr(bytes32)"))[:4]),
+		})}
+	})
+	client, err := NewClient(backend, WithClientTimeout(50*time.Millisecond))
+	require.NoError(t, err)
+
+	data, err := backend.contracts[resolverAddr].abi.Pack("addr", mustNameHash("offchain.eth"))
+	require.NoError(t, err)
+	started := time.Now()
+	_, err = client.Backend().CallContract(context.Background(), ethereum.CallMsg{To: &resolverAddr, Data: data}, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Less(t, time.Since(started), 10*time.Second)
+}
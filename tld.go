@@ -0,0 +1,160 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TLDRegistrar registers names under a top-level domain that has its own
+// registration flow.  L2Controller and RegistrarController are
+// implementations.
+type TLDRegistrar interface {
+	// Register registers a domain for an owner for a duration.
+	Register(opts *bind.TransactOpts,
+		domain string,
+		owner common.Address,
+		duration time.Duration,
+		resolver common.Address,
+		data [][]byte,
+		reverseRecord bool,
+	) (
+		*types.Transaction,
+		error,
+	)
+	// Renew extends the registration of a domain by a duration.
+	Renew(opts *bind.TransactOpts, domain string, duration time.Duration) (*types.Transaction, error)
+}
+
+var (
+	_ TLDRegistrar = (*L2Controller)(nil)
+	_ TLDRegistrar = (*RegistrarController)(nil)
+)
+
+// TLDHandler handles names under a top-level domain that is not managed by
+// the chain's ENS registry and registrar, for example an alternative
+// namespace or an enterprise's private TLD.
+type TLDHandler struct {
+	// TLD is the top-level domain, for example "box".
+	TLD string
+	// Registry is the address of the registry holding names under the TLD.
+	// If not supplied the chain's registry is used.
+	Registry common.Address
+	// Registrar creates the registrar for names under the TLD.  If not
+	// supplied names under the TLD cannot be registered or renewed with
+	// Client.Register and Client.Renew.
+	Registrar func(backend bind.ContractBackend) (TLDRegistrar, error)
+}
+
+type tldHandlerKey struct {
+	chainId ChainId
+	tld     string
+}
+
+var (
+	tldHandlersMu sync.RWMutex
+	tldHandlers   = map[tldHandlerKey]*TLDHandler{}
+)
+
+// RegisterTLDHandler registers the handler for a top-level domain on a chain,
+// replacing any existing handler for the same TLD.  The handler is consulted
+// when resolving names under the TLD and when registering them with Client.
+// Handlers should be registered before names under the TLD are resolved, as
+// resolvers found beforehand are cached.
+func RegisterTLDHandler(chainId ChainId, handler *TLDHandler) error {
+	if handler == nil {
+		return errors.New("no TLD handler supplied")
+	}
+	if handler.TLD == "" {
+		return errors.New("no TLD supplied")
+	}
+	tld, err := NormaliseDomain(handler.TLD)
+	if err != nil {
+		return err
+	}
+	if strings.Contains(tld, ".") {
+		return fmt.Errorf("%s is not a top-level domain", tld)
+	}
+	if tld == Tld(ChainConfigFor(chainId).Root) {
+		return fmt.Errorf("%s is handled by the chain's registrar", tld)
+	}
+
+	// Take a copy so that later changes by the caller have no effect.
+	registered := *handler
+	registered.TLD = tld
+
+	tldHandlersMu.Lock()
+	tldHandlers[tldHandlerKey{chainId: chainId, tld: tld}] = &registered
+	tldHandlersMu.Unlock()
+
+	return nil
+}
+
+// UnregisterTLDHandler removes the handler for a top-level domain on a chain.
+func UnregisterTLDHandler(chainId ChainId, tld string) {
+	tldHandlersMu.Lock()
+	delete(tldHandlers, tldHandlerKey{chainId: chainId, tld: Tld(tld)})
+	tldHandlersMu.Unlock()
+}
+
+// TLDHandlerFor returns the handler for the top-level domain of a name on a
+// chain, if one has been registered.
+func TLDHandlerFor(chainId ChainId, domain string) (*TLDHandler, bool) {
+	tldHandlersMu.RLock()
+	defer tldHandlersMu.RUnlock()
+
+	handler, exists := tldHandlers[tldHandlerKey{chainId: chainId, tld: Tld(domain)}]
+	if !exists {
+		return nil, false
+	}
+	// Return a copy so that the caller cannot alter the registered handler.
+	res := *handler
+
+	return &res, true
+}
+
+// registryFor obtains the registry holding a domain, which is the registry
+// of the domain's TLD handler if it has one, otherwise the chain's registry.
+func registryFor(backend bind.ContractBackend, domain string, chainId ChainId) (*Registry, error) {
+	if handler, exists := TLDHandlerFor(chainId, domain); exists && handler.Registry != UnknownAddress {
+		return NewRegistryAt(backend, handler.Registry)
+	}
+
+	return NewRegistry(backend, chainId)
+}
+
+// tldRegistrarFor obtains the registrar for a domain from its TLD handler.
+// It returns false if the domain does not have a TLD handler with a
+// registrar.
+func tldRegistrarFor(backend bind.ContractBackend, domain string, chainId ChainId) (TLDRegistrar, bool, error) {
+	handler, exists := TLDHandlerFor(chainId, domain)
+	if !exists || handler.Registrar == nil {
+		return nil, false, nil
+	}
+	registrar, err := handler.Registrar(backend)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return registrar, true, nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/registry"
+)
+
+// mockTLDRegistrar is a TLD registrar that records the names it registers
+// and renews.
+type mockTLDRegistrar struct {
+	registered []string
+	renewed    []string
+}
+
+func (r *mockTLDRegistrar) Register(_ *bind.TransactOpts,
+	domain string,
+	_ common.Address,
+	_ time.Duration,
+	_ common.Address,
+	_ [][]byte,
+	_ bool,
+) (
+	*types.Transaction,
+	error,
+) {
+	r.registered = append(r.registered, domain)
+	return types.NewTx(&types.LegacyTx{}), nil
+}
+
+func (r *mockTLDRegistrar) Renew(_ *bind.TransactOpts, domain string, _ time.Duration) (*types.Transaction, error) {
+	r.renewed = append(r.renewed, domain)
+	return types.NewTx(&types.LegacyTx{}), nil
+}
+
+func TestRegisterTLDHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		chainId ChainId
+		handler *TLDHandler
+		err     string
+	}{
+		{
+			name:    "Nil",
+			chainId: EthereumMainnet,
+			err:     "no TLD handler supplied",
+		},
+		{
+			name:    "NoTLD",
+			chainId: EthereumMainnet,
+			handler: &TLDHandler{},
+			err:     "no TLD supplied",
+		},
+		{
+			name:    "NotTopLevel",
+			chainId: EthereumMainnet,
+			handler: &TLDHandler{TLD: "foo.box"},
+			err:     "foo.box is not a top-level domain",
+		},
+		{
+			name:    "ChainRoot",
+			chainId: EthereumMainnet,
+			handler: &TLDHandler{TLD: "eth"},
+			err:     "eth is handled by the chain's registrar",
+		},
+		{
+			name:    "Good",
+			chainId: EthereumMainnet,
+			handler: &TLDHandler{TLD: "BOX"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := RegisterTLDHandler(test.chainId, test.handler)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			defer UnregisterTLDHandler(test.chainId, test.handler.TLD)
+
+			handler, exists := TLDHandlerFor(test.chainId, "foo.box")
+			require.True(t, exists)
+			require.Equal(t, "box", handler.TLD)
+			_, exists = TLDHandlerFor(BaseMainnet, "foo.box")
+			require.False(t, exists)
+		})
+	}
+
+	_, exists := TLDHandlerFor(EthereumMainnet, "foo.box")
+	require.False(t, exists)
+}
+
+func TestTLDHandlerResolution(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000b0c")
+	node := mustNameHash("foo.box")
+	m.mu.Lock()
+	m.addrs[node] = address
+	m.mu.Unlock()
+
+	// The name is only held in the TLD's own registry.
+	boxRegistryAddr := common.HexToAddress("0x0000000000000000000000000000000000000b01")
+	m.backend.deploy(boxRegistryAddr, registry.ContractABI).
+		on("owner", func(args []interface{}) ([]interface{}, error) {
+			if args[0].([32]byte) == node {
+				return []interface{}{address}, nil
+			}
+			return []interface{}{UnknownAddress}, nil
+		}).
+		on("resolver", func(args []interface{}) ([]interface{}, error) {
+			if args[0].([32]byte) == node {
+				return []interface{}{m.resolverAddr}, nil
+			}
+			return []interface{}{UnknownAddress}, nil
+		})
+
+	_, err := Resolve(m.backend, "foo.box", EthereumMainnet)
+	require.ErrorIs(t, err, ErrUnregisteredName)
+
+	registrar := &mockTLDRegistrar{}
+	require.NoError(t, RegisterTLDHandler(EthereumMainnet, &TLDHandler{
+		TLD:      "box",
+		Registry: boxRegistryAddr,
+		Registrar: func(_ bind.ContractBackend) (TLDRegistrar, error) {
+			return registrar, nil
+		},
+	}))
+	defer UnregisterTLDHandler(EthereumMainnet, "box")
+
+	resolved, err := Resolve(m.backend, "foo.box", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+
+	// Secure reverse resolution finds the resolver in the TLD's registry.
+	m.setReverse(address, "foo.box")
+	res, err := SecureReverseResolve(m.backend, address, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, m.resolverAddr, res.Resolver)
+	require.True(t, res.ForwardMatch)
+
+	client, err := NewClient(m.backend)
+	require.NoError(t, err)
+	opts := testTransactOpts(t)
+	_, err = client.Register(opts, "bar.box", address, 365*24*time.Hour, UnknownAddress, nil, false)
+	require.NoError(t, err)
+	_, err = client.Renew(opts, "foo.box", 365*24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, []string{"bar.box"}, registrar.registered)
+	require.Equal(t, []string{"foo.box"}, registrar.renewed)
+
+	// Names under other TLDs are unaffected.
+	_, err = client.Register(opts, "bar.eth", address, 365*24*time.Hour, UnknownAddress, nil, false)
+	require.EqualError(t, err, "chain 1 registers with commit and reveal; use Name to register")
+
+	// Registrar errors are returned.
+	require.NoError(t, RegisterTLDHandler(EthereumMainnet, &TLDHandler{
+		TLD: "box",
+		Registrar: func(_ bind.ContractBackend) (TLDRegistrar, error) {
+			return nil, errors.New("registrar unavailable")
+		},
+	}))
+	_, err = client.Renew(opts, "foo.box", 365*24*time.Hour)
+	require.EqualError(t, err, "registrar unavailable")
+}
@@ -17,9 +17,12 @@ package ens
 import (
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/wealdtech/go-ens/v3/contracts/baseregistrar"
 )
 
 // DeriveTokenID derive tokenID from the ENS domain.
@@ -53,3 +56,62 @@ func DeriveTokenID(backend bind.ContractBackend, domain string) (string, error)
 	}
 	return tokenID.String(), nil
 }
+
+// RegistrarTokenID returns the ERC-721 token ID of a second-level domain in
+// the base registrar, which is the labelhash of the domain's label.
+func RegistrarTokenID(domain string) (*big.Int, error) {
+	domain, err := NormaliseDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	if DomainLevel(domain) != 1 {
+		return nil, fmt.Errorf("%s is not a second-level domain", domain)
+	}
+	label, err := DomainPart(domain, 1)
+	if err != nil {
+		return nil, err
+	}
+	labelHash, err := LabelHash(label)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(labelHash[:]), nil
+}
+
+// WrapperTokenID returns the ERC-1155 token ID of a domain in the name
+// wrapper, which is the namehash of the domain.
+func WrapperTokenID(domain string) (*big.Int, error) {
+	nameHash, err := NameHash(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(nameHash[:]), nil
+}
+
+// TokenIDToHash converts a token ID to the labelhash or namehash from which
+// it was derived.
+func TokenIDToHash(tokenID *big.Int) ([32]byte, error) {
+	if tokenID == nil || tokenID.Sign() < 0 || tokenID.BitLen() > 256 {
+		return [32]byte{}, errors.New("invalid token ID")
+	}
+
+	return common.BigToHash(tokenID), nil
+}
+
+// OwnerOfToken returns the owner of a token in the base registrar or the name
+// wrapper, both of which provide ownerOf().  The base registrar reverts for
+// tokens of expired names; the name wrapper returns UnknownAddress for tokens
+// that do not exist.
+func OwnerOfToken(backend bind.ContractBackend, contract common.Address, tokenID *big.Int) (common.Address, error) {
+	if tokenID == nil {
+		return UnknownAddress, errors.New("invalid token ID")
+	}
+	token, err := baseregistrar.NewContract(contract, backend)
+	if err != nil {
+		return UnknownAddress, err
+	}
+
+	return token.OwnerOf(nil, tokenID)
+}
@@ -1,10 +1,13 @@
 package ens
 
 import (
+	"math/big"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/namewrapper"
 )
 
 func TestDeriveTokenId(t *testing.T) {
@@ -46,3 +49,81 @@ func TestDeriveTokenId(t *testing.T) {
 		})
 	}
 }
+
+func TestRegistrarTokenID(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+		err      string
+	}{
+		{
+			name:     "Valid",
+			input:    "vitalik.eth",
+			expected: "79233663829379634837589865448569342784712482819484549289560981379859480642508",
+		},
+		{
+			name:     "Unnormalised",
+			input:    "VITALIK.eth",
+			expected: "79233663829379634837589865448569342784712482819484549289560981379859480642508",
+		},
+		{
+			name:  "Subdomain",
+			input: "foo.vitalik.eth",
+			err:   "foo.vitalik.eth is not a second-level domain",
+		},
+		{
+			name:  "TLD",
+			input: "eth",
+			err:   "eth is not a second-level domain",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tokenID, err := RegistrarTokenID(test.input)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.expected, tokenID.String())
+		})
+	}
+}
+
+func TestWrapperTokenID(t *testing.T) {
+	tokenID, err := WrapperTokenID("vitalik.eth")
+	require.NoError(t, err)
+	hash, err := TokenIDToHash(tokenID)
+	require.NoError(t, err)
+	require.Equal(t, mustNameHash("vitalik.eth"), hash)
+
+	_, err = TokenIDToHash(new(big.Int).Lsh(big.NewInt(1), 256))
+	require.EqualError(t, err, "invalid token ID")
+	_, err = TokenIDToHash(big.NewInt(-1))
+	require.EqualError(t, err, "invalid token ID")
+}
+
+func TestOwnerOfToken(t *testing.T) {
+	backend := newMockBackend()
+	owner := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	wrapper := ChainConfigFor(EthereumMainnet).NameWrapper
+	tokenID, err := WrapperTokenID("wrapped.eth")
+	require.NoError(t, err)
+	backend.deploy(wrapper, namewrapper.ContractABI).
+		on("ownerOf", func(args []interface{}) ([]interface{}, error) {
+			if args[0].(*big.Int).Cmp(tokenID) == 0 {
+				return []interface{}{owner}, nil
+			}
+			return []interface{}{UnknownAddress}, nil
+		})
+
+	res, err := OwnerOfToken(backend, wrapper, tokenID)
+	require.NoError(t, err)
+	require.Equal(t, owner, res)
+
+	res, err = OwnerOfToken(backend, wrapper, big.NewInt(1))
+	require.NoError(t, err)
+	require.Equal(t, UnknownAddress, res)
+}
@@ -0,0 +1,59 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestResolveSpans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("traced.eth", address, address)
+
+	_, err := Resolve(m.backend, "traced.eth", EthereumMainnet)
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	names := make([]string, len(spans))
+	for i := range spans {
+		names[i] = spans[i].Name()
+	}
+	require.Equal(t, []string{"ens.registry.Lookup", "ens.resolver.Fetch", "ens.NewResolver", "ens.resolver.Addr", "ens.Resolve"}, names)
+
+	root := spans[len(spans)-1]
+	for _, span := range spans[:len(spans)-1] {
+		require.Equal(t, root.SpanContext().TraceID(), span.SpanContext().TraceID())
+	}
+	require.Equal(t, root.SpanContext().SpanID(), spans[2].Parent().SpanID())
+	require.Equal(t, spans[2].SpanContext().SpanID(), spans[0].Parent().SpanID())
+
+	_, err = Resolve(m.backend, "unregistered.eth", EthereumMainnet)
+	require.EqualError(t, err, "unregistered name")
+	spans = recorder.Ended()
+	require.Equal(t, codes.Error, spans[len(spans)-1].Status().Code)
+}
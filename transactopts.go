@@ -0,0 +1,227 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// FeeStrategy provides EIP-1559 fees for transactions.
+type FeeStrategy interface {
+	// Fees returns the maximum fee per gas and maximum priority fee per gas.
+	Fees(ctx context.Context, backend bind.ContractBackend) (*big.Int, *big.Int, error)
+}
+
+type fixedFees struct {
+	maxFee      *big.Int
+	priorityFee *big.Int
+}
+
+// FixedFees is a fee strategy that always uses the given fees.
+func FixedFees(maxFeePerGas *big.Int, maxPriorityFeePerGas *big.Int) FeeStrategy {
+	return &fixedFees{
+		maxFee:      maxFeePerGas,
+		priorityFee: maxPriorityFeePerGas,
+	}
+}
+
+func (f *fixedFees) Fees(_ context.Context, _ bind.ContractBackend) (*big.Int, *big.Int, error) {
+	if f.maxFee == nil || f.priorityFee == nil {
+		return nil, nil, errors.New("fees not supplied")
+	}
+	if f.priorityFee.Cmp(f.maxFee) > 0 {
+		return nil, nil, errors.New("priority fee greater than maximum fee")
+	}
+	return new(big.Int).Set(f.maxFee), new(big.Int).Set(f.priorityFee), nil
+}
+
+type baseFeeMultiplierFees struct {
+	multiplier  *big.Float
+	priorityFee *big.Int
+}
+
+// BaseFeeMultiplierFees is a fee strategy that sets the maximum fee to a
+// multiple of the latest block's base fee plus the priority fee.  If
+// maxPriorityFeePerGas is nil the priority fee is obtained from the backend.
+func BaseFeeMultiplierFees(multiplier float64, maxPriorityFeePerGas *big.Int) FeeStrategy {
+	return &baseFeeMultiplierFees{
+		multiplier:  big.NewFloat(multiplier),
+		priorityFee: maxPriorityFeePerGas,
+	}
+}
+
+func (f *baseFeeMultiplierFees) Fees(ctx context.Context, backend bind.ContractBackend) (*big.Int, *big.Int, error) {
+	header, err := backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.BaseFee == nil {
+		return nil, nil, errors.New("chain does not support EIP-1559")
+	}
+
+	priorityFee := f.priorityFee
+	if priorityFee == nil {
+		priorityFee, err = backend.SuggestGasTipCap(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	maxFee, _ := new(big.Float).Mul(new(big.Float).SetInt(header.BaseFee), f.multiplier).Int(nil)
+	maxFee.Add(maxFee, priorityFee)
+
+	return maxFee, new(big.Int).Set(priorityFee), nil
+}
+
+// OracleFees is a fee strategy that uses the priority fee suggested by the
+// backend, with a maximum fee of twice the latest base fee plus the priority
+// fee.
+func OracleFees() FeeStrategy {
+	return BaseFeeMultiplierFees(2, nil)
+}
+
+// NonceManager hands out sequential nonces for accounts, so that multiple
+// transactions can be built without waiting for each to be mined.  It is safe
+// for concurrent use.
+type NonceManager struct {
+	backend bind.ContractBackend
+	mu      sync.Mutex
+	nonces  map[common.Address]uint64
+}
+
+// NewNonceManager creates a nonce manager.
+func NewNonceManager(backend bind.ContractBackend) *NonceManager {
+	return &NonceManager{
+		backend: backend,
+		nonces:  make(map[common.Address]uint64),
+	}
+}
+
+// Next returns the next nonce for an account.  The first nonce for an account
+// is its pending nonce.
+func (n *NonceManager) Next(ctx context.Context, account common.Address) (uint64, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	nonce, exists := n.nonces[account]
+	if !exists {
+		var err error
+		nonce, err = n.backend.PendingNonceAt(ctx, account)
+		if err != nil {
+			return 0, err
+		}
+	}
+	n.nonces[account] = nonce + 1
+
+	return nonce, nil
+}
+
+// Reset forgets the nonce for an account, so that the next nonce is obtained
+// from the backend.  This should be called if a transaction using a nonce
+// from the manager was not sent.
+func (n *NonceManager) Reset(account common.Address) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	delete(n.nonces, account)
+}
+
+// TransactOptsBuilder builds transaction options for write operations.
+type TransactOptsBuilder struct {
+	backend  bind.ContractBackend
+	from     common.Address
+	signer   bind.SignerFn
+	fees     FeeStrategy
+	nonces   *NonceManager
+	gasLimit uint64
+	ctx      context.Context
+}
+
+// NewTransactOptsBuilder creates a builder for transactions from the given
+// account, signed by the given signer.  By default fees are obtained with
+// OracleFees and nonces are left to the backend.
+func NewTransactOptsBuilder(backend bind.ContractBackend, from common.Address, signer bind.SignerFn) *TransactOptsBuilder {
+	return &TransactOptsBuilder{
+		backend: backend,
+		from:    from,
+		signer:  signer,
+		fees:    OracleFees(),
+	}
+}
+
+// WithFees sets the fee strategy.
+func (b *TransactOptsBuilder) WithFees(fees FeeStrategy) *TransactOptsBuilder {
+	b.fees = fees
+	return b
+}
+
+// WithNonceManager sets the nonce manager.
+func (b *TransactOptsBuilder) WithNonceManager(nonces *NonceManager) *TransactOptsBuilder {
+	b.nonces = nonces
+	return b
+}
+
+// WithGasLimit sets a fixed gas limit.  If not set the gas limit is estimated.
+func (b *TransactOptsBuilder) WithGasLimit(gasLimit uint64) *TransactOptsBuilder {
+	b.gasLimit = gasLimit
+	return b
+}
+
+// WithContext sets the context for the transaction.
+func (b *TransactOptsBuilder) WithContext(ctx context.Context) *TransactOptsBuilder {
+	b.ctx = ctx
+	return b
+}
+
+// Build builds transaction options, obtaining fees and a nonce if required.
+// The options are for a single transaction.
+func (b *TransactOptsBuilder) Build() (*bind.TransactOpts, error) {
+	if b.signer == nil {
+		return nil, errors.New("no signer supplied")
+	}
+	ctx := b.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	maxFee, priorityFee, err := b.fees.Fees(ctx, b.backend)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &bind.TransactOpts{
+		From:      b.from,
+		Signer:    b.signer,
+		GasFeeCap: maxFee,
+		GasTipCap: priorityFee,
+		GasLimit:  b.gasLimit,
+		Context:   b.ctx,
+	}
+
+	if b.nonces != nil {
+		nonce, err := b.nonces.Next(ctx, b.from)
+		if err != nil {
+			return nil, err
+		}
+		opts.Nonce = new(big.Int).SetUint64(nonce)
+	}
+
+	return opts, nil
+}
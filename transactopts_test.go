@@ -0,0 +1,155 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/util"
+)
+
+func TestFeeStrategies(t *testing.T) {
+	backend := newMockBackend()
+	gwei := big.NewInt(1_000_000_000)
+
+	tests := []struct {
+		name        string
+		strategy    FeeStrategy
+		maxFee      *big.Int
+		priorityFee *big.Int
+		err         string
+	}{
+		{
+			name:        "Fixed",
+			strategy:    FixedFees(big.NewInt(5), big.NewInt(2)),
+			maxFee:      big.NewInt(5),
+			priorityFee: big.NewInt(2),
+		},
+		{
+			name:     "FixedBadTip",
+			strategy: FixedFees(big.NewInt(5), big.NewInt(6)),
+			err:      "priority fee greater than maximum fee",
+		},
+		{
+			name:        "Oracle",
+			strategy:    OracleFees(),
+			maxFee:      new(big.Int).Mul(gwei, big.NewInt(3)),
+			priorityFee: gwei,
+		},
+		{
+			name:        "Multiplier",
+			strategy:    BaseFeeMultiplierFees(1.5, big.NewInt(100)),
+			maxFee:      big.NewInt(1_500_000_100),
+			priorityFee: big.NewInt(100),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			maxFee, priorityFee, err := test.strategy.Fees(context.Background(), backend)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.maxFee, maxFee)
+			require.Equal(t, test.priorityFee, priorityFee)
+		})
+	}
+}
+
+func TestTransactOptsBuilder(t *testing.T) {
+	m := newMockENS()
+	key := testTransactOpts(t)
+	m.register("builder.eth", key.From, key.From)
+	resolver, err := NewResolver(m.backend, "builder.eth", EthereumMainnet)
+	require.NoError(t, err)
+
+	_, err = NewTransactOptsBuilder(m.backend, key.From, nil).Build()
+	require.EqualError(t, err, "no signer supplied")
+
+	nonces := NewNonceManager(m.backend)
+	builder := NewTransactOptsBuilder(m.backend, key.From, key.Signer).
+		WithFees(FixedFees(big.NewInt(3_000_000_000), big.NewInt(1_000_000_000))).
+		WithNonceManager(nonces).
+		WithGasLimit(100_000)
+
+	for i := 0; i < 3; i++ {
+		opts, err := builder.Build()
+		require.NoError(t, err)
+		require.Equal(t, big.NewInt(int64(i)), opts.Nonce)
+		tx, err := resolver.SetAddress(opts, key.From)
+		require.NoError(t, err)
+		require.Equal(t, uint64(i), tx.Nonce())
+		require.Equal(t, uint64(100_000), tx.Gas())
+		require.Equal(t, big.NewInt(3_000_000_000), tx.GasFeeCap())
+		require.Equal(t, big.NewInt(1_000_000_000), tx.GasTipCap())
+	}
+
+	nonces.Reset(key.From)
+	nonce, err := nonces.Next(context.Background(), key.From)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), nonce)
+}
+
+// kmsSigner is a transaction signer standing in for a remote key management
+// service.
+type kmsSigner struct {
+	key    *ecdsa.PrivateKey
+	signed int
+}
+
+func (s *kmsSigner) Address() common.Address {
+	return crypto.PubkeyToAddress(s.key.PublicKey)
+}
+
+func (s *kmsSigner) SignTx(_ context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	s.signed++
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), s.key)
+}
+
+func TestTransactOptsBuilderRemoteSigner(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer := &kmsSigner{key: key}
+
+	m := newMockENS()
+	m.register("remote.eth", signer.Address(), signer.Address())
+	resolver, err := NewResolver(m.backend, "remote.eth", EthereumMainnet)
+	require.NoError(t, err)
+
+	opts, err := NewTransactOptsBuilder(m.backend, signer.Address(), util.RemoteSigner(context.Background(), big.NewInt(1), signer)).
+		WithGasLimit(100_000).
+		Build()
+	require.NoError(t, err)
+	tx, err := resolver.SetAddress(opts, signer.Address())
+	require.NoError(t, err)
+	require.Equal(t, 1, signer.signed)
+
+	sender, err := types.Sender(types.LatestSignerForChainID(big.NewInt(1)), tx)
+	require.NoError(t, err)
+	require.Equal(t, signer.Address(), sender)
+
+	opts.From = common.HexToAddress("0x0000000000000000000000000000000000000001")
+	_, err = resolver.SetAddress(opts, signer.Address())
+	require.EqualError(t, err, "not authorized to sign this account")
+}
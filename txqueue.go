@@ -0,0 +1,327 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// TxOperation is a write operation that creates and sends a transaction
+// using the supplied transaction options, for example:
+//
+//	func(opts *bind.TransactOpts) (*types.Transaction, error) {
+//		return resolver.SetText(opts, "url", "https://example.com/")
+//	}
+//
+// An operation may be called more than once with the same nonce and higher
+// fees, to replace a transaction that has not been mined.
+type TxOperation func(opts *bind.TransactOpts) (*types.Transaction, error)
+
+// TxResult is the outcome of a queued operation.
+type TxResult struct {
+	// Index is the position of the operation in the queue.
+	Index int
+	// Transaction is the transaction that was mined, or the latest
+	// transaction sent if none was mined.
+	Transaction *types.Transaction
+	// Receipt is the receipt of the mined transaction.
+	Receipt *types.Receipt
+	// Err is the reason that the operation failed, if it did.
+	Err error
+}
+
+// TxQueueOption is an option for a transaction queue.
+type TxQueueOption func(*txQueueOptions)
+
+type txQueueOptions struct {
+	maxInFlight  int
+	bumpInterval time.Duration
+	bumpPercent  uint64
+	maxFee       *big.Int
+	pollInterval time.Duration
+}
+
+// WithMaxInFlight sets the maximum number of transactions that the queue
+// has sent but not yet seen mined.  If not set this is 16.
+func WithMaxInFlight(maxInFlight int) TxQueueOption {
+	return func(o *txQueueOptions) {
+		o.maxInFlight = maxInFlight
+	}
+}
+
+// WithFeeBump sets the time after which a transaction that has not been mined
+// is replaced, and the percentage by which its fees are increased.  If not
+// set transactions are replaced after 3 minutes with fees 12% higher.
+func WithFeeBump(interval time.Duration, percent uint64) TxQueueOption {
+	return func(o *txQueueOptions) {
+		o.bumpInterval = interval
+		o.bumpPercent = percent
+	}
+}
+
+// WithMaxFeePerGas sets the maximum fee per gas to which fees are bumped.
+// If not set fees are bumped without limit.
+func WithMaxFeePerGas(maxFee *big.Int) TxQueueOption {
+	return func(o *txQueueOptions) {
+		o.maxFee = maxFee
+	}
+}
+
+// WithReceiptPollInterval sets the interval at which receipts are checked.
+// If not set this is 5 seconds.
+func WithReceiptPollInterval(interval time.Duration) TxQueueOption {
+	return func(o *txQueueOptions) {
+		o.pollInterval = interval
+	}
+}
+
+// TxQueue sends many write operations from a single account, for example
+// to create thousands of subdomains.  It assigns nonces, limits the number
+// of transactions in flight, replaces transactions that are slow to be mined
+// with higher fees, and reports the receipt of each operation.
+type TxQueue struct {
+	backend  bind.ContractBackend
+	receipts bind.DeployBackend
+	builder  TransactOptsBuilder
+	options  *txQueueOptions
+	mu       sync.Mutex
+	ops      []TxOperation
+}
+
+// queuedTx is a transaction that has been sent but not yet seen mined.
+type queuedTx struct {
+	index  int
+	opts   *bind.TransactOpts
+	txs    []*types.Transaction
+	sentAt time.Time
+}
+
+// NewTxQueue creates a transaction queue.  Transaction options are built by
+// the supplied builder, which provides the sender, signer and fee strategy;
+// nonces are managed by the queue, and any nonce manager on the builder is
+// not used.  The backend must be able to supply transaction receipts.
+func NewTxQueue(backend bind.ContractBackend, builder *TransactOptsBuilder, opts ...TxQueueOption) (*TxQueue, error) {
+	if backend == nil {
+		return nil, errors.New("no backend supplied")
+	}
+	if builder == nil {
+		return nil, errors.New("no transaction options builder supplied")
+	}
+	receipts, isDeployBackend := backend.(bind.DeployBackend)
+	if !isDeployBackend {
+		return nil, errors.New("backend does not support transaction receipts")
+	}
+
+	options := &txQueueOptions{
+		maxInFlight:  16,
+		bumpInterval: 3 * time.Minute,
+		bumpPercent:  12,
+		pollInterval: 5 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.maxInFlight <= 0 {
+		return nil, errors.New("maximum in-flight transactions must be greater than 0")
+	}
+	if options.pollInterval <= 0 {
+		return nil, errors.New("receipt poll interval must be greater than 0")
+	}
+	if options.bumpPercent < 10 {
+		// Nodes reject replacements with fees less than 10% higher.
+		return nil, errors.New("fee bump must be at least 10%")
+	}
+
+	queue := &TxQueue{
+		backend:  backend,
+		receipts: receipts,
+		builder:  *builder,
+		options:  options,
+	}
+	queue.builder.nonces = nil
+
+	return queue, nil
+}
+
+// Add adds an operation to the queue, returning its index.
+func (q *TxQueue) Add(op TxOperation) int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.ops = append(q.ops, op)
+
+	return len(q.ops) - 1
+}
+
+// Len returns the number of operations in the queue.
+func (q *TxQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.ops)
+}
+
+// Run sends the queued operations and waits for them to be mined, returning
+// the result of each operation in the order that they were added.  If
+// onResult is supplied it is called with each result as it becomes
+// available.  Operations that fail to send, or whose transactions revert,
+// have Err set in their result; the remaining operations continue.
+//
+// If the context is cancelled the operations that have not completed are
+// given the context's error, which is also returned.  The queue is empty
+// once Run returns.
+func (q *TxQueue) Run(ctx context.Context, onResult func(*TxResult)) ([]*TxResult, error) {
+	q.mu.Lock()
+	ops := q.ops
+	q.ops = nil
+	q.mu.Unlock()
+
+	results := make([]*TxResult, len(ops))
+	complete := func(result *TxResult) {
+		results[result.Index] = result
+		if onResult != nil {
+			onResult(result)
+		}
+	}
+
+	builder := q.builder
+	builder.ctx = ctx
+	from := builder.from
+	nonce, err := q.backend.PendingNonceAt(ctx, from)
+	if err != nil {
+		return nil, err
+	}
+
+	inFlight := make([]*queuedTx, 0, q.options.maxInFlight)
+	next := 0
+	for {
+		// Send operations until the limit of transactions in flight is reached.
+		for len(inFlight) < q.options.maxInFlight && next < len(ops) {
+			index := next
+			next++
+			opts, err := builder.Build()
+			if err != nil {
+				complete(&TxResult{Index: index, Err: err})
+				continue
+			}
+			opts.Nonce = new(big.Int).SetUint64(nonce)
+			tx, err := ops[index](opts)
+			if err != nil {
+				// The nonce was not used, so is kept for the next operation.
+				complete(&TxResult{Index: index, Err: revertError(err)})
+				continue
+			}
+			nonce++
+			inFlight = append(inFlight, &queuedTx{
+				index:  index,
+				opts:   opts,
+				txs:    []*types.Transaction{tx},
+				sentAt: time.Now(),
+			})
+		}
+		if len(inFlight) == 0 && next == len(ops) {
+			return results, nil
+		}
+
+		if err := sleepContext(ctx, q.options.pollInterval); err != nil {
+			for _, queued := range inFlight {
+				complete(&TxResult{Index: queued.index, Transaction: queued.txs[len(queued.txs)-1], Err: err})
+			}
+			for ; next < len(ops); next++ {
+				complete(&TxResult{Index: next, Err: err})
+			}
+			return results, err
+		}
+
+		remaining := inFlight[:0]
+		for _, queued := range inFlight {
+			if result := q.check(ctx, ops[queued.index], queued); result != nil {
+				complete(result)
+				continue
+			}
+			remaining = append(remaining, queued)
+		}
+		inFlight = remaining
+	}
+}
+
+// check checks if any of the transactions sent for a queued operation has
+// been mined, returning the result if so.  If none has been mined and the
+// latest transaction has waited for long enough it is replaced with one
+// with higher fees.
+func (q *TxQueue) check(ctx context.Context, op TxOperation, queued *queuedTx) *TxResult {
+	for _, tx := range queued.txs {
+		receipt, err := q.receipts.TransactionReceipt(ctx, tx.Hash())
+		if err != nil {
+			// Not yet mined, or a transient failure; check again later.
+			continue
+		}
+		result := &TxResult{
+			Index:       queued.index,
+			Transaction: tx,
+			Receipt:     receipt,
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			result.Err = errors.New("transaction reverted")
+		}
+		return result
+	}
+
+	if time.Since(queued.sentAt) < q.options.bumpInterval {
+		return nil
+	}
+	opts := *queued.opts
+	opts.GasFeeCap = bumpFee(opts.GasFeeCap, q.options.bumpPercent)
+	opts.GasTipCap = bumpFee(opts.GasTipCap, q.options.bumpPercent)
+	if opts.GasFeeCap == nil || opts.GasTipCap == nil || (q.options.maxFee != nil && opts.GasFeeCap.Cmp(q.options.maxFee) > 0) {
+		// Fees cannot be bumped further; keep waiting.
+		return nil
+	}
+	if opts.GasTipCap.Cmp(opts.GasFeeCap) > 0 {
+		opts.GasTipCap = new(big.Int).Set(opts.GasFeeCap)
+	}
+	tx, err := op(&opts)
+	if err != nil {
+		// The replacement was rejected, for example because the original
+		// transaction has since been mined; keep waiting.
+		return nil
+	}
+	queued.opts = &opts
+	queued.txs = append(queued.txs, tx)
+	queued.sentAt = time.Now()
+
+	return nil
+}
+
+// bumpFee increases a fee by a percentage, rounding up.
+func bumpFee(fee *big.Int, percent uint64) *big.Int {
+	if fee == nil {
+		return nil
+	}
+	bumped := new(big.Int).Mul(fee, new(big.Int).SetUint64(100+percent))
+	bumped.Add(bumped, big.NewInt(99))
+	bumped.Div(bumped, big.NewInt(100))
+	if bumped.Cmp(fee) <= 0 {
+		bumped = new(big.Int).Add(fee, big.NewInt(1))
+	}
+
+	return bumped
+}
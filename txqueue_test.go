@@ -0,0 +1,271 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/require"
+)
+
+// mockReceiptBackend is a mock backend that supplies receipts for the
+// transactions sent to it that mined returns a receipt for.
+type mockReceiptBackend struct {
+	*mockBackend
+	mined func(tx *types.Transaction) *types.Receipt
+}
+
+func (b *mockReceiptBackend) TransactionReceipt(_ context.Context, hash common.Hash) (*types.Receipt, error) {
+	b.mu.Lock()
+	var sent *types.Transaction
+	for _, tx := range b.sent {
+		if tx.Hash() == hash {
+			sent = tx
+		}
+	}
+	b.mu.Unlock()
+	if sent == nil {
+		return nil, ethereum.NotFound
+	}
+	receipt := b.mined(sent)
+	if receipt == nil {
+		return nil, ethereum.NotFound
+	}
+	receipt.TxHash = hash
+
+	return receipt, nil
+}
+
+// transferOp is an operation that sends a transfer with the given options.
+func transferOp(backend bind.ContractBackend) TxOperation {
+	to := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	return func(opts *bind.TransactOpts) (*types.Transaction, error) {
+		tx, err := opts.Signer(opts.From, types.NewTx(&types.DynamicFeeTx{
+			ChainID:   big.NewInt(1),
+			Nonce:     opts.Nonce.Uint64(),
+			GasFeeCap: opts.GasFeeCap,
+			GasTipCap: opts.GasTipCap,
+			Gas:       opts.GasLimit,
+			To:        &to,
+		}))
+		if err != nil {
+			return nil, err
+		}
+		return tx, backend.SendTransaction(opts.Context, tx)
+	}
+}
+
+func testTxQueueBuilder(t *testing.T, backend bind.ContractBackend) *TransactOptsBuilder {
+	t.Helper()
+	opts := testTransactOpts(t)
+	return NewTransactOptsBuilder(backend, opts.From, opts.Signer).
+		WithFees(FixedFees(big.NewInt(2_000_000_000), big.NewInt(1_000_000_000))).
+		WithGasLimit(21000)
+}
+
+func TestNewTxQueue(t *testing.T) {
+	backend := &mockReceiptBackend{mockBackend: newMockBackend()}
+	builder := testTxQueueBuilder(t, backend)
+
+	tests := []struct {
+		name    string
+		backend bind.ContractBackend
+		builder *TransactOptsBuilder
+		opts    []TxQueueOption
+		err     string
+	}{
+		{
+			name:    "NoBackend",
+			builder: builder,
+			err:     "no backend supplied",
+		},
+		{
+			name:    "NoBuilder",
+			backend: backend,
+			err:     "no transaction options builder supplied",
+		},
+		{
+			name:    "NoReceipts",
+			backend: struct{ bind.ContractBackend }{},
+			builder: builder,
+			err:     "backend does not support transaction receipts",
+		},
+		{
+			name:    "BadMaxInFlight",
+			backend: backend,
+			builder: builder,
+			opts:    []TxQueueOption{WithMaxInFlight(0)},
+			err:     "maximum in-flight transactions must be greater than 0",
+		},
+		{
+			name:    "BadPollInterval",
+			backend: backend,
+			builder: builder,
+			opts:    []TxQueueOption{WithReceiptPollInterval(0)},
+			err:     "receipt poll interval must be greater than 0",
+		},
+		{
+			name:    "BadFeeBump",
+			backend: backend,
+			builder: builder,
+			opts:    []TxQueueOption{WithFeeBump(time.Minute, 5)},
+			err:     "fee bump must be at least 10%",
+		},
+		{
+			name:    "Good",
+			backend: backend,
+			builder: builder,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := NewTxQueue(test.backend, test.builder, test.opts...)
+			if test.err != "" {
+				require.EqualError(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTxQueueRun(t *testing.T) {
+	backend := &mockReceiptBackend{mockBackend: newMockBackend()}
+	backend.mined = func(tx *types.Transaction) *types.Receipt {
+		status := types.ReceiptStatusSuccessful
+		if tx.Nonce() == 3 {
+			status = types.ReceiptStatusFailed
+		}
+		return &types.Receipt{Status: status}
+	}
+
+	queue, err := NewTxQueue(backend, testTxQueueBuilder(t, backend),
+		WithMaxInFlight(2),
+		WithReceiptPollInterval(time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 6; i++ {
+		if i == 1 {
+			queue.Add(func(_ *bind.TransactOpts) (*types.Transaction, error) {
+				return nil, errors.New("operation failed")
+			})
+			continue
+		}
+		queue.Add(transferOp(backend))
+	}
+	require.Equal(t, 6, queue.Len())
+
+	reported := 0
+	results, err := queue.Run(context.Background(), func(_ *TxResult) { reported++ })
+	require.NoError(t, err)
+	require.Equal(t, 6, reported)
+	require.Len(t, results, 6)
+	require.Zero(t, queue.Len())
+
+	// The failed operation did not use a nonce.
+	require.Len(t, backend.sent, 5)
+	for i, tx := range backend.sent {
+		require.Equal(t, uint64(i), tx.Nonce())
+	}
+
+	for i, result := range results {
+		require.Equal(t, i, result.Index)
+		switch i {
+		case 1:
+			require.EqualError(t, result.Err, "operation failed")
+			require.Nil(t, result.Transaction)
+		case 4:
+			require.EqualError(t, result.Err, "transaction reverted")
+			require.NotNil(t, result.Receipt)
+		default:
+			require.NoError(t, result.Err)
+			require.Equal(t, result.Transaction.Hash(), result.Receipt.TxHash)
+		}
+	}
+}
+
+func TestTxQueueFeeBump(t *testing.T) {
+	backend := &mockReceiptBackend{mockBackend: newMockBackend()}
+	// Only transactions with bumped fees are mined.
+	backend.mined = func(tx *types.Transaction) *types.Receipt {
+		if tx.GasFeeCap().Cmp(big.NewInt(2_000_000_000)) > 0 {
+			return &types.Receipt{Status: types.ReceiptStatusSuccessful}
+		}
+		return nil
+	}
+
+	queue, err := NewTxQueue(backend, testTxQueueBuilder(t, backend),
+		WithReceiptPollInterval(time.Millisecond),
+		WithFeeBump(time.Millisecond, 10),
+	)
+	require.NoError(t, err)
+	queue.Add(transferOp(backend))
+
+	results, err := queue.Run(context.Background(), nil)
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	require.Len(t, backend.sent, 2)
+	require.Equal(t, backend.sent[1].Hash(), results[0].Transaction.Hash())
+	require.Equal(t, uint64(0), results[0].Transaction.Nonce())
+	require.Equal(t, big.NewInt(2_200_000_000), results[0].Transaction.GasFeeCap())
+	require.Equal(t, big.NewInt(1_100_000_000), results[0].Transaction.GasTipCap())
+}
+
+func TestTxQueueMaxFee(t *testing.T) {
+	backend := &mockReceiptBackend{mockBackend: newMockBackend()}
+	backend.mined = func(_ *types.Transaction) *types.Receipt {
+		return nil
+	}
+
+	queue, err := NewTxQueue(backend, testTxQueueBuilder(t, backend),
+		WithMaxInFlight(1),
+		WithReceiptPollInterval(time.Millisecond),
+		WithFeeBump(time.Millisecond, 10),
+		WithMaxFeePerGas(big.NewInt(2_000_000_000)),
+	)
+	require.NoError(t, err)
+	queue.Add(transferOp(backend))
+	queue.Add(transferOp(backend))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	results, err := queue.Run(ctx, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Len(t, results, 2)
+	require.ErrorIs(t, results[0].Err, context.DeadlineExceeded)
+	require.NotNil(t, results[0].Transaction)
+	require.ErrorIs(t, results[1].Err, context.DeadlineExceeded)
+	require.Nil(t, results[1].Transaction)
+
+	// Fees were not bumped beyond the maximum.
+	require.Len(t, backend.sent, 1)
+}
+
+func TestBumpFee(t *testing.T) {
+	require.Nil(t, bumpFee(nil, 10))
+	require.Equal(t, big.NewInt(110), bumpFee(big.NewInt(100), 10))
+	require.Equal(t, big.NewInt(12), bumpFee(big.NewInt(10), 12))
+	require.Equal(t, big.NewInt(2), bumpFee(big.NewInt(1), 10))
+}
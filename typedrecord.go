@@ -0,0 +1,256 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+// Record is a resolver record that can be read with GetRecord and written
+// with SetRecord.  A record holds both its key, such as the key of a text
+// record, and its value.  New record types only need to implement Record to
+// be read and written.
+type Record interface {
+	// ReadData returns the data of the resolver call that reads the record
+	// for the given node.
+	ReadData(node [32]byte) ([]byte, error)
+	// DecodeRead sets the value of the record from the data returned by the
+	// resolver call.
+	DecodeRead(data []byte) error
+	// WriteData returns the data of the resolver call that writes the record
+	// for the given node.
+	WriteData(node [32]byte) ([]byte, error)
+}
+
+// AddrRecord is an ENSIP-9 address record.
+type AddrRecord struct {
+	// CoinType is the coin type of the address.
+	CoinType uint64
+	// Address is the address in binary format.
+	Address []byte
+}
+
+var _ Record = (*AddrRecord)(nil)
+
+// ReadData returns the data of the resolver call that reads the record.
+func (r *AddrRecord) ReadData(node [32]byte) ([]byte, error) {
+	return packResolverCall("addr0", node, new(big.Int).SetUint64(r.CoinType))
+}
+
+// DecodeRead sets the address from the data returned by the resolver.
+func (r *AddrRecord) DecodeRead(data []byte) error {
+	value, err := unpackResolverResult("addr0", data)
+	if err != nil {
+		return err
+	}
+	r.Address = *abi.ConvertType(value[0], new([]byte)).(*[]byte)
+
+	return nil
+}
+
+// WriteData returns the data of the resolver call that writes the record.
+func (r *AddrRecord) WriteData(node [32]byte) ([]byte, error) {
+	return packResolverCall("setAddr0", node, new(big.Int).SetUint64(r.CoinType), r.Address)
+}
+
+// TextRecord is an ENSIP-5 text record.
+type TextRecord struct {
+	// Key is the key of the record.
+	Key string
+	// Value is the value of the record.
+	Value string
+}
+
+var _ Record = (*TextRecord)(nil)
+
+// ReadData returns the data of the resolver call that reads the record.
+func (r *TextRecord) ReadData(node [32]byte) ([]byte, error) {
+	return packResolverCall("text", node, r.Key)
+}
+
+// DecodeRead sets the value from the data returned by the resolver.
+func (r *TextRecord) DecodeRead(data []byte) error {
+	value, err := unpackResolverResult("text", data)
+	if err != nil {
+		return err
+	}
+	r.Value = *abi.ConvertType(value[0], new(string)).(*string)
+
+	return nil
+}
+
+// WriteData returns the data of the resolver call that writes the record.
+func (r *TextRecord) WriteData(node [32]byte) ([]byte, error) {
+	return packResolverCall("setText", node, r.Key, r.Value)
+}
+
+// ContenthashRecord is an ENSIP-7 contenthash record.
+type ContenthashRecord struct {
+	// Contenthash is the contenthash in binary format.
+	Contenthash []byte
+}
+
+var _ Record = (*ContenthashRecord)(nil)
+
+// ReadData returns the data of the resolver call that reads the record.
+func (r *ContenthashRecord) ReadData(node [32]byte) ([]byte, error) {
+	return packResolverCall("contenthash", node)
+}
+
+// DecodeRead sets the contenthash from the data returned by the resolver.
+func (r *ContenthashRecord) DecodeRead(data []byte) error {
+	value, err := unpackResolverResult("contenthash", data)
+	if err != nil {
+		return err
+	}
+	r.Contenthash = *abi.ConvertType(value[0], new([]byte)).(*[]byte)
+
+	return nil
+}
+
+// WriteData returns the data of the resolver call that writes the record.
+func (r *ContenthashRecord) WriteData(node [32]byte) ([]byte, error) {
+	return packResolverCall("setContenthash", node, r.Contenthash)
+}
+
+// PubkeyRecord is an EIP-619 SECP256k1 public key record.
+type PubkeyRecord struct {
+	// X is the x co-ordinate of the public key.
+	X [32]byte
+	// Y is the y co-ordinate of the public key.
+	Y [32]byte
+}
+
+var _ Record = (*PubkeyRecord)(nil)
+
+// ReadData returns the data of the resolver call that reads the record.
+func (r *PubkeyRecord) ReadData(node [32]byte) ([]byte, error) {
+	return packResolverCall("pubkey", node)
+}
+
+// DecodeRead sets the public key from the data returned by the resolver.
+func (r *PubkeyRecord) DecodeRead(data []byte) error {
+	value, err := unpackResolverResult("pubkey", data)
+	if err != nil {
+		return err
+	}
+	if len(value) != 2 {
+		return errors.New("unexpected pubkey result")
+	}
+	r.X = *abi.ConvertType(value[0], new([32]byte)).(*[32]byte)
+	r.Y = *abi.ConvertType(value[1], new([32]byte)).(*[32]byte)
+
+	return nil
+}
+
+// WriteData returns the data of the resolver call that writes the record.
+func (r *PubkeyRecord) WriteData(node [32]byte) ([]byte, error) {
+	return packResolverCall("setPubkey", node, r.X, r.Y)
+}
+
+// GetRecord reads a record of the resolver's domain, returning the record
+// with its value set.  For example:
+//
+//	record, err := ens.GetRecord(resolver, &ens.TextRecord{Key: "url"})
+func GetRecord[R Record](r *Resolver, record R, opts ...CallOption) (R, error) {
+	nameHash, err := NameHash(r.domain)
+	if err != nil {
+		return record, err
+	}
+	data, err := record.ReadData(nameHash)
+	if err != nil {
+		return record, err
+	}
+
+	o, release := newCallOptions(opts)
+	defer release()
+	callOpts := o.callOpts()
+	msg := ethereum.CallMsg{
+		From: callOpts.From,
+		To:   &r.ContractAddr,
+		Data: data,
+	}
+	var res []byte
+	if callOpts.Pending {
+		pendingBackend, isPendingBackend := r.backend.(bind.PendingContractCaller)
+		if !isPendingBackend {
+			return record, errors.New("backend does not support pending state")
+		}
+		res, err = pendingBackend.PendingCallContract(callOpts.Context, msg)
+	} else {
+		res, err = r.backend.CallContract(callOpts.Context, msg, callOpts.BlockNumber)
+	}
+	if err != nil {
+		return record, err
+	}
+	if len(res) == 0 {
+		return record, bind.ErrNoCode
+	}
+	if err := record.DecodeRead(res); err != nil {
+		return record, err
+	}
+
+	return record, nil
+}
+
+// SetRecord writes a record of the resolver's domain.
+func SetRecord(r *Resolver, opts *bind.TransactOpts, record Record) (*types.Transaction, error) {
+	nameHash, err := NameHash(r.domain)
+	if err != nil {
+		return nil, err
+	}
+	data, err := record.WriteData(nameHash)
+	if err != nil {
+		return nil, err
+	}
+
+	return bind.NewBoundContract(r.ContractAddr, abi.ABI{}, r.backend, r.backend, r.backend).RawTransact(opts, data)
+}
+
+// packResolverCall packs a call to a method of the resolver ABI.
+func packResolverCall(method string, args ...interface{}) ([]byte, error) {
+	parsed, err := resolver.ContractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+
+	return parsed.Pack(method, args...)
+}
+
+// unpackResolverResult unpacks the result of a call to a method of the
+// resolver ABI.
+func unpackResolverResult(method string, data []byte) ([]interface{}, error) {
+	parsed, err := resolver.ContractMetaData.GetAbi()
+	if err != nil {
+		return nil, err
+	}
+	values, err := parsed.Unpack(method, data)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, fmt.Errorf("no result for %s", method)
+	}
+
+	return values, nil
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+// nameRecord is a record type defined outside of the package's own types.
+type nameRecord struct {
+	Name string
+}
+
+func (r *nameRecord) ReadData(node [32]byte) ([]byte, error) {
+	return packResolverCall("name", node)
+}
+
+func (r *nameRecord) DecodeRead(data []byte) error {
+	value, err := unpackResolverResult("name", data)
+	if err != nil {
+		return err
+	}
+	r.Name = *abi.ConvertType(value[0], new(string)).(*string)
+	return nil
+}
+
+func (r *nameRecord) WriteData(node [32]byte) ([]byte, error) {
+	return packResolverCall("setName", node, r.Name)
+}
+
+func TestGetRecord(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("records.eth", address, address)
+	m.setText("records.eth", "url", "https://example.com/")
+	m.setCoinAddr("records.eth", 0, []byte{0x00, 0x14, 0x01})
+	m.mu.Lock()
+	m.names[mustNameHash("records.eth")] = "records.eth"
+	m.mu.Unlock()
+
+	resolver, err := NewResolver(m.backend, "records.eth", EthereumMainnet)
+	require.NoError(t, err)
+
+	text, err := GetRecord(resolver, &TextRecord{Key: "url"})
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/", text.Value)
+
+	addr, err := GetRecord(resolver, &AddrRecord{CoinType: 60})
+	require.NoError(t, err)
+	require.Equal(t, address.Bytes(), addr.Address)
+	addr, err = GetRecord(resolver, &AddrRecord{CoinType: 0})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x00, 0x14, 0x01}, addr.Address)
+
+	contenthash, err := GetRecord(resolver, &ContenthashRecord{})
+	require.NoError(t, err)
+	require.Empty(t, contenthash.Contenthash)
+
+	pubkey, err := GetRecord(resolver, &PubkeyRecord{})
+	require.NoError(t, err)
+	require.Equal(t, [32]byte{}, pubkey.X)
+
+	name, err := GetRecord(resolver, &nameRecord{})
+	require.NoError(t, err)
+	require.Equal(t, "records.eth", name.Name)
+}
+
+func TestSetRecord(t *testing.T) {
+	m := newMockENS()
+	address := common.HexToAddress("0x0000000000000000000000000000000000000001")
+	m.register("records.eth", address, address)
+	calls := make(map[string][]interface{})
+	for _, method := range []string{"setText", "setAddr0", "setContenthash", "setPubkey"} {
+		method := method
+		m.backend.contracts[m.resolverAddr].on(method, func(args []interface{}) ([]interface{}, error) {
+			calls[method] = args
+			return []interface{}{}, nil
+		})
+	}
+	resolver, err := NewResolver(m.backend, "records.eth", EthereumMainnet)
+	require.NoError(t, err)
+	node := mustNameHash("records.eth")
+
+	tests := []struct {
+		name   string
+		record Record
+		method string
+		args   []interface{}
+	}{
+		{
+			name:   "Text",
+			record: &TextRecord{Key: "url", Value: "https://example.com/"},
+			method: "setText",
+			args:   []interface{}{node, "url", "https://example.com/"},
+		},
+		{
+			name:   "Addr",
+			record: &AddrRecord{CoinType: 60, Address: address.Bytes()},
+			method: "setAddr0",
+		},
+		{
+			name:   "Contenthash",
+			record: &ContenthashRecord{Contenthash: []byte{0xe3, 0x01}},
+			method: "setContenthash",
+			args:   []interface{}{node, []byte{0xe3, 0x01}},
+		},
+		{
+			name:   "Pubkey",
+			record: &PubkeyRecord{X: [32]byte{0x01}, Y: [32]byte{0x02}},
+			method: "setPubkey",
+			args:   []interface{}{node, [32]byte{0x01}, [32]byte{0x02}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tx, err := SetRecord(resolver, testTransactOpts(t), test.record)
+			require.NoError(t, err)
+			require.Equal(t, m.resolverAddr, *tx.To())
+			require.Contains(t, calls, test.method)
+			if test.args != nil {
+				require.Equal(t, test.args, calls[test.method])
+			}
+		})
+	}
+}
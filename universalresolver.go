@@ -0,0 +1,121 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wealdtech/go-ens/v3/contracts/universalresolver"
+)
+
+// UniversalResolver is the structure for the universal resolver contract,
+// which finds the resolver for a name and queries it in a single call.
+type UniversalResolver struct {
+	Contract     *universalresolver.Contract
+	ContractAddr common.Address
+}
+
+// NewUniversalResolver obtains the universal resolver for a given chain.
+func NewUniversalResolver(backend bind.ContractBackend, chainId ChainId) (*UniversalResolver, error) {
+	config := ChainConfigFor(chainId)
+	if config.ChainId != chainId || config.UniversalResolver == UnknownAddress {
+		return nil, fmt.Errorf("no universal resolver for chain %d", chainId)
+	}
+
+	return NewUniversalResolverAt(backend, config.UniversalResolver)
+}
+
+// universalResolverKey identifies the universal resolver of a chain on a given
+// backend.
+type universalResolverKey struct {
+	backend bind.ContractBackend
+	chainId ChainId
+}
+
+// missingUniversalResolvers holds the backends and chains on which the
+// universal resolver has no code, for example local chains using mainnet's
+// configuration, so that it is not called again.
+var missingUniversalResolvers sync.Map
+
+// cacheableBackend returns true if the backend can be used as part of a map
+// key.
+func cacheableBackend(backend bind.ContractBackend) bool {
+	return backend != nil && reflect.TypeOf(backend).Comparable()
+}
+
+// universalResolverFor obtains the universal resolver for a given chain, if
+// it is present on the backend.
+func universalResolverFor(backend bind.ContractBackend, chainId ChainId) (*UniversalResolver, bool) {
+	if cacheableBackend(backend) {
+		if _, missing := missingUniversalResolvers.Load(universalResolverKey{backend: backend, chainId: chainId}); missing {
+			return nil, false
+		}
+	}
+	universalResolver, err := NewUniversalResolver(backend, chainId)
+	if err != nil {
+		return nil, false
+	}
+
+	return universalResolver, true
+}
+
+// checkUniversalResolverError notes if an error from the universal resolver
+// shows that it is not present on the backend.
+func checkUniversalResolverError(backend bind.ContractBackend, chainId ChainId, err error) {
+	if errors.Is(err, bind.ErrNoCode) && cacheableBackend(backend) {
+		missingUniversalResolvers.Store(universalResolverKey{backend: backend, chainId: chainId}, struct{}{})
+	}
+}
+
+// NewUniversalResolverAt obtains the universal resolver at a given address.
+func NewUniversalResolverAt(backend bind.ContractBackend, address common.Address) (*UniversalResolver, error) {
+	contract, err := universalresolver.NewContract(address, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UniversalResolver{
+		Contract:     contract,
+		ContractAddr: address,
+	}, nil
+}
+
+// Reverse obtains the name held for a reverse name, for example
+// "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed.addr.reverse", along with the
+// address to which that name resolves.  The name is empty if there is no
+// reverse record.
+func (r *UniversalResolver) Reverse(reverseName string, opts ...CallOption) (string, common.Address, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	return r.reverse(reverseName, o)
+}
+
+func (r *UniversalResolver) reverse(reverseName string, o *callOptions) (string, common.Address, error) {
+	encodedName, err := DNSEncodeName(reverseName)
+	if err != nil {
+		return "", UnknownAddress, err
+	}
+	name, resolvedAddress, _, _, err := r.Contract.Reverse(o.callOpts(), encodedName)
+	if err != nil {
+		return "", UnknownAddress, err
+	}
+
+	return name, resolvedAddress, nil
+}
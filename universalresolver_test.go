@@ -0,0 +1,144 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/universalresolver"
+)
+
+func TestReverseResolveUniversalResolver(t *testing.T) {
+	m := newMockENS()
+	named := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	unverified := common.HexToAddress("0x000000000000000000000000000000000000b0b0")
+	offchain := common.HexToAddress("0x0000000000000000000000000000000000000ff1")
+	offchainUnverified := common.HexToAddress("0x0000000000000000000000000000000000000ff2")
+	noResolver := common.HexToAddress("0x000000000000000000000000000000000000dead")
+	m.register("offchain.eth", offchain, offchain)
+	m.setReverse(offchain, "offchain.eth")
+	m.setReverse(offchainUnverified, "offchain.eth")
+	m.register("dead.eth", noResolver, noResolver)
+	m.setReverse(noResolver, "dead.eth")
+
+	m.backend.deploy(ChainConfigFor(EthereumMainnet).UniversalResolver, universalresolver.ContractABI).
+		on("reverse", func(args []interface{}) ([]interface{}, error) {
+			reverseName, err := DNSDecodeName(args[0].([]byte))
+			if err != nil {
+				return nil, err
+			}
+			switch reverseName {
+			case ReverseName(named, 60):
+				return []interface{}{"alice.eth", named, m.resolverAddr, m.resolverAddr}, nil
+			case ReverseName(unverified, 60):
+				return []interface{}{"alice.eth", named, m.resolverAddr, m.resolverAddr}, nil
+			case ReverseName(offchain, 60), ReverseName(offchainUnverified, 60):
+				return nil, errors.New("execution reverted")
+			case ReverseName(noResolver, 60):
+				return nil, &mockRevertError{data: customErrorData("ResolverNotFound()")}
+			default:
+				return []interface{}{"", UnknownAddress, UnknownAddress, UnknownAddress}, nil
+			}
+		})
+
+	tests := []struct {
+		name    string
+		address common.Address
+		opts    []CallOption
+		res     string
+		err     error
+	}{
+		{
+			name:    "Verified",
+			address: named,
+			res:     "alice.eth",
+		},
+		{
+			name:    "Unverified",
+			address: unverified,
+			err:     ErrNoResolution,
+		},
+		{
+			name:    "NoName",
+			address: common.HexToAddress("0x0000000000000000000000000000000000000001"),
+			err:     ErrNoResolution,
+		},
+		{
+			name:    "FallBack",
+			address: offchain,
+			res:     "offchain.eth",
+		},
+		{
+			name:    "FallBackUnverified",
+			address: offchainUnverified,
+			err:     ErrNoResolution,
+		},
+		{
+			// The universal resolver's answer is used without falling back
+			// to the registry.
+			name:    "ResolverNotFound",
+			address: noResolver,
+			err:     ErrNoResolver,
+		},
+		{
+			name:    "WithoutUniversalResolver",
+			address: named,
+			opts:    []CallOption{WithoutUniversalResolver()},
+			err:     ErrNotAResolver,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			res, err := ReverseResolve(m.backend, test.address, EthereumMainnet, test.opts...)
+			if test.err != nil {
+				require.ErrorIs(t, err, test.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, test.res, res)
+		})
+	}
+}
+
+func TestReverseResolveUniversalResolverCalls(t *testing.T) {
+	m := newMockENS()
+	named := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	m.setReverse(named, "alice.eth")
+	m.register("alice.eth", named, named)
+	m.backend.deploy(ChainConfigFor(EthereumMainnet).UniversalResolver, universalresolver.ContractABI).
+		on("reverse", func(_ []interface{}) ([]interface{}, error) {
+			return []interface{}{"alice.eth", named, m.resolverAddr, m.resolverAddr}, nil
+		})
+
+	// The universal resolver answers in a single call.
+	calls := m.backend.callCount()
+	res, err := ReverseResolve(m.backend, named, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, "alice.eth", res)
+	require.Equal(t, calls+1, m.backend.callCount())
+
+	// The client does not use it if disabled.
+	client, err := NewClient(m.backend, WithUniversalResolver(false))
+	require.NoError(t, err)
+	calls = m.backend.callCount()
+	res, err = client.ReverseResolve(named)
+	require.NoError(t, err)
+	require.Equal(t, "alice.eth", res)
+	require.Greater(t, m.backend.callCount(), calls+1)
+}
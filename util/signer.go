@@ -15,12 +15,14 @@
 package util
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"errors"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/accounts/external"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -46,3 +48,38 @@ func AccountSigner(chainID *big.Int, wallet *accounts.Wallet, account *accounts.
 		return (*wallet).SignTxWithPassphrase(*account, passphrase, tx, chainID)
 	}
 }
+
+// TxSigner signs transactions for an account whose key is held outside of
+// the process, for example in a remote key management service or hardware
+// security module.
+type TxSigner interface {
+	// Address returns the address of the account.
+	Address() common.Address
+	// SignTx signs a transaction.
+	SignTx(ctx context.Context, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error)
+}
+
+// RemoteSigner generates a signer using a transaction signer.
+func RemoteSigner(ctx context.Context, chainID *big.Int, signer TxSigner) bind.SignerFn {
+	return func(address common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if address != signer.Address() {
+			return nil, errors.New("not authorized to sign this account")
+		}
+		return signer.SignTx(ctx, tx, chainID)
+	}
+}
+
+// ClefSigner generates a signer using clef, for example:
+//
+//	clef, err := external.NewExternalSigner("http://localhost:8550")
+//	signer := util.ClefSigner(chainID, clef, address)
+//
+// Each transaction must be approved in clef, either manually or by its rules.
+func ClefSigner(chainID *big.Int, clef *external.ExternalSigner, address common.Address) bind.SignerFn {
+	return func(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+		if addr != address {
+			return nil, errors.New("not authorized to sign this account")
+		}
+		return clef.SignTx(accounts.Account{Address: address}, tx, chainID)
+	}
+}
@@ -0,0 +1,205 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ValidationError is a reason that a name is not valid.
+type ValidationError struct {
+	// Label is the label of the name that is not valid, as supplied.
+	Label string
+	// Offset is the offset in runes of the problem in the name as supplied.
+	Offset int
+	// Reason describes the problem.
+	Reason string
+}
+
+// Error returns the error message.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Reason, e.Offset)
+}
+
+// Unwrap returns ErrInvalidName, so that validation errors can be matched
+// with errors.Is.
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidName
+}
+
+// ValidationErrors are the reasons that a name is not valid.
+type ValidationErrors []*ValidationError
+
+// Error returns the error message.
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+
+	return fmt.Sprintf("%v: %s", ErrInvalidName, strings.Join(msgs, "; "))
+}
+
+// Unwrap returns the individual validation errors.
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i := range e {
+		errs[i] = e[i]
+	}
+
+	return errs
+}
+
+// ValidateName checks that a name is valid, returning every problem found
+// rather than only whether the name can be normalized.  The problems found
+// are:
+//
+//   - characters that are not allowed in names, including ASCII other than
+//     letters, digits, '-', '_' and '$'
+//   - empty labels, for example in "foo..eth"
+//   - labels with hyphens in the third and fourth positions, such as punycode
+//   - underscores other than at the start of a label
+//   - labels that start with a combining mark
+//
+// If the name is not valid the error is ValidationErrors, which holds a
+// *ValidationError for each problem with its offset in runes in the name as
+// supplied.  Validation errors match ErrInvalidName with errors.Is.  The
+// empty name, which is the root of ENS, is valid.
+func ValidateName(name string) error {
+	if name == "" {
+		return nil
+	}
+
+	errs := make(ValidationErrors, 0)
+	runes := []rune(name)
+	// Each label is checked once it is complete, using the normalized runes
+	// so that characters that normalize to others are checked correctly.
+	start := 0
+	mapped := make([]rune, 0)
+	offsets := make([]int, 0)
+	for i := 0; i <= len(runes); i++ {
+		var normalized string
+		if i < len(runes) {
+			var err error
+			normalized, err = p.ToUnicode(string(runes[i]))
+			if err != nil || !allowedASCII(normalized) {
+				errs = append(errs, &ValidationError{
+					Offset: i,
+					Reason: fmt.Sprintf("disallowed character %U", runes[i]),
+				})
+				continue
+			}
+		}
+		if i < len(runes) && normalized != "." {
+			for _, r := range normalized {
+				mapped = append(mapped, r)
+				offsets = append(offsets, i)
+			}
+			continue
+		}
+
+		// End of the label.
+		label := string(runes[start:i])
+		for _, labelErr := range labelValidationErrors(mapped, offsets, start) {
+			labelErr.Label = label
+			errs = append(errs, labelErr)
+		}
+		start = i + 1
+		mapped = mapped[:0]
+		offsets = offsets[:0]
+	}
+
+	if len(errs) > 0 {
+		for _, err := range errs {
+			if err.Label == "" {
+				err.Label = labelAt(runes, err.Offset)
+			}
+		}
+		return errs
+	}
+
+	return nil
+}
+
+// labelValidationErrors returns the problems with a normalized label, using
+// the offsets of the runes from which the label's runes were normalized.
+func labelValidationErrors(label []rune, offsets []int, start int) []*ValidationError {
+	if len(label) == 0 {
+		return []*ValidationError{{
+			Offset: start,
+			Reason: "empty label",
+		}}
+	}
+
+	errs := make([]*ValidationError, 0)
+	if len(label) >= 4 && label[2] == '-' && label[3] == '-' {
+		errs = append(errs, &ValidationError{
+			Offset: offsets[2],
+			Reason: "hyphens in the third and fourth positions of a label",
+		})
+	}
+	if unicode.Is(unicode.Mn, label[0]) {
+		errs = append(errs, &ValidationError{
+			Offset: offsets[0],
+			Reason: fmt.Sprintf("label starts with combining mark %U", label[0]),
+		})
+	}
+	leadingUnderscores := true
+	for i, r := range label {
+		if r != '_' {
+			leadingUnderscores = false
+			continue
+		}
+		if !leadingUnderscores {
+			errs = append(errs, &ValidationError{
+				Offset: offsets[i],
+				Reason: "underscore after the start of a label",
+			})
+		}
+	}
+
+	return errs
+}
+
+// allowedASCII returns false if a normalized character is ASCII other than
+// that allowed in names.
+func allowedASCII(normalized string) bool {
+	for _, r := range normalized {
+		if r >= unicode.MaxASCII || r == '.' {
+			continue
+		}
+		if !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9') && r != '-' && r != '_' && r != '$' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// labelAt returns the label of a name that contains the given rune offset.
+func labelAt(name []rune, offset int) string {
+	start := offset
+	for start > 0 && name[start-1] != '.' {
+		start--
+	}
+	end := offset
+	for end < len(name) && name[end] != '.' {
+		end++
+	}
+
+	return string(name[start:end])
+}
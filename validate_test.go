@@ -0,0 +1,126 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		errs  ValidationErrors
+	}{
+		{
+			name:  "Root",
+			input: "",
+		},
+		{
+			name:  "Valid",
+			input: "Foo.eth",
+		},
+		{
+			name:  "AlternativeSeparator",
+			input: "foo。eth",
+		},
+		{
+			name:  "LeadingUnderscores",
+			input: "__foo.eth",
+		},
+		{
+			name:  "EmptyLabel",
+			input: "foo..eth",
+			errs: ValidationErrors{
+				{Label: "", Offset: 4, Reason: "empty label"},
+			},
+		},
+		{
+			name:  "TrailingPeriod",
+			input: "foo.eth.",
+			errs: ValidationErrors{
+				{Label: "", Offset: 8, Reason: "empty label"},
+			},
+		},
+		{
+			name:  "DisallowedCharacter",
+			input: "fooㅤbar.eth",
+			errs: ValidationErrors{
+				{Label: "fooㅤbar", Offset: 3, Reason: "disallowed character U+3164"},
+			},
+		},
+		{
+			name:  "DisallowedASCII",
+			input: "foo bar.eth",
+			errs: ValidationErrors{
+				{Label: "foo bar", Offset: 3, Reason: "disallowed character U+0020"},
+			},
+		},
+		{
+			name:  "Punycode",
+			input: "sub.xn--ls8h.eth",
+			errs: ValidationErrors{
+				{Label: "xn--ls8h", Offset: 6, Reason: "hyphens in the third and fourth positions of a label"},
+			},
+		},
+		{
+			name:  "Underscore",
+			input: "foo_bar.eth",
+			errs: ValidationErrors{
+				{Label: "foo_bar", Offset: 3, Reason: "underscore after the start of a label"},
+			},
+		},
+		{
+			name:  "LeadingCombiningMark",
+			input: "foo.́bar.eth",
+			errs: ValidationErrors{
+				{Label: "́bar", Offset: 4, Reason: "label starts with combining mark U+0301"},
+			},
+		},
+		{
+			name:  "Multiple",
+			input: "a b..c_d",
+			errs: ValidationErrors{
+				{Label: "a b", Offset: 1, Reason: "disallowed character U+0020"},
+				{Label: "", Offset: 4, Reason: "empty label"},
+				{Label: "c_d", Offset: 6, Reason: "underscore after the start of a label"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := ValidateName(test.input)
+			if test.errs == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.Equal(t, test.errs, err)
+			require.True(t, errors.Is(err, ErrInvalidName))
+		})
+	}
+}
+
+func TestValidationErrorsError(t *testing.T) {
+	err := ValidateName("a b..eth")
+	require.EqualError(t, err, "invalid name: disallowed character U+0020 at offset 1; empty label at offset 4")
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	require.Equal(t, 1, validationErr.Offset)
+}
@@ -0,0 +1,492 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// ErrUnverifiable is returned when a record cannot be verified with storage
+// proofs, for example because the layout of its resolver's storage is not
+// known.
+var ErrUnverifiable = errors.New("unverifiable")
+
+// maxVerifiedRecordLength is the maximum length of a record obtained by a
+// verifier, to bound the number of storage slots requested.
+const maxVerifiedRecordLength = 64 * 1024
+
+// Storage slots of the ENS registry's records and, for the registry with
+// fallback on Ethereum mainnet, the previous registry.
+const (
+	registryRecordsSlot = 0
+	registryOldSlot     = 2
+)
+
+// AccountProof is the proof of an account and some of its storage, as
+// returned by eth_getProof.  Only the proof nodes are used, as values are
+// obtained from the proofs themselves.
+type AccountProof struct {
+	// Proof is the proof of the account against the block's state root.
+	Proof [][]byte
+	// StorageProofs are the proofs of the storage slots against the
+	// account's storage root, in the order in which they were requested.
+	StorageProofs [][][]byte
+}
+
+// ProofBackend provides block headers and proofs of account storage.
+// NewRPCProofBackend provides one for a JSON-RPC connection.
+type ProofBackend interface {
+	// HeaderByHash returns the header of the block with the given hash.
+	HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error)
+	// GetProof returns the proof of an account and the given storage slots
+	// at the given block.
+	GetProof(ctx context.Context, account common.Address, slots []common.Hash, blockNumber *big.Int) (*AccountProof, error)
+}
+
+// rpcProofBackend is a proof backend using JSON-RPC.
+type rpcProofBackend struct {
+	client *rpc.Client
+}
+
+// NewRPCProofBackend creates a proof backend for a JSON-RPC connection, for
+// example that returned by ethclient.Client.Client().
+func NewRPCProofBackend(client *rpc.Client) ProofBackend {
+	return &rpcProofBackend{client: client}
+}
+
+// HeaderByHash returns the header of the block with the given hash.
+func (b *rpcProofBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	var header *types.Header
+	if err := b.client.CallContext(ctx, &header, "eth_getBlockByHash", hash, false); err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block %#x not found", hash)
+	}
+
+	return header, nil
+}
+
+// GetProof returns the proof of an account and the given storage slots at the
+// given block.
+func (b *rpcProofBackend) GetProof(ctx context.Context, account common.Address, slots []common.Hash, blockNumber *big.Int) (*AccountProof, error) {
+	var res struct {
+		AccountProof []hexutil.Bytes `json:"accountProof"`
+		StorageProof []struct {
+			Proof []hexutil.Bytes `json:"proof"`
+		} `json:"storageProof"`
+	}
+	if err := b.client.CallContext(ctx, &res, "eth_getProof", account, slots, hexutil.EncodeBig(blockNumber)); err != nil {
+		return nil, err
+	}
+
+	proof := &AccountProof{
+		Proof:         make([][]byte, len(res.AccountProof)),
+		StorageProofs: make([][][]byte, len(res.StorageProof)),
+	}
+	for i := range res.AccountProof {
+		proof.Proof[i] = res.AccountProof[i]
+	}
+	for i := range res.StorageProof {
+		proof.StorageProofs[i] = make([][]byte, len(res.StorageProof[i].Proof))
+		for j := range res.StorageProof[i].Proof {
+			proof.StorageProofs[i][j] = res.StorageProof[i].Proof[j]
+		}
+	}
+
+	return proof, nil
+}
+
+// ResolverStorageLayout is the layout of a resolver's storage, giving the
+// slots of the mappings holding its records so that they can be verified with
+// storage proofs.
+type ResolverStorageLayout struct {
+	// RecordVersions is the slot of mapping(bytes32 => uint64), the version
+	// of each node's records.
+	RecordVersions uint64
+	// Addresses is the slot of
+	// mapping(uint64 => mapping(bytes32 => mapping(uint256 => bytes))), the
+	// address of each node for each coin type.
+	Addresses uint64
+	// Contenthashes is the slot of mapping(uint64 => mapping(bytes32 => bytes)),
+	// the contenthash of each node.
+	Contenthashes uint64
+	// Texts is the slot of
+	// mapping(uint64 => mapping(bytes32 => mapping(string => string))), the
+	// text records of each node.
+	Texts uint64
+}
+
+// PublicResolverStorageLayout is the storage layout of the versionable
+// PublicResolver.
+var PublicResolverStorageLayout = ResolverStorageLayout{
+	RecordVersions: 0,
+	Addresses:      2,
+	Contenthashes:  3,
+	Texts:          10,
+}
+
+var (
+	resolverStorageLayoutsMu sync.RWMutex
+	resolverStorageLayouts   = map[common.Address]*ResolverStorageLayout{
+		// Ethereum mainnet PublicResolver.
+		common.HexToAddress("231b0Ee14048e9dCcD1d247744d114a4EB5E8E63"): &PublicResolverStorageLayout,
+		// Sepolia PublicResolver.
+		common.HexToAddress("8FADE66B79cC9f707aB26799354482EB93a5B7dD"): &PublicResolverStorageLayout,
+	}
+)
+
+// RegisterResolverStorageLayout registers the storage layout of the resolver
+// at an address, replacing any existing layout, so that its records can be
+// verified.
+func RegisterResolverStorageLayout(address common.Address, layout *ResolverStorageLayout) error {
+	if layout == nil {
+		return errors.New("no storage layout supplied")
+	}
+
+	// Take a copy so that later changes by the caller have no effect.
+	registered := *layout
+
+	resolverStorageLayoutsMu.Lock()
+	resolverStorageLayouts[address] = &registered
+	resolverStorageLayoutsMu.Unlock()
+
+	return nil
+}
+
+// UnregisterResolverStorageLayout removes the storage layout of the resolver
+// at an address.
+func UnregisterResolverStorageLayout(address common.Address) {
+	resolverStorageLayoutsMu.Lock()
+	delete(resolverStorageLayouts, address)
+	resolverStorageLayoutsMu.Unlock()
+}
+
+// ResolverStorageLayoutFor returns the storage layout of the resolver at an
+// address, if it is known.
+func ResolverStorageLayoutFor(address common.Address) (*ResolverStorageLayout, bool) {
+	resolverStorageLayoutsMu.RLock()
+	defer resolverStorageLayoutsMu.RUnlock()
+
+	layout, exists := resolverStorageLayouts[address]
+	if !exists {
+		return nil, false
+	}
+	res := *layout
+
+	return &res, true
+}
+
+// Verifier obtains ENS records from a block's state, verifying them with
+// storage proofs against the block's state root, for consumers that do not
+// trust their provider.  The block is identified by a trusted hash, for
+// example from a light client, and its header is checked against that hash
+// before it is used.
+//
+// Only records held directly in the registry and in resolvers with a known
+// storage layout (see RegisterResolverStorageLayout) can be verified; names
+// that rely on wildcard resolution or offchain lookups cannot.
+type Verifier struct {
+	backend ProofBackend
+	chainId ChainId
+	header  *types.Header
+}
+
+// NewVerifier creates a verifier for the state of the block with the trusted
+// hash.  The block's header is obtained from the backend and rejected if it
+// does not hash to the trusted hash.  Headers are hashed with go-ethereum's
+// types.Header, so blocks with header fields that it does not support are
+// rejected.
+func NewVerifier(backend ProofBackend, trustedBlockHash common.Hash, chainId ChainId, opts ...CallOption) (*Verifier, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	header, err := backend.HeaderByHash(o.ctx, trustedBlockHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil || header.Number == nil {
+		return nil, errors.New("no header returned")
+	}
+	if header.Hash() != trustedBlockHash {
+		return nil, fmt.Errorf("header hash %#x does not match trusted block hash %#x", header.Hash(), trustedBlockHash)
+	}
+
+	return &Verifier{
+		backend: backend,
+		chainId: chainId,
+		header:  header,
+	}, nil
+}
+
+// Header returns the header of the block whose state is verified.
+func (v *Verifier) Header() *types.Header {
+	return types.CopyHeader(v.header)
+}
+
+// Owner obtains the verified owner of a name in the registry.  For wrapped
+// names this is the name wrapper.
+func (v *Verifier) Owner(name string, opts ...CallOption) (common.Address, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	owner, _, err := v.registryRecord(name, o)
+	return owner, err
+}
+
+// ResolverAddress obtains the verified address of the resolver for a name.
+func (v *Verifier) ResolverAddress(name string, opts ...CallOption) (common.Address, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	_, resolver, err := v.registryRecord(name, o)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	if resolver == UnknownAddress {
+		return UnknownAddress, ErrNoResolver
+	}
+
+	return resolver, nil
+}
+
+// Address obtains the verified Ethereum address of a name.
+func (v *Verifier) Address(name string, opts ...CallOption) (common.Address, error) {
+	address, err := v.CoinAddress(name, 60, opts...)
+	if err != nil {
+		return UnknownAddress, err
+	}
+	if len(address) != common.AddressLength {
+		return UnknownAddress, ErrNoAddress
+	}
+
+	return common.BytesToAddress(address), nil
+}
+
+// CoinAddress obtains the verified address of a name for a coin type.
+func (v *Verifier) CoinAddress(name string, coinType uint64, opts ...CallOption) ([]byte, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	return v.resolverRecord(name, o, func(layout *ResolverStorageLayout, version common.Hash, node common.Hash) common.Hash {
+		slot := mappingSlot(version[:], uint64Slot(layout.Addresses))
+		slot = mappingSlot(node[:], slot)
+		return mappingSlot(common.BigToHash(new(big.Int).SetUint64(coinType)).Bytes(), slot)
+	})
+}
+
+// Contenthash obtains the verified contenthash of a name.
+func (v *Verifier) Contenthash(name string, opts ...CallOption) ([]byte, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	return v.resolverRecord(name, o, func(layout *ResolverStorageLayout, version common.Hash, node common.Hash) common.Hash {
+		slot := mappingSlot(version[:], uint64Slot(layout.Contenthashes))
+		return mappingSlot(node[:], slot)
+	})
+}
+
+// Text obtains the verified value of a text record of a name.
+func (v *Verifier) Text(name string, key string, opts ...CallOption) (string, error) {
+	o, release := newCallOptions(opts)
+	defer release()
+	value, err := v.resolverRecord(name, o, func(layout *ResolverStorageLayout, version common.Hash, node common.Hash) common.Hash {
+		slot := mappingSlot(version[:], uint64Slot(layout.Texts))
+		slot = mappingSlot(node[:], slot)
+		return mappingSlot([]byte(key), slot)
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(value), nil
+}
+
+// registryRecord obtains the verified owner and resolver of a name, falling
+// back to the previous registry if the registry has one and does not hold a
+// record for the name.
+func (v *Verifier) registryRecord(name string, o *callOptions) (common.Address, common.Address, error) {
+	node, err := NameHash(name)
+	if err != nil {
+		return UnknownAddress, UnknownAddress, err
+	}
+	registry, err := RegistryContractAddress(nil, v.chainId)
+	if err != nil {
+		return UnknownAddress, UnknownAddress, err
+	}
+
+	record := mappingSlot(node[:], uint64Slot(registryRecordsSlot))
+	resolverSlot := common.BigToHash(new(big.Int).Add(record.Big(), big.NewInt(1)))
+	values, err := v.storage(o.ctx, registry, []common.Hash{record, resolverSlot, uint64Slot(registryOldSlot)})
+	if err != nil {
+		return UnknownAddress, UnknownAddress, err
+	}
+	owner := common.BytesToAddress(values[0][12:])
+	resolver := common.BytesToAddress(values[1][12:])
+	old := common.BytesToAddress(values[2][12:])
+	if owner != UnknownAddress || old == UnknownAddress {
+		return owner, resolver, nil
+	}
+
+	values, err = v.storage(o.ctx, old, []common.Hash{record, resolverSlot})
+	if err != nil {
+		return UnknownAddress, UnknownAddress, err
+	}
+
+	return common.BytesToAddress(values[0][12:]), common.BytesToAddress(values[1][12:]), nil
+}
+
+// resolverRecord obtains a verified record of a name from its resolver, at
+// the slot given by the supplied function.
+func (v *Verifier) resolverRecord(name string, o *callOptions, recordSlot func(*ResolverStorageLayout, common.Hash, common.Hash) common.Hash) ([]byte, error) {
+	node, err := NameHash(name)
+	if err != nil {
+		return nil, err
+	}
+	_, resolver, err := v.registryRecord(name, o)
+	if err != nil {
+		return nil, err
+	}
+	if resolver == UnknownAddress {
+		return nil, ErrNoResolver
+	}
+	layout, exists := ResolverStorageLayoutFor(resolver)
+	if !exists {
+		return nil, wrapError(ErrUnverifiable, "storage layout of resolver %s is not known", resolver.Hex())
+	}
+
+	values, err := v.storage(o.ctx, resolver, []common.Hash{mappingSlot(node[:], uint64Slot(layout.RecordVersions))})
+	if err != nil {
+		return nil, err
+	}
+	// Versions are uint64, held in the low-order bytes of the slot.
+	version := common.BigToHash(new(big.Int).SetBytes(values[0][24:]))
+
+	return v.storageBytes(o.ctx, resolver, recordSlot(layout, version, common.Hash(node)))
+}
+
+// storageBytes obtains verified bytes or string held at a storage slot in
+// Solidity's layout, where short values are held in the slot itself and long
+// values in consecutive slots starting at the hash of the slot.
+func (v *Verifier) storageBytes(ctx context.Context, account common.Address, slot common.Hash) ([]byte, error) {
+	values, err := v.storage(ctx, account, []common.Hash{slot})
+	if err != nil {
+		return nil, err
+	}
+	header := values[0]
+	if header[31]&1 == 0 {
+		// Short value, with twice its length in the lowest byte.
+		length := int(header[31] / 2)
+		if length > 31 {
+			return nil, errors.New("invalid short value in storage")
+		}
+		return common.CopyBytes(header[:length]), nil
+	}
+
+	// Long value, with twice its length plus one in the slot.
+	length := new(big.Int).Rsh(header.Big(), 1)
+	if !length.IsInt64() || length.Int64() > maxVerifiedRecordLength {
+		return nil, fmt.Errorf("value in storage is longer than %d bytes", maxVerifiedRecordLength)
+	}
+	dataSlot := crypto.Keccak256Hash(slot[:]).Big()
+	slots := make([]common.Hash, (length.Int64()+31)/32)
+	for i := range slots {
+		slots[i] = common.BigToHash(new(big.Int).Add(dataSlot, big.NewInt(int64(i))))
+	}
+	values, err = v.storage(ctx, account, slots)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, 32*len(values))
+	for i := range values {
+		data = append(data, values[i][:]...)
+	}
+
+	return data[:length.Int64()], nil
+}
+
+// storage obtains the values of storage slots of an account, verifying them
+// against the block's state root.
+func (v *Verifier) storage(ctx context.Context, account common.Address, slots []common.Hash) ([]common.Hash, error) {
+	proof, err := v.backend.GetProof(ctx, account, slots, v.header.Number)
+	if err != nil {
+		return nil, err
+	}
+	if proof == nil || len(proof.StorageProofs) != len(slots) {
+		return nil, errors.New("incomplete proof returned")
+	}
+
+	encodedAccount, err := trie.VerifyProof(v.header.Root, crypto.Keccak256(account.Bytes()), proofDB(proof.Proof))
+	if err != nil {
+		return nil, fmt.Errorf("invalid proof for account %s: %w", account.Hex(), err)
+	}
+	values := make([]common.Hash, len(slots))
+	if len(encodedAccount) == 0 {
+		// The account does not exist, so its storage is empty.
+		return values, nil
+	}
+	var state types.StateAccount
+	if err := rlp.DecodeBytes(encodedAccount, &state); err != nil {
+		return nil, fmt.Errorf("invalid account %s: %w", account.Hex(), err)
+	}
+
+	for i := range slots {
+		encodedValue, err := trie.VerifyProof(state.Root, crypto.Keccak256(slots[i][:]), proofDB(proof.StorageProofs[i]))
+		if err != nil {
+			return nil, fmt.Errorf("invalid proof for slot %#x of %s: %w", slots[i], account.Hex(), err)
+		}
+		if len(encodedValue) == 0 {
+			continue
+		}
+		_, content, _, err := rlp.Split(encodedValue)
+		if err != nil || len(content) > common.HashLength {
+			return nil, fmt.Errorf("invalid value for slot %#x of %s", slots[i], account.Hex())
+		}
+		values[i] = common.BytesToHash(content)
+	}
+
+	return values, nil
+}
+
+// proofDB creates a database of proof nodes keyed by their hashes.
+func proofDB(proof [][]byte) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range proof {
+		// Errors cannot occur when writing to a memory database.
+		_ = db.Put(crypto.Keccak256(node), node)
+	}
+
+	return db
+}
+
+// mappingSlot returns the storage slot of the value for a key in a Solidity
+// mapping held at a slot.  Value type keys must be padded to 32 bytes.
+func mappingSlot(key []byte, slot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(bytes.Join([][]byte{key, slot[:]}, nil))
+}
+
+// uint64Slot returns a storage slot as a hash.
+func uint64Slot(slot uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(slot))
+}
@@ -0,0 +1,292 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/require"
+)
+
+// mockProofBackend holds account storage and provides proofs of it.
+type mockProofBackend struct {
+	storage map[common.Address]map[common.Hash]common.Hash
+	header  *types.Header
+}
+
+func newMockProofBackend() *mockProofBackend {
+	return &mockProofBackend{
+		storage: make(map[common.Address]map[common.Hash]common.Hash),
+	}
+}
+
+func (b *mockProofBackend) set(account common.Address, slot common.Hash, value common.Hash) {
+	if _, exists := b.storage[account]; !exists {
+		b.storage[account] = make(map[common.Hash]common.Hash)
+	}
+	b.storage[account][slot] = value
+}
+
+// setBytes sets bytes at a slot using Solidity's layout.
+func (b *mockProofBackend) setBytes(account common.Address, slot common.Hash, data []byte) {
+	if len(data) < 32 {
+		var value common.Hash
+		copy(value[:], data)
+		value[31] = byte(2 * len(data))
+		b.set(account, slot, value)
+		return
+	}
+	b.set(account, slot, common.BigToHash(big.NewInt(int64(2*len(data)+1))))
+	dataSlot := crypto.Keccak256Hash(slot[:]).Big()
+	for i := 0; i < len(data); i += 32 {
+		var value common.Hash
+		copy(value[:], data[i:])
+		b.set(account, common.BigToHash(new(big.Int).Add(dataSlot, big.NewInt(int64(i/32)))), value)
+	}
+}
+
+// tries builds the state and storage tries.
+func (b *mockProofBackend) tries() (*trie.Trie, map[common.Address]*trie.Trie) {
+	db := triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil)
+	state := trie.NewEmpty(db)
+	storageTries := make(map[common.Address]*trie.Trie)
+	for account, slots := range b.storage {
+		storage := trie.NewEmpty(db)
+		for slot, value := range slots {
+			encoded, _ := rlp.EncodeToBytes(common.TrimLeftZeroes(value[:]))
+			_ = storage.Update(crypto.Keccak256(slot[:]), encoded)
+		}
+		storageTries[account] = storage
+		encoded, _ := rlp.EncodeToBytes(&types.StateAccount{
+			Nonce:    1,
+			Balance:  uint256.NewInt(0),
+			Root:     storage.Hash(),
+			CodeHash: types.EmptyCodeHash.Bytes(),
+		})
+		_ = state.Update(crypto.Keccak256(account.Bytes()), encoded)
+	}
+
+	return state, storageTries
+}
+
+// seal creates the header for the current state, returning its hash.  The
+// header has the fields of a current mainnet header, so that it is only
+// accepted if all of them are hashed.
+func (b *mockProofBackend) seal() common.Hash {
+	state, _ := b.tries()
+	blobGasUsed := uint64(0x20000)
+	excessBlobGas := uint64(0)
+	parentBeaconRoot := common.HexToHash("0x01")
+	b.header = &types.Header{
+		Number:           big.NewInt(1000),
+		Difficulty:       big.NewInt(0),
+		Root:             state.Hash(),
+		BaseFee:          big.NewInt(1_000_000_000),
+		WithdrawalsHash:  &types.EmptyWithdrawalsHash,
+		BlobGasUsed:      &blobGasUsed,
+		ExcessBlobGas:    &excessBlobGas,
+		ParentBeaconRoot: &parentBeaconRoot,
+		RequestsHash:     &types.EmptyRequestsHash,
+	}
+
+	return b.header.Hash()
+}
+
+func (b *mockProofBackend) HeaderByHash(_ context.Context, hash common.Hash) (*types.Header, error) {
+	if b.header == nil || b.header.Hash() != hash {
+		return nil, errors.New("not found")
+	}
+
+	return b.header, nil
+}
+
+func (b *mockProofBackend) GetProof(_ context.Context, account common.Address, slots []common.Hash, _ *big.Int) (*AccountProof, error) {
+	state, storageTries := b.tries()
+	res := &AccountProof{
+		StorageProofs: make([][][]byte, len(slots)),
+	}
+	res.Proof = proofNodes(state, account.Bytes())
+	for i, slot := range slots {
+		storage, exists := storageTries[account]
+		if !exists {
+			storage = trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil))
+		}
+		res.StorageProofs[i] = proofNodes(storage, slot[:])
+	}
+
+	return res, nil
+}
+
+func proofNodes(t *trie.Trie, key []byte) [][]byte {
+	db := memorydb.New()
+	_ = t.Prove(crypto.Keccak256(key), db)
+	nodes := make([][]byte, 0)
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		nodes = append(nodes, common.CopyBytes(it.Value()))
+	}
+
+	return nodes
+}
+
+func TestVerifier(t *testing.T) {
+	b := newMockProofBackend()
+	registry := ChainConfigFor(EthereumMainnet).Registry
+	oldRegistry := common.HexToAddress("0x314159265dD8dbb310642f98f50C066173C1259b")
+	resolver := common.HexToAddress("0x231b0Ee14048e9dCcD1d247744d114a4EB5E8E63")
+	unknownResolver := common.HexToAddress("0x000000000000000000000000000000000000dead")
+	owner := common.HexToAddress("0x000000000000000000000000000000000000a11c")
+	address := common.HexToAddress("0x000000000000000000000000000000000000b0b0")
+	longText := strings.Repeat("long text record ", 5)
+
+	setRecord := func(registry common.Address, name string, resolver common.Address) {
+		node, _ := NameHash(name)
+		record := mappingSlot(node[:], uint64Slot(registryRecordsSlot))
+		b.set(registry, record, common.BytesToHash(owner.Bytes()))
+		if resolver != UnknownAddress {
+			b.set(registry, common.BigToHash(new(big.Int).Add(record.Big(), big.NewInt(1))), common.BytesToHash(resolver.Bytes()))
+		}
+	}
+	setRecord(registry, "verified.eth", resolver)
+	setRecord(registry, "noresolver.eth", UnknownAddress)
+	setRecord(registry, "unknown.eth", unknownResolver)
+	setRecord(oldRegistry, "old.eth", resolver)
+	b.set(registry, uint64Slot(registryOldSlot), common.BytesToHash(oldRegistry.Bytes()))
+
+	// Records are held at version 1 of the node.
+	node, _ := NameHash("verified.eth")
+	layout := PublicResolverStorageLayout
+	b.set(resolver, mappingSlot(node[:], uint64Slot(layout.RecordVersions)), common.BigToHash(big.NewInt(1)))
+	version := common.BigToHash(big.NewInt(1))
+	addresses := mappingSlot(node[:], mappingSlot(version[:], uint64Slot(layout.Addresses)))
+	b.setBytes(resolver, mappingSlot(common.BigToHash(big.NewInt(60)).Bytes(), addresses), address.Bytes())
+	texts := mappingSlot(node[:], mappingSlot(version[:], uint64Slot(layout.Texts)))
+	b.setBytes(resolver, mappingSlot([]byte("url"), texts), []byte("https://example.com/"))
+	b.setBytes(resolver, mappingSlot([]byte("description"), texts), []byte(longText))
+	contenthash := common.FromHex("0xe301017012204edd2984eeaf3ddf50bac238ec95c5713fb40b5e428b508fdbe55d3b9f155ffe")
+	b.setBytes(resolver, mappingSlot(node[:], mappingSlot(version[:], uint64Slot(layout.Contenthashes))), contenthash)
+	// Records at the previous version are not returned.
+	previous := common.BigToHash(big.NewInt(0))
+	b.setBytes(resolver, mappingSlot([]byte("avatar"), mappingSlot(node[:], mappingSlot(previous[:], uint64Slot(layout.Texts)))), []byte("stale"))
+
+	trusted := b.seal()
+
+	_, err := NewVerifier(b, common.HexToHash("0x01"), EthereumMainnet)
+	require.Error(t, err)
+
+	v, err := NewVerifier(b, trusted, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, trusted, v.Header().Hash())
+
+	res, err := v.Owner("verified.eth")
+	require.NoError(t, err)
+	require.Equal(t, owner, res)
+	res, err = v.ResolverAddress("verified.eth")
+	require.NoError(t, err)
+	require.Equal(t, resolver, res)
+	res, err = v.Address("verified.eth")
+	require.NoError(t, err)
+	require.Equal(t, address, res)
+	text, err := v.Text("verified.eth", "url")
+	require.NoError(t, err)
+	require.Equal(t, "https://example.com/", text)
+	text, err = v.Text("verified.eth", "description")
+	require.NoError(t, err)
+	require.Equal(t, longText, text)
+	text, err = v.Text("verified.eth", "avatar")
+	require.NoError(t, err)
+	require.Equal(t, "", text)
+	data, err := v.Contenthash("verified.eth")
+	require.NoError(t, err)
+	require.Equal(t, contenthash, data)
+
+	// Names without a record in the registry use the previous registry.
+	res, err = v.ResolverAddress("old.eth")
+	require.NoError(t, err)
+	require.Equal(t, resolver, res)
+
+	_, err = v.ResolverAddress("noresolver.eth")
+	require.ErrorIs(t, err, ErrNoResolver)
+	_, err = v.Address("unregistered.eth")
+	require.ErrorIs(t, err, ErrNoResolver)
+	_, err = v.Address("unknown.eth")
+	require.ErrorIs(t, err, ErrUnverifiable)
+
+	// Registering the layout of a resolver allows its records to be verified.
+	require.NoError(t, RegisterResolverStorageLayout(unknownResolver, &layout))
+	defer UnregisterResolverStorageLayout(unknownResolver)
+	_, err = v.Address("unknown.eth")
+	require.ErrorIs(t, err, ErrNoAddress)
+
+	// Proofs of state that differs from the trusted block are rejected.
+	b.set(resolver, mappingSlot(common.BigToHash(big.NewInt(60)).Bytes(), addresses), common.BytesToHash(owner.Bytes()))
+	_, err = v.Address("verified.eth")
+	require.ErrorContains(t, err, "invalid proof")
+}
+
+// headerService serves recorded block headers as the eth JSON-RPC namespace.
+type headerService struct {
+	headers map[common.Hash]json.RawMessage
+}
+
+func (s *headerService) GetBlockByHash(hash common.Hash, _ bool) (json.RawMessage, error) {
+	header, exists := s.headers[hash]
+	if !exists {
+		return json.RawMessage("null"), nil
+	}
+
+	return header, nil
+}
+
+func TestNewVerifierMainnetHeader(t *testing.T) {
+	// Block 19431837 is after the Dencun upgrade, so its header has the blob
+	// gas and parent beacon block root fields.
+	trusted := common.HexToHash("0x4cf7d9108fc01b50023ab7cab9b372a96068fddcadec551630393b65acb1f34c")
+	header, err := os.ReadFile(filepath.Join("testdata", "verify", "header-19431837.json"))
+	require.NoError(t, err)
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	require.NoError(t, server.RegisterName("eth", &headerService{
+		headers: map[common.Hash]json.RawMessage{trusted: header},
+	}))
+	backend := NewRPCProofBackend(rpc.DialInProc(server))
+
+	v, err := NewVerifier(backend, trusted, EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, trusted, v.Header().Hash())
+	require.Equal(t, uint64(19431837), v.Header().Number.Uint64())
+
+	_, err = NewVerifier(backend, common.HexToHash("0x01"), EthereumMainnet)
+	require.EqualError(t, err, "block 0x0000000000000000000000000000000000000000000000000000000000000001 not found")
+}
@@ -0,0 +1,118 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+// supportsInterfaceSelector is the selector of the EIP-165 supportsInterface
+// function, which is called on wildcard resolvers directly.
+var supportsInterfaceSelector = []byte{0x01, 0xff, 0xc9, 0xa7}
+
+// wildcardBackend is a backend that passes calls to a wildcard resolver
+// through its ENSIP-10 resolve function, so that the resolver can be used as
+// if it held the records of the name itself.  Calls to other contracts are
+// passed through unchanged.
+type wildcardBackend struct {
+	bind.ContractBackend
+	resolver    common.Address
+	encodedName []byte
+}
+
+// CallContract executes an Ethereum contract call, wrapping calls to the
+// resolver in resolve(bytes,bytes) and unwrapping the result.
+func (b *wildcardBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if call.To == nil || *call.To != b.resolver || len(call.Data) < 4 || bytes.Equal(call.Data[:4], supportsInterfaceSelector) {
+		return b.ContractBackend.CallContract(ctx, call, blockNumber)
+	}
+
+	args, err := callbackArgs.Pack(b.encodedName, call.Data)
+	if err != nil {
+		return nil, err
+	}
+	call.Data = append(extendedInterfaceID[:], args...)
+	res, err := b.ContractBackend.CallContract(ctx, call, blockNumber)
+	if err != nil {
+		return nil, err
+	}
+	values, err := callbackArgs[:1].Unpack(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return values[0].([]byte), nil
+}
+
+// newWildcardResolver obtains the resolver for a name that does not have a
+// resolver of its own, using ENSIP-10 wildcard resolution: the resolver of
+// the closest ancestor is used if it supports the resolve function.
+func newWildcardResolver(backend bind.ContractBackend, domain string, chainId ChainId, o *callOptions) (*Resolver, error) {
+	resolverAddr, wildcard, err := findResolver(backend, domain, chainId, o)
+	if err != nil {
+		return nil, err
+	}
+	if resolverAddr == UnknownAddress || !wildcard {
+		return nil, ErrUnregisteredName
+	}
+	if !implementsInterface(backend, resolverAddr, extendedInterfaceID, o.callOpts()) {
+		return nil, ErrUnregisteredName
+	}
+
+	return bindResolver(backend, domain, resolverAddr, true)
+}
+
+// bindResolver creates a resolver for a domain at a known address without
+// checking the contract.  Wildcard resolvers are called with the ENSIP-10
+// resolve function.
+func bindResolver(backend bind.ContractBackend, domain string, address common.Address, wildcard bool) (*Resolver, error) {
+	if wildcard {
+		encodedName, err := DNSEncodeName(domain)
+		if err != nil {
+			return nil, err
+		}
+		backend = &wildcardBackend{
+			ContractBackend: backend,
+			resolver:        address,
+			encodedName:     encodedName,
+		}
+	}
+	contract, err := resolver.NewContract(address, backend)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Resolver{
+		Contract:     contract,
+		ContractAddr: address,
+		backend:      backend,
+		domain:       domain,
+		wildcard:     wildcard,
+	}, nil
+}
+
+// Wildcard returns true if the resolver is set on an ancestor of the name
+// rather than the name itself, so the name is resolved with ENSIP-10
+// wildcard resolution.
+func (r *Resolver) Wildcard() bool {
+	return r.wildcard
+}
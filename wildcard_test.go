@@ -0,0 +1,270 @@
+// Copyright 2024 Weald Technology Trading.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ens
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-ens/v3/contracts/resolver"
+)
+
+const mockWildcardResolverABI = `[
+{"type":"function","name":"resolve","stateMutability":"view","inputs":[{"name":"name","type":"bytes"},{"name":"data","type":"bytes"}],"outputs":[{"name":"","type":"bytes"}]},
+{"type":"function","name":"resolveCallback","stateMutability":"view","inputs":[{"name":"response","type":"bytes"},{"name":"extraData","type":"bytes"}],"outputs":[{"name":"","type":"bytes"}]},
+{"type":"function","name":"resolveWithProof","stateMutability":"view","inputs":[{"name":"response","type":"bytes"},{"name":"extraData","type":"bytes"}],"outputs":[{"name":"","type":"bytes"}]},
+{"type":"function","name":"signers","stateMutability":"view","inputs":[{"name":"signer","type":"address"}],"outputs":[{"name":"","type":"bool"}]},
+{"type":"function","name":"supportsInterface","stateMutability":"view","inputs":[{"name":"interfaceID","type":"bytes4"}],"outputs":[{"name":"","type":"bool"}]}
+]`
+
+// deployWildcardResolver deploys a wildcard resolver for a name on the mock
+// ENS deployment, answering calls for names under it with resolve.
+func deployWildcardResolver(m *mockENS, name string, address common.Address, resolve mockMethod) *mockContract {
+	m.mu.Lock()
+	m.owners[mustNameHash(name)] = address
+	m.resolvers[mustNameHash(name)] = address
+	m.mu.Unlock()
+
+	return m.backend.deploy(address, mockWildcardResolverABI).
+		on("resolve", resolve).
+		on("supportsInterface", func(args []interface{}) ([]interface{}, error) {
+			interfaceID := args[0].([4]byte)
+			return []interface{}{interfaceID == extendedInterfaceID || interfaceID == [4]byte{0x01, 0xff, 0xc9, 0xa7}}, nil
+		})
+}
+
+func TestWildcardResolver(t *testing.T) {
+	address := common.HexToAddress("0x0102030405060708090a0b0c0d0e0f1011121314")
+	resolverABI, err := abi.JSON(strings.NewReader(resolver.ContractABI))
+	require.NoError(t, err)
+
+	m := newMockENS()
+	deployWildcardResolver(m, "wild.eth", common.HexToAddress("0x000000000000000000000000000000000000a11d"),
+		func(args []interface{}) ([]interface{}, error) {
+			name, err := DNSDecodeName(args[0].([]byte))
+			if err != nil {
+				return nil, err
+			}
+			if name != "sub.wild.eth" {
+				return nil, &mockRevertError{data: revertReasonData("unknown name")}
+			}
+			data := args[1].([]byte)
+			method, err := resolverABI.MethodById(data[:4])
+			if err != nil {
+				return nil, err
+			}
+			var res []byte
+			switch method.Name {
+			case "addr":
+				res, err = method.Outputs.Pack(address)
+			case "text":
+				res, err = method.Outputs.Pack("wildcard")
+			default:
+				return nil, &mockRevertError{data: revertReasonData("unsupported")}
+			}
+			return []interface{}{res}, err
+		})
+	// A resolver that does not support wildcard resolution.
+	m.register("plain.eth", address, address)
+
+	resolver, err := NewResolver(m.backend, "sub.wild.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.True(t, resolver.Wildcard())
+	require.Equal(t, common.HexToAddress("0x000000000000000000000000000000000000a11d"), resolver.ContractAddr)
+
+	resolved, err := resolver.Address()
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+
+	text, err := resolver.Text("url")
+	require.NoError(t, err)
+	require.Equal(t, "wildcard", text)
+
+	records, err := resolver.Records(&RecordsSpec{CoinTypes: []uint64{60}, Texts: []string{"url"}})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"url": "wildcard"}, records.Texts)
+	require.Empty(t, records.Addresses)
+
+	resolved, err = Resolve(m.backend, "sub.wild.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.Equal(t, address, resolved)
+
+	_, err = Resolve(m.backend, "other.wild.eth", EthereumMainnet)
+	require.ErrorIs(t, err, ErrUnregisteredName)
+
+	resolver, err = NewResolver(m.backend, "plain.eth", EthereumMainnet)
+	require.NoError(t, err)
+	require.False(t, resolver.Wildcard())
+
+	_, err = NewResolver(m.backend, "sub.plain.eth", EthereumMainnet)
+	require.ErrorIs(t, err, ErrUnregisteredName)
+
+	_, err = NewResolver(m.backend, "unknown.eth", EthereumMainnet)
+	require.ErrorIs(t, err, ErrUnregisteredName)
+}
+
+// offchainFixture is a recorded exchange with the gateway of an offchain
+// subname provider.
+type offchainFixture struct {
+	Description  string `json:"description"`
+	Name         string `json:"name"`
+	ResolverName string `json:"resolverName"`
+	URL          string `json:"url"`
+	Quirks       struct {
+		Post bool `json:"post"`
+	} `json:"quirks"`
+	Signer    common.Address    `json:"signer"`
+	Address   common.Address    `json:"address"`
+	Texts     map[string]string `json:"texts"`
+	Exchanges []struct {
+		Request string `json:"request"`
+		Body    string `json:"body"`
+	} `json:"exchanges"`
+}
+
+// serve serves the fixture as the provider's gateway, answering only the
+// requests in the recorded exchanges.
+func (f *offchainFixture) serve(t *testing.T, methods *[]string, mu *sync.Mutex) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		*methods = append(*methods, r.Method)
+		mu.Unlock()
+
+		var data string
+		switch r.Method {
+		case http.MethodGet:
+			if f.Quirks.Post {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+				return
+			}
+			data = r.URL.Path[strings.LastIndexByte(r.URL.Path, '/')+1:]
+		case http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			request := &GatewayRequest{}
+			require.NoError(t, json.Unmarshal(body, request))
+			data = request.Data
+		}
+		data = strings.ToLower(strings.TrimSuffix(data, ".json"))
+
+		for _, exchange := range f.Exchanges {
+			if strings.ToLower(exchange.Request) == data {
+				_, _ = w.Write([]byte(exchange.Body))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"record not found"}`))
+	}))
+}
+
+func TestOffchainSubnameProviders(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "offchain", "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+
+	wildcardABI, err := abi.JSON(strings.NewReader(mockWildcardResolverABI))
+	require.NoError(t, err)
+
+	for _, file := range files {
+		t.Run(strings.TrimSuffix(filepath.Base(file), ".json"), func(t *testing.T) {
+			contents, err := os.ReadFile(file)
+			require.NoError(t, err)
+			fixture := &offchainFixture{}
+			require.NoError(t, json.Unmarshal(contents, fixture))
+
+			var mu sync.Mutex
+			methods := make([]string, 0)
+			gateway := fixture.serve(t, &methods, &mu)
+			defer gateway.Close()
+
+			gatewayURL, err := url.Parse(gateway.URL)
+			require.NoError(t, err)
+			require.NoError(t, RegisterGatewayQuirks(gatewayURL.Hostname(), &GatewayQuirks{Post: fixture.Quirks.Post}))
+			defer UnregisterGatewayQuirks(gatewayURL.Hostname())
+
+			resolverAddr := common.BytesToAddress(crypto.Keccak256([]byte(fixture.ResolverName)))
+			m := newMockENS()
+			deployWildcardResolver(m, fixture.ResolverName, resolverAddr, func(args []interface{}) ([]interface{}, error) {
+				callData, err := wildcardABI.Pack("resolve", args[0], args[1])
+				if err != nil {
+					return nil, err
+				}
+				return nil, &mockRevertError{data: offchainLookupData(&OffchainLookup{
+					Sender:           resolverAddr,
+					URLs:             []string{gateway.URL + fixture.URL},
+					CallData:         callData,
+					CallbackFunction: resolveWithProofSelector,
+					ExtraData:        callData,
+				})}
+			}).
+				on("resolveWithProof", func(args []interface{}) ([]interface{}, error) {
+					values, err := signedResponseArgs.Unpack(args[0].([]byte))
+					if err != nil {
+						return nil, err
+					}
+					return []interface{}{values[0].([]byte)}, nil
+				}).
+				on("signers", func(args []interface{}) ([]interface{}, error) {
+					return []interface{}{args[0].(common.Address) == fixture.Signer}, nil
+				})
+
+			client, err := NewClient(m.backend)
+			require.NoError(t, err)
+
+			address, err := client.Resolve(fixture.Name)
+			require.NoError(t, err)
+			require.Equal(t, fixture.Address, address)
+
+			resolver, err := client.Resolver(fixture.Name)
+			require.NoError(t, err)
+			require.True(t, resolver.Wildcard())
+			for key, value := range fixture.Texts {
+				text, err := resolver.Text(key)
+				require.NoError(t, err)
+				require.Equal(t, value, text)
+			}
+
+			profile, err := client.Profile(fixture.Name, nil)
+			require.NoError(t, err)
+			require.Equal(t, fixture.Address, profile.Address)
+			require.Equal(t, fixture.Texts, profile.Texts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			require.NotEmpty(t, methods)
+			if fixture.Quirks.Post {
+				// The registered quirks send every lookup with POST.
+				require.NotContains(t, methods, http.MethodGet)
+			} else {
+				require.NotContains(t, methods, http.MethodPost)
+			}
+		})
+	}
+}